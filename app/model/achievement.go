@@ -37,12 +37,33 @@ const (
 	PublicationTypeBook       PublicationType = "book"
 )
 
+// TeamRole peran anggota pada prestasi tim
+type TeamRole string
+
+const (
+	TeamRoleLeader TeamRole = "leader"
+	TeamRoleMember TeamRole = "member"
+)
+
+// TeamMember anggota prestasi tim. Setiap anggota dikonfirmasi oleh dosen walinya masing-masing.
+type TeamMember struct {
+	StudentID  string     `bson:"studentId" json:"student_id"` // UUID reference to PostgreSQL
+	Role       TeamRole   `bson:"role" json:"role"`
+	VerifiedBy *string    `bson:"verifiedBy,omitempty" json:"verified_by,omitempty"` // User ID dosen wali yang mengkonfirmasi
+	VerifiedAt *time.Time `bson:"verifiedAt,omitempty" json:"verified_at,omitempty"`
+}
+
 // Attachment model sesuai spesifikasi
 type Attachment struct {
-	FileName    string    `bson:"fileName" json:"file_name"`
-	FileURL     string    `bson:"fileUrl" json:"file_url"`
-	FileType    string    `bson:"fileType" json:"file_type"`
-	UploadedAt  time.Time `bson:"uploadedAt" json:"uploaded_at"`
+	ID         string    `bson:"id,omitempty" json:"id"` // Kosong pada attachment lama, lihat service.attachmentID
+	FileName   string    `bson:"fileName" json:"file_name"`
+	FileURL    string    `bson:"fileUrl" json:"file_url"`
+	FileType   string    `bson:"fileType" json:"file_type"`
+	UploadedAt time.Time `bson:"uploadedAt" json:"uploaded_at"`
+	// Quarantined true jika pemindai malware mendeteksi file terinfeksi; file dipindah ke
+	// area karantina dan tidak dapat diunduh
+	Quarantined   bool   `bson:"quarantined,omitempty" json:"quarantined,omitempty"`
+	ScanSignature string `bson:"scanSignature,omitempty" json:"scan_signature,omitempty"`
 }
 
 // Period model untuk organization
@@ -66,6 +87,7 @@ type AchievementDetails struct {
 	Authors          []string         `bson:"authors,omitempty" json:"authors,omitempty"`
 	Publisher        *string          `bson:"publisher,omitempty" json:"publisher,omitempty"`
 	ISSN             *string          `bson:"issn,omitempty" json:"issn,omitempty"`
+	DOI              *string          `bson:"doi,omitempty" json:"doi,omitempty"`
 
 	// Untuk organization
 	OrganizationName *string  `bson:"organizationName,omitempty" json:"organization_name,omitempty"`
@@ -97,9 +119,27 @@ type Achievement struct {
 	Details         AchievementDetails  `bson:"details" json:"details"` // Field dinamis berdasarkan tipe prestasi
 	Attachments     []Attachment        `bson:"attachments" json:"attachments"`
 	Tags            []string            `bson:"tags" json:"tags"`
+	Members         []TeamMember        `bson:"members,omitempty" json:"members,omitempty"` // Kosong untuk prestasi individu; StudentID adalah pembuat
 	Points          float64             `bson:"points" json:"points"` // poin prestasi untuk keperluan scoring
 	Status          AchievementStatus   `bson:"status" json:"status"` // Untuk workflow: draft, submitted, verified, rejected
 	CreatedAt       time.Time           `bson:"createdAt" json:"created_at"`
 	UpdatedAt       time.Time           `bson:"updatedAt" json:"updated_at"`
 	DeletedAt       *time.Time          `bson:"deletedAt,omitempty" json:"deleted_at,omitempty"` // Soft delete
 }
+
+// IsTeam menandakan prestasi tim
+func (a *Achievement) IsTeam() bool {
+	return len(a.Members) > 0
+}
+
+// ParticipantIDs mengembalikan student ID seluruh mahasiswa yang memiliki prestasi ini
+func (a *Achievement) ParticipantIDs() []string {
+	if !a.IsTeam() {
+		return []string{a.StudentID}
+	}
+	ids := make([]string, 0, len(a.Members))
+	for _, member := range a.Members {
+		ids = append(ids, member.StudentID)
+	}
+	return ids
+}
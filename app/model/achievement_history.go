@@ -18,6 +18,9 @@ type AchievementHistory struct {
 	ChangedBy          uuid.UUID         `gorm:"type:uuid;not null" json:"changed_by"`
 	ChangedByUser      User              `gorm:"foreignKey:ChangedBy" json:"changed_by_user,omitempty"`
 	Notes              string            `gorm:"type:text" json:"notes,omitempty"`
+	Stage              *ApprovalStage    `gorm:"type:varchar(30)" json:"stage,omitempty"` // Tahap persetujuan yang menghasilkan entri ini
+	OnBehalfOf         *uuid.UUID        `gorm:"type:uuid" json:"on_behalf_of,omitempty"` // Dosen wali yang diwakili dosen pengganti
+	IsOverride         bool              `gorm:"not null;default:false" json:"is_override"` // Perubahan paksa oleh admin
 	CreatedAt          time.Time         `json:"created_at"`
 }
 
@@ -27,8 +27,17 @@ type AchievementReference struct {
 	VerifiedBy         *uuid.UUID        `gorm:"type:uuid" json:"verified_by,omitempty"`
 	Verifier           User              `gorm:"foreignKey:VerifiedBy" json:"verifier,omitempty"`
 	RejectionNote      string            `gorm:"type:text" json:"rejection_note,omitempty"`
+	ApprovalStages     ApprovalStages    `gorm:"type:jsonb" json:"approval_stages,omitempty"` // Chain yang berlaku saat submit
+	CurrentStage       *ApprovalStage    `gorm:"type:varchar(30);index" json:"current_stage,omitempty"` // Null jika tidak sedang menunggu persetujuan
+	StageStartedAt     *time.Time        `json:"stage_started_at,omitempty"` // Awal tahap saat ini, dasar SLA; null = sejak SubmittedAt
+	SLAFlaggedAt       *time.Time        `json:"sla_flagged_at,omitempty"` // Diisi saat verifikasi melewati batas SLA
+	EscalatedAt        *time.Time        `json:"escalated_at,omitempty"`
+	EscalatedTo        *uuid.UUID        `gorm:"type:uuid" json:"escalated_to,omitempty"` // Verifikator pengganti, jika dikonfigurasi
+	ExpiryReminderDays *int              `json:"expiry_reminder_days,omitempty"` // Jendela pengingat masa berlaku sertifikasi terakhir yang dikirim
+	ExpiryReminderFor  *time.Time        `json:"-"` // validUntil saat pengingat dikirim, pengingat diulang jika berubah
 	CreatedAt          time.Time         `json:"created_at"`
 	UpdatedAt          time.Time         `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt    `gorm:"index" json:"-"` // Diisi saat achievement dihapus, dapat dipulihkan
 }
 
 func (a *AchievementReference) BeforeCreate(tx *gorm.DB) error {
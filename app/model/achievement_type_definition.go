@@ -0,0 +1,48 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FieldType tipe data field pada schema tipe prestasi
+type FieldType string
+
+const (
+	FieldTypeString  FieldType = "string"
+	FieldTypeNumber  FieldType = "number"
+	FieldTypeInteger FieldType = "integer"
+	FieldTypeBoolean FieldType = "boolean"
+	FieldTypeDate    FieldType = "date"  // string tanggal "2006-01-02" atau RFC3339
+	FieldTypeEnum    FieldType = "enum"  // string yang harus salah satu dari Enum
+	FieldTypeArray   FieldType = "array" // daftar string
+)
+
+// FieldDefinition definisi satu field pada schema (mirip JSON Schema sederhana)
+type FieldDefinition struct {
+	Key         string    `bson:"key" json:"key"`
+	Label       string    `bson:"label" json:"label"`
+	Type        FieldType `bson:"type" json:"type"`
+	Required    bool      `bson:"required" json:"required"`
+	Enum        []string  `bson:"enum,omitempty" json:"enum,omitempty"`
+	Description string    `bson:"description,omitempty" json:"description,omitempty"`
+	Min         *float64  `bson:"min,omitempty" json:"min,omitempty"`              // untuk number/integer
+	Max         *float64  `bson:"max,omitempty" json:"max,omitempty"`              // untuk number/integer
+	MaxLength   *int      `bson:"maxLength,omitempty" json:"max_length,omitempty"` // untuk string
+}
+
+// AchievementTypeDefinition model untuk MongoDB
+// Collection achievement_types berisi tipe prestasi tambahan yang didefinisikan admin.
+// Nilai field disimpan di AchievementDetails.CustomFields.
+type AchievementTypeDefinition struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Code        AchievementType    `bson:"code" json:"code"`
+	Name        string             `bson:"name" json:"name"`
+	Description string             `bson:"description" json:"description"`
+	Fields      []FieldDefinition  `bson:"fields" json:"fields"`
+	IsActive    bool               `bson:"isActive" json:"is_active"`
+	CreatedBy   string             `bson:"createdBy" json:"created_by"`
+	CreatedAt   time.Time          `bson:"createdAt" json:"created_at"`
+	UpdatedAt   time.Time          `bson:"updatedAt" json:"updated_at"`
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// VersionKind menandai alasan sebuah versi disimpan
+type VersionKind string
+
+const (
+	VersionKindRevision     VersionKind = "revision"     // setiap kali konten disimpan
+	VersionKindSubmission   VersionKind = "submission"   // snapshot beku saat disubmit
+	VersionKindVerification VersionKind = "verification" // snapshot beku saat diverifikasi
+)
+
+// AchievementVersion model untuk MongoDB
+// Collection achievement_versions menyimpan setiap revisi konten achievement
+type AchievementVersion struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	AchievementID   string             `bson:"achievementId" json:"achievement_id"`
+	Version         int                `bson:"version" json:"version"`
+	Kind            VersionKind        `bson:"kind" json:"kind"`
+	Frozen          bool               `bson:"frozen" json:"frozen"` // true untuk snapshot submission/verification
+	AchievementType AchievementType    `bson:"achievementType" json:"achievement_type"`
+	Title           string             `bson:"title" json:"title"`
+	Description     string             `bson:"description" json:"description"`
+	Details         AchievementDetails `bson:"details" json:"details"`
+	Attachments     []Attachment       `bson:"attachments" json:"attachments"`
+	Tags            []string           `bson:"tags" json:"tags"`
+	Status          AchievementStatus  `bson:"status" json:"status"`
+	CreatedBy       string             `bson:"createdBy" json:"created_by"` // UUID user yang menyimpan versi
+	CreatedAt       time.Time          `bson:"createdAt" json:"created_at"`
+}
@@ -0,0 +1,36 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AdvisorDelegation penunjukan dosen pengganti yang bertindak sebagai dosen wali
+// selama rentang tanggal tertentu (misalnya saat cuti atau sabatikal)
+type AdvisorDelegation struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	LecturerID uuid.UUID `gorm:"type:uuid;not null;index" json:"lecturer_id"` // Dosen wali yang diwakilkan
+	Lecturer   Lecturer  `gorm:"foreignKey:LecturerID" json:"lecturer,omitempty"`
+	DelegateID uuid.UUID `gorm:"type:uuid;not null;index" json:"delegate_id"` // Dosen pengganti
+	Delegate   Lecturer  `gorm:"foreignKey:DelegateID" json:"delegate,omitempty"`
+	StartDate  time.Time `gorm:"not null" json:"start_date"`
+	EndDate    time.Time `gorm:"not null" json:"end_date"`
+	Reason     string    `gorm:"type:text" json:"reason,omitempty"`
+	CreatedBy  uuid.UUID `gorm:"type:uuid;not null" json:"created_by"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func (d *AdvisorDelegation) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsActiveAt true jika delegasi berlaku pada waktu t
+func (d *AdvisorDelegation) IsActiveAt(t time.Time) bool {
+	return !t.Before(d.StartDate) && !t.After(d.EndDate)
+}
@@ -0,0 +1,83 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ApprovalStage tahap persetujuan prestasi
+type ApprovalStage string
+
+const (
+	ApprovalStageAdvisor        ApprovalStage = "advisor"         // dosen wali
+	ApprovalStageProgramHead    ApprovalStage = "program_head"    // ketua program studi
+	ApprovalStageStudentAffairs ApprovalStage = "student_affairs" // bagian kemahasiswaan
+)
+
+// ApprovalStages daftar tahap berurutan, disimpan sebagai JSON di PostgreSQL
+type ApprovalStages []ApprovalStage
+
+func (s ApprovalStages) Value() (driver.Value, error) {
+	if s == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (s *ApprovalStages) Scan(value interface{}) error {
+	if value == nil {
+		*s = nil
+		return nil
+	}
+
+	var data []byte
+	switch v := value.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return errors.New("tipe data approval stages tidak didukung")
+	}
+	return json.Unmarshal(data, s)
+}
+
+// Next mengembalikan tahap setelah stage, atau nil jika stage adalah tahap terakhir
+func (s ApprovalStages) Next(stage ApprovalStage) *ApprovalStage {
+	for i, current := range s {
+		if current == stage && i+1 < len(s) {
+			next := s[i+1]
+			return &next
+		}
+	}
+	return nil
+}
+
+// ApprovalChain urutan persetujuan untuk tipe prestasi (dan tingkat kompetisi) tertentu.
+// CompetitionLevel NULL berarti berlaku untuk semua tingkat.
+type ApprovalChain struct {
+	ID               uuid.UUID         `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	Name             string            `gorm:"type:varchar(100);not null" json:"name"`
+	AchievementType  AchievementType   `gorm:"type:varchar(50);not null;index" json:"achievement_type"`
+	CompetitionLevel *CompetitionLevel `gorm:"type:varchar(20)" json:"competition_level,omitempty"`
+	Stages           ApprovalStages    `gorm:"type:jsonb;not null" json:"stages"`
+	IsActive         bool              `gorm:"default:true" json:"is_active"`
+	CreatedAt        time.Time         `json:"created_at"`
+	UpdatedAt        time.Time         `json:"updated_at"`
+}
+
+func (a *ApprovalChain) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// BadgeAssertion assertion Open Badges 2.0 untuk satu peserta achievement terverifikasi.
+// ID dipakai sebagai bagian URL hosted assertion sehingga tidak boleh berubah.
+type BadgeAssertion struct {
+	ID                 uuid.UUID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	MongoAchievementID string     `gorm:"type:varchar(24);not null;index" json:"mongo_achievement_id"`
+	StudentID          uuid.UUID  `gorm:"type:uuid;not null;index" json:"student_id"`
+	RecipientIdentity  string     `gorm:"type:varchar(100);not null" json:"recipient_identity"` // sha256$<hex> dari email + salt
+	RecipientSalt      string     `gorm:"type:varchar(32);not null" json:"-"`
+	IssuedOn           time.Time  `gorm:"not null" json:"issued_on"`
+	Credential         string     `gorm:"type:text" json:"-"` // Verifiable Credential (JWT), kosong jika tidak diterbitkan
+	RevokedAt          *time.Time `json:"revoked_at,omitempty"`
+	RevocationReason   string     `gorm:"type:text" json:"revocation_reason,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+}
+
+func (b *BadgeAssertion) BeforeCreate(tx *gorm.DB) error {
+	if b.ID == uuid.Nil {
+		b.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Certificate sertifikat verifikasi prestasi untuk satu peserta.
+// Data yang tercetak disimpan sebagai snapshot agar dapat diverifikasi ulang dari kode QR.
+type Certificate struct {
+	ID                 uuid.UUID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	Code               string     `gorm:"type:varchar(32);not null;uniqueIndex" json:"code"`
+	MongoAchievementID string     `gorm:"type:varchar(24);not null;index" json:"mongo_achievement_id"`
+	StudentID          uuid.UUID  `gorm:"type:uuid;not null;index" json:"student_id"`
+	StudentName        string     `gorm:"type:varchar(100);not null" json:"student_name"`
+	StudentNIM         string     `gorm:"type:varchar(20);not null" json:"student_nim"`
+	ProgramStudy       string     `gorm:"type:varchar(100)" json:"program_study"`
+	Title              string     `gorm:"type:text;not null" json:"title"`
+	AchievementType    string     `gorm:"type:varchar(50)" json:"achievement_type"`
+	VerifiedBy         uuid.UUID  `gorm:"type:uuid;not null" json:"verified_by"`
+	VerifierName       string     `gorm:"type:varchar(100);not null" json:"verifier_name"`
+	VerifiedAt         time.Time  `gorm:"not null" json:"verified_at"`
+	Signature          string     `gorm:"type:varchar(64);not null" json:"signature"` // HMAC-SHA256 atas data sertifikat
+	FilePath           string     `gorm:"type:varchar(255);not null" json:"-"`
+	RevokedAt          *time.Time `json:"revoked_at,omitempty"` // Diisi saat digantikan sertifikat baru atau prestasi batal terverifikasi
+	CreatedAt          time.Time  `json:"created_at"`
+}
+
+func (c *Certificate) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type NotificationType string
+
+const (
+	NotificationSLAOverdue            NotificationType = "sla_overdue"
+	NotificationEscalation            NotificationType = "escalation"
+	NotificationCertificationExpiring NotificationType = "certification_expiring"
+)
+
+// Notification pemberitahuan in-app untuk pengguna
+type Notification struct {
+	ID                 uuid.UUID        `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	UserID             uuid.UUID        `gorm:"type:uuid;not null;index" json:"user_id"`
+	Type               NotificationType `gorm:"type:varchar(30);not null" json:"type"`
+	Title              string           `gorm:"type:varchar(200);not null" json:"title"`
+	Message            string           `gorm:"type:text" json:"message"`
+	MongoAchievementID string           `gorm:"type:varchar(24)" json:"mongo_achievement_id,omitempty"`
+	ReadAt             *time.Time       `json:"read_at,omitempty"`
+	CreatedAt          time.Time        `json:"created_at"`
+}
+
+func (n *Notification) BeforeCreate(tx *gorm.DB) error {
+	if n.ID == uuid.Nil {
+		n.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PointRule aturan perhitungan poin prestasi yang dikelola admin.
+// Kriteria yang bernilai NULL berarti berlaku untuk semua nilai.
+type PointRule struct {
+	ID               uuid.UUID         `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	Name             string            `gorm:"type:varchar(100);not null" json:"name"`
+	AchievementType  AchievementType   `gorm:"type:varchar(50);not null;index" json:"achievement_type"`
+	CompetitionLevel *CompetitionLevel `gorm:"type:varchar(20)" json:"competition_level,omitempty"`
+	Rank             *int              `json:"rank,omitempty"`
+	MedalType        *string           `gorm:"type:varchar(20)" json:"medal_type,omitempty"`
+	PublicationType  *PublicationType  `gorm:"type:varchar(20)" json:"publication_type,omitempty"`
+	Position         *string           `gorm:"type:varchar(100)" json:"position,omitempty"`
+	Points           float64           `gorm:"type:numeric(10,2);not null" json:"points"`
+	Priority         int               `gorm:"default:0" json:"priority"` // penentu jika dua aturan sama spesifik
+	IsActive         bool              `gorm:"default:true" json:"is_active"`
+	CreatedAt        time.Time         `json:"created_at"`
+	UpdatedAt        time.Time         `json:"updated_at"`
+}
+
+func (p *PointRule) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
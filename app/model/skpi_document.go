@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SKPIDocument versi final SKPI (Surat Keterangan Pendamping Ijazah) yang dikunci admin
+// saat kelulusan. Content menyimpan snapshot JSON dokumen sehingga tidak berubah lagi.
+type SKPIDocument struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	StudentID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"student_id"`
+	Number    string    `gorm:"type:varchar(100);not null" json:"number"`
+	Content   string    `gorm:"type:jsonb;not null" json:"-"`
+	LockedBy  uuid.UUID `gorm:"type:uuid;not null" json:"locked_by"`
+	LockedAt  time.Time `gorm:"not null" json:"locked_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (d *SKPIDocument) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return nil
+}
@@ -9,8 +9,10 @@ import (
 
 type AchievementHistoryRepository interface {
 	CreateHistory(ctx context.Context, history *model.AchievementHistory) error
+	CreateHistories(ctx context.Context, histories []model.AchievementHistory) error
 	FindHistoriesByAchievementRefID(ctx context.Context, achievementRefID uuid.UUID) ([]model.AchievementHistory, error)
 	FindHistoriesByMongoAchievementID(ctx context.Context, mongoID string) ([]model.AchievementHistory, error)
+	DeleteHistoriesByMongoAchievementID(ctx context.Context, mongoID string) error
 }
 
 type achievementHistoryRepository struct {
@@ -27,6 +29,13 @@ func (r *achievementHistoryRepository) CreateHistory(ctx context.Context, histor
 	return r.db.WithContext(ctx).Create(history).Error
 }
 
+func (r *achievementHistoryRepository) CreateHistories(ctx context.Context, histories []model.AchievementHistory) error {
+	if len(histories) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(&histories).Error
+}
+
 func (r *achievementHistoryRepository) FindHistoriesByAchievementRefID(ctx context.Context, achievementRefID uuid.UUID) ([]model.AchievementHistory, error) {
 	var histories []model.AchievementHistory
 	err := r.db.WithContext(ctx).Preload("ChangedByUser").Preload("AchievementRef").
@@ -45,3 +54,6 @@ func (r *achievementHistoryRepository) FindHistoriesByMongoAchievementID(ctx con
 	return histories, err
 }
 
+func (r *achievementHistoryRepository) DeleteHistoriesByMongoAchievementID(ctx context.Context, mongoID string) error {
+	return r.db.WithContext(ctx).Where("mongo_achievement_id = ?", mongoID).Delete(&model.AchievementHistory{}).Error
+}
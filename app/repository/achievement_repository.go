@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,6 +11,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"gorm.io/gorm"
 )
 
@@ -18,6 +20,7 @@ type AchievementStatistics struct {
 	TotalByPeriod               []PeriodStat
 	TopStudents                 []TopStudentStat
 	CompetitionLevelDistribution map[string]int64
+	ExpiredCertifications        int64
 }
 
 type PeriodStat struct {
@@ -31,22 +34,56 @@ type TopStudentStat struct {
 	TotalAchievements int64
 }
 
+// DuplicateCandidateFilter kriteria pencarian kandidat duplikat. Kandidat harus bertipe sama
+// dan memenuhi minimal satu kriteria lainnya; skor kemiripan dihitung di service.
+type DuplicateCandidateFilter struct {
+	ExcludeID        primitive.ObjectID
+	AchievementType  model.AchievementType
+	StudentID        string
+	EventDateFrom    *time.Time
+	EventDateTo      *time.Time
+	CompetitionName  string
+	PublicationTitle string
+	ISSN             string // tanpa tanda hubung, lihat normalisasi di service
+	DOI              string // tanpa prefix URL
+	Limit            int64
+}
+
 type AchievementRepository interface {
 	// MongoDB operations
 	CreateAchievement(ctx context.Context, achievement *model.Achievement) (*model.Achievement, error)
+	CreateAchievements(ctx context.Context, achievements []*model.Achievement) error
 	FindAchievementByID(ctx context.Context, id string) (*model.Achievement, error)
 	FindAchievementsByStudentID(ctx context.Context, studentID string) ([]model.Achievement, error)
+	FindAllAchievements(ctx context.Context) ([]model.Achievement, error)
 	UpdateAchievement(ctx context.Context, id string, achievement *model.Achievement) error
+	UpdateAchievementPoints(ctx context.Context, id string, points float64) error
 	SoftDeleteAchievement(ctx context.Context, id string) error
+	UpdateAchievementOwner(ctx context.Context, id string, studentID string) error
+	FindDeletedAchievementByID(ctx context.Context, id string) (*model.Achievement, error)
+	FindAchievementsDeletedBefore(ctx context.Context, cutoff time.Time) ([]model.Achievement, error)
+	RestoreAchievement(ctx context.Context, id string) error
+	PurgeAchievement(ctx context.Context, id string) error
+	FindDuplicateCandidates(ctx context.Context, filter DuplicateCandidateFilter) ([]model.Achievement, error)
+	FindCertificationsValidUntil(ctx context.Context, from, to *time.Time) ([]model.Achievement, error)
+	FindAchievementsWithAttachments(ctx context.Context) ([]model.Achievement, error)
+	UpdateAttachments(ctx context.Context, id primitive.ObjectID, attachments []model.Attachment) error
 
 	// PostgreSQL operations
 	CreateReference(ctx context.Context, reference *model.AchievementReference) error
+	CreateReferences(ctx context.Context, references []model.AchievementReference) error
 	UpdateReference(ctx context.Context, reference *model.AchievementReference) error
 	FindReferenceByID(ctx context.Context, id uuid.UUID) (*model.AchievementReference, error)
 	FindReferenceByMongoID(ctx context.Context, mongoID string) (*model.AchievementReference, error)
+	FindReferencesByMongoID(ctx context.Context, mongoID string) ([]model.AchievementReference, error)
 	FindReferencesByStudentIDs(ctx context.Context, studentIDs []uuid.UUID) ([]model.AchievementReference, error)
 	FindReferencesWithPagination(ctx context.Context, studentIDs []uuid.UUID, page, limit int) ([]model.AchievementReference, int64, error)
+	FindPendingReferencesByStage(ctx context.Context, stage model.ApprovalStage, studentIDs []uuid.UUID) ([]model.AchievementReference, error)
+	FindReferencesEscalatedTo(ctx context.Context, userID uuid.UUID) ([]model.AchievementReference, error)
 	DeleteReference(ctx context.Context, id uuid.UUID) error
+	PurgeReference(ctx context.Context, id uuid.UUID) error
+	RestoreReferences(ctx context.Context, mongoID string) error
+	PurgeReferencesByMongoID(ctx context.Context, mongoID string) error
 
 	// Statistics operations
 	GetAchievementStatistics(ctx context.Context, studentIDs []string) (*AchievementStatistics, error)
@@ -79,6 +116,28 @@ func (r *achievementRepository) CreateAchievement(ctx context.Context, achieveme
 	return achievement, nil
 }
 
+// CreateAchievements menyimpan banyak achievement sekaligus (dipakai import massal).
+// ID hasil insert diisi kembali ke masing-masing achievement.
+func (r *achievementRepository) CreateAchievements(ctx context.Context, achievements []*model.Achievement) error {
+	if len(achievements) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	documents := make([]interface{}, len(achievements))
+	for i, achievement := range achievements {
+		if achievement.ID.IsZero() {
+			achievement.ID = primitive.NewObjectID()
+		}
+		achievement.CreatedAt = now
+		achievement.UpdatedAt = now
+		documents[i] = achievement
+	}
+
+	_, err := r.mongoCollection.InsertMany(ctx, documents)
+	return err
+}
+
 func (r *achievementRepository) FindAchievementByID(ctx context.Context, id string) (*model.Achievement, error) {
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
@@ -99,9 +158,32 @@ func (r *achievementRepository) FindAchievementByID(ctx context.Context, id stri
 	return &achievement, nil
 }
 
+// FindAchievementsByStudentID termasuk prestasi tim di mana mahasiswa menjadi anggota
 func (r *achievementRepository) FindAchievementsByStudentID(ctx context.Context, studentID string) ([]model.Achievement, error) {
 	filter := bson.M{
-		"studentId": studentID,
+		"$or": bson.A{
+			bson.M{"studentId": studentID},
+			bson.M{"members.studentId": studentID},
+		},
+		"deletedAt": bson.M{"$exists": false},
+	}
+
+	cursor, err := r.mongoCollection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var achievements []model.Achievement
+	if err = cursor.All(ctx, &achievements); err != nil {
+		return nil, err
+	}
+
+	return achievements, nil
+}
+
+func (r *achievementRepository) FindAllAchievements(ctx context.Context) ([]model.Achievement, error) {
+	filter := bson.M{
 		"deletedAt": bson.M{"$exists": false},
 	}
 
@@ -119,6 +201,117 @@ func (r *achievementRepository) FindAchievementsByStudentID(ctx context.Context,
 	return achievements, nil
 }
 
+// FindAchievementsWithAttachments termasuk achievement yang dihapus (masih dapat dipulihkan)
+func (r *achievementRepository) FindAchievementsWithAttachments(ctx context.Context) ([]model.Achievement, error) {
+	filter := bson.M{"attachments.0": bson.M{"$exists": true}}
+
+	cursor, err := r.mongoCollection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	achievements := []model.Achievement{}
+	if err = cursor.All(ctx, &achievements); err != nil {
+		return nil, err
+	}
+
+	return achievements, nil
+}
+
+// UpdateAttachments hanya mengganti daftar attachment tanpa mengubah updatedAt,
+// dipakai saat memindahkan file antar backend penyimpanan
+func (r *achievementRepository) UpdateAttachments(ctx context.Context, id primitive.ObjectID, attachments []model.Attachment) error {
+	_, err := r.mongoCollection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"attachments": attachments}})
+	return err
+}
+
+// FindCertificationsValidUntil mengembalikan sertifikasi terverifikasi dengan validUntil dalam
+// rentang [from, to], diurutkan dari yang paling cepat berakhir. from/to nil = tanpa batas.
+func (r *achievementRepository) FindCertificationsValidUntil(ctx context.Context, from, to *time.Time) ([]model.Achievement, error) {
+	validUntil := bson.M{"$exists": true, "$ne": nil}
+	if from != nil {
+		validUntil["$gte"] = *from
+	}
+	if to != nil {
+		validUntil["$lte"] = *to
+	}
+
+	filter := bson.M{
+		"achievementType":    model.AchievementTypeCertification,
+		"status":             model.StatusVerified,
+		"details.validUntil": validUntil,
+		"deletedAt":          bson.M{"$exists": false},
+	}
+
+	opts := options.Find().SetSort(bson.M{"details.validUntil": 1})
+	cursor, err := r.mongoCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	achievements := []model.Achievement{}
+	if err := cursor.All(ctx, &achievements); err != nil {
+		return nil, err
+	}
+	return achievements, nil
+}
+
+func (r *achievementRepository) FindDuplicateCandidates(ctx context.Context, filter DuplicateCandidateFilter) ([]model.Achievement, error) {
+	or := bson.A{}
+	if filter.StudentID != "" {
+		or = append(or, bson.M{"studentId": filter.StudentID})
+	}
+	if filter.EventDateFrom != nil && filter.EventDateTo != nil {
+		or = append(or, bson.M{"details.eventDate": bson.M{"$gte": *filter.EventDateFrom, "$lte": *filter.EventDateTo}})
+	}
+	if filter.CompetitionName != "" {
+		or = append(or, bson.M{"details.competitionName": bson.M{"$regex": "^" + regexp.QuoteMeta(filter.CompetitionName) + "$", "$options": "i"}})
+	}
+	if filter.PublicationTitle != "" {
+		or = append(or, bson.M{"details.publicationTitle": bson.M{"$regex": "^" + regexp.QuoteMeta(filter.PublicationTitle) + "$", "$options": "i"}})
+	}
+	if len(filter.ISSN) == 8 {
+		// ISSN bisa tersimpan dengan atau tanpa tanda hubung
+		pattern := "^" + regexp.QuoteMeta(filter.ISSN[:4]) + "-?" + regexp.QuoteMeta(filter.ISSN[4:]) + "$"
+		or = append(or, bson.M{"details.issn": bson.M{"$regex": pattern, "$options": "i"}})
+	}
+	if filter.DOI != "" {
+		// DOI bisa tersimpan sebagai URL doi.org atau dengan prefix "doi:"
+		pattern := `(^|doi\.org/|doi:)\s*` + regexp.QuoteMeta(filter.DOI) + "$"
+		or = append(or, bson.M{"details.doi": bson.M{"$regex": pattern, "$options": "i"}})
+	}
+	if len(or) == 0 {
+		return []model.Achievement{}, nil
+	}
+
+	query := bson.M{
+		"_id":             bson.M{"$ne": filter.ExcludeID},
+		"achievementType": filter.AchievementType,
+		"deletedAt":       bson.M{"$exists": false},
+		"$or":             or,
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	if filter.Limit > 0 {
+		opts.SetLimit(filter.Limit)
+	}
+
+	cursor, err := r.mongoCollection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	achievements := []model.Achievement{}
+	if err = cursor.All(ctx, &achievements); err != nil {
+		return nil, err
+	}
+
+	return achievements, nil
+}
+
 func (r *achievementRepository) UpdateAchievement(ctx context.Context, id string, achievement *model.Achievement) error {
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
@@ -134,6 +327,7 @@ func (r *achievementRepository) UpdateAchievement(ctx context.Context, id string
 			"details":         achievement.Details,
 			"attachments":     achievement.Attachments,
 			"tags":            achievement.Tags,
+			"members":         achievement.Members,
 			"points":          achievement.Points,
 			"status":          achievement.Status,
 			"updatedAt":       achievement.UpdatedAt,
@@ -149,6 +343,28 @@ func (r *achievementRepository) UpdateAchievement(ctx context.Context, id string
 	return err
 }
 
+func (r *achievementRepository) UpdateAchievementPoints(ctx context.Context, id string, points float64) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"points":    points,
+			"updatedAt": time.Now(),
+		},
+	}
+
+	filter := bson.M{
+		"_id":       objectID,
+		"deletedAt": bson.M{"$exists": false},
+	}
+
+	_, err = r.mongoCollection.UpdateOne(ctx, filter, update)
+	return err
+}
+
 func (r *achievementRepository) SoftDeleteAchievement(ctx context.Context, id string) error {
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
@@ -168,12 +384,110 @@ func (r *achievementRepository) SoftDeleteAchievement(ctx context.Context, id st
 	return err
 }
 
+// UpdateAchievementOwner memindahkan kepemilikan achievement ke mahasiswa lain
+func (r *achievementRepository) UpdateAchievementOwner(ctx context.Context, id string, studentID string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"studentId": studentID,
+			"updatedAt": time.Now(),
+		},
+	}
+
+	filter := bson.M{
+		"_id":       objectID,
+		"deletedAt": bson.M{"$exists": false},
+	}
+
+	_, err = r.mongoCollection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+// FindDeletedAchievementByID hanya mengembalikan achievement yang sudah di-soft delete
+func (r *achievementRepository) FindDeletedAchievementByID(ctx context.Context, id string) (*model.Achievement, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var achievement model.Achievement
+	filter := bson.M{
+		"_id":       objectID,
+		"deletedAt": bson.M{"$exists": true},
+	}
+
+	err = r.mongoCollection.FindOne(ctx, filter).Decode(&achievement)
+	if err != nil {
+		return nil, err
+	}
+
+	return &achievement, nil
+}
+
+func (r *achievementRepository) FindAchievementsDeletedBefore(ctx context.Context, cutoff time.Time) ([]model.Achievement, error) {
+	filter := bson.M{
+		"deletedAt": bson.M{"$lt": cutoff},
+	}
+
+	cursor, err := r.mongoCollection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var achievements []model.Achievement
+	if err = cursor.All(ctx, &achievements); err != nil {
+		return nil, err
+	}
+
+	return achievements, nil
+}
+
+func (r *achievementRepository) RestoreAchievement(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{
+		"$unset": bson.M{"deletedAt": ""},
+		"$set":   bson.M{"updatedAt": time.Now()},
+	}
+
+	filter := bson.M{"_id": objectID}
+	_, err = r.mongoCollection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+// PurgeAchievement menghapus dokumen achievement secara permanen
+func (r *achievementRepository) PurgeAchievement(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.mongoCollection.DeleteOne(ctx, bson.M{"_id": objectID})
+	return err
+}
+
 // PostgreSQL operations
 
 func (r *achievementRepository) CreateReference(ctx context.Context, reference *model.AchievementReference) error {
 	return r.db.WithContext(ctx).Create(reference).Error
 }
 
+// CreateReferences menyimpan banyak reference dalam satu transaksi
+func (r *achievementRepository) CreateReferences(ctx context.Context, references []model.AchievementReference) error {
+	if len(references) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(&references).Error
+}
+
 func (r *achievementRepository) UpdateReference(ctx context.Context, reference *model.AchievementReference) error {
 	return r.db.WithContext(ctx).Save(reference).Error
 }
@@ -188,16 +502,42 @@ func (r *achievementRepository) FindReferenceByID(ctx context.Context, id uuid.U
 	return &reference, nil
 }
 
+// FindReferenceByMongoID mengembalikan reference milik pembuat achievement.
+// Prestasi tim memiliki satu reference per anggota; reference pembuat dicari berdasarkan
+// studentId achievement (termasuk yang sudah dihapus), bukan urutan pembuatan reference,
+// karena import massal dan pemindahan pemilik tidak menjamin urutan tersebut.
 func (r *achievementRepository) FindReferenceByMongoID(ctx context.Context, mongoID string) (*model.AchievementReference, error) {
+	objectID, err := primitive.ObjectIDFromHex(mongoID)
+	if err != nil {
+		return nil, err
+	}
+
+	var owner struct {
+		StudentID string `bson:"studentId"`
+	}
+	opts := options.FindOne().SetProjection(bson.M{"studentId": 1})
+	if err := r.mongoCollection.FindOne(ctx, bson.M{"_id": objectID}, opts).Decode(&owner); err != nil {
+		return nil, err
+	}
+
 	var reference model.AchievementReference
-	err := r.db.WithContext(ctx).Preload("Student").Preload("Student.User").Preload("Verifier").
-		Where("mongo_achievement_id = ?", mongoID).First(&reference).Error
+	err = r.db.WithContext(ctx).Preload("Student").Preload("Student.User").Preload("Verifier").
+		Where("mongo_achievement_id = ? AND student_id = ?", mongoID, owner.StudentID).First(&reference).Error
 	if err != nil {
 		return nil, err
 	}
 	return &reference, nil
 }
 
+func (r *achievementRepository) FindReferencesByMongoID(ctx context.Context, mongoID string) ([]model.AchievementReference, error) {
+	var references []model.AchievementReference
+	err := r.db.WithContext(ctx).Preload("Student").Preload("Student.User").Preload("Verifier").
+		Where("mongo_achievement_id = ?", mongoID).
+		Order("created_at ASC").
+		Find(&references).Error
+	return references, err
+}
+
 func (r *achievementRepository) FindReferencesByStudentIDs(ctx context.Context, studentIDs []uuid.UUID) ([]model.AchievementReference, error) {
 	var references []model.AchievementReference
 	err := r.db.WithContext(ctx).Preload("Student").Preload("Student.User").Preload("Verifier").
@@ -207,10 +547,28 @@ func (r *achievementRepository) FindReferencesByStudentIDs(ctx context.Context,
 	return references, err
 }
 
+// DeleteReference soft delete, reference masih dapat dipulihkan
 func (r *achievementRepository) DeleteReference(ctx context.Context, id uuid.UUID) error {
 	return r.db.WithContext(ctx).Delete(&model.AchievementReference{}, id).Error
 }
 
+// PurgeReference menghapus reference secara permanen
+func (r *achievementRepository) PurgeReference(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Unscoped().Delete(&model.AchievementReference{}, id).Error
+}
+
+func (r *achievementRepository) RestoreReferences(ctx context.Context, mongoID string) error {
+	return r.db.WithContext(ctx).Unscoped().Model(&model.AchievementReference{}).
+		Where("mongo_achievement_id = ? AND deleted_at IS NOT NULL", mongoID).
+		Update("deleted_at", nil).Error
+}
+
+func (r *achievementRepository) PurgeReferencesByMongoID(ctx context.Context, mongoID string) error {
+	return r.db.WithContext(ctx).Unscoped().
+		Where("mongo_achievement_id = ?", mongoID).
+		Delete(&model.AchievementReference{}).Error
+}
+
 func (r *achievementRepository) FindReferencesWithPagination(ctx context.Context, studentIDs []uuid.UUID, page, limit int) ([]model.AchievementReference, int64, error) {
 	var references []model.AchievementReference
 	var total int64
@@ -231,6 +589,39 @@ func (r *achievementRepository) FindReferencesWithPagination(ctx context.Context
 	return references, total, err
 }
 
+// FindPendingReferencesByStage mengembalikan reference berstatus submitted yang menunggu tahap tertentu.
+// studentIDs nil berarti semua mahasiswa. Reference lama tanpa current_stage dianggap tahap advisor.
+func (r *achievementRepository) FindPendingReferencesByStage(ctx context.Context, stage model.ApprovalStage, studentIDs []uuid.UUID) ([]model.AchievementReference, error) {
+	var references []model.AchievementReference
+
+	query := r.db.WithContext(ctx).Preload("Student").Preload("Student.User").
+		Where("status = ?", model.StatusSubmitted)
+
+	if stage == model.ApprovalStageAdvisor {
+		query = query.Where("(current_stage = ? OR current_stage IS NULL)", stage)
+	} else {
+		query = query.Where("current_stage = ?", stage)
+	}
+
+	if studentIDs != nil {
+		query = query.Where("student_id IN ?", studentIDs)
+	}
+
+	err := query.Order("submitted_at ASC").Find(&references).Error
+	return references, err
+}
+
+// FindReferencesEscalatedTo mengembalikan reference tahap advisor yang dieskalasi ke verifikator pengganti
+func (r *achievementRepository) FindReferencesEscalatedTo(ctx context.Context, userID uuid.UUID) ([]model.AchievementReference, error) {
+	var references []model.AchievementReference
+	err := r.db.WithContext(ctx).Preload("Student").Preload("Student.User").
+		Where("status = ? AND escalated_to = ?", model.StatusSubmitted, userID).
+		Where("(current_stage = ? OR current_stage IS NULL)", model.ApprovalStageAdvisor).
+		Order("submitted_at ASC").
+		Find(&references).Error
+	return references, err
+}
+
 func (r *achievementRepository) GetAchievementStatistics(ctx context.Context, studentIDs []string) (*AchievementStatistics, error) {
 	stats := &AchievementStatistics{
 		TotalByType:                  make(map[string]int64),
@@ -239,12 +630,18 @@ func (r *achievementRepository) GetAchievementStatistics(ctx context.Context, st
 		CompetitionLevelDistribution: make(map[string]int64),
 	}
 
+	// Hanya prestasi yang sudah menyelesaikan seluruh tahap persetujuan
 	matchFilter := bson.M{
+		"status":    model.StatusVerified,
 		"deletedAt": bson.M{"$exists": false},
 	}
 
+	// Prestasi tim dihitung jika salah satu anggotanya termasuk studentIDs
 	if len(studentIDs) > 0 {
-		matchFilter["studentId"] = bson.M{"$in": studentIDs}
+		matchFilter["$or"] = bson.A{
+			bson.M{"studentId": bson.M{"$in": studentIDs}},
+			bson.M{"members.studentId": bson.M{"$in": studentIDs}},
+		}
 	}
 
 	totalByTypePipeline := []bson.M{
@@ -306,10 +703,25 @@ func (r *achievementRepository) GetAchievementStatistics(ctx context.Context, st
 		}
 	}
 
+	// Poin prestasi tim diberikan ke setiap anggota, jadi pecah per peserta
+	participantFilter := bson.M{}
+	if len(studentIDs) > 0 {
+		participantFilter["participants"] = bson.M{"$in": studentIDs}
+	}
+
 	topStudentsPipeline := []bson.M{
 		{"$match": matchFilter},
+		{"$addFields": bson.M{
+			"participants": bson.M{"$cond": bson.A{
+				bson.M{"$gt": bson.A{bson.M{"$size": bson.M{"$ifNull": bson.A{"$members", bson.A{}}}}, 0}},
+				"$members.studentId",
+				bson.A{"$studentId"},
+			}},
+		}},
+		{"$unwind": "$participants"},
+		{"$match": participantFilter},
 		{"$group": bson.M{
-			"_id":              "$studentId",
+			"_id":              "$participants",
 			"totalPoints":      bson.M{"$sum": "$points"},
 			"totalAchievements": bson.M{"$sum": 1},
 		}},
@@ -340,11 +752,12 @@ func (r *achievementRepository) GetAchievementStatistics(ctx context.Context, st
 
 	competitionMatchFilter := bson.M{
 		"achievementType": "competition",
+		"status":          model.StatusVerified,
 		"deletedAt":       bson.M{"$exists": false},
 	}
 
 	if len(studentIDs) > 0 {
-		competitionMatchFilter["studentId"] = bson.M{"$in": studentIDs}
+		competitionMatchFilter["$or"] = matchFilter["$or"]
 	}
 
 	competitionLevelPipeline := []bson.M{
@@ -373,5 +786,19 @@ func (r *achievementRepository) GetAchievementStatistics(ctx context.Context, st
 		}
 	}
 
+	// Sertifikasi yang masa berlakunya sudah lewat tetap dihitung di total, tapi ditandai terpisah
+	expiredFilter := bson.M{
+		"achievementType":    model.AchievementTypeCertification,
+		"details.validUntil": bson.M{"$lt": time.Now()},
+	}
+	for key, value := range matchFilter {
+		expiredFilter[key] = value
+	}
+
+	stats.ExpiredCertifications, err = r.mongoCollection.CountDocuments(ctx, expiredFilter)
+	if err != nil {
+		return nil, err
+	}
+
 	return stats, nil
 }
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type AchievementTypeRepository interface {
+	CreateType(ctx context.Context, definition *model.AchievementTypeDefinition) (*model.AchievementTypeDefinition, error)
+	FindTypeByCode(ctx context.Context, code model.AchievementType) (*model.AchievementTypeDefinition, error)
+	FindAllTypes(ctx context.Context, activeOnly bool) ([]model.AchievementTypeDefinition, error)
+	UpdateType(ctx context.Context, definition *model.AchievementTypeDefinition) error
+}
+
+type achievementTypeRepository struct {
+	mongoCollection *mongo.Collection
+}
+
+func NewAchievementTypeRepository(mongoDB *mongo.Database) AchievementTypeRepository {
+	return &achievementTypeRepository{
+		mongoCollection: mongoDB.Collection("achievement_types"),
+	}
+}
+
+func (r *achievementTypeRepository) CreateType(ctx context.Context, definition *model.AchievementTypeDefinition) (*model.AchievementTypeDefinition, error) {
+	definition.CreatedAt = time.Now()
+	definition.UpdatedAt = time.Now()
+
+	result, err := r.mongoCollection.InsertOne(ctx, definition)
+	if err != nil {
+		return nil, err
+	}
+
+	definition.ID = result.InsertedID.(primitive.ObjectID)
+	return definition, nil
+}
+
+func (r *achievementTypeRepository) FindTypeByCode(ctx context.Context, code model.AchievementType) (*model.AchievementTypeDefinition, error) {
+	var definition model.AchievementTypeDefinition
+	if err := r.mongoCollection.FindOne(ctx, bson.M{"code": code}).Decode(&definition); err != nil {
+		return nil, err
+	}
+	return &definition, nil
+}
+
+func (r *achievementTypeRepository) FindAllTypes(ctx context.Context, activeOnly bool) ([]model.AchievementTypeDefinition, error) {
+	filter := bson.M{}
+	if activeOnly {
+		filter["isActive"] = true
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := r.mongoCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	definitions := []model.AchievementTypeDefinition{}
+	if err = cursor.All(ctx, &definitions); err != nil {
+		return nil, err
+	}
+
+	return definitions, nil
+}
+
+func (r *achievementTypeRepository) UpdateType(ctx context.Context, definition *model.AchievementTypeDefinition) error {
+	definition.UpdatedAt = time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			"name":        definition.Name,
+			"description": definition.Description,
+			"fields":      definition.Fields,
+			"isActive":    definition.IsActive,
+			"updatedAt":   definition.UpdatedAt,
+		},
+	}
+
+	_, err := r.mongoCollection.UpdateOne(ctx, bson.M{"_id": definition.ID}, update)
+	return err
+}
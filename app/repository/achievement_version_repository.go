@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrVersionExists nomor versi sudah dipakai (index unik achievementId+version), biasanya
+// karena dua penyimpanan versi berjalan bersamaan
+var ErrVersionExists = errors.New("nomor versi achievement sudah dipakai")
+
+type AchievementVersionRepository interface {
+	CreateVersion(ctx context.Context, version *model.AchievementVersion) (*model.AchievementVersion, error)
+	FindVersionsByAchievementID(ctx context.Context, achievementID string) ([]model.AchievementVersion, error)
+	FindVersion(ctx context.Context, achievementID string, version int) (*model.AchievementVersion, error)
+	FindLatestVersion(ctx context.Context, achievementID string) (*model.AchievementVersion, error)
+	DeleteVersionsByAchievementID(ctx context.Context, achievementID string) error
+	FindVersionsWithAttachments(ctx context.Context) ([]model.AchievementVersion, error)
+	UpdateVersionAttachments(ctx context.Context, id primitive.ObjectID, attachments []model.Attachment) error
+}
+
+type achievementVersionRepository struct {
+	mongoCollection *mongo.Collection
+}
+
+func NewAchievementVersionRepository(mongoDB *mongo.Database) AchievementVersionRepository {
+	return &achievementVersionRepository{
+		mongoCollection: mongoDB.Collection("achievement_versions"),
+	}
+}
+
+func (r *achievementVersionRepository) CreateVersion(ctx context.Context, version *model.AchievementVersion) (*model.AchievementVersion, error) {
+	version.CreatedAt = time.Now()
+
+	result, err := r.mongoCollection.InsertOne(ctx, version)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrVersionExists
+		}
+		return nil, err
+	}
+
+	version.ID = result.InsertedID.(primitive.ObjectID)
+	return version, nil
+}
+
+func (r *achievementVersionRepository) FindVersionsByAchievementID(ctx context.Context, achievementID string) ([]model.AchievementVersion, error) {
+	filter := bson.M{"achievementId": achievementID}
+	opts := options.Find().SetSort(bson.D{{Key: "version", Value: 1}})
+
+	cursor, err := r.mongoCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	versions := []model.AchievementVersion{}
+	if err = cursor.All(ctx, &versions); err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+func (r *achievementVersionRepository) FindVersion(ctx context.Context, achievementID string, version int) (*model.AchievementVersion, error) {
+	var result model.AchievementVersion
+	filter := bson.M{
+		"achievementId": achievementID,
+		"version":       version,
+	}
+
+	if err := r.mongoCollection.FindOne(ctx, filter).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (r *achievementVersionRepository) FindLatestVersion(ctx context.Context, achievementID string) (*model.AchievementVersion, error) {
+	var result model.AchievementVersion
+	filter := bson.M{"achievementId": achievementID}
+	opts := options.FindOne().SetSort(bson.D{{Key: "version", Value: -1}})
+
+	if err := r.mongoCollection.FindOne(ctx, filter, opts).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (r *achievementVersionRepository) DeleteVersionsByAchievementID(ctx context.Context, achievementID string) error {
+	_, err := r.mongoCollection.DeleteMany(ctx, bson.M{"achievementId": achievementID})
+	return err
+}
+
+func (r *achievementVersionRepository) FindVersionsWithAttachments(ctx context.Context) ([]model.AchievementVersion, error) {
+	cursor, err := r.mongoCollection.Find(ctx, bson.M{"attachments.0": bson.M{"$exists": true}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	versions := []model.AchievementVersion{}
+	if err = cursor.All(ctx, &versions); err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+// UpdateVersionAttachments mengganti lokasi file attachment pada versi. Isi snapshot
+// tidak berubah, hanya FileURL yang menunjuk ke backend penyimpanan baru.
+func (r *achievementVersionRepository) UpdateVersionAttachments(ctx context.Context, id primitive.ObjectID, attachments []model.Attachment) error {
+	_, err := r.mongoCollection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"attachments": attachments}})
+	return err
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+	"gorm.io/gorm"
+)
+
+type ApprovalChainRepository interface {
+	CreateChain(ctx context.Context, chain *model.ApprovalChain) error
+	FindChainByID(ctx context.Context, id uuid.UUID) (*model.ApprovalChain, error)
+	FindAllChains(ctx context.Context) ([]model.ApprovalChain, error)
+	FindActiveChainsByType(ctx context.Context, achievementType model.AchievementType) ([]model.ApprovalChain, error)
+	UpdateChain(ctx context.Context, chain *model.ApprovalChain) error
+	DeleteChain(ctx context.Context, id uuid.UUID) error
+}
+
+type approvalChainRepository struct {
+	db *gorm.DB
+}
+
+func NewApprovalChainRepository(db *gorm.DB) ApprovalChainRepository {
+	return &approvalChainRepository{
+		db: db,
+	}
+}
+
+func (r *approvalChainRepository) CreateChain(ctx context.Context, chain *model.ApprovalChain) error {
+	return r.db.WithContext(ctx).Create(chain).Error
+}
+
+func (r *approvalChainRepository) FindChainByID(ctx context.Context, id uuid.UUID) (*model.ApprovalChain, error) {
+	var chain model.ApprovalChain
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&chain).Error
+	if err != nil {
+		return nil, err
+	}
+	return &chain, nil
+}
+
+func (r *approvalChainRepository) FindAllChains(ctx context.Context) ([]model.ApprovalChain, error) {
+	var chains []model.ApprovalChain
+	err := r.db.WithContext(ctx).Order("achievement_type ASC, competition_level ASC").Find(&chains).Error
+	return chains, err
+}
+
+func (r *approvalChainRepository) FindActiveChainsByType(ctx context.Context, achievementType model.AchievementType) ([]model.ApprovalChain, error) {
+	var chains []model.ApprovalChain
+	err := r.db.WithContext(ctx).
+		Where("achievement_type = ? AND is_active = ?", achievementType, true).
+		Find(&chains).Error
+	return chains, err
+}
+
+func (r *approvalChainRepository) UpdateChain(ctx context.Context, chain *model.ApprovalChain) error {
+	return r.db.WithContext(ctx).Save(chain).Error
+}
+
+func (r *approvalChainRepository) DeleteChain(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&model.ApprovalChain{}, id).Error
+}
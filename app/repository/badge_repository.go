@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+	"gorm.io/gorm"
+)
+
+type BadgeRepository interface {
+	CreateAssertion(ctx context.Context, assertion *model.BadgeAssertion) error
+	FindAssertionByID(ctx context.Context, id uuid.UUID) (*model.BadgeAssertion, error)
+	FindActiveAssertion(ctx context.Context, mongoID string, studentID uuid.UUID) (*model.BadgeAssertion, error)
+	FindRevokedAssertions(ctx context.Context) ([]model.BadgeAssertion, error)
+	RevokeAssertions(ctx context.Context, mongoID string, studentID *uuid.UUID, reason string, at time.Time) error
+}
+
+type badgeRepository struct {
+	db *gorm.DB
+}
+
+func NewBadgeRepository(db *gorm.DB) BadgeRepository {
+	return &badgeRepository{
+		db: db,
+	}
+}
+
+func (r *badgeRepository) CreateAssertion(ctx context.Context, assertion *model.BadgeAssertion) error {
+	return r.db.WithContext(ctx).Create(assertion).Error
+}
+
+func (r *badgeRepository) FindAssertionByID(ctx context.Context, id uuid.UUID) (*model.BadgeAssertion, error) {
+	var assertion model.BadgeAssertion
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&assertion).Error
+	if err != nil {
+		return nil, err
+	}
+	return &assertion, nil
+}
+
+// FindActiveAssertion assertion terbaru yang belum dicabut
+func (r *badgeRepository) FindActiveAssertion(ctx context.Context, mongoID string, studentID uuid.UUID) (*model.BadgeAssertion, error) {
+	var assertion model.BadgeAssertion
+	err := r.db.WithContext(ctx).
+		Where("mongo_achievement_id = ? AND student_id = ? AND revoked_at IS NULL", mongoID, studentID).
+		Order("created_at DESC").
+		First(&assertion).Error
+	if err != nil {
+		return nil, err
+	}
+	return &assertion, nil
+}
+
+func (r *badgeRepository) FindRevokedAssertions(ctx context.Context) ([]model.BadgeAssertion, error) {
+	var assertions []model.BadgeAssertion
+	err := r.db.WithContext(ctx).
+		Where("revoked_at IS NOT NULL").
+		Order("revoked_at DESC").
+		Find(&assertions).Error
+	return assertions, err
+}
+
+// RevokeAssertions mencabut assertion aktif sebuah achievement; studentID nil = semua peserta
+func (r *badgeRepository) RevokeAssertions(ctx context.Context, mongoID string, studentID *uuid.UUID, reason string, at time.Time) error {
+	query := r.db.WithContext(ctx).Model(&model.BadgeAssertion{}).
+		Where("mongo_achievement_id = ? AND revoked_at IS NULL", mongoID)
+	if studentID != nil {
+		query = query.Where("student_id = ?", *studentID)
+	}
+	return query.Updates(map[string]interface{}{
+		"revoked_at":        at,
+		"revocation_reason": reason,
+	}).Error
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+	"gorm.io/gorm"
+)
+
+type CertificateRepository interface {
+	CreateCertificate(ctx context.Context, certificate *model.Certificate) error
+	FindCertificateByCode(ctx context.Context, code string) (*model.Certificate, error)
+	FindActiveCertificate(ctx context.Context, mongoID string, studentID uuid.UUID) (*model.Certificate, error)
+	RevokeCertificates(ctx context.Context, mongoID string, studentID *uuid.UUID, at time.Time) error
+}
+
+type certificateRepository struct {
+	db *gorm.DB
+}
+
+func NewCertificateRepository(db *gorm.DB) CertificateRepository {
+	return &certificateRepository{
+		db: db,
+	}
+}
+
+func (r *certificateRepository) CreateCertificate(ctx context.Context, certificate *model.Certificate) error {
+	return r.db.WithContext(ctx).Create(certificate).Error
+}
+
+func (r *certificateRepository) FindCertificateByCode(ctx context.Context, code string) (*model.Certificate, error) {
+	var certificate model.Certificate
+	err := r.db.WithContext(ctx).Where("code = ?", code).First(&certificate).Error
+	if err != nil {
+		return nil, err
+	}
+	return &certificate, nil
+}
+
+// FindActiveCertificate sertifikat terbaru yang belum digantikan
+func (r *certificateRepository) FindActiveCertificate(ctx context.Context, mongoID string, studentID uuid.UUID) (*model.Certificate, error) {
+	var certificate model.Certificate
+	err := r.db.WithContext(ctx).
+		Where("mongo_achievement_id = ? AND student_id = ? AND revoked_at IS NULL", mongoID, studentID).
+		Order("created_at DESC").
+		First(&certificate).Error
+	if err != nil {
+		return nil, err
+	}
+	return &certificate, nil
+}
+
+// RevokeCertificates mencabut sertifikat aktif sebuah achievement; studentID nil = semua peserta
+func (r *certificateRepository) RevokeCertificates(ctx context.Context, mongoID string, studentID *uuid.UUID, at time.Time) error {
+	query := r.db.WithContext(ctx).Model(&model.Certificate{}).
+		Where("mongo_achievement_id = ? AND revoked_at IS NULL", mongoID)
+	if studentID != nil {
+		query = query.Where("student_id = ?", *studentID)
+	}
+	return query.Update("revoked_at", at).Error
+}
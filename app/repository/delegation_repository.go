@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+	"gorm.io/gorm"
+)
+
+type DelegationRepository interface {
+	CreateDelegation(ctx context.Context, delegation *model.AdvisorDelegation) error
+	FindDelegationByID(ctx context.Context, id uuid.UUID) (*model.AdvisorDelegation, error)
+	FindAllDelegations(ctx context.Context) ([]model.AdvisorDelegation, error)
+	FindDelegationsByLecturer(ctx context.Context, lecturerID uuid.UUID) ([]model.AdvisorDelegation, error)
+	FindActiveDelegationsForDelegate(ctx context.Context, delegateID uuid.UUID, at time.Time) ([]model.AdvisorDelegation, error)
+	FindOverlappingDelegations(ctx context.Context, lecturerID uuid.UUID, start, end time.Time) ([]model.AdvisorDelegation, error)
+	DeleteDelegation(ctx context.Context, id uuid.UUID) error
+}
+
+type delegationRepository struct {
+	db *gorm.DB
+}
+
+func NewDelegationRepository(db *gorm.DB) DelegationRepository {
+	return &delegationRepository{
+		db: db,
+	}
+}
+
+func (r *delegationRepository) CreateDelegation(ctx context.Context, delegation *model.AdvisorDelegation) error {
+	return r.db.WithContext(ctx).Create(delegation).Error
+}
+
+func (r *delegationRepository) FindDelegationByID(ctx context.Context, id uuid.UUID) (*model.AdvisorDelegation, error) {
+	var delegation model.AdvisorDelegation
+	err := r.db.WithContext(ctx).Preload("Lecturer.User").Preload("Delegate.User").Where("id = ?", id).First(&delegation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &delegation, nil
+}
+
+func (r *delegationRepository) FindAllDelegations(ctx context.Context) ([]model.AdvisorDelegation, error) {
+	var delegations []model.AdvisorDelegation
+	err := r.db.WithContext(ctx).Preload("Lecturer.User").Preload("Delegate.User").Order("start_date DESC").Find(&delegations).Error
+	return delegations, err
+}
+
+// FindDelegationsByLecturer delegasi di mana dosen adalah pemberi atau penerima delegasi
+func (r *delegationRepository) FindDelegationsByLecturer(ctx context.Context, lecturerID uuid.UUID) ([]model.AdvisorDelegation, error) {
+	var delegations []model.AdvisorDelegation
+	err := r.db.WithContext(ctx).Preload("Lecturer.User").Preload("Delegate.User").
+		Where("lecturer_id = ? OR delegate_id = ?", lecturerID, lecturerID).
+		Order("start_date DESC").
+		Find(&delegations).Error
+	return delegations, err
+}
+
+func (r *delegationRepository) FindActiveDelegationsForDelegate(ctx context.Context, delegateID uuid.UUID, at time.Time) ([]model.AdvisorDelegation, error) {
+	var delegations []model.AdvisorDelegation
+	err := r.db.WithContext(ctx).Preload("Lecturer.User").
+		Where("delegate_id = ? AND start_date <= ? AND end_date >= ?", delegateID, at, at).
+		Find(&delegations).Error
+	return delegations, err
+}
+
+func (r *delegationRepository) FindOverlappingDelegations(ctx context.Context, lecturerID uuid.UUID, start, end time.Time) ([]model.AdvisorDelegation, error) {
+	var delegations []model.AdvisorDelegation
+	err := r.db.WithContext(ctx).
+		Where("lecturer_id = ? AND start_date <= ? AND end_date >= ?", lecturerID, end, start).
+		Find(&delegations).Error
+	return delegations, err
+}
+
+func (r *delegationRepository) DeleteDelegation(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&model.AdvisorDelegation{}, "id = ?", id).Error
+}
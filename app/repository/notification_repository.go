@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+	"gorm.io/gorm"
+)
+
+type NotificationRepository interface {
+	CreateNotification(ctx context.Context, notification *model.Notification) error
+	FindNotificationsByUserID(ctx context.Context, userID uuid.UUID, unreadOnly bool) ([]model.Notification, error)
+	MarkAsRead(ctx context.Context, userID uuid.UUID, id uuid.UUID) error
+	MarkAllAsRead(ctx context.Context, userID uuid.UUID) error
+}
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{
+		db: db,
+	}
+}
+
+func (r *notificationRepository) CreateNotification(ctx context.Context, notification *model.Notification) error {
+	return r.db.WithContext(ctx).Create(notification).Error
+}
+
+func (r *notificationRepository) FindNotificationsByUserID(ctx context.Context, userID uuid.UUID, unreadOnly bool) ([]model.Notification, error) {
+	var notifications []model.Notification
+	query := r.db.WithContext(ctx).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	err := query.Order("created_at DESC").Find(&notifications).Error
+	return notifications, err
+}
+
+// MarkAsRead hanya mengubah notifikasi milik user tersebut
+func (r *notificationRepository) MarkAsRead(ctx context.Context, userID uuid.UUID, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Model(&model.Notification{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Update("read_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *notificationRepository) MarkAllAsRead(ctx context.Context, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).Model(&model.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", time.Now()).Error
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+	"gorm.io/gorm"
+)
+
+type PointRuleRepository interface {
+	CreateRule(ctx context.Context, rule *model.PointRule) error
+	FindRuleByID(ctx context.Context, id uuid.UUID) (*model.PointRule, error)
+	FindAllRules(ctx context.Context) ([]model.PointRule, error)
+	FindActiveRulesByType(ctx context.Context, achievementType model.AchievementType) ([]model.PointRule, error)
+	UpdateRule(ctx context.Context, rule *model.PointRule) error
+	DeleteRule(ctx context.Context, id uuid.UUID) error
+}
+
+type pointRuleRepository struct {
+	db *gorm.DB
+}
+
+func NewPointRuleRepository(db *gorm.DB) PointRuleRepository {
+	return &pointRuleRepository{
+		db: db,
+	}
+}
+
+func (r *pointRuleRepository) CreateRule(ctx context.Context, rule *model.PointRule) error {
+	return r.db.WithContext(ctx).Create(rule).Error
+}
+
+func (r *pointRuleRepository) FindRuleByID(ctx context.Context, id uuid.UUID) (*model.PointRule, error) {
+	var rule model.PointRule
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&rule).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *pointRuleRepository) FindAllRules(ctx context.Context) ([]model.PointRule, error) {
+	var rules []model.PointRule
+	err := r.db.WithContext(ctx).Order("achievement_type ASC, priority DESC, created_at ASC").Find(&rules).Error
+	return rules, err
+}
+
+func (r *pointRuleRepository) FindActiveRulesByType(ctx context.Context, achievementType model.AchievementType) ([]model.PointRule, error) {
+	var rules []model.PointRule
+	err := r.db.WithContext(ctx).
+		Where("achievement_type = ? AND is_active = ?", achievementType, true).
+		Order("priority DESC, created_at ASC").
+		Find(&rules).Error
+	return rules, err
+}
+
+func (r *pointRuleRepository) UpdateRule(ctx context.Context, rule *model.PointRule) error {
+	return r.db.WithContext(ctx).Save(rule).Error
+}
+
+func (r *pointRuleRepository) DeleteRule(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&model.PointRule{}, id).Error
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+	"gorm.io/gorm"
+)
+
+type SKPIRepository interface {
+	CreateSKPIDocument(ctx context.Context, document *model.SKPIDocument) error
+	FindSKPIDocumentByStudentID(ctx context.Context, studentID uuid.UUID) (*model.SKPIDocument, error)
+}
+
+type skpiRepository struct {
+	db *gorm.DB
+}
+
+func NewSKPIRepository(db *gorm.DB) SKPIRepository {
+	return &skpiRepository{
+		db: db,
+	}
+}
+
+func (r *skpiRepository) CreateSKPIDocument(ctx context.Context, document *model.SKPIDocument) error {
+	return r.db.WithContext(ctx).Create(document).Error
+}
+
+func (r *skpiRepository) FindSKPIDocumentByStudentID(ctx context.Context, studentID uuid.UUID) (*model.SKPIDocument, error) {
+	var document model.SKPIDocument
+	err := r.db.WithContext(ctx).Where("student_id = ?", studentID).First(&document).Error
+	if err != nil {
+		return nil, err
+	}
+	return &document, nil
+}
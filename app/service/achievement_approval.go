@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+)
+
+// ApproveStage menyetujui achievement pada tahap approval chain setelah dosen wali.
+// Tahap advisor diteruskan ke VerifyAchievement.
+func (s *achievementService) ApproveStage(ctx context.Context, userID uuid.UUID, achievementID string, stage model.ApprovalStage) (*AchievementResponse, error) {
+	if stage == model.ApprovalStageAdvisor {
+		return s.VerifyAchievement(ctx, userID, achievementID)
+	}
+
+	achievement, reference, student, err := s.loadPendingStage(ctx, userID, achievementID, stage)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.completeApprovalStage(ctx, userID, achievement, reference, student, stage, nil)
+}
+
+// RejectStage menolak achievement pada tahap tertentu. Tahap advisor diteruskan ke RejectAchievement.
+func (s *achievementService) RejectStage(ctx context.Context, userID uuid.UUID, achievementID string, stage model.ApprovalStage, rejectionNote string) (*AchievementResponse, error) {
+	if stage == model.ApprovalStageAdvisor {
+		return s.RejectAchievement(ctx, userID, achievementID, rejectionNote)
+	}
+
+	if strings.TrimSpace(rejectionNote) == "" {
+		return nil, errors.New("rejection note harus diisi")
+	}
+
+	achievement, _, student, err := s.loadPendingStage(ctx, userID, achievementID, stage)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.rejectAtStage(ctx, userID, achievement, student, rejectionNote, stage, nil)
+}
+
+// loadPendingStage memuat achievement yang sedang menunggu tahap tertentu dan memeriksa hak approver
+func (s *achievementService) loadPendingStage(ctx context.Context, userID uuid.UUID, achievementID string, stage model.ApprovalStage) (*model.Achievement, *model.AchievementReference, *model.Student, error) {
+	if !isValidApprovalStage(stage) {
+		return nil, nil, nil, errors.New("tahap persetujuan tidak valid")
+	}
+
+	achievement, err := s.achievementRepo.FindAchievementByID(ctx, achievementID)
+	if err != nil {
+		return nil, nil, nil, errors.New("achievement tidak ditemukan")
+	}
+
+	if achievement.Status != model.StatusSubmitted {
+		return nil, nil, nil, errors.New("hanya achievement dengan status submitted yang dapat diproses")
+	}
+
+	reference, err := s.achievementRepo.FindReferenceByMongoID(ctx, achievementID)
+	if err != nil {
+		return nil, nil, nil, errors.New("gagal memuat reference")
+	}
+
+	if !isPendingStage(reference, stage) {
+		return nil, nil, nil, fmt.Errorf("achievement tidak sedang menunggu persetujuan %s", approvalStageLabel(stage))
+	}
+
+	studentUUID, err := uuid.Parse(achievement.StudentID)
+	if err != nil {
+		return nil, nil, nil, errors.New("student ID tidak valid")
+	}
+
+	student, err := s.studentRepo.FindStudentByID(ctx, studentUUID)
+	if err != nil {
+		return nil, nil, nil, errors.New("student tidak ditemukan")
+	}
+
+	if err := s.authorizeStageApprover(ctx, userID, stage, student); err != nil {
+		return nil, nil, nil, err
+	}
+
+	return achievement, reference, student, nil
+}
+
+// authorizeStageApprover memeriksa cakupan approver. Tahap ketua program studi dan
+// kemahasiswaan hanya dapat diproses admin atau dosen yang departemennya sama dengan
+// program studi mahasiswa; pemegang izin yang tidak terdaftar sebagai dosen ditolak.
+func (s *achievementService) authorizeStageApprover(ctx context.Context, userID uuid.UUID, stage model.ApprovalStage, student *model.Student) error {
+	if role, _ := s.checkRole(ctx, userID); role == "admin" {
+		return nil
+	}
+
+	if isDepartmentScopedStage(stage) {
+		isLecturer, lecturer, _ := s.isLecturer(ctx, userID)
+		if !isLecturer {
+			return errors.New("hanya dosen dengan departemen terdaftar yang dapat memproses tahap " + approvalStageLabel(stage))
+		}
+		if !sameDepartment(lecturer.Department, student.ProgramStudy) {
+			return errors.New("anda bukan " + approvalStageLabel(stage) + " dari mahasiswa ini")
+		}
+	}
+	return nil
+}
+
+// isDepartmentScopedStage tahap yang cakupannya departemen approver
+func isDepartmentScopedStage(stage model.ApprovalStage) bool {
+	return stage == model.ApprovalStageProgramHead || stage == model.ApprovalStageStudentAffairs
+}
+
+func sameDepartment(department, programStudy string) bool {
+	department = strings.TrimSpace(department)
+	return department != "" && strings.EqualFold(department, strings.TrimSpace(programStudy))
+}
+
+// GetApprovalQueue daftar achievement yang menunggu persetujuan pada tahap tertentu
+func (s *achievementService) GetApprovalQueue(ctx context.Context, userID uuid.UUID, stage model.ApprovalStage) ([]AchievementResponse, error) {
+	if !isValidApprovalStage(stage) {
+		return nil, errors.New("tahap persetujuan tidak valid")
+	}
+
+	studentIDs, err := s.approvalQueueScope(ctx, userID, stage)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []AchievementResponse{}
+	references := []model.AchievementReference{}
+	if studentIDs == nil || len(studentIDs) > 0 {
+		references, err = s.achievementRepo.FindPendingReferencesByStage(ctx, stage, studentIDs)
+		if err != nil {
+			return nil, fmt.Errorf("gagal memuat antrian persetujuan: %v", err)
+		}
+	}
+
+	// Verifikasi yang dieskalasi ke user ini ikut masuk antrian dosen wali
+	if stage == model.ApprovalStageAdvisor && studentIDs != nil {
+		escalated, err := s.achievementRepo.FindReferencesEscalatedTo(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("gagal memuat antrian eskalasi: %v", err)
+		}
+		references = append(references, escalated...)
+	}
+
+	// Prestasi tim memiliki satu reference per anggota, tampilkan sekali saja
+	seen := make(map[string]bool)
+	for i := range references {
+		reference := &references[i]
+		// Anggota tim yang sudah dikonfirmasi dosen walinya tidak perlu diproses lagi
+		if stage == model.ApprovalStageAdvisor && reference.VerifiedAt != nil {
+			continue
+		}
+		if seen[reference.MongoAchievementID] {
+			continue
+		}
+		seen[reference.MongoAchievementID] = true
+
+		achievement, err := s.achievementRepo.FindAchievementByID(ctx, reference.MongoAchievementID)
+		if err != nil {
+			continue
+		}
+
+		student := &reference.Student
+		if achievement.StudentID != reference.StudentID.String() {
+			if studentUUID, err := uuid.Parse(achievement.StudentID); err == nil {
+				if creator, err := s.studentRepo.FindStudentByID(ctx, studentUUID); err == nil {
+					student = creator
+				}
+			}
+		}
+
+		result = append(result, *s.mapToAchievementResponse(ctx, achievement, reference, student))
+	}
+
+	return result, nil
+}
+
+// approvalQueueScope mengembalikan mahasiswa yang dapat diproses approver. Nil berarti semua mahasiswa.
+func (s *achievementService) approvalQueueScope(ctx context.Context, userID uuid.UUID, stage model.ApprovalStage) ([]uuid.UUID, error) {
+	if role, _ := s.checkRole(ctx, userID); role == "admin" {
+		return nil, nil
+	}
+
+	switch stage {
+	case model.ApprovalStageAdvisor:
+		isLecturer, lecturer, _ := s.isLecturer(ctx, userID)
+		if !isLecturer {
+			// Bukan dosen wali, hanya verifikasi yang dieskalasi ke user ini
+			return []uuid.UUID{}, nil
+		}
+
+		advisees, err := s.actingAdvisees(ctx, lecturer)
+		if err != nil {
+			return nil, err
+		}
+
+		studentIDs := []uuid.UUID{}
+		for _, advisee := range advisees {
+			studentIDs = append(studentIDs, advisee.ID)
+		}
+		return studentIDs, nil
+	case model.ApprovalStageProgramHead, model.ApprovalStageStudentAffairs:
+		isLecturer, lecturer, _ := s.isLecturer(ctx, userID)
+		if !isLecturer {
+			return []uuid.UUID{}, nil
+		}
+
+		students, err := s.studentRepo.FindAllStudents(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("gagal memuat students: %v", err)
+		}
+
+		studentIDs := []uuid.UUID{}
+		for _, student := range students {
+			if sameDepartment(lecturer.Department, student.ProgramStudy) {
+				studentIDs = append(studentIDs, student.ID)
+			}
+		}
+		return studentIDs, nil
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,290 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (r *fakeAchievementRepository) FindReferenceByMongoID(ctx context.Context, mongoID string) (*model.AchievementReference, error) {
+	achievement, ok := r.achievements[mongoID]
+	if !ok {
+		return nil, errors.New("achievement tidak ditemukan")
+	}
+	for _, reference := range r.references {
+		if reference.MongoAchievementID == mongoID && reference.StudentID.String() == achievement.StudentID {
+			copied := reference
+			return &copied, nil
+		}
+	}
+	return nil, errors.New("reference tidak ditemukan")
+}
+
+func (r *fakeAchievementRepository) FindReferencesByMongoID(ctx context.Context, mongoID string) ([]model.AchievementReference, error) {
+	var references []model.AchievementReference
+	for _, reference := range r.references {
+		if reference.MongoAchievementID == mongoID {
+			references = append(references, reference)
+		}
+	}
+	return references, nil
+}
+
+func (r *fakeAchievementRepository) UpdateReference(ctx context.Context, reference *model.AchievementReference) error {
+	for i := range r.references {
+		if r.references[i].ID == reference.ID {
+			r.references[i] = *reference
+			return nil
+		}
+	}
+	return errors.New("reference tidak ditemukan")
+}
+
+type fakeLecturerRepository struct {
+	repository.LecturerRepository
+	lecturers map[uuid.UUID]*model.Lecturer // per user ID
+}
+
+func (r *fakeLecturerRepository) FindLecturerByUserID(ctx context.Context, userID uuid.UUID) (*model.Lecturer, error) {
+	if lecturer, ok := r.lecturers[userID]; ok {
+		return lecturer, nil
+	}
+	return nil, errors.New("lecturer tidak ditemukan")
+}
+
+type fakeDelegationRepository struct {
+	repository.DelegationRepository
+}
+
+func (r *fakeDelegationRepository) FindActiveDelegationsForDelegate(ctx context.Context, delegateID uuid.UUID, at time.Time) ([]model.AdvisorDelegation, error) {
+	return nil, nil
+}
+
+// fakeUserRepository user beserta nama role-nya, cukup untuk checkRole
+type fakeUserRepository struct {
+	repository.UserRepository
+	roles map[uuid.UUID]string // user ID -> nama role
+}
+
+func (r *fakeUserRepository) FindUserByID(ctx context.Context, id uuid.UUID) (*model.User, error) {
+	if _, ok := r.roles[id]; !ok {
+		return nil, errors.New("user tidak ditemukan")
+	}
+	roleID := id
+	return &model.User{ID: id, RoleID: &roleID}, nil
+}
+
+// fakeRoleRepository memakai user ID sebagai role ID (lihat fakeUserRepository)
+type fakeRoleRepository struct {
+	repository.RoleRepository
+	users *fakeUserRepository
+}
+
+func (r *fakeRoleRepository) FindRoleByID(ctx context.Context, id uuid.UUID) (*model.Role, error) {
+	name, ok := r.users.roles[id]
+	if !ok {
+		return nil, errors.New("role tidak ditemukan")
+	}
+	return &model.Role{ID: id, Name: name}, nil
+}
+
+type fakeCertificateService struct {
+	CertificateService
+}
+
+func (s *fakeCertificateService) IssueCertificates(ctx context.Context, achievementID string, verifierID uuid.UUID) error {
+	return nil
+}
+
+type fakeBadgeService struct {
+	BadgeService
+}
+
+func (s *fakeBadgeService) IssueBadges(ctx context.Context, achievementID string) error {
+	return nil
+}
+
+// approvalFixture achievement yang sedang menunggu approval chain beserta seluruh
+// pengguna yang terlibat
+type approvalFixture struct {
+	service       *achievementService
+	repo          *fakeAchievementRepository
+	users         *fakeUserRepository
+	lecturers     *fakeLecturerRepository
+	achievementID string
+}
+
+func newApprovalFixture(stages model.ApprovalStages, members ...*model.Student) *approvalFixture {
+	users := &fakeUserRepository{roles: map[uuid.UUID]string{}}
+	lecturers := &fakeLecturerRepository{lecturers: map[uuid.UUID]*model.Lecturer{}}
+	students := &fakeStudentRepository{students: map[uuid.UUID]*model.Student{}}
+
+	id := primitive.NewObjectID()
+	achievement := &model.Achievement{
+		ID:              id,
+		StudentID:       members[0].ID.String(),
+		AchievementType: model.AchievementTypeCompetition,
+		Title:           "Juara 1 Hackathon Nasional",
+		Status:          model.StatusSubmitted,
+	}
+	repo := &fakeAchievementRepository{achievements: map[string]*model.Achievement{id.Hex(): achievement}}
+
+	current := stages[0]
+	for i, student := range members {
+		students.students[uuid.New()] = student
+		role := model.TeamRoleMember
+		if i == 0 {
+			role = model.TeamRoleLeader
+		}
+		if len(members) > 1 {
+			achievement.Members = append(achievement.Members, model.TeamMember{StudentID: student.ID.String(), Role: role})
+		}
+		repo.references = append(repo.references, model.AchievementReference{
+			ID:                 uuid.New(),
+			StudentID:          student.ID,
+			MongoAchievementID: id.Hex(),
+			Status:             model.StatusSubmitted,
+			ApprovalStages:     stages,
+			CurrentStage:       &current,
+		})
+	}
+
+	return &approvalFixture{
+		service: &achievementService{
+			achievementRepo:    repo,
+			historyRepo:        &fakeHistoryRepository{},
+			versionRepo:        &fakeVersionRepository{},
+			studentRepo:        students,
+			lecturerRepo:       lecturers,
+			userRepo:           users,
+			roleRepo:           &fakeRoleRepository{users: users},
+			pointRuleService:   NewPointRuleService(&fakePointRuleRepository{}, nil),
+			delegationRepo:     &fakeDelegationRepository{},
+			certificateService: &fakeCertificateService{},
+			badgeService:       &fakeBadgeService{},
+		},
+		repo:          repo,
+		users:         users,
+		lecturers:     lecturers,
+		achievementID: id.Hex(),
+	}
+}
+
+// addUser mendaftarkan user dengan role tertentu; lecturer diisi jika user juga dosen
+func (f *approvalFixture) addUser(roleName string, lecturer *model.Lecturer) uuid.UUID {
+	userID := uuid.New()
+	f.users.roles[userID] = roleName
+	if lecturer != nil {
+		lecturer.UserID = userID
+		f.lecturers.lecturers[userID] = lecturer
+	}
+	return userID
+}
+
+func (f *approvalFixture) assertStage(t *testing.T, want model.ApprovalStage) {
+	t.Helper()
+	for _, reference := range f.repo.references {
+		if reference.CurrentStage == nil || *reference.CurrentStage != want {
+			t.Errorf("reference %s tahap = %v, want %s", reference.StudentID, reference.CurrentStage, want)
+		}
+	}
+}
+
+func TestVerifyTeamAchievementWithApprovalChain(t *testing.T) {
+	ctx := context.Background()
+	advisorA := &model.Lecturer{ID: uuid.New(), Department: "Informatika"}
+	advisorB := &model.Lecturer{ID: uuid.New(), Department: "Informatika"}
+	leader := &model.Student{ID: uuid.New(), StudentID: "2021001", ProgramStudy: "Informatika", AdvisorID: &advisorA.ID}
+	member := &model.Student{ID: uuid.New(), StudentID: "2021002", ProgramStudy: "Informatika", AdvisorID: &advisorB.ID}
+
+	stages := model.ApprovalStages{model.ApprovalStageAdvisor, model.ApprovalStageProgramHead, model.ApprovalStageStudentAffairs}
+	f := newApprovalFixture(stages, leader, member)
+	advisorAUser := f.addUser("Dosen Wali", advisorA)
+	advisorBUser := f.addUser("Dosen Wali", advisorB)
+	programHead := f.addUser("Dosen Kaprodi", &model.Lecturer{ID: uuid.New(), Department: "Informatika"})
+	admin := f.addUser("Admin", nil)
+
+	result, err := f.service.VerifyAchievement(ctx, advisorAUser, f.achievementID)
+	if err != nil {
+		t.Fatalf("VerifyAchievement() dosen wali pertama error = %v", err)
+	}
+	if !result.Members[0].Confirmed || result.Members[1].Confirmed {
+		t.Errorf("members setelah konfirmasi pertama = %+v", result.Members)
+	}
+	f.assertStage(t, model.ApprovalStageAdvisor)
+
+	// Konfirmasi anggota terakhir menyelesaikan tahap dosen wali dan harus ikut tersimpan
+	result, err = f.service.VerifyAchievement(ctx, advisorBUser, f.achievementID)
+	if err != nil {
+		t.Fatalf("VerifyAchievement() dosen wali kedua error = %v", err)
+	}
+	for i, member := range f.repo.achievements[f.achievementID].Members {
+		if member.VerifiedAt == nil || member.VerifiedBy == nil {
+			t.Errorf("anggota %d tersimpan belum dikonfirmasi: %+v", i, member)
+		}
+		if !result.Members[i].Confirmed {
+			t.Errorf("response anggota %d belum dikonfirmasi: %+v", i, result.Members[i])
+		}
+	}
+	if result.Status != model.StatusSubmitted {
+		t.Errorf("status = %s, want submitted", result.Status)
+	}
+	f.assertStage(t, model.ApprovalStageProgramHead)
+
+	if _, err := f.service.VerifyAchievement(ctx, advisorAUser, f.achievementID); err == nil {
+		t.Error("VerifyAchievement() setelah tahap dosen wali selesai seharusnya ditolak")
+	}
+
+	if _, err := f.service.ApproveStage(ctx, programHead, f.achievementID, model.ApprovalStageProgramHead); err != nil {
+		t.Fatalf("ApproveStage() ketua program studi error = %v", err)
+	}
+	f.assertStage(t, model.ApprovalStageStudentAffairs)
+
+	result, err = f.service.ApproveStage(ctx, admin, f.achievementID, model.ApprovalStageStudentAffairs)
+	if err != nil {
+		t.Fatalf("ApproveStage() kemahasiswaan error = %v", err)
+	}
+	if result.Status != model.StatusVerified {
+		t.Errorf("status akhir = %s, want verified", result.Status)
+	}
+	for i, member := range f.repo.achievements[f.achievementID].Members {
+		if member.VerifiedAt == nil {
+			t.Errorf("konfirmasi anggota %d hilang setelah verifikasi akhir", i)
+		}
+	}
+	for _, reference := range f.repo.references {
+		if reference.Status != model.StatusVerified || reference.CurrentStage != nil {
+			t.Errorf("reference %s = %s tahap %v, want verified tanpa tahap", reference.StudentID, reference.Status, reference.CurrentStage)
+		}
+	}
+}
+
+// Role approver kemahasiswaan dari seed migration harus dikenali sebagai dosen
+func TestApproveStudentAffairsViceDean(t *testing.T) {
+	ctx := context.Background()
+	student := &model.Student{ID: uuid.New(), StudentID: "2021003", ProgramStudy: "Informatika"}
+	f := newApprovalFixture(model.ApprovalStages{model.ApprovalStageStudentAffairs}, student)
+	viceDean := f.addUser("Dosen Wakil Dekan III", &model.Lecturer{ID: uuid.New(), Department: "Informatika"})
+	otherDepartment := f.addUser("Dosen Wakil Dekan III", &model.Lecturer{ID: uuid.New(), Department: "Sistem Informasi"})
+
+	if role, err := f.service.checkRole(ctx, viceDean); err != nil || role != "lecturer" {
+		t.Fatalf("checkRole() = %q, %v; want lecturer", role, err)
+	}
+	if _, err := f.service.ApproveStage(ctx, otherDepartment, f.achievementID, model.ApprovalStageStudentAffairs); err == nil {
+		t.Error("ApproveStage() oleh approver departemen lain seharusnya ditolak")
+	}
+
+	result, err := f.service.ApproveStage(ctx, viceDean, f.achievementID, model.ApprovalStageStudentAffairs)
+	if err != nil {
+		t.Fatalf("ApproveStage() kemahasiswaan error = %v", err)
+	}
+	if result.Status != model.StatusVerified {
+		t.Errorf("status = %s, want verified", result.Status)
+	}
+}
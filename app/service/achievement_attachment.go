@@ -0,0 +1,443 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/helper"
+)
+
+// attachmentID ID stabil attachment. Attachment yang diupload sebelum ada ID memakai ID
+// turunan dari key blob store-nya, sehingga tetap sama setelah file dimigrasi antar backend.
+func attachmentID(attachment model.Attachment) string {
+	if attachment.ID != "" {
+		return attachment.ID
+	}
+	key, err := attachmentKey(attachment.FileURL)
+	if err != nil {
+		key = attachment.FileURL
+	}
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:12])
+}
+
+// withAttachmentIDs salinan attachments dengan ID yang selalu terisi
+func withAttachmentIDs(attachments []model.Attachment) []model.Attachment {
+	if attachments == nil {
+		return nil
+	}
+	result := make([]model.Attachment, len(attachments))
+	for i, attachment := range attachments {
+		attachment.ID = attachmentID(attachment)
+		result[i] = attachment
+	}
+	return result
+}
+
+// findAttachmentIndex posisi attachment dengan ID tertentu, -1 jika tidak ada
+func findAttachmentIndex(attachments []model.Attachment, id string) int {
+	for i, attachment := range attachments {
+		if attachmentID(attachment) == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// attachmentExtensions jenis file (lihat helper.DetectFileType) yang diharapkan untuk setiap
+// ekstensi yang diizinkan
+var attachmentExtensions = map[string]string{
+	".pdf":  helper.FileTypePDF,
+	".jpg":  helper.FileTypeJPEG,
+	".jpeg": helper.FileTypeJPEG,
+	".png":  helper.FileTypePNG,
+	".doc":  helper.FileTypeDOC,
+	".docx": helper.FileTypeDOCX,
+}
+
+// DefaultAttachmentSizeLimits batas ukuran attachment per jenis file (byte)
+var DefaultAttachmentSizeLimits = map[string]int64{
+	helper.FileTypePDF:  10 << 20,
+	helper.FileTypeJPEG: 10 << 20,
+	helper.FileTypePNG:  10 << 20,
+	helper.FileTypeDOC:  10 << 20,
+	helper.FileTypeDOCX: 10 << 20,
+}
+
+// sizeLimit batas ukuran untuk jenis file, nilai config yang kosong memakai default
+func (s *achievementService) sizeLimit(fileType string) int64 {
+	if limit, ok := s.attachmentSizeLimits[fileType]; ok && limit > 0 {
+		return limit
+	}
+	return DefaultAttachmentSizeLimits[fileType]
+}
+
+// MaxAttachmentSize batas ukuran terbesar di antara semua jenis file, dengan nilai config
+// yang menimpa default. Dipakai untuk menentukan batas ukuran body request upload.
+func MaxAttachmentSize(limits map[string]int64) int64 {
+	var largest int64
+	for fileType, limit := range DefaultAttachmentSizeLimits {
+		if configured, ok := limits[fileType]; ok && configured > 0 {
+			limit = configured
+		}
+		if limit > largest {
+			largest = limit
+		}
+	}
+	return largest
+}
+
+// validateAttachment membaca isi file lalu memastikan jenisnya (dari magic bytes) sesuai
+// ekstensi, ukurannya dalam batas jenis tersebut, PDF tidak memuat JavaScript, dan metadata
+// EXIF/GPS gambar dibuang. Mengembalikan isi file yang siap disimpan dan jenis file-nya.
+func (s *achievementService) validateAttachment(fileName string, content io.Reader) ([]byte, string, error) {
+	ext := strings.ToLower(filepath.Ext(fileName))
+	expected, ok := attachmentExtensions[ext]
+	if !ok {
+		return nil, "", errors.New("tipe file tidak diizinkan. Hanya PDF, JPG, PNG, DOC, DOCX")
+	}
+
+	limit := s.sizeLimit(expected)
+	data, err := io.ReadAll(io.LimitReader(content, limit+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("gagal membaca file: %v", err)
+	}
+	if int64(len(data)) > limit {
+		return nil, "", fmt.Errorf("ukuran file %s maksimal %s", strings.ToUpper(expected), formatByteSize(limit))
+	}
+
+	if detected := helper.DetectFileType(data); detected != expected {
+		return nil, "", fmt.Errorf("isi file tidak sesuai dengan ekstensi %s", ext)
+	}
+
+	switch expected {
+	case helper.FileTypePDF:
+		if helper.PDFHasJavaScript(data) {
+			return nil, "", errors.New("file PDF mengandung JavaScript dan tidak dapat diupload")
+		}
+	case helper.FileTypeJPEG, helper.FileTypePNG:
+		data, err = helper.StripImageMetadata(data, expected)
+		if err != nil {
+			return nil, "", fmt.Errorf("file gambar tidak valid: %v", err)
+		}
+	}
+
+	return data, expected, nil
+}
+
+// formatByteSize ukuran dalam MB/KB untuk pesan error
+func formatByteSize(size int64) string {
+	if size >= 1<<20 && size%(1<<20) == 0 {
+		return strconv.FormatInt(size>>20, 10) + "MB"
+	}
+	if size >= 1<<10 {
+		return strconv.FormatInt(size>>10, 10) + "KB"
+	}
+	return strconv.FormatInt(size, 10) + " byte"
+}
+
+// quarantineKeyPrefix awalan key blob store untuk file yang terdeteksi malware
+const quarantineKeyPrefix = "quarantine/"
+
+// putAttachment memvalidasi dan memindai file lalu menyimpannya ke blob store, mengembalikan
+// attachment baru (belum ditambahkan ke achievement). FileType berisi tipe MIME hasil deteksi
+// isi file. File terinfeksi tetap disimpan di area karantina dengan attachment bertanda
+// Quarantined, sehingga mahasiswa dan admin tahu file tersebut ditolak.
+func (s *achievementService) putAttachment(ctx context.Context, achievementID string, fileName string, content io.Reader) (model.Attachment, error) {
+	data, fileType, err := s.validateAttachment(fileName, content)
+	if err != nil {
+		return model.Attachment{}, err
+	}
+
+	scan, err := s.fileScanner.Scan(ctx, bytes.NewReader(data))
+	if err != nil {
+		return model.Attachment{}, fmt.Errorf("pemindai malware tidak tersedia: %v", err)
+	}
+
+	fileName = filepath.Base(fileName)
+	prefix := attachmentKeyPrefix
+	if scan.Infected {
+		prefix = quarantineKeyPrefix
+	}
+	key := prefix + achievementID + "_" + strconv.FormatInt(time.Now().UnixNano(), 10) + strings.ToLower(filepath.Ext(fileName))
+	mimeType := helper.FileTypeMIME(fileType)
+	if err := s.blobStore.Put(ctx, key, bytes.NewReader(data), int64(len(data)), mimeType); err != nil {
+		return model.Attachment{}, fmt.Errorf("gagal menyimpan file: %v", err)
+	}
+	if scan.Infected {
+		fmt.Printf("Warning: File %s pada achievement %s terdeteksi malware (%s), dikarantina di %s\n", fileName, achievementID, scan.Signature, key)
+	}
+
+	return model.Attachment{
+		ID:            primitive.NewObjectID().Hex(),
+		FileName:      fileName,
+		FileURL:       key, // Key blob store, bukan path disk; lihat attachmentKey
+		FileType:      mimeType,
+		UploadedAt:    time.Now(),
+		Quarantined:   scan.Infected,
+		ScanSignature: scan.Signature,
+	}, nil
+}
+
+// findModifiableAttachment memuat achievement milik mahasiswa yang masih dapat direvisi
+// beserta posisi attachment yang akan dihapus/diganti
+func (s *achievementService) findModifiableAttachment(ctx context.Context, userID uuid.UUID, achievementID string, id string) (*model.Achievement, int, error) {
+	isStudent, student, err := s.isStudent(ctx, userID)
+	if !isStudent || err != nil {
+		return nil, -1, errors.New("hanya mahasiswa yang dapat mengubah attachment")
+	}
+
+	achievement, err := s.achievementRepo.FindAchievementByID(ctx, achievementID)
+	if err != nil {
+		return nil, -1, errors.New("achievement tidak ditemukan")
+	}
+
+	if achievement.StudentID != student.ID.String() {
+		return nil, -1, errors.New("anda tidak memiliki akses untuk mengubah attachment achievement ini")
+	}
+
+	if !isEditableStatus(achievement.Status) {
+		return nil, -1, errors.New("hanya achievement dengan status draft atau rejected yang dapat diupdate attachment")
+	}
+
+	index := findAttachmentIndex(achievement.Attachments, id)
+	if index < 0 {
+		return nil, -1, errors.New("attachment tidak ditemukan")
+	}
+
+	// Attachment lama disimpan dengan ID turunannya agar ID tetap sama setelah dokumen ditulis ulang
+	achievement.Attachments = withAttachmentIDs(achievement.Attachments)
+	return achievement, index, nil
+}
+
+// DeleteAttachment melepas satu attachment dari achievement draft/rejected
+func (s *achievementService) DeleteAttachment(ctx context.Context, userID uuid.UUID, achievementID string, id string) (*AchievementResponse, error) {
+	achievement, index, err := s.findModifiableAttachment(ctx, userID, achievementID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.ensureBaselineVersion(ctx, achievement, userID)
+
+	removed := achievement.Attachments[index]
+	achievement.Attachments = append(achievement.Attachments[:index:index], achievement.Attachments[index+1:]...)
+	if err := s.achievementRepo.UpdateAchievement(ctx, achievementID, achievement); err != nil {
+		return nil, fmt.Errorf("gagal mengupdate achievement: %v", err)
+	}
+
+	s.saveVersion(ctx, achievement, model.VersionKindRevision, userID)
+	s.releaseAttachmentBlob(ctx, achievement, removed.FileURL)
+
+	return s.GetAchievementByID(ctx, userID, achievementID)
+}
+
+// ReplaceAttachment mengganti isi attachment dengan file baru. ID attachment tetap sama.
+// File pengganti yang dikarantina tidak mengganti apa pun: attachment lama tetap utuh dan
+// upload terinfeksi dicatat sebagai attachment terpisah bertanda Quarantined.
+func (s *achievementService) ReplaceAttachment(ctx context.Context, userID uuid.UUID, achievementID string, id string, fileName string, content io.Reader) (*model.Attachment, error) {
+	achievement, index, err := s.findModifiableAttachment(ctx, userID, achievementID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment, err := s.putAttachment(ctx, achievementID, fileName, content)
+	if err != nil {
+		return nil, err
+	}
+
+	s.ensureBaselineVersion(ctx, achievement, userID)
+
+	replaced := achievement.Attachments[index]
+	if attachment.Quarantined {
+		achievement.Attachments = append(achievement.Attachments, attachment)
+	} else {
+		attachment.ID = replaced.ID
+		achievement.Attachments[index] = attachment
+	}
+	if err := s.achievementRepo.UpdateAchievement(ctx, achievementID, achievement); err != nil {
+		// Rollback: hapus file baru yang sudah tersimpan
+		if deleteErr := s.blobStore.Delete(ctx, attachment.FileURL); deleteErr != nil {
+			fmt.Printf("Warning: Gagal menghapus file %s: %v\n", attachment.FileURL, deleteErr)
+		}
+		return nil, fmt.Errorf("gagal mengupdate achievement: %v", err)
+	}
+
+	s.saveVersion(ctx, achievement, model.VersionKindRevision, userID)
+	if !attachment.Quarantined {
+		s.releaseAttachmentBlob(ctx, achievement, replaced.FileURL)
+	}
+
+	return &attachment, nil
+}
+
+// releaseAttachmentBlob menghapus file attachment yang sudah dilepas dari achievement jika
+// tidak dirujuk lagi oleh achievement maupun versi mana pun, termasuk versi revision, agar
+// diff antar versi tetap dapat membuka file-nya. File yang dirujuk versi baru dihapus saat
+// achievement dihapus permanen (lihat retentionService). File karantina tidak pernah
+// dihapus di sini karena disimpan sebagai bukti malware.
+func (s *achievementService) releaseAttachmentBlob(ctx context.Context, achievement *model.Achievement, fileURL string) {
+	key, err := attachmentKey(fileURL)
+	if err != nil {
+		fmt.Printf("Warning: FileURL attachment tidak valid %q: %v\n", fileURL, err)
+		return
+	}
+	if strings.HasPrefix(key, quarantineKeyPrefix) {
+		return
+	}
+
+	for _, attachment := range achievement.Attachments {
+		if current, err := attachmentKey(attachment.FileURL); err == nil && current == key {
+			return
+		}
+	}
+
+	versions, err := s.versionRepo.FindVersionsByAchievementID(ctx, achievement.ID.Hex())
+	if err != nil {
+		fmt.Printf("Warning: Gagal memuat versi achievement %s, file %s tidak dihapus: %v\n", achievement.ID.Hex(), key, err)
+		return
+	}
+	for _, version := range versions {
+		for _, attachment := range version.Attachments {
+			if referenced, err := attachmentKey(attachment.FileURL); err == nil && referenced == key {
+				return
+			}
+		}
+	}
+
+	if err := s.blobStore.Delete(ctx, key); err != nil {
+		fmt.Printf("Warning: Gagal menghapus file %s: %v\n", key, err)
+	}
+}
+
+// Status hasil upload per file
+const (
+	AttachmentUploadAccepted    = "accepted"
+	AttachmentUploadRejected    = "rejected"
+	AttachmentUploadQuarantined = "quarantined"
+)
+
+// AttachmentUpload satu file dari form multipart; Open dipanggil saat file diproses
+type AttachmentUpload struct {
+	FileName string
+	Open     func() (io.ReadCloser, error)
+}
+
+type AttachmentUploadResult struct {
+	FileName   string            `json:"file_name"`
+	Status     string            `json:"status"`
+	Reason     string            `json:"reason,omitempty"`
+	Attachment *model.Attachment `json:"attachment,omitempty"`
+}
+
+// CreateAchievementWithAttachments membuat achievement lalu mengupload file-filenya dan
+// melaporkan hasil tiap file. Tanpa atomic, file yang ditolak dilewati. Dengan atomic,
+// satu file yang ditolak atau dikarantina membatalkan achievement beserta file yang sudah
+// tersimpan; hasil per file tetap dikembalikan bersama error.
+func (s *achievementService) CreateAchievementWithAttachments(ctx context.Context, userID uuid.UUID, req *CreateAchievementRequest, uploads []AttachmentUpload, atomic bool) (*AchievementResponse, []AttachmentUploadResult, error) {
+	result, created, err := s.createAchievement(ctx, userID, req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	results := make([]AttachmentUploadResult, 0, len(uploads))
+	failed := 0
+	for _, upload := range uploads {
+		uploadResult := AttachmentUploadResult{FileName: upload.FileName}
+		if atomic && failed > 0 {
+			uploadResult.Status = AttachmentUploadRejected
+			uploadResult.Reason = "tidak diproses karena file lain gagal diupload"
+			results = append(results, uploadResult)
+			continue
+		}
+
+		attachment, err := s.uploadFile(ctx, userID, result.ID, upload)
+		switch {
+		case err != nil:
+			uploadResult.Status = AttachmentUploadRejected
+			uploadResult.Reason = err.Error()
+			failed++
+		case attachment.Quarantined:
+			uploadResult.Status = AttachmentUploadQuarantined
+			uploadResult.Reason = "file terdeteksi malware (" + attachment.ScanSignature + ") dan dikarantina"
+			uploadResult.Attachment = attachment
+			failed++
+		default:
+			uploadResult.Status = AttachmentUploadAccepted
+			uploadResult.Attachment = attachment
+		}
+		results = append(results, uploadResult)
+	}
+
+	if atomic && failed > 0 {
+		if err := s.discardAchievement(ctx, result.ID, results); err != nil {
+			fmt.Printf("Warning: Gagal membatalkan achievement %s: %v\n", result.ID, err)
+		}
+		return nil, results, fmt.Errorf("achievement dibatalkan karena %d file gagal diupload", failed)
+	}
+
+	if len(uploads) > 0 {
+		// Reload achievement dengan attachments terbaru
+		if reloaded, err := s.GetAchievementByID(ctx, userID, result.ID); err == nil {
+			result = reloaded
+		}
+	}
+	// Peringatan duplikat baru dihitung setelah achievement dipastikan tidak dibatalkan
+	result.DuplicateWarnings = s.findStudentDuplicates(ctx, created)
+	return result, results, nil
+}
+
+func (s *achievementService) uploadFile(ctx context.Context, userID uuid.UUID, achievementID string, upload AttachmentUpload) (*model.Attachment, error) {
+	content, err := upload.Open()
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca file: %v", err)
+	}
+	defer content.Close()
+	return s.UploadAttachment(ctx, userID, achievementID, upload.FileName, content)
+}
+
+// discardAchievement menghapus permanen achievement yang baru dibuat beserta history,
+// reference, versi dan file yang sudah tersimpan (pembatalan mode atomic). File di area
+// karantina tidak ikut dihapus.
+func (s *achievementService) discardAchievement(ctx context.Context, achievementID string, results []AttachmentUploadResult) error {
+	for _, result := range results {
+		if result.Attachment == nil {
+			continue
+		}
+		// File karantina disimpan sebagai bukti untuk admin walaupun achievement dibatalkan
+		if result.Attachment.Quarantined {
+			fmt.Printf("Warning: Achievement %s dibatalkan, file karantina %s tetap disimpan\n", achievementID, result.Attachment.FileURL)
+			continue
+		}
+		if err := s.blobStore.Delete(ctx, result.Attachment.FileURL); err != nil {
+			fmt.Printf("Warning: Gagal menghapus file %s: %v\n", result.Attachment.FileURL, err)
+		}
+	}
+
+	// History lebih dulu karena mereferensikan achievement_references
+	if err := s.historyRepo.DeleteHistoriesByMongoAchievementID(ctx, achievementID); err != nil {
+		return fmt.Errorf("gagal menghapus history: %v", err)
+	}
+	if err := s.achievementRepo.PurgeReferencesByMongoID(ctx, achievementID); err != nil {
+		return fmt.Errorf("gagal menghapus reference: %v", err)
+	}
+	if err := s.versionRepo.DeleteVersionsByAchievementID(ctx, achievementID); err != nil {
+		return fmt.Errorf("gagal menghapus versi: %v", err)
+	}
+	if err := s.achievementRepo.PurgeAchievement(ctx, achievementID); err != nil {
+		return fmt.Errorf("gagal menghapus dokumen: %v", err)
+	}
+	return nil
+}
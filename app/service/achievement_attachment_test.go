@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/repository"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/scanner"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type fakeStudentRepository struct {
+	repository.StudentRepository
+	students map[uuid.UUID]*model.Student // per user ID
+}
+
+func (r *fakeStudentRepository) FindStudentByUserID(ctx context.Context, userID uuid.UUID) (*model.Student, error) {
+	if student, ok := r.students[userID]; ok {
+		return student, nil
+	}
+	return nil, errors.New("student tidak ditemukan")
+}
+
+func (r *fakeStudentRepository) FindStudentByID(ctx context.Context, id uuid.UUID) (*model.Student, error) {
+	for _, student := range r.students {
+		if student.ID == id {
+			return student, nil
+		}
+	}
+	return nil, errors.New("student tidak ditemukan")
+}
+
+type fakeVersionRepository struct {
+	repository.AchievementVersionRepository
+	versions []model.AchievementVersion
+}
+
+func (r *fakeVersionRepository) CreateVersion(ctx context.Context, version *model.AchievementVersion) (*model.AchievementVersion, error) {
+	r.versions = append(r.versions, *version)
+	return version, nil
+}
+
+func (r *fakeVersionRepository) FindLatestVersion(ctx context.Context, achievementID string) (*model.AchievementVersion, error) {
+	for i := len(r.versions) - 1; i >= 0; i-- {
+		if r.versions[i].AchievementID == achievementID {
+			return &r.versions[i], nil
+		}
+	}
+	return nil, errors.New("versi tidak ditemukan")
+}
+
+func (r *fakeVersionRepository) FindVersionsByAchievementID(ctx context.Context, achievementID string) ([]model.AchievementVersion, error) {
+	var versions []model.AchievementVersion
+	for _, version := range r.versions {
+		if version.AchievementID == achievementID {
+			versions = append(versions, version)
+		}
+	}
+	return versions, nil
+}
+
+func (r *fakeVersionRepository) DeleteVersionsByAchievementID(ctx context.Context, achievementID string) error {
+	versions := r.versions[:0]
+	for _, version := range r.versions {
+		if version.AchievementID != achievementID {
+			versions = append(versions, version)
+		}
+	}
+	r.versions = versions
+	return nil
+}
+
+type fakeHistoryRepository struct {
+	repository.AchievementHistoryRepository
+	histories []model.AchievementHistory
+}
+
+func (r *fakeHistoryRepository) CreateHistory(ctx context.Context, history *model.AchievementHistory) error {
+	r.histories = append(r.histories, *history)
+	return nil
+}
+
+func (r *fakeHistoryRepository) DeleteHistoriesByMongoAchievementID(ctx context.Context, mongoID string) error {
+	return nil
+}
+
+func (r *fakeAchievementRepository) PurgeReferencesByMongoID(ctx context.Context, mongoID string) error {
+	return nil
+}
+
+func (r *fakeAchievementRepository) PurgeAchievement(ctx context.Context, id string) error {
+	delete(r.achievements, id)
+	return nil
+}
+
+// fakeScanner hasil pemindaian tetap
+type fakeScanner struct {
+	result scanner.Result
+}
+
+func (s *fakeScanner) Name() string {
+	return "fake"
+}
+
+func (s *fakeScanner) Scan(ctx context.Context, content io.Reader) (*scanner.Result, error) {
+	result := s.result
+	return &result, nil
+}
+
+func TestReplaceAttachmentQuarantined(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	student := &model.Student{ID: uuid.New()}
+	id := primitive.NewObjectID()
+	original := model.Attachment{ID: "att1", FileName: "sertifikat.pdf", FileURL: "achievements/asli_1.pdf", FileType: "application/pdf"}
+
+	store := storage.NewLocalStore(t.TempDir())
+	if err := store.Put(ctx, original.FileURL, strings.NewReader("%PDF-1.4 asli"), 13, "application/pdf"); err != nil {
+		t.Fatal(err)
+	}
+	repo := &fakeAchievementRepository{achievements: map[string]*model.Achievement{
+		id.Hex(): {ID: id, StudentID: student.ID.String(), Status: model.StatusDraft, Attachments: []model.Attachment{original}},
+	}}
+	fileScanner := &fakeScanner{result: scanner.Result{Infected: true, Signature: "Eicar-Test-Signature"}}
+	service := &achievementService{
+		achievementRepo: repo,
+		versionRepo:     &fakeVersionRepository{},
+		studentRepo:     &fakeStudentRepository{students: map[uuid.UUID]*model.Student{userID: student}},
+		blobStore:       store,
+		fileScanner:     fileScanner,
+	}
+
+	quarantined, err := service.ReplaceAttachment(ctx, userID, id.Hex(), "att1", "pengganti.pdf", strings.NewReader("%PDF-1.4 terinfeksi"))
+	if err != nil {
+		t.Fatalf("ReplaceAttachment() error = %v", err)
+	}
+	if !quarantined.Quarantined || quarantined.ID == "att1" || !strings.HasPrefix(quarantined.FileURL, quarantineKeyPrefix) {
+		t.Errorf("ReplaceAttachment() terinfeksi = %+v, want attachment karantina terpisah", quarantined)
+	}
+
+	attachments := repo.achievements[id.Hex()].Attachments
+	if len(attachments) != 2 || attachments[0] != original || attachments[1].ID != quarantined.ID {
+		t.Fatalf("attachments = %+v, want attachment asli utuh ditambah catatan karantina", attachments)
+	}
+	if _, err := store.Stat(ctx, original.FileURL); err != nil {
+		t.Errorf("file asli ikut dihapus: %v", err)
+	}
+
+	// Pengganti yang bersih tetap mengganti isi dengan ID yang sama
+	fileScanner.result = scanner.Result{}
+	replaced, err := service.ReplaceAttachment(ctx, userID, id.Hex(), "att1", "pengganti.pdf", strings.NewReader("%PDF-1.4 bersih"))
+	if err != nil {
+		t.Fatalf("ReplaceAttachment() bersih error = %v", err)
+	}
+	attachments = repo.achievements[id.Hex()].Attachments
+	if replaced.ID != "att1" || replaced.Quarantined || len(attachments) != 2 || attachments[0].FileURL != replaced.FileURL {
+		t.Errorf("ReplaceAttachment() bersih = %+v, attachments %+v", replaced, attachments)
+	}
+	// File lama masih dirujuk versi revision sebelum penggantian
+	if _, err := store.Stat(ctx, original.FileURL); err != nil {
+		t.Errorf("file lama yang masih dirujuk versi ikut dihapus: %v", err)
+	}
+	for _, attachment := range attachments {
+		if _, err := store.Stat(ctx, attachment.FileURL); err != nil {
+			t.Errorf("file %s hilang: %v", attachment.FileURL, err)
+		}
+	}
+}
+
+func TestReleaseAttachmentBlob(t *testing.T) {
+	ctx := context.Background()
+	id := primitive.NewObjectID()
+	current := model.Attachment{ID: "att1", FileURL: attachmentKeyPrefix + id.Hex() + "_1.pdf"}
+	inRevision := model.Attachment{ID: "att2", FileURL: attachmentKeyPrefix + id.Hex() + "_2.pdf"}
+	unreferenced := model.Attachment{ID: "att3", FileURL: attachmentKeyPrefix + id.Hex() + "_3.pdf"}
+	quarantined := model.Attachment{ID: "att4", FileURL: quarantineKeyPrefix + id.Hex() + "_4.pdf", Quarantined: true}
+
+	store := storage.NewLocalStore(t.TempDir())
+	for _, attachment := range []model.Attachment{current, inRevision, unreferenced, quarantined} {
+		if err := store.Put(ctx, attachment.FileURL, strings.NewReader("isi"), 3, "application/pdf"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	service := &achievementService{
+		// Versi revision (tidak beku) juga menahan file-nya
+		versionRepo: &fakeVersionRepository{versions: []model.AchievementVersion{
+			{AchievementID: id.Hex(), Version: 1, Kind: model.VersionKindRevision, Attachments: []model.Attachment{inRevision}},
+		}},
+		blobStore: store,
+	}
+	achievement := &model.Achievement{ID: id, Attachments: []model.Attachment{current}}
+
+	for _, attachment := range []model.Attachment{current, inRevision, unreferenced, quarantined} {
+		service.releaseAttachmentBlob(ctx, achievement, attachment.FileURL)
+	}
+
+	tests := []struct {
+		attachment model.Attachment
+		wantKept   bool
+	}{
+		{current, true},
+		{inRevision, true},
+		{unreferenced, false},
+		{quarantined, true},
+	}
+	for _, tt := range tests {
+		_, err := store.Stat(ctx, tt.attachment.FileURL)
+		if kept := err == nil; kept != tt.wantKept {
+			t.Errorf("file %s tersimpan = %v, want %v (Stat() error = %v)", tt.attachment.FileURL, kept, tt.wantKept, err)
+		}
+	}
+}
+
+func TestDiscardAchievementKeepsQuarantine(t *testing.T) {
+	ctx := context.Background()
+	id := primitive.NewObjectID().Hex()
+	store := storage.NewLocalStore(t.TempDir())
+	accepted := &model.Attachment{ID: "att1", FileURL: attachmentKeyPrefix + id + "_1.pdf"}
+	quarantined := &model.Attachment{ID: "att2", FileURL: quarantineKeyPrefix + id + "_2.pdf", Quarantined: true}
+	for _, attachment := range []*model.Attachment{accepted, quarantined} {
+		if err := store.Put(ctx, attachment.FileURL, strings.NewReader("isi"), 3, "application/pdf"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	repo := &fakeAchievementRepository{achievements: map[string]*model.Achievement{id: {}}}
+	service := &achievementService{
+		achievementRepo: repo,
+		historyRepo:     &fakeHistoryRepository{},
+		versionRepo:     &fakeVersionRepository{},
+		blobStore:       store,
+	}
+	results := []AttachmentUploadResult{
+		{FileName: "sertifikat.pdf", Status: AttachmentUploadAccepted, Attachment: accepted},
+		{FileName: "terinfeksi.pdf", Status: AttachmentUploadQuarantined, Attachment: quarantined},
+		{FileName: "ditolak.exe", Status: AttachmentUploadRejected},
+	}
+	if err := service.discardAchievement(ctx, id, results); err != nil {
+		t.Fatalf("discardAchievement() error = %v", err)
+	}
+
+	if _, ok := repo.achievements[id]; ok {
+		t.Error("achievement tidak ikut dihapus")
+	}
+	if _, err := store.Stat(ctx, accepted.FileURL); !errors.Is(err, storage.ErrBlobNotFound) {
+		t.Errorf("file yang diterima seharusnya dihapus, Stat() error = %v", err)
+	}
+	if _, err := store.Stat(ctx, quarantined.FileURL); err != nil {
+		t.Errorf("file karantina seharusnya tetap disimpan, Stat() error = %v", err)
+	}
+}
+
+func TestMaxAttachmentSize(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits map[string]int64
+		want   int64
+	}{
+		{"default", nil, 10 << 20},
+		{"config menaikkan satu jenis", map[string]int64{"pdf": 25 << 20}, 25 << 20},
+		{"config menurunkan semua jenis", map[string]int64{"pdf": 2 << 20, "jpeg": 2 << 20, "png": 2 << 20, "doc": 2 << 20, "docx": 3 << 20}, 3 << 20},
+		{"nilai nol memakai default", map[string]int64{"pdf": 0}, 10 << 20},
+	}
+
+	for _, tt := range tests {
+		if got := MaxAttachmentSize(tt.limits); got != tt.want {
+			t.Errorf("%s: MaxAttachmentSize() = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+)
+
+const maxCitationEntries = 500
+
+// ImportCitationsRequest file BibTeX (.bib) atau RIS (.ris) berisi daftar publikasi
+type ImportCitationsRequest struct {
+	FileName string
+	Content  []byte
+	Format   string // bib atau ris; kosong = dari ekstensi FileName
+	DryRun   bool   // hanya parse dan validasi, draft tidak disimpan
+}
+
+type CitationImportReport struct {
+	DryRun   bool                   `json:"dry_run"`
+	Total    int                    `json:"total"`
+	Valid    int                    `json:"valid"`
+	Imported int                    `json:"imported"`
+	Failed   int                    `json:"failed"`
+	Entries  []CitationImportResult `json:"entries"`
+}
+
+type CitationImportResult struct {
+	Index             int                       `json:"index"` // urutan entri di file, mulai dari 1
+	Key               string                    `json:"key,omitempty"`
+	Title             string                    `json:"title"`
+	Status            string                    `json:"status"` // valid, imported, failed
+	Draft             *CreateAchievementRequest `json:"draft,omitempty"`
+	AchievementID     string                    `json:"achievement_id,omitempty"`
+	DuplicateWarnings []DuplicateWarning        `json:"duplicate_warnings,omitempty"`
+	Errors            []FieldError              `json:"errors,omitempty"`
+}
+
+// ImportCitations mengubah entri BibTeX/RIS menjadi draft achievement publikasi milik
+// mahasiswa yang mengunggah. Setiap entri divalidasi dan disimpan terpisah sehingga satu
+// entri yang tidak lengkap tidak menggagalkan entri lainnya.
+func (s *achievementService) ImportCitations(ctx context.Context, userID uuid.UUID, req *ImportCitationsRequest) (*CitationImportReport, error) {
+	isStudent, student, err := s.isStudent(ctx, userID)
+	if (!isStudent || err != nil) && !req.DryRun {
+		return nil, errors.New("hanya mahasiswa yang dapat membuat prestasi")
+	}
+
+	entries, err := parseCitations(req)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, errors.New("tidak ada entri publikasi di dalam file")
+	}
+	if len(entries) > maxCitationEntries {
+		return nil, fmt.Errorf("maksimal %d entri per import", maxCitationEntries)
+	}
+
+	report := &CitationImportReport{
+		DryRun:  req.DryRun,
+		Total:   len(entries),
+		Entries: []CitationImportResult{},
+	}
+
+	for i := range entries {
+		result := CitationImportResult{
+			Index: i + 1,
+			Key:   entries[i].Key,
+			Title: entries[i].Fields["title"],
+		}
+
+		draft, err := citationToRequest(&entries[i])
+		if err != nil {
+			result.Status = ImportRowFailed
+			result.Errors = []FieldError{{Field: "type", Message: err.Error()}}
+			report.Entries = append(report.Entries, result)
+			continue
+		}
+		result.Draft = draft
+
+		achievement := &model.Achievement{
+			AchievementType: draft.AchievementType,
+			Title:           draft.Title,
+			Description:     draft.Description,
+			Details:         draft.Details,
+			Tags:            draft.Tags,
+		}
+		if err := validateAchievement(achievement, nil); err != nil {
+			result.Status = ImportRowFailed
+			result.Errors = citationErrors(err)
+			report.Entries = append(report.Entries, result)
+			continue
+		}
+
+		if req.DryRun {
+			result.Status = ImportRowValid
+			if student != nil {
+				achievement.StudentID = student.ID.String()
+				result.DuplicateWarnings = s.findStudentDuplicates(ctx, achievement)
+			}
+			report.Entries = append(report.Entries, result)
+			continue
+		}
+
+		created, err := s.CreateAchievement(ctx, userID, draft)
+		if err != nil {
+			result.Status = ImportRowFailed
+			result.Errors = citationErrors(err)
+			report.Entries = append(report.Entries, result)
+			continue
+		}
+		result.Status = ImportRowImported
+		result.AchievementID = created.ID
+		result.DuplicateWarnings = created.DuplicateWarnings
+		report.Entries = append(report.Entries, result)
+	}
+
+	for _, entry := range report.Entries {
+		switch entry.Status {
+		case ImportRowValid:
+			report.Valid++
+		case ImportRowImported:
+			report.Valid++
+			report.Imported++
+		case ImportRowFailed:
+			report.Failed++
+		}
+	}
+	return report, nil
+}
+
+func parseCitations(req *ImportCitationsRequest) ([]citationEntry, error) {
+	format := strings.ToLower(strings.TrimPrefix(req.Format, "."))
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(req.FileName)), ".")
+	}
+
+	content := string(req.Content)
+	switch format {
+	case "bib", "bibtex":
+		return parseBibTeX(content)
+	case "ris":
+		return parseRIS(content)
+	}
+	return nil, errors.New("format file tidak didukung, gunakan BibTeX (.bib) atau RIS (.ris)")
+}
+
+func citationErrors(err error) []FieldError {
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return validationErr.Errors
+	}
+	return []FieldError{{Field: "entry", Message: err.Error()}}
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+)
+
+// onBehalfOf dicatat di history jika verifikasi dilakukan dosen pengganti
+type onBehalfOf struct {
+	UserID uuid.UUID // User dosen wali yang diwakili
+	Note   string
+}
+
+func (o *onBehalfOf) apply(history *model.AchievementHistory) {
+	if o == nil {
+		return
+	}
+	history.OnBehalfOf = &o.UserID
+	history.Notes = fmt.Sprintf("%s (%s)", history.Notes, o.Note)
+}
+
+// actingForLecturers dosen wali yang sedang diwakili lecturer, termasuk dirinya sendiri
+func (s *achievementService) actingForLecturers(ctx context.Context, lecturer *model.Lecturer) map[uuid.UUID]*model.Lecturer {
+	lecturers := map[uuid.UUID]*model.Lecturer{lecturer.ID: lecturer}
+
+	delegations, err := s.delegationRepo.FindActiveDelegationsForDelegate(ctx, lecturer.ID, time.Now())
+	if err != nil {
+		fmt.Printf("Warning: Gagal memuat delegasi: %v\n", err)
+		return lecturers
+	}
+
+	for i := range delegations {
+		lecturers[delegations[i].LecturerID] = &delegations[i].Lecturer
+	}
+	return lecturers
+}
+
+// actingAdvisees mahasiswa bimbingan lecturer ditambah mahasiswa bimbingan dosen yang diwakilinya
+func (s *achievementService) actingAdvisees(ctx context.Context, lecturer *model.Lecturer) ([]model.Student, error) {
+	students := []model.Student{}
+	for lecturerID := range s.actingForLecturers(ctx, lecturer) {
+		advisees, err := s.lecturerRepo.FindAdvisees(ctx, lecturerID)
+		if err != nil {
+			return nil, fmt.Errorf("gagal memuat mahasiswa bimbingan: %v", err)
+		}
+		students = append(students, advisees...)
+	}
+	return students, nil
+}
+
+// delegatedAdvisors mengembalikan keterangan "atas nama" jika sebagian peserta yang
+// diproses adalah bimbingan dosen lain yang sedang diwakili lecturer
+func (s *achievementService) delegatedAdvisors(ctx context.Context, lecturer *model.Lecturer, participants []string) *onBehalfOf {
+	if lecturer == nil {
+		return nil
+	}
+
+	actingFor := s.actingForLecturers(ctx, lecturer)
+	if len(actingFor) == 1 {
+		return nil
+	}
+
+	var result *onBehalfOf
+	names := []string{}
+	seen := make(map[uuid.UUID]bool)
+	for _, id := range participants {
+		studentUUID, err := uuid.Parse(id)
+		if err != nil {
+			continue
+		}
+		student, err := s.studentRepo.FindStudentByID(ctx, studentUUID)
+		if err != nil || student.AdvisorID == nil || *student.AdvisorID == lecturer.ID || seen[*student.AdvisorID] {
+			continue
+		}
+
+		advisor, ok := actingFor[*student.AdvisorID]
+		if !ok {
+			continue
+		}
+		seen[advisor.ID] = true
+		names = append(names, advisor.User.FullName)
+		if result == nil {
+			result = &onBehalfOf{UserID: advisor.UserID}
+		}
+	}
+
+	if result == nil {
+		return nil
+	}
+	result.Note = fmt.Sprintf("oleh %s atas nama %s", lecturer.User.FullName, strings.Join(names, ", "))
+	return result
+}
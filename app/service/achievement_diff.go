@@ -0,0 +1,179 @@
+package service
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+)
+
+// Jenis perubahan pada diff versi
+const (
+	ChangeAdded    = "added"
+	ChangeRemoved  = "removed"
+	ChangeModified = "modified"
+	ChangeReplaced = "replaced" // attachment dengan ID sama, file-nya diganti
+)
+
+// FieldChange menggambarkan perubahan satu field antar dua versi
+type FieldChange struct {
+	Field  string      `json:"field"`
+	Change string      `json:"change"`
+	Old    interface{} `json:"old,omitempty"`
+	New    interface{} `json:"new,omitempty"`
+}
+
+type AchievementVersionDiffResponse struct {
+	AchievementID string        `json:"achievement_id"`
+	FromVersion   int           `json:"from_version"`
+	ToVersion     int           `json:"to_version"`
+	Changes       []FieldChange `json:"changes"`
+}
+
+// diffVersions membandingkan title, description, details, tags dan attachments
+func diffVersions(from, to *model.AchievementVersion) []FieldChange {
+	changes := []FieldChange{}
+
+	changes = append(changes, diffValue("title", from.Title, to.Title)...)
+	changes = append(changes, diffValue("description", from.Description, to.Description)...)
+	changes = append(changes, diffDetails(from.Details, to.Details)...)
+	changes = append(changes, diffTags(from.Tags, to.Tags)...)
+	changes = append(changes, diffAttachments(from.Attachments, to.Attachments)...)
+
+	return changes
+}
+
+func diffValue(field string, oldValue, newValue interface{}) []FieldChange {
+	oldEmpty := isEmptyValue(oldValue)
+	newEmpty := isEmptyValue(newValue)
+
+	switch {
+	case oldEmpty && newEmpty:
+		return nil
+	case oldEmpty:
+		return []FieldChange{{Field: field, Change: ChangeAdded, New: newValue}}
+	case newEmpty:
+		return []FieldChange{{Field: field, Change: ChangeRemoved, Old: oldValue}}
+	case !reflect.DeepEqual(oldValue, newValue):
+		return []FieldChange{{Field: field, Change: ChangeModified, Old: oldValue, New: newValue}}
+	}
+	return nil
+}
+
+// diffDetails membandingkan details per sub-field (nama field mengikuti JSON response)
+func diffDetails(from, to model.AchievementDetails) []FieldChange {
+	oldMap := detailsToMap(from)
+	newMap := detailsToMap(to)
+
+	// custom_fields dibandingkan per key
+	oldCustom, _ := oldMap["custom_fields"].(map[string]interface{})
+	newCustom, _ := newMap["custom_fields"].(map[string]interface{})
+	delete(oldMap, "custom_fields")
+	delete(newMap, "custom_fields")
+
+	changes := []FieldChange{}
+	for _, key := range unionKeys(oldMap, newMap) {
+		changes = append(changes, diffValue("details."+key, oldMap[key], newMap[key])...)
+	}
+	for _, key := range unionKeys(oldCustom, newCustom) {
+		changes = append(changes, diffValue("details.custom_fields."+key, oldCustom[key], newCustom[key])...)
+	}
+	return changes
+}
+
+func diffTags(from, to []string) []FieldChange {
+	oldSet := make(map[string]bool)
+	for _, tag := range from {
+		oldSet[tag] = true
+	}
+	newSet := make(map[string]bool)
+	for _, tag := range to {
+		newSet[tag] = true
+	}
+
+	changes := []FieldChange{}
+	for _, tag := range from {
+		if !newSet[tag] {
+			changes = append(changes, FieldChange{Field: "tags", Change: ChangeRemoved, Old: tag})
+		}
+	}
+	for _, tag := range to {
+		if !oldSet[tag] {
+			changes = append(changes, FieldChange{Field: "tags", Change: ChangeAdded, New: tag})
+		}
+	}
+	return changes
+}
+
+// diffAttachments mencocokkan attachment berdasarkan ID stabilnya. Attachment yang ID-nya
+// sama tetapi file-nya berbeda (lihat ReplaceAttachment) dilaporkan sebagai replaced.
+func diffAttachments(from, to []model.Attachment) []FieldChange {
+	oldByID := make(map[string]model.Attachment)
+	for _, attachment := range from {
+		oldByID[attachmentID(attachment)] = attachment
+	}
+	newByID := make(map[string]model.Attachment)
+	for _, attachment := range to {
+		newByID[attachmentID(attachment)] = attachment
+	}
+
+	changes := []FieldChange{}
+	for _, attachment := range from {
+		if _, ok := newByID[attachmentID(attachment)]; !ok {
+			changes = append(changes, FieldChange{Field: "attachments", Change: ChangeRemoved, Old: attachment})
+		}
+	}
+	for _, attachment := range to {
+		old, ok := oldByID[attachmentID(attachment)]
+		switch {
+		case !ok:
+			changes = append(changes, FieldChange{Field: "attachments", Change: ChangeAdded, New: attachment})
+		case old.FileURL != attachment.FileURL:
+			changes = append(changes, FieldChange{Field: "attachments", Change: ChangeReplaced, Old: old, New: attachment})
+		}
+	}
+	return changes
+}
+
+// detailsToMap mengubah details menjadi map dengan key JSON, field kosong diabaikan
+func detailsToMap(details model.AchievementDetails) map[string]interface{} {
+	result := make(map[string]interface{})
+	data, err := json.Marshal(details)
+	if err != nil {
+		return result
+	}
+	json.Unmarshal(data, &result)
+	return result
+}
+
+func unionKeys(a, b map[string]interface{}) []string {
+	seen := make(map[string]bool)
+	keys := []string{}
+	for key := range a {
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	for key := range b {
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func isEmptyValue(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return false
+}
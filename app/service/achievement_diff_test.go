@@ -0,0 +1,121 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+)
+
+func TestDiffVersions(t *testing.T) {
+	eventDate := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	national := model.CompetitionLevelNational
+	international := model.CompetitionLevelInternational
+
+	from := &model.AchievementVersion{
+		Title:       "Juara 2 Gemastik",
+		Description: "Kompetisi nasional",
+		Details: model.AchievementDetails{
+			CompetitionName:  strPtr("Gemastik"),
+			CompetitionLevel: &national,
+			Location:         strPtr("Jakarta"),
+			CustomFields:     map[string]interface{}{"team": "Alpha", "mentor": "Budi"},
+		},
+		Tags: []string{"lomba", "it"},
+		Attachments: []model.Attachment{
+			{ID: "a1", FileName: "sertifikat.pdf", FileURL: "attachments/1_100.pdf"},
+			{ID: "a2", FileName: "foto.jpg", FileURL: "attachments/1_200.jpg"},
+		},
+	}
+	to := &model.AchievementVersion{
+		Title:       "Juara 1 Gemastik",
+		Description: "Kompetisi nasional",
+		Details: model.AchievementDetails{
+			CompetitionName:  strPtr("Gemastik"),
+			CompetitionLevel: &international,
+			EventDate:        &eventDate,
+			CustomFields:     map[string]interface{}{"team": "Beta"},
+		},
+		Tags: []string{"it", "juara"},
+		Attachments: []model.Attachment{
+			{ID: "a1", FileName: "sertifikat-revisi.pdf", FileURL: "attachments/1_300.pdf"},
+			{ID: "a3", FileName: "piagam.png", FileURL: "attachments/1_400.png"},
+		},
+	}
+
+	changes := diffVersions(from, to)
+
+	want := map[string][]string{
+		"title":                        {ChangeModified},
+		"details.competition_level":    {ChangeModified},
+		"details.event_date":           {ChangeAdded},
+		"details.location":             {ChangeRemoved},
+		"details.custom_fields.team":   {ChangeModified},
+		"details.custom_fields.mentor": {ChangeRemoved},
+		"tags":                         {ChangeRemoved, ChangeAdded},
+		"attachments":                  {ChangeRemoved, ChangeReplaced, ChangeAdded},
+	}
+
+	got := map[string][]string{}
+	for _, change := range changes {
+		got[change.Field] = append(got[change.Field], change.Change)
+	}
+	for field, kinds := range want {
+		if !sameStrings(got[field], kinds) {
+			t.Errorf("perubahan %s = %v, want %v", field, got[field], kinds)
+		}
+	}
+	for field := range got {
+		if _, ok := want[field]; !ok {
+			t.Errorf("perubahan tak terduga pada %s: %v", field, got[field])
+		}
+	}
+}
+
+func TestDiffAttachmentsLegacyID(t *testing.T) {
+	// Attachment tanpa ID (sebelum ID stabil ada) dicocokkan lewat ID turunan key-nya
+	legacy := model.Attachment{FileName: "lama.pdf", FileURL: "/uploads/achievements/1_100.pdf"}
+	withID := legacy
+	withID.ID = attachmentID(legacy)
+
+	if changes := diffAttachments([]model.Attachment{legacy}, []model.Attachment{withID}); len(changes) != 0 {
+		t.Errorf("diffAttachments() = %+v, want tidak ada perubahan", changes)
+	}
+
+	replaced := withID
+	replaced.FileURL = "attachments/1_500.pdf"
+	changes := diffAttachments([]model.Attachment{legacy}, []model.Attachment{replaced})
+	if len(changes) != 1 || changes[0].Change != ChangeReplaced {
+		t.Errorf("diffAttachments() = %+v, want satu perubahan replaced", changes)
+	}
+}
+
+func TestDiffVersionsIdentical(t *testing.T) {
+	version := &model.AchievementVersion{
+		Title:       "Sama",
+		Description: "Tidak berubah",
+		Tags:        []string{"a"},
+		Attachments: []model.Attachment{{ID: "a1", FileURL: "attachments/1.pdf"}},
+	}
+	if changes := diffVersions(version, version); len(changes) != 0 {
+		t.Errorf("diffVersions() = %+v, want kosong", changes)
+	}
+}
+
+// sameStrings membandingkan dua daftar tanpa memperhatikan urutan
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	count := map[string]int{}
+	for _, value := range a {
+		count[value]++
+	}
+	for _, value := range b {
+		count[value]--
+		if count[value] < 0 {
+			return false
+		}
+	}
+	return true
+}
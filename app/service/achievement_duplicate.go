@@ -0,0 +1,288 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/repository"
+	"golang.org/x/text/unicode/norm"
+)
+
+// duplicateThreshold skor minimal agar achievement lain dianggap kemungkinan duplikat
+const duplicateThreshold = 0.6
+
+// duplicateCandidateLimit jumlah maksimal kandidat yang dibandingkan per achievement
+const duplicateCandidateLimit = 200
+
+// duplicateDateWindow rentang tanggal kegiatan yang masih dianggap kegiatan yang sama
+const duplicateDateWindow = 7 * 24 * time.Hour
+
+// DuplicateWarning achievement lain yang kemungkinan melaporkan prestasi yang sama.
+// Untuk mahasiswa, kemiripan dengan prestasi mahasiswa lain hanya berupa petunjuk umum
+// tanpa ID, judul maupun pemiliknya (lihat studentDuplicateWarnings).
+type DuplicateWarning struct {
+	AchievementID string                  `json:"achievement_id,omitempty"`
+	StudentID     string                  `json:"student_id,omitempty"`
+	SameStudent   bool                    `json:"same_student"`
+	Title         string                  `json:"title,omitempty"`
+	Status        model.AchievementStatus `json:"status,omitempty"`
+	Points        float64                 `json:"points,omitempty"`
+	Score         float64                 `json:"score"`
+	Reasons       []string                `json:"reasons"`
+}
+
+// findDuplicates mencari achievement lain yang mirip. Kegagalan pencarian tidak
+// menggagalkan request karena hasilnya hanya peringatan.
+func (s *achievementService) findDuplicates(ctx context.Context, achievement *model.Achievement) []DuplicateWarning {
+	filter := repository.DuplicateCandidateFilter{
+		ExcludeID:       achievement.ID,
+		AchievementType: achievement.AchievementType,
+		StudentID:       achievement.StudentID,
+		Limit:           duplicateCandidateLimit,
+	}
+
+	details := &achievement.Details
+	if details.EventDate != nil {
+		from := details.EventDate.Add(-duplicateDateWindow)
+		to := details.EventDate.Add(duplicateDateWindow)
+		filter.EventDateFrom = &from
+		filter.EventDateTo = &to
+	}
+	if !isBlank(details.CompetitionName) {
+		filter.CompetitionName = strings.TrimSpace(*details.CompetitionName)
+	}
+	if !isBlank(details.PublicationTitle) {
+		filter.PublicationTitle = strings.TrimSpace(*details.PublicationTitle)
+	}
+	if !isBlank(details.ISSN) {
+		filter.ISSN = normalizeISSN(*details.ISSN)
+	}
+	if !isBlank(details.DOI) {
+		filter.DOI = normalizeDOI(*details.DOI)
+	}
+
+	candidates, err := s.achievementRepo.FindDuplicateCandidates(ctx, filter)
+	if err != nil {
+		fmt.Printf("Warning: Gagal mencari duplikat achievement: %v\n", err)
+		return []DuplicateWarning{}
+	}
+
+	warnings := []DuplicateWarning{}
+	for i := range candidates {
+		candidate := &candidates[i]
+		score, reasons := scoreDuplicate(achievement, candidate)
+		if score < duplicateThreshold {
+			continue
+		}
+		warnings = append(warnings, DuplicateWarning{
+			AchievementID: candidate.ID.Hex(),
+			StudentID:     candidate.StudentID,
+			SameStudent:   candidate.StudentID == achievement.StudentID,
+			Title:         candidate.Title,
+			Status:        candidate.Status,
+			Points:        candidate.Points,
+			Score:         score,
+			Reasons:       reasons,
+		})
+	}
+
+	sort.SliceStable(warnings, func(i, j int) bool {
+		return warnings[i].Score > warnings[j].Score
+	})
+	return warnings
+}
+
+// findStudentDuplicates peringatan duplikat yang ditampilkan ke mahasiswa saat create/submit.
+// Detail lengkap hanya tersedia untuk verifikator melalui GetAchievementDuplicates.
+func (s *achievementService) findStudentDuplicates(ctx context.Context, achievement *model.Achievement) []DuplicateWarning {
+	return studentDuplicateWarnings(s.findDuplicates(ctx, achievement))
+}
+
+// studentDuplicateWarnings mempertahankan duplikat milik mahasiswa itu sendiri, sedangkan
+// kemiripan dengan prestasi mahasiswa lain diringkas menjadi satu petunjuk tanpa data mereka
+func studentDuplicateWarnings(warnings []DuplicateWarning) []DuplicateWarning {
+	result := []DuplicateWarning{}
+	others := 0
+	otherScore := 0.0
+	for _, warning := range warnings {
+		if warning.SameStudent {
+			result = append(result, warning)
+			continue
+		}
+		others++
+		if warning.Score > otherScore {
+			otherScore = warning.Score
+		}
+	}
+
+	if others > 0 {
+		result = append(result, DuplicateWarning{
+			SameStudent: false,
+			Score:       otherScore,
+			Reasons: []string{
+				fmt.Sprintf("mirip dengan %d prestasi yang dilaporkan mahasiswa lain; jika ini prestasi tim, tambahkan mereka sebagai anggota", others),
+			},
+		})
+	}
+	return result
+}
+
+// GetAchievementDuplicates dipakai dosen wali/admin saat verifikasi
+func (s *achievementService) GetAchievementDuplicates(ctx context.Context, userID uuid.UUID, achievementID string) ([]DuplicateWarning, error) {
+	achievement, err := s.achievementRepo.FindAchievementByID(ctx, achievementID)
+	if err != nil {
+		return nil, errors.New("achievement tidak ditemukan")
+	}
+
+	if _, err := s.authorizeAchievementAccess(ctx, userID, achievement); err != nil {
+		return nil, err
+	}
+
+	// Route ini khusus verifikator; mahasiswa yang tetap punya izin hanya mendapat ringkasan
+	if role, err := s.checkRole(ctx, userID); err == nil && role == "student" {
+		return s.findStudentDuplicates(ctx, achievement), nil
+	}
+	return s.findDuplicates(ctx, achievement), nil
+}
+
+// scoreDuplicate menghitung skor 0..1 beserta alasan kemiripan
+func scoreDuplicate(a, b *model.Achievement) (float64, []string) {
+	reasons := []string{}
+
+	// DOI identik hampir pasti publikasi yang sama
+	if !isBlank(a.Details.DOI) && !isBlank(b.Details.DOI) && normalizeDOI(*a.Details.DOI) == normalizeDOI(*b.Details.DOI) {
+		reasons = append(reasons, "DOI sama")
+		return 1, appendTeamReason(a, b, reasons)
+	}
+
+	score := 0.0
+
+	titleSimilarity := textSimilarity(a.Title, b.Title)
+	if titleSimilarity >= 0.5 {
+		score += 0.4 * titleSimilarity
+		reasons = append(reasons, "judul mirip")
+	}
+
+	if nameSimilarity := textSimilarity(stringValue(a.Details.CompetitionName), stringValue(b.Details.CompetitionName)); nameSimilarity >= 0.8 {
+		score += 0.3
+		reasons = append(reasons, "nama kompetisi sama")
+	}
+	if nameSimilarity := textSimilarity(stringValue(a.Details.PublicationTitle), stringValue(b.Details.PublicationTitle)); nameSimilarity >= 0.8 {
+		score += 0.3
+		reasons = append(reasons, "judul publikasi sama")
+	}
+
+	if !isBlank(a.Details.ISSN) && !isBlank(b.Details.ISSN) && normalizeISSN(*a.Details.ISSN) == normalizeISSN(*b.Details.ISSN) {
+		score += 0.2
+		reasons = append(reasons, "ISSN sama")
+	}
+
+	if a.Details.EventDate != nil && b.Details.EventDate != nil {
+		diff := a.Details.EventDate.Sub(*b.Details.EventDate)
+		if diff < 0 {
+			diff = -diff
+		}
+		switch {
+		case diff < 24*time.Hour:
+			score += 0.2
+			reasons = append(reasons, "tanggal kegiatan sama")
+		case diff <= duplicateDateWindow:
+			score += 0.1
+			reasons = append(reasons, "tanggal kegiatan berdekatan")
+		}
+	}
+
+	if a.StudentID == b.StudentID {
+		score += 0.1
+		reasons = append(reasons, "dilaporkan oleh mahasiswa yang sama")
+	}
+
+	if score > 1 {
+		score = 1
+	}
+	return score, appendTeamReason(a, b, reasons)
+}
+
+func appendTeamReason(a, b *model.Achievement, reasons []string) []string {
+	if a.StudentID != b.StudentID {
+		reasons = append(reasons, "kemungkinan prestasi tim yang dilaporkan anggota lain")
+	}
+	return reasons
+}
+
+// normalizeText mengubah ke huruf kecil, menghapus aksen dan tanda baca
+func normalizeText(value string) string {
+	decomposed := norm.NFD.String(strings.ToLower(value))
+
+	var b strings.Builder
+	lastSpace := true
+	for _, r := range decomposed {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			continue
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+			lastSpace = false
+		default:
+			if !lastSpace {
+				b.WriteRune(' ')
+				lastSpace = true
+			}
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// textSimilarity koefisien Jaccard atas kata-kata hasil normalisasi
+func textSimilarity(a, b string) float64 {
+	tokensA := strings.Fields(normalizeText(a))
+	tokensB := strings.Fields(normalizeText(b))
+	if len(tokensA) == 0 || len(tokensB) == 0 {
+		return 0
+	}
+
+	setA := make(map[string]bool)
+	for _, t := range tokensA {
+		setA[t] = true
+	}
+	setB := make(map[string]bool)
+	for _, t := range tokensB {
+		setB[t] = true
+	}
+
+	intersection := 0
+	for t := range setA {
+		if setB[t] {
+			intersection++
+		}
+	}
+	union := len(setA) + len(setB) - intersection
+	return float64(intersection) / float64(union)
+}
+
+// normalizeDOI menghapus prefix URL/"doi:" dan menyeragamkan huruf
+func normalizeDOI(doi string) string {
+	value := strings.ToLower(strings.TrimSpace(doi))
+	for _, prefix := range []string{"https://doi.org/", "http://doi.org/", "https://dx.doi.org/", "http://dx.doi.org/", "doi:"} {
+		value = strings.TrimPrefix(value, prefix)
+	}
+	return strings.TrimSpace(value)
+}
+
+func normalizeISSN(issn string) string {
+	return strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(issn), "-", ""))
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+)
+
+func TestTextSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"Juara 1 Gemastik 2024", "juara 1 gemastik 2024", 1},
+		{"Juara 1: GEMASTIK, 2024!", "juara 1 gemastik 2024", 1},
+		{"Lomba Désain Grafis", "lomba desain grafis", 1},
+		{"juara 1 gemastik", "juara 2 gemastik", 0.5},
+		{"gemastik gemastik juara", "juara gemastik", 1},
+		{"kompetisi robotik", "olimpiade matematika", 0},
+		{"", "juara", 0},
+		{"!!!", "???", 0},
+	}
+
+	for _, tt := range tests {
+		if got := textSimilarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("textSimilarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeDOI(t *testing.T) {
+	for _, doi := range []string{"10.1000/XYZ123", "https://doi.org/10.1000/xyz123", "doi:10.1000/xyz123", " http://dx.doi.org/10.1000/XYZ123 "} {
+		if got := normalizeDOI(doi); got != "10.1000/xyz123" {
+			t.Errorf("normalizeDOI(%q) = %q", doi, got)
+		}
+	}
+}
+
+func TestScoreDuplicate(t *testing.T) {
+	date := time.Date(2024, 10, 5, 0, 0, 0, 0, time.UTC)
+	nearDate := date.AddDate(0, 0, 3)
+
+	base := model.Achievement{
+		StudentID: "s1",
+		Title:     "Juara 1 Gemastik 2024",
+		Details: model.AchievementDetails{
+			CompetitionName: strPtr("Gemastik"),
+			EventDate:       &date,
+		},
+	}
+
+	t.Run("DOI sama langsung skor penuh", func(t *testing.T) {
+		a := model.Achievement{StudentID: "s1", Title: "Paper A", Details: model.AchievementDetails{DOI: strPtr("https://doi.org/10.1/ABC")}}
+		b := model.Achievement{StudentID: "s2", Title: "Tulisan lain", Details: model.AchievementDetails{DOI: strPtr("10.1/abc")}}
+		score, reasons := scoreDuplicate(&a, &b)
+		if score != 1 {
+			t.Errorf("score = %v, want 1", score)
+		}
+		if !containsString(reasons, "DOI sama") || !containsString(reasons, "kemungkinan prestasi tim yang dilaporkan anggota lain") {
+			t.Errorf("reasons = %v", reasons)
+		}
+	})
+
+	t.Run("laporan ulang mahasiswa yang sama", func(t *testing.T) {
+		other := base
+		score, reasons := scoreDuplicate(&base, &other)
+		// judul 0.4 + kompetisi 0.3 + tanggal 0.2 + mahasiswa sama 0.1
+		if math.Abs(score-1) > 1e-9 {
+			t.Errorf("score = %v, want 1", score)
+		}
+		for _, reason := range []string{"judul mirip", "nama kompetisi sama", "tanggal kegiatan sama", "dilaporkan oleh mahasiswa yang sama"} {
+			if !containsString(reasons, reason) {
+				t.Errorf("reasons = %v, tidak memuat %q", reasons, reason)
+			}
+		}
+	})
+
+	t.Run("tanggal berdekatan dari mahasiswa lain", func(t *testing.T) {
+		other := base
+		other.StudentID = "s2"
+		other.Details.EventDate = &nearDate
+		score, _ := scoreDuplicate(&base, &other)
+		// judul 0.4 + kompetisi 0.3 + tanggal berdekatan 0.1
+		if math.Abs(score-0.8) > 1e-9 {
+			t.Errorf("score = %v, want 0.8", score)
+		}
+	})
+
+	t.Run("prestasi berbeda", func(t *testing.T) {
+		other := model.Achievement{StudentID: "s2", Title: "Ketua Himpunan", Details: model.AchievementDetails{OrganizationName: strPtr("HIMA")}}
+		score, _ := scoreDuplicate(&base, &other)
+		if score != 0 {
+			t.Errorf("score = %v, want 0", score)
+		}
+	})
+}
+
+func TestStudentDuplicateWarnings(t *testing.T) {
+	warnings := []DuplicateWarning{
+		{AchievementID: "own", StudentID: "s1", SameStudent: true, Title: "Juara 1", Score: 0.9, Reasons: []string{"judul mirip"}},
+		{AchievementID: "other1", StudentID: "s2", Title: "Juara 1 tim", Status: model.StatusVerified, Points: 50, Score: 0.95, Reasons: []string{"DOI sama"}},
+		{AchievementID: "other2", StudentID: "s3", Title: "Juara 1 lain", Score: 0.7, Reasons: []string{"judul mirip"}},
+	}
+
+	got := studentDuplicateWarnings(warnings)
+	if len(got) != 2 {
+		t.Fatalf("studentDuplicateWarnings() = %+v, want 2 peringatan", got)
+	}
+	if got[0].AchievementID != "own" {
+		t.Errorf("duplikat milik sendiri seharusnya utuh, got %+v", got[0])
+	}
+
+	hint := got[1]
+	if hint.AchievementID != "" || hint.StudentID != "" || hint.Title != "" || hint.Status != "" || hint.Points != 0 {
+		t.Errorf("petunjuk duplikat mahasiswa lain membocorkan data: %+v", hint)
+	}
+	if hint.SameStudent || hint.Score != 0.95 || len(hint.Reasons) != 1 || !strings.Contains(hint.Reasons[0], "2 prestasi") {
+		t.Errorf("petunjuk = %+v", hint)
+	}
+
+	if got := studentDuplicateWarnings(warnings[:1]); len(got) != 1 {
+		t.Errorf("tanpa duplikat mahasiswa lain = %+v, want 1 peringatan", got)
+	}
+}
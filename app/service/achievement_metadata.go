@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+)
+
+// metadataTitleThreshold kemiripan judul minimal agar dianggap sama dengan metadata DOI
+const metadataTitleThreshold = 0.8
+
+// MetadataMismatch perbedaan antara data yang diisi mahasiswa dan metadata DOI
+type MetadataMismatch struct {
+	Field    string `json:"field"`
+	Entered  string `json:"entered"`
+	Resolved string `json:"resolved"`
+}
+
+type MetadataCheckResponse struct {
+	AchievementID string               `json:"achievement_id"`
+	DOI           string               `json:"doi"`
+	Metadata      *PublicationMetadata `json:"metadata"`
+	Mismatches    []MetadataMismatch   `json:"mismatches"`
+}
+
+// LookupDOI mengambil metadata DOI untuk mengisi form publikasi
+func (s *achievementService) LookupDOI(ctx context.Context, doi string) (*PublicationMetadata, error) {
+	if s.metadataResolver == nil {
+		return nil, errors.New("resolver metadata DOI tidak dikonfigurasi")
+	}
+	doi = normalizeDOI(doi)
+	if !strings.HasPrefix(doi, "10.") || !strings.Contains(doi, "/") {
+		return nil, errors.New("DOI tidak valid, contoh: 10.1000/xyz123")
+	}
+	return s.metadataResolver.Resolve(ctx, doi)
+}
+
+// CheckPublicationMetadata membandingkan publikasi dengan metadata DOI-nya. Dipakai dosen
+// wali/admin saat verifikasi, sama seperti pemeriksaan duplikat.
+func (s *achievementService) CheckPublicationMetadata(ctx context.Context, userID uuid.UUID, achievementID string) (*MetadataCheckResponse, error) {
+	achievement, err := s.achievementRepo.FindAchievementByID(ctx, achievementID)
+	if err != nil {
+		return nil, errors.New("achievement tidak ditemukan")
+	}
+
+	if _, err := s.authorizeAchievementAccess(ctx, userID, achievement); err != nil {
+		return nil, err
+	}
+
+	if achievement.AchievementType != model.AchievementTypePublication || isBlank(achievement.Details.DOI) {
+		return nil, errors.New("achievement bukan publikasi dengan DOI")
+	}
+
+	metadata, err := s.LookupDOI(ctx, *achievement.Details.DOI)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MetadataCheckResponse{
+		AchievementID: achievementID,
+		DOI:           metadata.DOI,
+		Metadata:      metadata,
+		Mismatches:    compareMetadata(&achievement.Details, metadata),
+	}, nil
+}
+
+// enrichPublication mengisi field publikasi yang masih kosong dari metadata DOI. Resolver
+// yang gagal tidak menggagalkan request; mahasiswa tetap dapat mengisi data secara manual.
+func (s *achievementService) enrichPublication(ctx context.Context, achievement *model.Achievement) {
+	if s.metadataResolver == nil || achievement.AchievementType != model.AchievementTypePublication || isBlank(achievement.Details.DOI) {
+		return
+	}
+
+	metadata, err := s.LookupDOI(ctx, *achievement.Details.DOI)
+	if err != nil {
+		fmt.Printf("Warning: Gagal mengambil metadata DOI %s: %v\n", *achievement.Details.DOI, err)
+		return
+	}
+
+	details := &achievement.Details
+	if isBlank(details.PublicationTitle) && metadata.Title != "" {
+		details.PublicationTitle = &metadata.Title
+	}
+	if strings.TrimSpace(achievement.Title) == "" {
+		achievement.Title = metadata.Title
+	}
+	if len(details.Authors) == 0 && len(metadata.Authors) > 0 {
+		details.Authors = metadata.Authors
+	}
+	if isBlank(details.Publisher) && metadata.Publisher != "" {
+		details.Publisher = &metadata.Publisher
+	}
+	if isBlank(details.ISSN) && len(metadata.ISSN) > 0 {
+		details.ISSN = &metadata.ISSN[0]
+	}
+	if details.EventDate == nil && metadata.PublishedAt != nil {
+		details.EventDate = metadata.PublishedAt
+	}
+	if (details.PublicationType == nil || *details.PublicationType == "") && metadata.PublicationType != "" {
+		details.PublicationType = &metadata.PublicationType
+	}
+}
+
+// compareMetadata hanya membandingkan field yang diisi di kedua sisi
+func compareMetadata(details *model.AchievementDetails, metadata *PublicationMetadata) []MetadataMismatch {
+	mismatches := []MetadataMismatch{}
+	add := func(field, entered, resolved string) {
+		mismatches = append(mismatches, MetadataMismatch{Field: field, Entered: entered, Resolved: resolved})
+	}
+
+	if title := stringValue(details.PublicationTitle); title != "" && metadata.Title != "" &&
+		textSimilarity(title, metadata.Title) < metadataTitleThreshold {
+		add("details.publication_title", title, metadata.Title)
+	}
+
+	if len(details.Authors) > 0 && len(metadata.Authors) > 0 && !sameAuthors(details.Authors, metadata.Authors) {
+		add("details.authors", strings.Join(details.Authors, "; "), strings.Join(metadata.Authors, "; "))
+	}
+
+	if publisher := stringValue(details.Publisher); publisher != "" && metadata.Publisher != "" &&
+		textSimilarity(publisher, metadata.Publisher) < metadataTitleThreshold {
+		add("details.publisher", publisher, metadata.Publisher)
+	}
+
+	if issn := stringValue(details.ISSN); issn != "" && len(metadata.ISSN) > 0 {
+		found := false
+		for _, resolved := range metadata.ISSN {
+			if normalizeISSN(resolved) == normalizeISSN(issn) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			add("details.issn", issn, strings.Join(metadata.ISSN, ", "))
+		}
+	}
+
+	if details.EventDate != nil && metadata.PublishedAt != nil && details.EventDate.Year() != metadata.PublishedAt.Year() {
+		add("details.event_date", strconv.Itoa(details.EventDate.Year()), strconv.Itoa(metadata.PublishedAt.Year()))
+	}
+
+	if details.PublicationType != nil && *details.PublicationType != "" && metadata.PublicationType != "" &&
+		*details.PublicationType != metadata.PublicationType {
+		add("details.publication_type", string(*details.PublicationType), string(metadata.PublicationType))
+	}
+	return mismatches
+}
+
+// sameAuthors membandingkan jumlah penulis dan nama keluarganya, urutan diabaikan.
+// Nama ditulis "Nama Depan Belakang" atau "Belakang, Nama Depan".
+func sameAuthors(entered, resolved []string) bool {
+	if len(entered) != len(resolved) {
+		return false
+	}
+	surnames := make(map[string]int)
+	for _, author := range resolved {
+		surnames[authorSurname(author)]++
+	}
+	for _, author := range entered {
+		surname := authorSurname(author)
+		if surnames[surname] == 0 {
+			return false
+		}
+		surnames[surname]--
+	}
+	return true
+}
+
+func authorSurname(author string) string {
+	if i := strings.Index(author, ","); i >= 0 {
+		author = author[:i]
+	}
+	tokens := strings.Fields(normalizeText(author))
+	if len(tokens) == 0 {
+		return ""
+	}
+	return tokens[len(tokens)-1]
+}
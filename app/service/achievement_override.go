@@ -0,0 +1,348 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+)
+
+// OverrideAchievementRequest perubahan konten oleh admin tanpa batasan status
+type OverrideAchievementRequest struct {
+	UpdateAchievementRequest
+	Points *float64 `json:"points,omitempty"` // Mengganti poin hasil perhitungan aturan poin
+	Reason string   `json:"reason"`
+}
+
+type OverrideStatusRequest struct {
+	Status model.AchievementStatus `json:"status"`
+	Reason string                  `json:"reason"`
+}
+
+type ReassignAchievementRequest struct {
+	StudentID string `json:"student_id"` // NIM mahasiswa tujuan
+	Reason    string `json:"reason"`
+}
+
+// authorizeOverride memastikan user adalah admin dan alasan override diisi
+func (s *achievementService) authorizeOverride(ctx context.Context, userID uuid.UUID, reason string) (string, error) {
+	role, err := s.checkRole(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if role != "admin" {
+		return "", errors.New("hanya admin yang dapat melakukan override")
+	}
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return "", errors.New("alasan override harus diisi")
+	}
+	return reason, nil
+}
+
+// loadOverrideTarget memuat achievement beserta data mahasiswa pemiliknya
+func (s *achievementService) loadOverrideTarget(ctx context.Context, achievementID string) (*model.Achievement, *model.Student, error) {
+	achievement, err := s.achievementRepo.FindAchievementByID(ctx, achievementID)
+	if err != nil {
+		return nil, nil, errors.New("achievement tidak ditemukan")
+	}
+
+	studentUUID, err := uuid.Parse(achievement.StudentID)
+	if err != nil {
+		return nil, nil, errors.New("student ID tidak valid")
+	}
+	student, err := s.studentRepo.FindStudentByID(ctx, studentUUID)
+	if err != nil {
+		return nil, nil, errors.New("student tidak ditemukan")
+	}
+	return achievement, student, nil
+}
+
+// recordOverride mencatat history dengan penanda override
+func (s *achievementService) recordOverride(ctx context.Context, userID uuid.UUID, achievementID string, oldStatus, newStatus model.AchievementStatus, notes string) *model.AchievementReference {
+	reference, err := s.achievementRepo.FindReferenceByMongoID(ctx, achievementID)
+	if err != nil {
+		fmt.Printf("Warning: Gagal memuat reference: %v\n", err)
+		return nil
+	}
+
+	history := &model.AchievementHistory{
+		AchievementRefID:   reference.ID,
+		MongoAchievementID: achievementID,
+		OldStatus:          &oldStatus,
+		NewStatus:          newStatus,
+		ChangedBy:          userID,
+		Notes:              notes,
+		IsOverride:         true,
+	}
+	if err := s.historyRepo.CreateHistory(ctx, history); err != nil {
+		fmt.Printf("Warning: Gagal membuat history: %v\n", err)
+	}
+	return reference
+}
+
+// OverrideAchievement mengubah field apa pun pada achievement, termasuk yang sudah
+// disubmit atau diverifikasi
+func (s *achievementService) OverrideAchievement(ctx context.Context, userID uuid.UUID, achievementID string, req *OverrideAchievementRequest) (*AchievementResponse, error) {
+	reason, err := s.authorizeOverride(ctx, userID, req.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	achievement, student, err := s.loadOverrideTarget(ctx, achievementID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.ensureBaselineVersion(ctx, achievement, userID)
+
+	typeChanged := applyAchievementUpdate(achievement, &req.UpdateAchievementRequest)
+	if req.Members != nil {
+		members, err := s.resolveTeamMembers(ctx, student, req.Members)
+		if err != nil {
+			return nil, err
+		}
+		achievement.Members = members
+	}
+	if req.Points != nil {
+		if *req.Points < 0 {
+			return nil, errors.New("points tidak boleh negatif")
+		}
+		achievement.Points = *req.Points
+	}
+
+	if err := s.validateAchievementContent(ctx, achievement, typeChanged); err != nil {
+		return nil, err
+	}
+
+	if err := s.achievementRepo.UpdateAchievement(ctx, achievementID, achievement); err != nil {
+		return nil, fmt.Errorf("gagal mengupdate achievement: %v", err)
+	}
+
+	updatedAchievement, err := s.achievementRepo.FindAchievementByID(ctx, achievementID)
+	if err != nil {
+		return nil, errors.New("gagal memuat achievement setelah update")
+	}
+
+	s.saveVersion(ctx, updatedAchievement, model.VersionKindRevision, userID)
+
+	if req.Members != nil {
+		if err := s.syncTeamReferences(ctx, updatedAchievement); err != nil {
+			return nil, err
+		}
+	}
+
+	reference := s.recordOverride(ctx, userID, achievementID, achievement.Status, achievement.Status,
+		fmt.Sprintf("Override admin: konten achievement diubah. Alasan: %s", reason))
+
+	return s.mapToAchievementResponse(ctx, updatedAchievement, reference, student), nil
+}
+
+// OverrideStatus memaksa perpindahan status tanpa melalui alur persetujuan
+func (s *achievementService) OverrideStatus(ctx context.Context, userID uuid.UUID, achievementID string, req *OverrideStatusRequest) (*AchievementResponse, error) {
+	reason, err := s.authorizeOverride(ctx, userID, req.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	switch req.Status {
+	case model.StatusDraft, model.StatusSubmitted, model.StatusVerified, model.StatusRejected:
+	default:
+		return nil, errors.New("status tidak valid. Pilih: draft, submitted, verified, rejected")
+	}
+
+	achievement, student, err := s.loadOverrideTarget(ctx, achievementID)
+	if err != nil {
+		return nil, err
+	}
+
+	oldStatus := achievement.Status
+	if oldStatus == req.Status {
+		return nil, fmt.Errorf("status achievement sudah %s", req.Status)
+	}
+
+	var stages model.ApprovalStages
+	if req.Status == model.StatusSubmitted {
+		stages, err = s.approvalChainService.ResolveStages(ctx, achievement)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	notes := fmt.Sprintf("Override admin: status diubah dari %s ke %s. Alasan: %s", oldStatus, req.Status, reason)
+	achievement.Status = req.Status
+	if req.Status == model.StatusVerified {
+		points, rule, err := s.pointRuleService.CalculatePoints(ctx, achievement)
+		if err != nil {
+			return nil, err
+		}
+		achievement.Points = points
+		notes = fmt.Sprintf("%s (poin: %.2f%s)", notes, points, pointRuleNote(rule))
+	} else {
+		// Poin hanya berlaku untuk achievement terverifikasi
+		achievement.Points = 0
+		resetMemberConfirmations(achievement)
+	}
+
+	if err := s.achievementRepo.UpdateAchievement(ctx, achievementID, achievement); err != nil {
+		return nil, fmt.Errorf("gagal mengupdate status: %v", err)
+	}
+
+	now := time.Now()
+	if err := s.updateAllReferences(ctx, achievementID, func(reference *model.AchievementReference) {
+		reference.Status = req.Status
+		reference.CurrentStage = nil
+		reference.StageStartedAt = nil
+		reference.SLAFlaggedAt = nil
+		reference.EscalatedAt = nil
+		reference.EscalatedTo = nil
+
+		switch req.Status {
+		case model.StatusDraft:
+			reference.SubmittedAt = nil
+			reference.VerifiedAt = nil
+			reference.VerifiedBy = nil
+			reference.RejectionNote = ""
+			reference.ApprovalStages = nil
+		case model.StatusSubmitted:
+			reference.SubmittedAt = &now
+			reference.VerifiedAt = nil
+			reference.VerifiedBy = nil
+			reference.RejectionNote = ""
+			reference.ApprovalStages = stages
+			reference.CurrentStage = &stages[0]
+			reference.StageStartedAt = &now
+		case model.StatusVerified:
+			reference.RejectionNote = ""
+			if reference.VerifiedAt == nil {
+				reference.VerifiedAt = &now
+				reference.VerifiedBy = &userID
+			}
+		case model.StatusRejected:
+			reference.VerifiedAt = nil
+			reference.VerifiedBy = nil
+			reference.RejectionNote = reason
+		}
+	}); err != nil {
+		return nil, err
+	}
+
+	reference := s.recordOverride(ctx, userID, achievementID, oldStatus, req.Status, notes)
+
+	if req.Status == model.StatusVerified {
+		if err := s.certificateService.IssueCertificates(ctx, achievementID, userID); err != nil {
+			fmt.Printf("Warning: Gagal membuat sertifikat: %v\n", err)
+		}
+		if err := s.badgeService.IssueBadges(ctx, achievementID); err != nil {
+			fmt.Printf("Warning: Gagal menerbitkan badge: %v\n", err)
+		}
+	} else if oldStatus == model.StatusVerified {
+		if err := s.badgeService.RevokeBadges(ctx, achievementID, nil, "Status prestasi diubah admin menjadi "+string(req.Status)+": "+reason); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+		}
+		if err := s.certificateService.RevokeCertificates(ctx, achievementID, nil); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+		}
+	}
+
+	updatedAchievement, err := s.achievementRepo.FindAchievementByID(ctx, achievementID)
+	if err != nil {
+		return nil, errors.New("gagal memuat achievement setelah update")
+	}
+
+	kind := model.VersionKindRevision
+	switch req.Status {
+	case model.StatusSubmitted:
+		kind = model.VersionKindSubmission
+	case model.StatusVerified:
+		kind = model.VersionKindVerification
+	}
+	s.saveVersion(ctx, updatedAchievement, kind, userID)
+
+	return s.mapToAchievementResponse(ctx, updatedAchievement, reference, student), nil
+}
+
+// ReassignAchievement memindahkan achievement ke mahasiswa lain. Reference pembuat ikut
+// dipindahkan sehingga riwayat dan status persetujuan tetap utuh.
+func (s *achievementService) ReassignAchievement(ctx context.Context, userID uuid.UUID, achievementID string, req *ReassignAchievementRequest) (*AchievementResponse, error) {
+	reason, err := s.authorizeOverride(ctx, userID, req.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	nim := strings.TrimSpace(req.StudentID)
+	if nim == "" {
+		return nil, errors.New("student_id harus diisi (NIM)")
+	}
+
+	achievement, oldStudent, err := s.loadOverrideTarget(ctx, achievementID)
+	if err != nil {
+		return nil, err
+	}
+
+	newStudent, err := s.studentRepo.FindStudentByStudentID(ctx, nim)
+	if err != nil {
+		return nil, errors.New("mahasiswa dengan NIM " + nim + " tidak ditemukan")
+	}
+	if newStudent.ID == oldStudent.ID {
+		return nil, errors.New("achievement sudah dimiliki mahasiswa tersebut")
+	}
+	if isParticipant(achievement, newStudent.ID.String()) {
+		return nil, errors.New("mahasiswa tujuan sudah menjadi anggota tim achievement ini")
+	}
+
+	reference, err := s.achievementRepo.FindReferenceByMongoID(ctx, achievementID)
+	if err != nil {
+		return nil, errors.New("gagal memuat reference")
+	}
+
+	// Posisi pembuat di daftar anggota tim ikut digantikan
+	for i := range achievement.Members {
+		if achievement.Members[i].StudentID == oldStudent.ID.String() {
+			achievement.Members[i].StudentID = newStudent.ID.String()
+			achievement.Members[i].VerifiedBy = nil
+			achievement.Members[i].VerifiedAt = nil
+		}
+	}
+	if err := s.achievementRepo.UpdateAchievement(ctx, achievementID, achievement); err != nil {
+		return nil, fmt.Errorf("gagal mengupdate achievement: %v", err)
+	}
+	if err := s.achievementRepo.UpdateAchievementOwner(ctx, achievementID, newStudent.ID.String()); err != nil {
+		return nil, fmt.Errorf("gagal memindahkan achievement: %v", err)
+	}
+
+	reference.StudentID = newStudent.ID
+	reference.Student = model.Student{}
+	if err := s.achievementRepo.UpdateReference(ctx, reference); err != nil {
+		return nil, fmt.Errorf("gagal mengupdate reference: %v", err)
+	}
+
+	// Badge pemilik lama dicabut, pemilik baru mendapat badge jika prestasi terverifikasi
+	if err := s.badgeService.RevokeBadges(ctx, achievementID, &oldStudent.ID, "Prestasi dipindahkan ke mahasiswa lain: "+reason); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+	if err := s.certificateService.RevokeCertificates(ctx, achievementID, &oldStudent.ID); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+	if achievement.Status == model.StatusVerified {
+		if err := s.badgeService.IssueBadges(ctx, achievementID); err != nil {
+			fmt.Printf("Warning: Gagal menerbitkan badge: %v\n", err)
+		}
+	}
+
+	reference = s.recordOverride(ctx, userID, achievementID, achievement.Status, achievement.Status,
+		fmt.Sprintf("Override admin: achievement dipindahkan dari %s (%s) ke %s (%s). Alasan: %s",
+			oldStudent.User.FullName, oldStudent.StudentID, newStudent.User.FullName, newStudent.StudentID, reason))
+
+	updatedAchievement, err := s.achievementRepo.FindAchievementByID(ctx, achievementID)
+	if err != nil {
+		return nil, errors.New("gagal memuat achievement setelah update")
+	}
+
+	return s.mapToAchievementResponse(ctx, updatedAchievement, reference, newStudent), nil
+}
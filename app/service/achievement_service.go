@@ -4,13 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"path/filepath"
+	"io"
+	"path"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
 	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/repository"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/scanner"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/storage"
 )
 
 type AchievementService interface {
@@ -23,33 +26,81 @@ type AchievementService interface {
 	GetAchievements(ctx context.Context, userID uuid.UUID, page, limit int, status string) (*AchievementListResponse, error)
 	GetAchievementByID(ctx context.Context, userID uuid.UUID, achievementID string) (*AchievementResponse, error)
 	GetAchievementHistory(ctx context.Context, userID uuid.UUID, achievementID string) ([]AchievementHistoryResponse, error)
-	UploadAttachment(ctx context.Context, userID uuid.UUID, achievementID string, filePath string) (string, error)
+	UploadAttachment(ctx context.Context, userID uuid.UUID, achievementID string, fileName string, content io.Reader) (*model.Attachment, error)
+	CreateAchievementWithAttachments(ctx context.Context, userID uuid.UUID, req *CreateAchievementRequest, uploads []AttachmentUpload, atomic bool) (*AchievementResponse, []AttachmentUploadResult, error)
+	DeleteAttachment(ctx context.Context, userID uuid.UUID, achievementID string, attachmentID string) (*AchievementResponse, error)
+	ReplaceAttachment(ctx context.Context, userID uuid.UUID, achievementID string, attachmentID string, fileName string, content io.Reader) (*model.Attachment, error)
+	GetAchievementVersions(ctx context.Context, userID uuid.UUID, achievementID string) ([]model.AchievementVersion, error)
+	GetAchievementVersionDiff(ctx context.Context, userID uuid.UUID, achievementID string, version int, against int) (*AchievementVersionDiffResponse, error)
+	GetAchievementDuplicates(ctx context.Context, userID uuid.UUID, achievementID string) ([]DuplicateWarning, error)
+	ApproveStage(ctx context.Context, userID uuid.UUID, achievementID string, stage model.ApprovalStage) (*AchievementResponse, error)
+	RejectStage(ctx context.Context, userID uuid.UUID, achievementID string, stage model.ApprovalStage, rejectionNote string) (*AchievementResponse, error)
+	GetApprovalQueue(ctx context.Context, userID uuid.UUID, stage model.ApprovalStage) ([]AchievementResponse, error)
+	OverrideAchievement(ctx context.Context, userID uuid.UUID, achievementID string, req *OverrideAchievementRequest) (*AchievementResponse, error)
+	OverrideStatus(ctx context.Context, userID uuid.UUID, achievementID string, req *OverrideStatusRequest) (*AchievementResponse, error)
+	ReassignAchievement(ctx context.Context, userID uuid.UUID, achievementID string, req *ReassignAchievementRequest) (*AchievementResponse, error)
+	ImportCitations(ctx context.Context, userID uuid.UUID, req *ImportCitationsRequest) (*CitationImportReport, error)
+	LookupDOI(ctx context.Context, doi string) (*PublicationMetadata, error)
+	CheckPublicationMetadata(ctx context.Context, userID uuid.UUID, achievementID string) (*MetadataCheckResponse, error)
 }
 
 type achievementService struct {
 	achievementRepo     repository.AchievementRepository
 	historyRepo         repository.AchievementHistoryRepository
+	versionRepo         repository.AchievementVersionRepository
+	typeRepo            repository.AchievementTypeRepository
 	studentRepo         repository.StudentRepository
 	lecturerRepo        repository.LecturerRepository
 	userRepo            repository.UserRepository
 	roleRepo            repository.RoleRepository
+	pointRuleService    PointRuleService
+	approvalChainService ApprovalChainService
+	delegationRepo      repository.DelegationRepository
+	certificateService  CertificateService
+	badgeService        BadgeService
+	metadataResolver    MetadataResolver // nil = pengayaan metadata DOI nonaktif
+	blobStore           storage.BlobStore
+	attachmentSizeLimits map[string]int64 // Batas ukuran per jenis file, kosong = DefaultAttachmentSizeLimits
+	fileScanner         scanner.Scanner
 }
 
 func NewAchievementService(
 	achievementRepo repository.AchievementRepository,
 	historyRepo repository.AchievementHistoryRepository,
+	versionRepo repository.AchievementVersionRepository,
+	typeRepo repository.AchievementTypeRepository,
 	studentRepo repository.StudentRepository,
 	lecturerRepo repository.LecturerRepository,
 	userRepo repository.UserRepository,
 	roleRepo repository.RoleRepository,
+	pointRuleService PointRuleService,
+	approvalChainService ApprovalChainService,
+	delegationRepo repository.DelegationRepository,
+	certificateService CertificateService,
+	badgeService BadgeService,
+	metadataResolver MetadataResolver,
+	blobStore storage.BlobStore,
+	attachmentSizeLimits map[string]int64,
+	fileScanner scanner.Scanner,
 ) AchievementService {
 	return &achievementService{
 		achievementRepo: achievementRepo,
 		historyRepo:      historyRepo,
+		versionRepo:      versionRepo,
+		typeRepo:         typeRepo,
 		studentRepo:      studentRepo,
 		lecturerRepo:     lecturerRepo,
 		userRepo:         userRepo,
 		roleRepo:         roleRepo,
+		pointRuleService: pointRuleService,
+		approvalChainService: approvalChainService,
+		delegationRepo:   delegationRepo,
+		certificateService: certificateService,
+		badgeService:     badgeService,
+		metadataResolver: metadataResolver,
+		blobStore:        blobStore,
+		attachmentSizeLimits: attachmentSizeLimits,
+		fileScanner:      fileScanner,
 	}
 }
 
@@ -60,7 +111,7 @@ type CreateAchievementRequest struct {
 	Description     string                 `json:"description"`
 	Details         model.AchievementDetails `json:"details"`
 	Tags            []string               `json:"tags,omitempty"`
-	Points          float64                `json:"points,omitempty"`
+	Members         []TeamMemberRequest    `json:"members,omitempty"` // Diisi untuk prestasi tim
 }
 
 type UpdateAchievementRequest struct {
@@ -69,7 +120,7 @@ type UpdateAchievementRequest struct {
 	Description     string                 `json:"description,omitempty"`
 	Details         *model.AchievementDetails `json:"details,omitempty"`
 	Tags            []string               `json:"tags,omitempty"`
-	Points          *float64                `json:"points,omitempty"`
+	Members         []TeamMemberRequest    `json:"members,omitempty"` // Array kosong mengubah menjadi prestasi individu
 }
 
 type AchievementResponse struct {
@@ -82,9 +133,12 @@ type AchievementResponse struct {
 	Details         model.AchievementDetails `json:"details"`
 	Attachments     []model.Attachment     `json:"attachments"`
 	Tags            []string               `json:"tags"`
+	Members         []TeamMemberResponse   `json:"members,omitempty"`
 	Points          float64                `json:"points"`
 	Status          model.AchievementStatus `json:"status"`
 	Reference       *AchievementReferenceInfo `json:"reference,omitempty"`
+	DuplicateWarnings []DuplicateWarning   `json:"duplicate_warnings,omitempty"` // Hanya diisi saat create dan submit
+	Expired         bool                   `json:"expired,omitempty"` // Sertifikasi yang masa berlakunya sudah lewat
 	CreatedAt       time.Time              `json:"created_at"`
 	UpdatedAt       time.Time              `json:"updated_at"`
 }
@@ -104,6 +158,9 @@ type AchievementHistoryResponse struct {
 	ChangedBy          string                   `json:"changed_by"`
 	ChangedByUser      *UserInfo                `json:"changed_by_user,omitempty"`
 	Notes              string                   `json:"notes,omitempty"`
+	Stage              *model.ApprovalStage     `json:"stage,omitempty"`
+	OnBehalfOf         *string                  `json:"on_behalf_of,omitempty"` // User dosen wali yang diwakili dosen pengganti
+	IsOverride         bool                     `json:"is_override"`
 	CreatedAt          time.Time                `json:"created_at"`
 }
 
@@ -122,6 +179,11 @@ type AchievementReferenceInfo struct {
 	VerifiedAt    *time.Time `json:"verified_at,omitempty"`
 	VerifiedBy    *string    `json:"verified_by,omitempty"`
 	RejectionNote string    `json:"rejection_note,omitempty"`
+	ApprovalStages model.ApprovalStages `json:"approval_stages,omitempty"`
+	CurrentStage  *model.ApprovalStage `json:"current_stage,omitempty"`
+	StageStartedAt *time.Time `json:"stage_started_at,omitempty"`
+	SLAFlaggedAt  *time.Time `json:"sla_flagged_at,omitempty"`
+	EscalatedAt   *time.Time `json:"escalated_at,omitempty"`
 }
 
 type UserInfo struct {
@@ -177,48 +239,145 @@ func (s *achievementService) checkRole(ctx context.Context, userID uuid.UUID) (s
 	return "", errors.New("role tidak dikenali")
 }
 
-// CreateAchievement (FR-003)
-func (s *achievementService) CreateAchievement(ctx context.Context, userID uuid.UUID, req *CreateAchievementRequest) (*AchievementResponse, error) {
-	// Validasi user adalah mahasiswa
-	isStudent, student, err := s.isStudent(ctx, userID)
-	if !isStudent || err != nil {
-		return nil, errors.New("hanya mahasiswa yang dapat membuat prestasi")
+// isEditableStatus menentukan apakah konten achievement masih boleh diubah mahasiswa.
+// Achievement yang ditolak boleh direvisi lalu disubmit ulang.
+func isEditableStatus(status model.AchievementStatus) bool {
+	return status == model.StatusDraft || status == model.StatusRejected
+}
+
+// validateAchievementContent memuat definisi tipe custom (jika ada) lalu memvalidasi achievement.
+// requireActive dipakai saat membuat achievement atau mengganti tipe; achievement lama
+// dengan tipe yang sudah dinonaktifkan tetap dapat direvisi dan disubmit.
+func (s *achievementService) validateAchievementContent(ctx context.Context, achievement *model.Achievement, requireActive bool) error {
+	var customType *model.AchievementTypeDefinition
+	if achievement.AchievementType != "" && !isBuiltInAchievementType(achievement.AchievementType) {
+		definition, err := s.typeRepo.FindTypeByCode(ctx, achievement.AchievementType)
+		if err == nil && (definition.IsActive || !requireActive) {
+			customType = definition
+		}
+	}
+	return validateAchievement(achievement, customType)
+}
+
+// authorizeAchievementAccess memeriksa hak baca user terhadap achievement
+// (mahasiswa pemilik, dosen wali, atau admin) dan mengembalikan data mahasiswanya
+func (s *achievementService) authorizeAchievementAccess(ctx context.Context, userID uuid.UUID, achievement *model.Achievement) (*model.Student, error) {
+	role, err := s.checkRole(ctx, userID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Validasi required fields
-	if req.Title == "" || req.Description == "" {
-		return nil, errors.New("title dan description harus diisi")
+	if role == "student" {
+		isStudent, student, err := s.isStudent(ctx, userID)
+		if !isStudent || err != nil {
+			return nil, errors.New("user tidak ditemukan sebagai mahasiswa")
+		}
+
+		if !isParticipant(achievement, student.ID.String()) {
+			return nil, errors.New("anda tidak memiliki akses untuk melihat achievement ini")
+		}
+
+		return student, nil
+	}
+
+	studentUUID, err := uuid.Parse(achievement.StudentID)
+	if err != nil {
+		return nil, errors.New("student ID tidak valid")
 	}
 
-	// Validasi achievement type
-	if req.AchievementType == "" {
-		return nil, errors.New("achievement_type harus diisi")
+	if role == "lecturer" {
+		isLecturer, lecturer, err := s.isLecturer(ctx, userID)
+		if !isLecturer || err != nil {
+			return nil, errors.New("user tidak ditemukan sebagai dosen")
+		}
+
+		student, err := s.studentRepo.FindStudentByID(ctx, studentUUID)
+		if err != nil {
+			return nil, errors.New("student tidak ditemukan")
+		}
+
+		// Dosen wali salah satu anggota tim juga boleh melihat
+		if len(s.advisedParticipants(ctx, achievement, lecturer)) == 0 {
+			return nil, errors.New("anda bukan dosen wali dari mahasiswa ini")
+		}
+
+		return student, nil
 	}
 
-	// Validasi achievement type value
-	validTypes := []model.AchievementType{
-		model.AchievementTypeAcademic,
-		model.AchievementTypeCompetition,
-		model.AchievementTypeOrganization,
-		model.AchievementTypePublication,
-		model.AchievementTypeCertification,
-		model.AchievementTypeOther,
+	// Admin bisa lihat semua
+	student, _ := s.studentRepo.FindStudentByID(ctx, studentUUID)
+	return student, nil
+}
+
+// saveVersion menyimpan isi achievement saat ini sebagai versi baru.
+// Kind submission dan verification disimpan sebagai snapshot beku.
+func (s *achievementService) saveVersion(ctx context.Context, achievement *model.Achievement, kind model.VersionKind, userID uuid.UUID) {
+	version := &model.AchievementVersion{
+		AchievementID:   achievement.ID.Hex(),
+		Kind:            kind,
+		Frozen:          kind != model.VersionKindRevision,
+		AchievementType: achievement.AchievementType,
+		Title:           achievement.Title,
+		Description:     achievement.Description,
+		Details:         achievement.Details,
+		Attachments:     achievement.Attachments,
+		Tags:            achievement.Tags,
+		Status:          achievement.Status,
+		CreatedBy:       userID.String(),
 	}
-	validType := false
-	for _, t := range validTypes {
-		if req.AchievementType == t {
-			validType = true
+
+	// Nomor versi berikutnya dibaca lalu disimpan; jika penyimpanan lain mendahului, index unik
+	// menolak nomor yang sama dan nomor dihitung ulang agar snapshot tidak hilang
+	var err error
+	for attempt := 0; attempt < maxVersionAttempts; attempt++ {
+		version.Version = 1
+		if latest, findErr := s.versionRepo.FindLatestVersion(ctx, achievement.ID.Hex()); findErr == nil {
+			version.Version = latest.Version + 1
+		}
+
+		if _, err = s.versionRepo.CreateVersion(ctx, version); !errors.Is(err, repository.ErrVersionExists) {
 			break
 		}
 	}
-	if !validType {
-		return nil, errors.New("achievement_type tidak valid. Pilih: academic, competition, organization, publication, certification, other")
+	if err != nil {
+		fmt.Printf("Warning: Gagal menyimpan versi achievement: %v\n", err)
 	}
+}
+
+// maxVersionAttempts batas percobaan ulang saat nomor versi bentrok
+const maxVersionAttempts = 5
+
+// ensureBaselineVersion menyimpan kondisi awal achievement lama yang belum punya versi,
+// supaya perubahan pertama tetap bisa dibandingkan
+func (s *achievementService) ensureBaselineVersion(ctx context.Context, achievement *model.Achievement, userID uuid.UUID) {
+	if _, err := s.versionRepo.FindLatestVersion(ctx, achievement.ID.Hex()); err == nil {
+		return
+	}
+	s.saveVersion(ctx, achievement, model.VersionKindRevision, userID)
+}
 
-	// Set default points jika tidak diisi
-	points := req.Points
-	if points == 0 {
-		points = 0 // Default 0, bisa dihitung berdasarkan type nanti
+// CreateAchievement (FR-003)
+func (s *achievementService) CreateAchievement(ctx context.Context, userID uuid.UUID, req *CreateAchievementRequest) (*AchievementResponse, error) {
+	result, created, err := s.createAchievement(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+	result.DuplicateWarnings = s.findStudentDuplicates(ctx, created)
+	return result, nil
+}
+
+// createAchievement menyimpan achievement baru tanpa menghitung peringatan duplikat, sehingga
+// pemanggil yang masih dapat membatalkan achievement menghitungnya setelah keputusan akhir
+func (s *achievementService) createAchievement(ctx context.Context, userID uuid.UUID, req *CreateAchievementRequest) (*AchievementResponse, *model.Achievement, error) {
+	// Validasi user adalah mahasiswa
+	isStudent, student, err := s.isStudent(ctx, userID)
+	if !isStudent || err != nil {
+		return nil, nil, errors.New("hanya mahasiswa yang dapat membuat prestasi")
+	}
+
+	members, err := s.resolveTeamMembers(ctx, student, req.Members)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	// Create achievement di MongoDB
@@ -230,13 +389,22 @@ func (s *achievementService) CreateAchievement(ctx context.Context, userID uuid.
 		Details:         req.Details,
 		Attachments:     []model.Attachment{},
 		Tags:            req.Tags,
-		Points:          points,
+		Members:         members,
+		Points:          0, // Poin dihitung server saat verifikasi
 		Status:          model.StatusDraft,
 	}
 
+	// Lengkapi field publikasi yang kosong dari metadata DOI
+	s.enrichPublication(ctx, achievement)
+
+	// Validasi field umum dan details sesuai tipe prestasi (tipe custom harus aktif)
+	if err := s.validateAchievementContent(ctx, achievement, true); err != nil {
+		return nil, nil, err
+	}
+
 	createdAchievement, err := s.achievementRepo.CreateAchievement(ctx, achievement)
 	if err != nil {
-		return nil, fmt.Errorf("gagal menyimpan achievement: %v", err)
+		return nil, nil, fmt.Errorf("gagal menyimpan achievement: %v", err)
 	}
 
 	// Create reference di PostgreSQL
@@ -249,7 +417,18 @@ func (s *achievementService) CreateAchievement(ctx context.Context, userID uuid.
 	if err := s.achievementRepo.CreateReference(ctx, reference); err != nil {
 		// Rollback: delete dari MongoDB jika reference gagal
 		s.achievementRepo.SoftDeleteAchievement(ctx, createdAchievement.ID.Hex())
-		return nil, fmt.Errorf("gagal menyimpan reference: %v", err)
+		return nil, nil, fmt.Errorf("gagal menyimpan reference: %v", err)
+	}
+
+	// Reference untuk anggota tim lainnya agar muncul di portofolio masing-masing. Jika gagal,
+	// achievement dibatalkan seluruhnya agar tidak ada anggota yang tidak melihat prestasinya.
+	if createdAchievement.IsTeam() {
+		if err := s.syncTeamReferences(ctx, createdAchievement); err != nil {
+			if discardErr := s.discardAchievement(ctx, createdAchievement.ID.Hex(), nil); discardErr != nil {
+				fmt.Printf("Warning: Gagal membatalkan achievement %s: %v\n", createdAchievement.ID.Hex(), discardErr)
+			}
+			return nil, nil, err
+		}
 	}
 
 	// Create initial history
@@ -267,8 +446,9 @@ func (s *achievementService) CreateAchievement(ctx context.Context, userID uuid.
 		fmt.Printf("Warning: Gagal membuat history: %v\n", err)
 	}
 
-	result := s.mapToAchievementResponse(ctx, createdAchievement, reference, student)
-	return result, nil
+	s.saveVersion(ctx, createdAchievement, model.VersionKindRevision, userID)
+
+	return s.mapToAchievementResponse(ctx, createdAchievement, reference, student), createdAchievement, nil
 }
 
 // UpdateAchievement
@@ -290,41 +470,74 @@ func (s *achievementService) UpdateAchievement(ctx context.Context, userID uuid.
 		return nil, errors.New("anda tidak memiliki akses untuk mengupdate achievement ini")
 	}
 
-	// Validasi status adalah draft
-	if achievement.Status != model.StatusDraft {
-		return nil, errors.New("hanya achievement dengan status draft yang dapat diupdate")
+	// Validasi status adalah draft atau rejected (revisi)
+	if !isEditableStatus(achievement.Status) {
+		return nil, errors.New("hanya achievement dengan status draft atau rejected yang dapat diupdate")
+	}
+
+	s.ensureBaselineVersion(ctx, achievement, userID)
+
+	typeChanged := applyAchievementUpdate(achievement, req)
+	if req.Members != nil {
+		members, err := s.resolveTeamMembers(ctx, student, req.Members)
+		if err != nil {
+			return nil, err
+		}
+		achievement.Members = members
+	}
+
+	s.enrichPublication(ctx, achievement)
+
+	// Validasi hasil gabungan sebelum disimpan
+	if err := s.validateAchievementContent(ctx, achievement, typeChanged); err != nil {
+		return nil, err
+	}
+
+	if err := s.achievementRepo.UpdateAchievement(ctx, achievementID, achievement); err != nil {
+		return nil, fmt.Errorf("gagal mengupdate achievement: %v", err)
+	}
+
+	// Get updated achievement
+	updatedAchievement, err := s.achievementRepo.FindAchievementByID(ctx, achievementID)
+	if err != nil {
+		return nil, errors.New("gagal memuat achievement setelah update")
+	}
+
+	s.saveVersion(ctx, updatedAchievement, model.VersionKindRevision, userID)
+
+	if req.Members != nil {
+		if err := s.syncTeamReferences(ctx, updatedAchievement); err != nil {
+			return nil, err
+		}
+	}
+
+	// Get reference
+	reference, err := s.achievementRepo.FindReferenceByMongoID(ctx, achievementID)
+	if err != nil {
+		return nil, errors.New("gagal memuat reference")
 	}
 
-	// Update achievement fields (hanya update field yang diisi)
+	result := s.mapToAchievementResponse(ctx, updatedAchievement, reference, student)
+	return result, nil
+}
+
+// applyAchievementUpdate menerapkan field yang diisi pada request ke achievement
+// dan mengembalikan true jika tipe achievement berubah
+func applyAchievementUpdate(achievement *model.Achievement, req *UpdateAchievementRequest) (typeChanged bool) {
 	if req.Title != "" {
 		achievement.Title = req.Title
 	}
 	if req.Description != "" {
 		achievement.Description = req.Description
 	}
+	// Pindah ke tipe custom hanya boleh ke tipe yang masih aktif
+	typeChanged = req.AchievementType != "" && req.AchievementType != achievement.AchievementType
 	if req.AchievementType != "" {
-		// Validasi achievement type
-		validTypes := []model.AchievementType{
-			model.AchievementTypeAcademic,
-			model.AchievementTypeCompetition,
-			model.AchievementTypeOrganization,
-			model.AchievementTypePublication,
-			model.AchievementTypeCertification,
-			model.AchievementTypeOther,
-		}
-		validType := false
-		for _, t := range validTypes {
-			if req.AchievementType == t {
-				validType = true
-				break
-			}
-		}
-		if !validType {
-			return nil, errors.New("achievement_type tidak valid")
-		}
 		achievement.AchievementType = req.AchievementType
 	}
-	if req.Details.CustomFields != nil || req.Details.CompetitionName != nil {
+	if req.Details == nil {
+		// Details tidak dikirim, biarkan apa adanya
+	} else if req.Details.CustomFields != nil || req.Details.CompetitionName != nil {
 		// Merge details (update field yang ada, keep yang tidak diupdate)
 		if req.Details.CompetitionName != nil {
 			achievement.Details.CompetitionName = req.Details.CompetitionName
@@ -362,6 +575,9 @@ func (s *achievementService) UpdateAchievement(ctx context.Context, userID uuid.
 		if req.Details.Period != nil {
 			achievement.Details.Period = req.Details.Period
 		}
+		if req.Details.DOI != nil {
+			achievement.Details.DOI = req.Details.DOI
+		}
 		if req.Details.CertificationName != nil {
 			achievement.Details.CertificationName = req.Details.CertificationName
 		}
@@ -391,6 +607,11 @@ func (s *achievementService) UpdateAchievement(ctx context.Context, userID uuid.
 				achievement.Details.CustomFields = make(map[string]interface{})
 			}
 			for k, v := range req.Details.CustomFields {
+				// Nilai null menghapus field custom
+				if v == nil {
+					delete(achievement.Details.CustomFields, k)
+					continue
+				}
 				achievement.Details.CustomFields[k] = v
 			}
 		}
@@ -401,28 +622,7 @@ func (s *achievementService) UpdateAchievement(ctx context.Context, userID uuid.
 	if req.Tags != nil {
 		achievement.Tags = req.Tags
 	}
-	if req.Points != nil {
-		achievement.Points = *req.Points
-	}
-
-	if err := s.achievementRepo.UpdateAchievement(ctx, achievementID, achievement); err != nil {
-		return nil, fmt.Errorf("gagal mengupdate achievement: %v", err)
-	}
-
-	// Get updated achievement
-	updatedAchievement, err := s.achievementRepo.FindAchievementByID(ctx, achievementID)
-	if err != nil {
-		return nil, errors.New("gagal memuat achievement setelah update")
-	}
-
-	// Get reference
-	reference, err := s.achievementRepo.FindReferenceByMongoID(ctx, achievementID)
-	if err != nil {
-		return nil, errors.New("gagal memuat reference")
-	}
-
-	result := s.mapToAchievementResponse(ctx, updatedAchievement, reference, student)
-	return result, nil
+	return typeChanged
 }
 
 // DeleteAchievement (FR-005)
@@ -454,12 +654,14 @@ func (s *achievementService) DeleteAchievement(ctx context.Context, userID uuid.
 		return fmt.Errorf("gagal menghapus achievement: %v", err)
 	}
 
-	// Delete reference di PostgreSQL
-	reference, err := s.achievementRepo.FindReferenceByMongoID(ctx, achievementID)
-	if err == nil && reference != nil {
-		if err := s.achievementRepo.DeleteReference(ctx, reference.ID); err != nil {
-			// Log error but don't fail
-			fmt.Printf("Warning: Gagal menghapus reference: %v\n", err)
+	// Soft delete reference di PostgreSQL (termasuk reference anggota tim) agar dapat dipulihkan
+	references, err := s.achievementRepo.FindReferencesByMongoID(ctx, achievementID)
+	if err == nil {
+		for _, reference := range references {
+			if err := s.achievementRepo.DeleteReference(ctx, reference.ID); err != nil {
+				// Log error but don't fail
+				fmt.Printf("Warning: Gagal menghapus reference: %v\n", err)
+			}
 		}
 	}
 
@@ -485,39 +687,61 @@ func (s *achievementService) SubmitAchievement(ctx context.Context, userID uuid.
 		return nil, errors.New("anda tidak memiliki akses untuk submit achievement ini")
 	}
 
-	// Validasi status adalah draft
-	if achievement.Status != model.StatusDraft {
-		return nil, errors.New("hanya achievement dengan status draft yang dapat disubmit")
+	// Validasi status adalah draft atau rejected (submit ulang setelah revisi)
+	if !isEditableStatus(achievement.Status) {
+		return nil, errors.New("hanya achievement dengan status draft atau rejected yang dapat disubmit")
+	}
+
+	// Pastikan data lengkap sebelum diajukan untuk verifikasi
+	if err := s.validateAchievementContent(ctx, achievement, false); err != nil {
+		return nil, err
+	}
+
+	// Tentukan tahap persetujuan berdasarkan approval chain yang berlaku
+	stages, err := s.approvalChainService.ResolveStages(ctx, achievement)
+	if err != nil {
+		return nil, err
 	}
 
 	// Update status ke submitted
+	oldStatus := achievement.Status
 	achievement.Status = model.StatusSubmitted
+	resetMemberConfirmations(achievement)
 	if err := s.achievementRepo.UpdateAchievement(ctx, achievementID, achievement); err != nil {
 		return nil, fmt.Errorf("gagal mengupdate status: %v", err)
 	}
 
-	// Update reference
+	// Update reference seluruh peserta
+	now := time.Now()
+	if err := s.updateAllReferences(ctx, achievementID, func(reference *model.AchievementReference) {
+		reference.Status = model.StatusSubmitted
+		reference.SubmittedAt = &now
+		reference.RejectionNote = ""
+		reference.VerifiedAt = nil
+		reference.VerifiedBy = nil
+		reference.ApprovalStages = stages
+		reference.CurrentStage = &stages[0]
+		reference.StageStartedAt = &now
+		reference.SLAFlaggedAt = nil
+		reference.EscalatedAt = nil
+		reference.EscalatedTo = nil
+	}); err != nil {
+		return nil, err
+	}
+
 	reference, err := s.achievementRepo.FindReferenceByMongoID(ctx, achievementID)
 	if err != nil {
 		return nil, errors.New("gagal memuat reference")
 	}
 
-	now := time.Now()
-	reference.Status = model.StatusSubmitted
-	reference.SubmittedAt = &now
-
-	if err := s.achievementRepo.UpdateReference(ctx, reference); err != nil {
-		return nil, fmt.Errorf("gagal mengupdate reference: %v", err)
-	}
-
 	// Create history
-	oldStatus := model.StatusDraft
 	history := &model.AchievementHistory{
 		AchievementRefID:   reference.ID,
 		MongoAchievementID: achievementID,
 		OldStatus:          &oldStatus,
 		NewStatus:          model.StatusSubmitted,
 		ChangedBy:          userID,
+		Stage:              &stages[0],
 		Notes:              "Achievement disubmit untuk verifikasi",
 	}
 
@@ -531,17 +755,18 @@ func (s *achievementService) SubmitAchievement(ctx context.Context, userID uuid.
 		return nil, errors.New("gagal memuat achievement setelah update")
 	}
 
+	s.saveVersion(ctx, updatedAchievement, model.VersionKindSubmission, userID)
+
 	result := s.mapToAchievementResponse(ctx, updatedAchievement, reference, student)
+	result.DuplicateWarnings = s.findStudentDuplicates(ctx, updatedAchievement)
 	return result, nil
 }
 
 // VerifyAchievement (FR-007)
+// Verifikasi dosen wali adalah tahap pertama approval chain. Untuk prestasi tim, setiap
+// dosen wali mengkonfirmasi mahasiswa bimbingannya sendiri sebelum tahap berikutnya.
 func (s *achievementService) VerifyAchievement(ctx context.Context, userID uuid.UUID, achievementID string) (*AchievementResponse, error) {
-	// Validasi user adalah dosen wali
-	isLecturer, lecturer, err := s.isLecturer(ctx, userID)
-	if !isLecturer || err != nil {
-		return nil, errors.New("hanya dosen wali yang dapat memverifikasi prestasi")
-	}
+	isLecturer, lecturer, _ := s.isLecturer(ctx, userID)
 
 	// Get achievement
 	achievement, err := s.achievementRepo.FindAchievementByID(ctx, achievementID)
@@ -554,41 +779,171 @@ func (s *achievementService) VerifyAchievement(ctx context.Context, userID uuid.
 		return nil, errors.New("hanya achievement dengan status submitted yang dapat diverifikasi")
 	}
 
-	// Get student
+	reference, err := s.achievementRepo.FindReferenceByMongoID(ctx, achievementID)
+	if err != nil {
+		return nil, errors.New("gagal memuat reference")
+	}
+
+	if !isPendingStage(reference, model.ApprovalStageAdvisor) {
+		return nil, errors.New("achievement sudah melewati tahap verifikasi dosen wali")
+	}
+
+	// Get student (pembuat achievement)
 	studentUUID, err := uuid.Parse(achievement.StudentID)
 	if err != nil {
 		return nil, errors.New("student ID tidak valid")
 	}
 
-		student, err := s.studentRepo.FindStudentByID(ctx, studentUUID)
+	student, err := s.studentRepo.FindStudentByID(ctx, studentUUID)
 	if err != nil {
 		return nil, errors.New("student tidak ditemukan")
 	}
 
-	// Validasi dosen adalah advisor dari student
-	if student.AdvisorID == nil || *student.AdvisorID != lecturer.ID {
-		return nil, errors.New("anda bukan dosen wali dari mahasiswa ini")
+	// Validasi dosen adalah advisor dari salah satu peserta, atau verifikator eskalasi
+	advised, err := s.verifiableParticipants(ctx, userID, achievement, isLecturer, lecturer)
+	if err != nil {
+		return nil, err
 	}
 
-	// Update status ke verified
-	achievement.Status = model.StatusVerified
-	if err := s.achievementRepo.UpdateAchievement(ctx, achievementID, achievement); err != nil {
-		return nil, fmt.Errorf("gagal mengupdate status: %v", err)
+	now := time.Now()
+	var delegated *onBehalfOf
+	if isLecturer {
+		delegated = s.delegatedAdvisors(ctx, lecturer, advised)
+	}
+
+	if achievement.IsTeam() {
+		confirmedNow := s.confirmTeamMembers(achievement, advised, userID, now)
+		if len(confirmedNow) == 0 {
+			return nil, errors.New("mahasiswa bimbingan anda pada prestasi tim ini sudah dikonfirmasi")
+		}
+		if isLecturer {
+			delegated = s.delegatedAdvisors(ctx, lecturer, confirmedNow)
+		}
+
+		if err := s.updateAllReferences(ctx, achievementID, func(reference *model.AchievementReference) {
+			for _, id := range confirmedNow {
+				if reference.StudentID.String() == id {
+					reference.VerifiedAt = &now
+					reference.VerifiedBy = &userID
+				}
+			}
+		}); err != nil {
+			return nil, err
+		}
+
+		if !allMembersConfirmed(achievement) {
+			return s.recordTeamConfirmation(ctx, userID, achievement, confirmedNow, student, delegated)
+		}
+	}
+
+	return s.completeApprovalStage(ctx, userID, achievement, reference, student, model.ApprovalStageAdvisor, delegated)
+}
+
+// completeApprovalStage menyelesaikan satu tahap: lanjut ke tahap berikutnya atau
+// memfinalisasi verifikasi jika tahap tersebut adalah tahap terakhir chain
+// delegated diisi jika tahap advisor diselesaikan dosen pengganti.
+func (s *achievementService) completeApprovalStage(ctx context.Context, userID uuid.UUID, achievement *model.Achievement, reference *model.AchievementReference, student *model.Student, stage model.ApprovalStage, delegated *onBehalfOf) (*AchievementResponse, error) {
+	achievementID := achievement.ID.Hex()
+	now := time.Now()
+
+	stages := reference.ApprovalStages
+	if len(stages) == 0 {
+		stages = defaultApprovalStages
+	}
+
+	next := stages.Next(stage)
+	if next == nil {
+		return s.finalizeVerification(ctx, userID, achievement, student, stage, delegated)
+	}
+
+	// Konfirmasi anggota tim terakhir hanya ada di memori (confirmTeamMembers), simpan
+	// sebelum reference dipindahkan ke tahap berikutnya
+	if stage == model.ApprovalStageAdvisor && achievement.IsTeam() {
+		if err := s.achievementRepo.UpdateAchievement(ctx, achievementID, achievement); err != nil {
+			return nil, fmt.Errorf("gagal menyimpan konfirmasi anggota: %v", err)
+		}
+	}
+
+	// Masih ada tahap berikutnya, status tetap submitted
+	if err := s.updateAllReferences(ctx, achievementID, func(reference *model.AchievementReference) {
+		reference.CurrentStage = next
+		// SLA dihitung ulang dari awal tahap baru, notifikasinya juga sekali per tahap
+		reference.StageStartedAt = &now
+		reference.SLAFlaggedAt = nil
+		reference.EscalatedAt = nil
+		reference.EscalatedTo = nil
+		if stage == model.ApprovalStageAdvisor && reference.VerifiedAt == nil {
+			reference.VerifiedAt = &now
+			reference.VerifiedBy = &userID
+		}
+	}); err != nil {
+		return nil, err
 	}
 
-	// Update reference
 	reference, err := s.achievementRepo.FindReferenceByMongoID(ctx, achievementID)
 	if err != nil {
 		return nil, errors.New("gagal memuat reference")
 	}
 
+	oldStatus := model.StatusSubmitted
+	history := &model.AchievementHistory{
+		AchievementRefID:   reference.ID,
+		MongoAchievementID: achievementID,
+		OldStatus:          &oldStatus,
+		NewStatus:          model.StatusSubmitted,
+		ChangedBy:          userID,
+		Stage:              &stage,
+		Notes:              fmt.Sprintf("Disetujui %s, menunggu persetujuan %s", approvalStageLabel(stage), approvalStageLabel(*next)),
+	}
+	delegated.apply(history)
+
+	if err := s.historyRepo.CreateHistory(ctx, history); err != nil {
+		fmt.Printf("Warning: Gagal membuat history: %v\n", err)
+	}
+
+	updatedAchievement, err := s.achievementRepo.FindAchievementByID(ctx, achievementID)
+	if err != nil {
+		return nil, errors.New("gagal memuat achievement setelah update")
+	}
+
+	result := s.mapToAchievementResponse(ctx, updatedAchievement, reference, student)
+	return result, nil
+}
+
+// finalizeVerification menghitung poin dan menandai achievement verified setelah tahap terakhir
+func (s *achievementService) finalizeVerification(ctx context.Context, userID uuid.UUID, achievement *model.Achievement, student *model.Student, stage model.ApprovalStage, delegated *onBehalfOf) (*AchievementResponse, error) {
+	achievementID := achievement.ID.Hex()
 	now := time.Now()
-	reference.Status = model.StatusVerified
-	reference.VerifiedAt = &now
-	reference.VerifiedBy = &userID
 
-	if err := s.achievementRepo.UpdateReference(ctx, reference); err != nil {
-		return nil, fmt.Errorf("gagal mengupdate reference: %v", err)
+	// Hitung poin berdasarkan aturan poin
+	points, rule, err := s.pointRuleService.CalculatePoints(ctx, achievement)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update status ke verified
+	achievement.Status = model.StatusVerified
+	achievement.Points = points
+	if err := s.achievementRepo.UpdateAchievement(ctx, achievementID, achievement); err != nil {
+		return nil, fmt.Errorf("gagal mengupdate status: %v", err)
+	}
+
+	// Update reference seluruh peserta
+	if err := s.updateAllReferences(ctx, achievementID, func(reference *model.AchievementReference) {
+		reference.Status = model.StatusVerified
+		reference.CurrentStage = nil
+		reference.StageStartedAt = nil
+		if reference.VerifiedAt == nil {
+			reference.VerifiedAt = &now
+			reference.VerifiedBy = &userID
+		}
+	}); err != nil {
+		return nil, err
+	}
+
+	reference, err := s.achievementRepo.FindReferenceByMongoID(ctx, achievementID)
+	if err != nil {
+		return nil, errors.New("gagal memuat reference")
 	}
 
 	// Create history
@@ -599,30 +954,114 @@ func (s *achievementService) VerifyAchievement(ctx context.Context, userID uuid.
 		OldStatus:          &oldStatus,
 		NewStatus:          model.StatusVerified,
 		ChangedBy:          userID,
-		Notes:              "Achievement diverifikasi",
+		Stage:              &stage,
+		Notes:              fmt.Sprintf("Achievement diverifikasi (poin: %.2f%s)", points, pointRuleNote(rule)),
 	}
+	delegated.apply(history)
 
 	if err := s.historyRepo.CreateHistory(ctx, history); err != nil {
 		fmt.Printf("Warning: Gagal membuat history: %v\n", err)
 	}
 
+	// Sertifikat yang gagal dibuat tidak membatalkan verifikasi, dapat dibuat ulang saat diunduh
+	if err := s.certificateService.IssueCertificates(ctx, achievementID, userID); err != nil {
+		fmt.Printf("Warning: Gagal membuat sertifikat: %v\n", err)
+	}
+	if err := s.badgeService.IssueBadges(ctx, achievementID); err != nil {
+		fmt.Printf("Warning: Gagal menerbitkan badge: %v\n", err)
+	}
+
 	// Get updated achievement
 	updatedAchievement, err := s.achievementRepo.FindAchievementByID(ctx, achievementID)
 	if err != nil {
 		return nil, errors.New("gagal memuat achievement setelah update")
 	}
 
+	s.saveVersion(ctx, updatedAchievement, model.VersionKindVerification, userID)
+
+	result := s.mapToAchievementResponse(ctx, updatedAchievement, reference, student)
+	return result, nil
+}
+
+// confirmTeamMembers menandai anggota bimbingan dosen yang belum dikonfirmasi
+func (s *achievementService) confirmTeamMembers(achievement *model.Achievement, advised []string, userID uuid.UUID, now time.Time) []string {
+	verifiedBy := userID.String()
+	confirmed := []string{}
+	for i := range achievement.Members {
+		member := &achievement.Members[i]
+		if member.VerifiedAt != nil {
+			continue
+		}
+		for _, id := range advised {
+			if member.StudentID == id {
+				member.VerifiedAt = &now
+				member.VerifiedBy = &verifiedBy
+				confirmed = append(confirmed, id)
+				break
+			}
+		}
+	}
+	return confirmed
+}
+
+// recordTeamConfirmation menyimpan konfirmasi sebagian anggota; status tetap submitted
+func (s *achievementService) recordTeamConfirmation(ctx context.Context, userID uuid.UUID, achievement *model.Achievement, confirmed []string, student *model.Student, delegated *onBehalfOf) (*AchievementResponse, error) {
+	achievementID := achievement.ID.Hex()
+	if err := s.achievementRepo.UpdateAchievement(ctx, achievementID, achievement); err != nil {
+		return nil, fmt.Errorf("gagal menyimpan konfirmasi anggota: %v", err)
+	}
+
+	reference, err := s.achievementRepo.FindReferenceByMongoID(ctx, achievementID)
+	if err != nil {
+		return nil, errors.New("gagal memuat reference")
+	}
+
+	nims := []string{}
+	for _, member := range mapTeamMembers(ctx, s.studentRepo, achievement.Members) {
+		for _, id := range confirmed {
+			if member.StudentID == id {
+				nims = append(nims, member.NIM)
+			}
+		}
+	}
+
+	confirmedCount := 0
+	for _, member := range achievement.Members {
+		if member.VerifiedAt != nil {
+			confirmedCount++
+		}
+	}
+
+	oldStatus := model.StatusSubmitted
+	advisorStage := model.ApprovalStageAdvisor
+	history := &model.AchievementHistory{
+		AchievementRefID:   reference.ID,
+		MongoAchievementID: achievementID,
+		OldStatus:          &oldStatus,
+		NewStatus:          model.StatusSubmitted,
+		ChangedBy:          userID,
+		Stage:              &advisorStage,
+		Notes:              fmt.Sprintf("Anggota tim dikonfirmasi dosen wali: %s (%d/%d anggota)", strings.Join(nims, ", "), confirmedCount, len(achievement.Members)),
+	}
+	delegated.apply(history)
+
+	if err := s.historyRepo.CreateHistory(ctx, history); err != nil {
+		fmt.Printf("Warning: Gagal membuat history: %v\n", err)
+	}
+
+	updatedAchievement, err := s.achievementRepo.FindAchievementByID(ctx, achievementID)
+	if err != nil {
+		return nil, errors.New("gagal memuat achievement setelah update")
+	}
+
 	result := s.mapToAchievementResponse(ctx, updatedAchievement, reference, student)
 	return result, nil
 }
 
 // RejectAchievement (FR-008)
+// Untuk prestasi tim, dosen wali salah satu anggota dapat menolak seluruh achievement.
 func (s *achievementService) RejectAchievement(ctx context.Context, userID uuid.UUID, achievementID string, rejectionNote string) (*AchievementResponse, error) {
-	// Validasi user adalah dosen wali
-	isLecturer, lecturer, err := s.isLecturer(ctx, userID)
-	if !isLecturer || err != nil {
-		return nil, errors.New("hanya dosen wali yang dapat menolak prestasi")
-	}
+	isLecturer, lecturer, _ := s.isLecturer(ctx, userID)
 
 	// Validasi rejection note
 	if rejectionNote == "" {
@@ -640,41 +1079,71 @@ func (s *achievementService) RejectAchievement(ctx context.Context, userID uuid.
 		return nil, errors.New("hanya achievement dengan status submitted yang dapat ditolak")
 	}
 
-	// Get student
+	reference, err := s.achievementRepo.FindReferenceByMongoID(ctx, achievementID)
+	if err != nil {
+		return nil, errors.New("gagal memuat reference")
+	}
+
+	if !isPendingStage(reference, model.ApprovalStageAdvisor) {
+		return nil, errors.New("achievement sudah melewati tahap verifikasi dosen wali")
+	}
+
+	// Get student (pembuat achievement)
 	studentUUID, err := uuid.Parse(achievement.StudentID)
 	if err != nil {
 		return nil, errors.New("student ID tidak valid")
 	}
 
-		student, err := s.studentRepo.FindStudentByID(ctx, studentUUID)
+	student, err := s.studentRepo.FindStudentByID(ctx, studentUUID)
 	if err != nil {
 		return nil, errors.New("student tidak ditemukan")
 	}
 
-	// Validasi dosen adalah advisor dari student
-	if student.AdvisorID == nil || *student.AdvisorID != lecturer.ID {
-		return nil, errors.New("anda bukan dosen wali dari mahasiswa ini")
+	// Validasi dosen adalah advisor dari salah satu peserta, atau verifikator eskalasi
+	advised, err := s.verifiableParticipants(ctx, userID, achievement, isLecturer, lecturer)
+	if err != nil {
+		return nil, err
+	}
+
+	var delegated *onBehalfOf
+	if isLecturer {
+		delegated = s.delegatedAdvisors(ctx, lecturer, advised)
 	}
 
-	// Update status ke rejected
+	return s.rejectAtStage(ctx, userID, achievement, student, rejectionNote, model.ApprovalStageAdvisor, delegated)
+}
+
+// rejectAtStage menolak achievement pada tahap mana pun; mahasiswa merevisi lalu submit ulang dari tahap pertama
+func (s *achievementService) rejectAtStage(ctx context.Context, userID uuid.UUID, achievement *model.Achievement, student *model.Student, rejectionNote string, stage model.ApprovalStage, delegated *onBehalfOf) (*AchievementResponse, error) {
+	achievementID := achievement.ID.Hex()
+
+	// Update status ke rejected, konfirmasi anggota diulang setelah submit ulang
 	achievement.Status = model.StatusRejected
+	resetMemberConfirmations(achievement)
 	if err := s.achievementRepo.UpdateAchievement(ctx, achievementID, achievement); err != nil {
 		return nil, fmt.Errorf("gagal mengupdate status: %v", err)
 	}
 
-	// Update reference
+	// Update reference seluruh peserta
+	if err := s.updateAllReferences(ctx, achievementID, func(reference *model.AchievementReference) {
+		reference.Status = model.StatusRejected
+		reference.RejectionNote = rejectionNote
+		reference.CurrentStage = nil
+		reference.StageStartedAt = nil
+		reference.VerifiedAt = nil
+		reference.VerifiedBy = nil
+		reference.SLAFlaggedAt = nil
+		reference.EscalatedAt = nil
+		reference.EscalatedTo = nil
+	}); err != nil {
+		return nil, err
+	}
+
 	reference, err := s.achievementRepo.FindReferenceByMongoID(ctx, achievementID)
 	if err != nil {
 		return nil, errors.New("gagal memuat reference")
 	}
 
-	reference.Status = model.StatusRejected
-	reference.RejectionNote = rejectionNote
-
-	if err := s.achievementRepo.UpdateReference(ctx, reference); err != nil {
-		return nil, fmt.Errorf("gagal mengupdate reference: %v", err)
-	}
-
 	// Create history
 	oldStatus := model.StatusSubmitted
 	history := &model.AchievementHistory{
@@ -683,8 +1152,10 @@ func (s *achievementService) RejectAchievement(ctx context.Context, userID uuid.
 		OldStatus:          &oldStatus,
 		NewStatus:          model.StatusRejected,
 		ChangedBy:          userID,
-		Notes:              fmt.Sprintf("Achievement ditolak: %s", rejectionNote),
+		Stage:              &stage,
+		Notes:              fmt.Sprintf("Achievement ditolak oleh %s: %s", approvalStageLabel(stage), rejectionNote),
 	}
+	delegated.apply(history)
 
 	if err := s.historyRepo.CreateHistory(ctx, history); err != nil {
 		fmt.Printf("Warning: Gagal membuat history: %v\n", err)
@@ -738,6 +1209,9 @@ func (s *achievementService) GetAchievements(ctx context.Context, userID uuid.UU
 			return nil, fmt.Errorf("gagal memuat references: %v", err)
 		}
 
+		// Samakan urutan reference dengan achievement (termasuk prestasi tim)
+		achievements, references = alignReferences(achievements, references)
+
 		total = int64(len(achievements))
 	} else if role == "lecturer" {
 		// Dosen wali melihat achievement mahasiswa bimbingannya
@@ -746,10 +1220,10 @@ func (s *achievementService) GetAchievements(ctx context.Context, userID uuid.UU
 			return nil, errors.New("user tidak ditemukan sebagai dosen")
 		}
 
-		// Get advisees
-		advisees, err := s.lecturerRepo.FindAdvisees(ctx, lecturer.ID)
+		// Get advisees, termasuk bimbingan dosen yang sedang diwakili
+		advisees, err := s.actingAdvisees(ctx, lecturer)
 		if err != nil {
-			return nil, fmt.Errorf("gagal memuat mahasiswa bimbingan: %v", err)
+			return nil, err
 		}
 
 		if len(advisees) == 0 {
@@ -866,53 +1340,11 @@ func (s *achievementService) GetAchievementByID(ctx context.Context, userID uuid
 	}
 
 	// Check access
-	role, err := s.checkRole(ctx, userID)
+	student, err := s.authorizeAchievementAccess(ctx, userID, achievement)
 	if err != nil {
 		return nil, err
 	}
 
-	if role == "student" {
-		// Mahasiswa hanya bisa lihat achievement sendiri
-		isStudent, student, err := s.isStudent(ctx, userID)
-		if !isStudent || err != nil {
-			return nil, errors.New("user tidak ditemukan sebagai mahasiswa")
-		}
-
-		if achievement.StudentID != student.ID.String() {
-			return nil, errors.New("anda tidak memiliki akses untuk melihat achievement ini")
-		}
-
-		result := s.mapToAchievementResponse(ctx, achievement, reference, student)
-		return result, nil
-	} else if role == "lecturer" {
-		// Dosen wali hanya bisa lihat achievement mahasiswa bimbingannya
-		isLecturer, lecturer, err := s.isLecturer(ctx, userID)
-		if !isLecturer || err != nil {
-			return nil, errors.New("user tidak ditemukan sebagai dosen")
-		}
-
-		studentUUID, err := uuid.Parse(achievement.StudentID)
-		if err != nil {
-			return nil, errors.New("student ID tidak valid")
-		}
-
-		student, err := s.studentRepo.FindStudentByID(ctx, studentUUID)
-		if err != nil {
-			return nil, errors.New("student tidak ditemukan")
-		}
-
-		if student.AdvisorID == nil || *student.AdvisorID != lecturer.ID {
-			return nil, errors.New("anda bukan dosen wali dari mahasiswa ini")
-		}
-
-		result := s.mapToAchievementResponse(ctx, achievement, reference, student)
-		return result, nil
-	}
-
-	// Admin bisa lihat semua
-	studentUUID, _ := uuid.Parse(achievement.StudentID)
-	student, _ := s.studentRepo.FindStudentByID(ctx, studentUUID)
-
 	result := s.mapToAchievementResponse(ctx, achievement, reference, student)
 	return result, nil
 }
@@ -926,49 +1358,13 @@ func (s *achievementService) GetAchievementHistory(ctx context.Context, userID u
 	}
 
 	// Check access (same as GetAchievementByID)
-	role, err := s.checkRole(ctx, userID)
+	achievement, err := s.achievementRepo.FindAchievementByID(ctx, achievementID)
 	if err != nil {
-		return nil, err
+		return nil, errors.New("achievement tidak ditemukan")
 	}
 
-	if role == "student" {
-		isStudent, student, err := s.isStudent(ctx, userID)
-		if !isStudent || err != nil {
-			return nil, errors.New("user tidak ditemukan sebagai mahasiswa")
-		}
-
-		achievement, err := s.achievementRepo.FindAchievementByID(ctx, achievementID)
-		if err != nil {
-			return nil, errors.New("achievement tidak ditemukan")
-		}
-
-		if achievement.StudentID != student.ID.String() {
-			return nil, errors.New("anda tidak memiliki akses untuk melihat history achievement ini")
-		}
-	} else if role == "lecturer" {
-		isLecturer, lecturer, err := s.isLecturer(ctx, userID)
-		if !isLecturer || err != nil {
-			return nil, errors.New("user tidak ditemukan sebagai dosen")
-		}
-
-		achievement, err := s.achievementRepo.FindAchievementByID(ctx, achievementID)
-		if err != nil {
-			return nil, errors.New("achievement tidak ditemukan")
-		}
-
-		studentUUID, err := uuid.Parse(achievement.StudentID)
-		if err != nil {
-			return nil, errors.New("student ID tidak valid")
-		}
-
-		student, err := s.studentRepo.FindStudentByID(ctx, studentUUID)
-		if err != nil {
-			return nil, errors.New("student tidak ditemukan")
-		}
-
-		if student.AdvisorID == nil || *student.AdvisorID != lecturer.ID {
-			return nil, errors.New("anda bukan dosen wali dari mahasiswa ini")
-		}
+	if _, err := s.authorizeAchievementAccess(ctx, userID, achievement); err != nil {
+		return nil, err
 	}
 
 	// Get history
@@ -989,6 +1385,12 @@ func (s *achievementService) GetAchievementHistory(ctx context.Context, userID u
 			}
 		}
 
+		var onBehalfOf *string
+		if history.OnBehalfOf != nil {
+			id := history.OnBehalfOf.String()
+			onBehalfOf = &id
+		}
+
 		response = append(response, AchievementHistoryResponse{
 			ID:            history.ID.String(),
 			OldStatus:     history.OldStatus,
@@ -996,6 +1398,9 @@ func (s *achievementService) GetAchievementHistory(ctx context.Context, userID u
 			ChangedBy:     history.ChangedBy.String(),
 			ChangedByUser: changedByUser,
 			Notes:         history.Notes,
+			Stage:         history.Stage,
+			OnBehalfOf:    onBehalfOf,
+			IsOverride:    history.IsOverride,
 			CreatedAt:     history.CreatedAt,
 		})
 	}
@@ -1003,64 +1408,133 @@ func (s *achievementService) GetAchievementHistory(ctx context.Context, userID u
 	return response, nil
 }
 
-// UploadAttachment
-func (s *achievementService) UploadAttachment(ctx context.Context, userID uuid.UUID, achievementID string, filePath string) (string, error) {
+// UploadAttachment menyimpan file ke blob store lalu menambahkannya ke attachment achievement.
+// FileURL attachment yang dikembalikan berisi key penyimpanan.
+func (s *achievementService) UploadAttachment(ctx context.Context, userID uuid.UUID, achievementID string, fileName string, content io.Reader) (*model.Attachment, error) {
 	// Validasi user adalah mahasiswa
 	isStudent, student, err := s.isStudent(ctx, userID)
 	if !isStudent || err != nil {
-		return "", errors.New("hanya mahasiswa yang dapat upload attachment")
+		return nil, errors.New("hanya mahasiswa yang dapat upload attachment")
 	}
 
 	// Get achievement
 	achievement, err := s.achievementRepo.FindAchievementByID(ctx, achievementID)
 	if err != nil {
-		return "", errors.New("achievement tidak ditemukan")
+		return nil, errors.New("achievement tidak ditemukan")
 	}
 
 	// Validasi ownership
 	if achievement.StudentID != student.ID.String() {
-		return "", errors.New("anda tidak memiliki akses untuk upload attachment ke achievement ini")
+		return nil, errors.New("anda tidak memiliki akses untuk upload attachment ke achievement ini")
 	}
 
-	// Validasi status adalah draft
-	if achievement.Status != model.StatusDraft {
-		return "", errors.New("hanya achievement dengan status draft yang dapat diupdate attachment")
+	// Validasi status adalah draft atau rejected (revisi)
+	if !isEditableStatus(achievement.Status) {
+		return nil, errors.New("hanya achievement dengan status draft atau rejected yang dapat diupdate attachment")
 	}
 
-	// Create attachment object
-	attachment := model.Attachment{
-		FileName:   filePath,
-		FileURL:    filePath, // In production, this should be full URL
-		FileType:   getFileTypeFromPath(filePath),
-		UploadedAt: time.Now(),
+	attachment, err := s.putAttachment(ctx, achievementID, fileName, content)
+	if err != nil {
+		return nil, err
 	}
 
+	s.ensureBaselineVersion(ctx, achievement, userID)
+
 	// Update achievement attachments
 	achievement.Attachments = append(achievement.Attachments, attachment)
 	if err := s.achievementRepo.UpdateAchievement(ctx, achievementID, achievement); err != nil {
-		return "", fmt.Errorf("gagal mengupdate achievement: %v", err)
+		// Rollback: hapus file yang sudah tersimpan
+		if deleteErr := s.blobStore.Delete(ctx, attachment.FileURL); deleteErr != nil {
+			fmt.Printf("Warning: Gagal menghapus file %s: %v\n", attachment.FileURL, deleteErr)
+		}
+		return nil, fmt.Errorf("gagal mengupdate achievement: %v", err)
+	}
+
+	s.saveVersion(ctx, achievement, model.VersionKindRevision, userID)
+
+	return &attachment, nil
+}
+
+// GetAchievementVersions mengembalikan seluruh versi konten achievement, dari yang terlama
+func (s *achievementService) GetAchievementVersions(ctx context.Context, userID uuid.UUID, achievementID string) ([]model.AchievementVersion, error) {
+	achievement, err := s.achievementRepo.FindAchievementByID(ctx, achievementID)
+	if err != nil {
+		return nil, errors.New("achievement tidak ditemukan")
+	}
+
+	if _, err := s.authorizeAchievementAccess(ctx, userID, achievement); err != nil {
+		return nil, err
+	}
+
+	versions, err := s.versionRepo.FindVersionsByAchievementID(ctx, achievementID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal memuat versi: %v", err)
 	}
 
-	return filePath, nil
+	return versions, nil
 }
 
-// Helper function to get file type from path
-func getFileTypeFromPath(filePath string) string {
-	ext := strings.ToLower(filepath.Ext(filePath))
-	switch ext {
-	case ".pdf":
-		return "application/pdf"
-	case ".jpg", ".jpeg":
-		return "image/jpeg"
-	case ".png":
-		return "image/png"
-	case ".doc":
-		return "application/msword"
-	case ".docx":
-		return "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
-	default:
-		return "application/octet-stream"
+// GetAchievementVersionDiff membandingkan versi tertentu dengan versi lain.
+// Jika against bernilai 0, versi pembanding adalah versi sebelumnya.
+func (s *achievementService) GetAchievementVersionDiff(ctx context.Context, userID uuid.UUID, achievementID string, version int, against int) (*AchievementVersionDiffResponse, error) {
+	achievement, err := s.achievementRepo.FindAchievementByID(ctx, achievementID)
+	if err != nil {
+		return nil, errors.New("achievement tidak ditemukan")
+	}
+
+	if _, err := s.authorizeAchievementAccess(ctx, userID, achievement); err != nil {
+		return nil, err
+	}
+
+	target, err := s.versionRepo.FindVersion(ctx, achievementID, version)
+	if err != nil {
+		return nil, errors.New("versi tidak ditemukan")
 	}
+
+	if against == 0 {
+		against = version - 1
+	}
+
+	// Versi pertama dibandingkan dengan achievement kosong
+	base := &model.AchievementVersion{}
+	if against > 0 {
+		base, err = s.versionRepo.FindVersion(ctx, achievementID, against)
+		if err != nil {
+			return nil, errors.New("versi pembanding tidak ditemukan")
+		}
+	}
+
+	return &AchievementVersionDiffResponse{
+		AchievementID: achievementID,
+		FromVersion:   base.Version,
+		ToVersion:     target.Version,
+		Changes:       diffVersions(base, target),
+	}, nil
+}
+
+// pointRuleNote keterangan aturan poin yang dipakai untuk catatan history
+func pointRuleNote(rule *model.PointRule) string {
+	if rule == nil {
+		return ", tidak ada aturan yang cocok"
+	}
+	return ", aturan: " + rule.Name
+}
+
+// attachmentKeyPrefix awalan key blob store untuk attachment achievement
+const attachmentKeyPrefix = "achievements/"
+
+// legacyUploadDir awalan FileURL lama yang berisi path disk relatif (uploads/achievements/...)
+const legacyUploadDir = "uploads/"
+
+// attachmentKey key blob store dari FileURL. FileURL lama dipetakan ke key yang sama dengan
+// file-nya pada local store berakar di uploads, sehingga tetap terbaca sebelum dimigrasi.
+// Path lama dari server Windows memakai backslash sebagai pemisah.
+func attachmentKey(fileURL string) (string, error) {
+	key := strings.TrimPrefix(path.Clean(strings.ReplaceAll(fileURL, "\\", "/")), legacyUploadDir)
+	if err := storage.ValidateKey(key); err != nil {
+		return "", err
+	}
+	return key, nil
 }
 
 // Helper: mapToAchievementResponse
@@ -1091,6 +1565,11 @@ func (s *achievementService) mapToAchievementResponse(ctx context.Context, achie
 			VerifiedAt:    reference.VerifiedAt,
 			VerifiedBy:    verifiedBy,
 			RejectionNote: reference.RejectionNote,
+			ApprovalStages: reference.ApprovalStages,
+			CurrentStage:  reference.CurrentStage,
+			StageStartedAt: reference.StageStartedAt,
+			SLAFlaggedAt:  reference.SLAFlaggedAt,
+			EscalatedAt:   reference.EscalatedAt,
 		}
 	}
 
@@ -1102,11 +1581,13 @@ func (s *achievementService) mapToAchievementResponse(ctx context.Context, achie
 		Title:           achievement.Title,
 		Description:     achievement.Description,
 		Details:         achievement.Details,
-		Attachments:     achievement.Attachments,
+		Attachments:     withAttachmentIDs(achievement.Attachments),
 		Tags:            achievement.Tags,
+		Members:         mapTeamMembers(ctx, s.studentRepo, achievement.Members),
 		Points:          achievement.Points,
 		Status:          achievement.Status,
 		Reference:       refInfo,
+		Expired:         certificationExpired(achievement, time.Now()),
 		CreatedAt:       achievement.CreatedAt,
 		UpdatedAt:       achievement.UpdatedAt,
 	}
@@ -0,0 +1,303 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/repository"
+)
+
+// maxTeamMembers batas jumlah anggota prestasi tim
+const maxTeamMembers = 30
+
+type TeamMemberRequest struct {
+	StudentID string         `json:"student_id"` // NIM mahasiswa
+	Role      model.TeamRole `json:"role"`       // leader atau member (default member)
+}
+
+type TeamMemberResponse struct {
+	StudentID  string         `json:"student_id"`
+	NIM        string         `json:"nim"`
+	FullName   string         `json:"full_name"`
+	Role       model.TeamRole `json:"role"`
+	Confirmed  bool           `json:"confirmed"`
+	VerifiedBy *string        `json:"verified_by,omitempty"`
+	VerifiedAt *time.Time     `json:"verified_at,omitempty"`
+}
+
+// resolveTeamMembers mengubah daftar NIM menjadi anggota tim. Pembuat otomatis
+// ditambahkan jika belum ada. Daftar kosong berarti prestasi individu.
+func (s *achievementService) resolveTeamMembers(ctx context.Context, creator *model.Student, reqs []TeamMemberRequest) ([]model.TeamMember, error) {
+	if len(reqs) == 0 {
+		return nil, nil
+	}
+
+	var errs fieldErrors
+	members := []model.TeamMember{}
+	seen := make(map[string]bool)
+	leaders := 0
+
+	for i, req := range reqs {
+		field := fmt.Sprintf("members[%d]", i)
+		role := req.Role
+		if role == "" {
+			role = model.TeamRoleMember
+		}
+		if role != model.TeamRoleLeader && role != model.TeamRoleMember {
+			errs.add(field+".role", "tidak valid. Pilih: leader, member")
+			continue
+		}
+
+		nim := strings.TrimSpace(req.StudentID)
+		if nim == "" {
+			errs.add(field+".student_id", "harus diisi (NIM)")
+			continue
+		}
+
+		student, err := s.studentRepo.FindStudentByStudentID(ctx, nim)
+		if err != nil {
+			errs.add(field+".student_id", "mahasiswa dengan NIM "+nim+" tidak ditemukan")
+			continue
+		}
+		if seen[student.ID.String()] {
+			errs.add(field+".student_id", "anggota duplikat")
+			continue
+		}
+		seen[student.ID.String()] = true
+
+		if role == model.TeamRoleLeader {
+			leaders++
+		}
+		members = append(members, model.TeamMember{StudentID: student.ID.String(), Role: role})
+	}
+
+	if !seen[creator.ID.String()] {
+		role := model.TeamRoleMember
+		if leaders == 0 {
+			role = model.TeamRoleLeader
+			leaders++
+		}
+		members = append([]model.TeamMember{{StudentID: creator.ID.String(), Role: role}}, members...)
+	}
+
+	if leaders != 1 {
+		errs.add("members", "harus memiliki tepat satu leader")
+	}
+	if len(members) < 2 {
+		errs.add("members", "prestasi tim minimal memiliki dua anggota")
+	}
+	if len(members) > maxTeamMembers {
+		errs.add("members", fmt.Sprintf("maksimal %d anggota", maxTeamMembers))
+	}
+
+	if err := errs.err(); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// syncTeamReferences memastikan setiap peserta memiliki reference di PostgreSQL
+// sehingga prestasi muncul di portofolio masing-masing anggota.
+func (s *achievementService) syncTeamReferences(ctx context.Context, achievement *model.Achievement) error {
+	achievementID := achievement.ID.Hex()
+	references, err := s.achievementRepo.FindReferencesByMongoID(ctx, achievementID)
+	if err != nil {
+		return fmt.Errorf("gagal memuat reference: %v", err)
+	}
+
+	participants := make(map[string]bool)
+	for _, id := range achievement.ParticipantIDs() {
+		participants[id] = true
+	}
+
+	existing := make(map[string]bool)
+	for _, reference := range references {
+		studentID := reference.StudentID.String()
+		existing[studentID] = true
+
+		// Reference pembuat tidak pernah dihapus
+		if !participants[studentID] && studentID != achievement.StudentID {
+			if err := s.achievementRepo.PurgeReference(ctx, reference.ID); err != nil {
+				fmt.Printf("Warning: Gagal menghapus reference anggota: %v\n", err)
+			}
+		}
+	}
+
+	for _, id := range achievement.ParticipantIDs() {
+		if existing[id] {
+			continue
+		}
+		studentUUID, err := uuid.Parse(id)
+		if err != nil {
+			continue
+		}
+		reference := &model.AchievementReference{
+			StudentID:          studentUUID,
+			MongoAchievementID: achievementID,
+			Status:             achievement.Status,
+		}
+		if err := s.achievementRepo.CreateReference(ctx, reference); err != nil {
+			return fmt.Errorf("gagal menyimpan reference anggota: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// updateAllReferences menerapkan perubahan ke seluruh reference (satu per anggota)
+func (s *achievementService) updateAllReferences(ctx context.Context, achievementID string, apply func(reference *model.AchievementReference)) error {
+	references, err := s.achievementRepo.FindReferencesByMongoID(ctx, achievementID)
+	if err != nil {
+		return fmt.Errorf("gagal memuat reference: %v", err)
+	}
+
+	for i := range references {
+		apply(&references[i])
+		if err := s.achievementRepo.UpdateReference(ctx, &references[i]); err != nil {
+			return fmt.Errorf("gagal mengupdate reference: %v", err)
+		}
+	}
+	return nil
+}
+
+// isParticipant memeriksa apakah mahasiswa adalah pembuat atau anggota tim
+func isParticipant(achievement *model.Achievement, studentID string) bool {
+	if achievement.StudentID == studentID {
+		return true
+	}
+	for _, member := range achievement.Members {
+		if member.StudentID == studentID {
+			return true
+		}
+	}
+	return false
+}
+
+// advisedParticipants mengembalikan peserta yang merupakan mahasiswa bimbingan dosen,
+// termasuk bimbingan dosen lain yang sedang didelegasikan kepadanya
+func (s *achievementService) advisedParticipants(ctx context.Context, achievement *model.Achievement, lecturer *model.Lecturer) []string {
+	actingFor := s.actingForLecturers(ctx, lecturer)
+	advised := []string{}
+	for _, id := range achievement.ParticipantIDs() {
+		studentUUID, err := uuid.Parse(id)
+		if err != nil {
+			continue
+		}
+		student, err := s.studentRepo.FindStudentByID(ctx, studentUUID)
+		if err != nil {
+			continue
+		}
+		if student.AdvisorID != nil && actingFor[*student.AdvisorID] != nil {
+			advised = append(advised, id)
+		}
+	}
+	return advised
+}
+
+// allMembersConfirmed true jika seluruh anggota tim sudah dikonfirmasi dosen walinya
+func allMembersConfirmed(achievement *model.Achievement) bool {
+	for _, member := range achievement.Members {
+		if member.VerifiedAt == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// resetMemberConfirmations menghapus konfirmasi anggota saat submit ulang atau ditolak
+func resetMemberConfirmations(achievement *model.Achievement) {
+	for i := range achievement.Members {
+		achievement.Members[i].VerifiedBy = nil
+		achievement.Members[i].VerifiedAt = nil
+	}
+}
+
+// alignReferences memasangkan achievement dengan reference milik mahasiswa berdasarkan Mongo ID
+func alignReferences(achievements []model.Achievement, references []model.AchievementReference) ([]model.Achievement, []model.AchievementReference) {
+	refMap := make(map[string]model.AchievementReference)
+	for _, reference := range references {
+		refMap[reference.MongoAchievementID] = reference
+	}
+
+	alignedAchievements := []model.Achievement{}
+	alignedReferences := []model.AchievementReference{}
+	for _, achievement := range achievements {
+		reference, ok := refMap[achievement.ID.Hex()]
+		if !ok {
+			continue
+		}
+		alignedAchievements = append(alignedAchievements, achievement)
+		alignedReferences = append(alignedReferences, reference)
+	}
+	return alignedAchievements, alignedReferences
+}
+
+// mapTeamMembers melengkapi anggota tim dengan NIM dan nama
+func mapTeamMembers(ctx context.Context, studentRepo repository.StudentRepository, members []model.TeamMember) []TeamMemberResponse {
+	if len(members) == 0 {
+		return nil
+	}
+
+	result := make([]TeamMemberResponse, 0, len(members))
+	for _, member := range members {
+		response := TeamMemberResponse{
+			StudentID:  member.StudentID,
+			Role:       member.Role,
+			Confirmed:  member.VerifiedAt != nil,
+			VerifiedBy: member.VerifiedBy,
+			VerifiedAt: member.VerifiedAt,
+		}
+		if studentUUID, err := uuid.Parse(member.StudentID); err == nil {
+			if student, err := studentRepo.FindStudentByID(ctx, studentUUID); err == nil {
+				response.NIM = student.StudentID
+				response.FullName = student.User.FullName
+			}
+		}
+		result = append(result, response)
+	}
+	return result
+}
+
+// verifiableParticipants mengembalikan peserta yang dapat diverifikasi user: mahasiswa
+// bimbingan dosen, atau seluruh peserta jika verifikasi sudah dieskalasi ke user tersebut.
+func (s *achievementService) verifiableParticipants(ctx context.Context, userID uuid.UUID, achievement *model.Achievement, isLecturer bool, lecturer *model.Lecturer) ([]string, error) {
+	if isLecturer {
+		if advised := s.advisedParticipants(ctx, achievement, lecturer); len(advised) > 0 {
+			return advised, nil
+		}
+	}
+
+	if s.canHandleEscalation(ctx, userID, achievement.ID.Hex()) {
+		return achievement.ParticipantIDs(), nil
+	}
+
+	if !isLecturer {
+		return nil, errors.New("hanya dosen wali yang dapat memverifikasi prestasi")
+	}
+	return nil, errors.New("anda bukan dosen wali dari mahasiswa ini")
+}
+
+// canHandleEscalation true jika verifikasi achievement sudah dieskalasi dan user adalah
+// admin atau verifikator pengganti yang ditunjuk
+func (s *achievementService) canHandleEscalation(ctx context.Context, userID uuid.UUID, achievementID string) bool {
+	references, err := s.achievementRepo.FindReferencesByMongoID(ctx, achievementID)
+	if err != nil {
+		return false
+	}
+
+	role, _ := s.checkRole(ctx, userID)
+	for _, reference := range references {
+		if reference.EscalatedAt == nil {
+			continue
+		}
+		if role == "admin" || (reference.EscalatedTo != nil && *reference.EscalatedTo == userID) {
+			return true
+		}
+	}
+	return false
+}
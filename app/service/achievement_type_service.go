@@ -0,0 +1,467 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type AchievementTypeService interface {
+	GetAllTypes(ctx context.Context, includeInactive bool) ([]AchievementTypeResponse, error)
+	GetTypeByCode(ctx context.Context, code string) (*AchievementTypeResponse, error)
+	CreateType(ctx context.Context, userID uuid.UUID, req *AchievementTypeRequest) (*AchievementTypeResponse, error)
+	UpdateType(ctx context.Context, code string, req *AchievementTypeRequest) (*AchievementTypeResponse, error)
+	DeactivateType(ctx context.Context, code string) error
+}
+
+type achievementTypeService struct {
+	typeRepo repository.AchievementTypeRepository
+}
+
+func NewAchievementTypeService(typeRepo repository.AchievementTypeRepository) AchievementTypeService {
+	return &achievementTypeService{
+		typeRepo: typeRepo,
+	}
+}
+
+type AchievementTypeRequest struct {
+	Code        string                  `json:"code"`
+	Name        string                  `json:"name"`
+	Description string                  `json:"description"`
+	Fields      []model.FieldDefinition `json:"fields"`
+	IsActive    *bool                   `json:"is_active,omitempty"`
+}
+
+// AchievementTypeResponse dipakai frontend untuk merender form secara dinamis.
+// Untuk tipe bawaan, Fields menggambarkan field di details; untuk tipe custom, field di details.custom_fields.
+type AchievementTypeResponse struct {
+	Code        model.AchievementType   `json:"code"`
+	Name        string                  `json:"name"`
+	Description string                  `json:"description"`
+	BuiltIn     bool                    `json:"built_in"`
+	FieldsPath  string                  `json:"fields_path"`
+	Fields      []model.FieldDefinition `json:"fields"`
+	IsActive    bool                    `json:"is_active"`
+	CreatedAt   *time.Time              `json:"created_at,omitempty"`
+	UpdatedAt   *time.Time              `json:"updated_at,omitempty"`
+}
+
+var achievementTypeCodePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{2,49}$`)
+var fieldKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+var validFieldTypes = []model.FieldType{
+	model.FieldTypeString,
+	model.FieldTypeNumber,
+	model.FieldTypeInteger,
+	model.FieldTypeBoolean,
+	model.FieldTypeDate,
+	model.FieldTypeEnum,
+	model.FieldTypeArray,
+}
+
+func (s *achievementTypeService) GetAllTypes(ctx context.Context, includeInactive bool) ([]AchievementTypeResponse, error) {
+	types := builtInAchievementTypeResponses()
+
+	definitions, err := s.typeRepo.FindAllTypes(ctx, !includeInactive)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil tipe prestasi: %v", err)
+	}
+
+	for i := range definitions {
+		types = append(types, *toAchievementTypeResponse(&definitions[i]))
+	}
+	return types, nil
+}
+
+func (s *achievementTypeService) GetTypeByCode(ctx context.Context, code string) (*AchievementTypeResponse, error) {
+	for _, t := range builtInAchievementTypeResponses() {
+		if string(t.Code) == code {
+			return &t, nil
+		}
+	}
+
+	definition, err := s.typeRepo.FindTypeByCode(ctx, model.AchievementType(code))
+	if err != nil {
+		return nil, errors.New("tipe prestasi tidak ditemukan")
+	}
+	return toAchievementTypeResponse(definition), nil
+}
+
+func (s *achievementTypeService) CreateType(ctx context.Context, userID uuid.UUID, req *AchievementTypeRequest) (*AchievementTypeResponse, error) {
+	code := strings.TrimSpace(req.Code)
+
+	var errs fieldErrors
+	if !achievementTypeCodePattern.MatchString(code) {
+		errs.add("code", "harus 3-50 karakter huruf kecil, angka atau underscore dan diawali huruf")
+	} else if isBuiltInAchievementType(model.AchievementType(code)) {
+		errs.add("code", "sudah digunakan oleh tipe bawaan")
+	}
+	validateTypeDefinitionRequest(req, &errs)
+	if err := errs.err(); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.typeRepo.FindTypeByCode(ctx, model.AchievementType(code)); err == nil {
+		return nil, errors.New("code tipe prestasi sudah digunakan")
+	}
+
+	definition := &model.AchievementTypeDefinition{
+		Code:        model.AchievementType(code),
+		Name:        strings.TrimSpace(req.Name),
+		Description: strings.TrimSpace(req.Description),
+		Fields:      normalizeFieldDefinitions(req.Fields),
+		IsActive:    true,
+		CreatedBy:   userID.String(),
+	}
+	if req.IsActive != nil {
+		definition.IsActive = *req.IsActive
+	}
+
+	created, err := s.typeRepo.CreateType(ctx, definition)
+	if err != nil {
+		return nil, fmt.Errorf("gagal menyimpan tipe prestasi: %v", err)
+	}
+	return toAchievementTypeResponse(created), nil
+}
+
+// UpdateType mengganti nama, deskripsi dan schema field. Code tidak dapat diubah
+// karena sudah tersimpan di achievement yang ada.
+func (s *achievementTypeService) UpdateType(ctx context.Context, code string, req *AchievementTypeRequest) (*AchievementTypeResponse, error) {
+	if isBuiltInAchievementType(model.AchievementType(code)) {
+		return nil, errors.New("tipe prestasi bawaan tidak dapat diubah")
+	}
+
+	definition, err := s.typeRepo.FindTypeByCode(ctx, model.AchievementType(code))
+	if err != nil {
+		return nil, errors.New("tipe prestasi tidak ditemukan")
+	}
+
+	var errs fieldErrors
+	if req.Code != "" && req.Code != code {
+		errs.add("code", "tidak dapat diubah")
+	}
+	validateTypeDefinitionRequest(req, &errs)
+	if err := errs.err(); err != nil {
+		return nil, err
+	}
+
+	definition.Name = strings.TrimSpace(req.Name)
+	definition.Description = strings.TrimSpace(req.Description)
+	definition.Fields = normalizeFieldDefinitions(req.Fields)
+	if req.IsActive != nil {
+		definition.IsActive = *req.IsActive
+	}
+
+	if err := s.typeRepo.UpdateType(ctx, definition); err != nil {
+		return nil, fmt.Errorf("gagal mengupdate tipe prestasi: %v", err)
+	}
+	return toAchievementTypeResponse(definition), nil
+}
+
+// DeactivateType menonaktifkan tipe custom. Tipe tidak dihapus agar achievement
+// lama tetap dapat ditampilkan dan divalidasi.
+func (s *achievementTypeService) DeactivateType(ctx context.Context, code string) error {
+	if isBuiltInAchievementType(model.AchievementType(code)) {
+		return errors.New("tipe prestasi bawaan tidak dapat dinonaktifkan")
+	}
+
+	definition, err := s.typeRepo.FindTypeByCode(ctx, model.AchievementType(code))
+	if err != nil {
+		return errors.New("tipe prestasi tidak ditemukan")
+	}
+
+	definition.IsActive = false
+	if err := s.typeRepo.UpdateType(ctx, definition); err != nil {
+		return fmt.Errorf("gagal menonaktifkan tipe prestasi: %v", err)
+	}
+	return nil
+}
+
+func validateTypeDefinitionRequest(req *AchievementTypeRequest, errs *fieldErrors) {
+	if strings.TrimSpace(req.Name) == "" {
+		errs.add("name", "harus diisi")
+	}
+	if len(req.Fields) == 0 {
+		errs.add("fields", "minimal satu field")
+	}
+
+	seen := make(map[string]bool)
+	for i, field := range req.Fields {
+		prefix := fmt.Sprintf("fields[%d]", i)
+		key := strings.TrimSpace(field.Key)
+
+		if !fieldKeyPattern.MatchString(key) {
+			errs.add(prefix+".key", "harus huruf kecil, angka atau underscore dan diawali huruf")
+		} else if seen[key] {
+			errs.add(prefix+".key", "duplikat")
+		}
+		seen[key] = true
+
+		if strings.TrimSpace(field.Label) == "" {
+			errs.add(prefix+".label", "harus diisi")
+		}
+		if !isValidFieldType(field.Type) {
+			errs.add(prefix+".type", "tidak valid. Pilih: string, number, integer, boolean, date, enum, array")
+		}
+		if field.Type == model.FieldTypeEnum && len(field.Enum) == 0 {
+			errs.add(prefix+".enum", "harus diisi untuk field bertipe enum")
+		}
+		if field.Min != nil && field.Max != nil && *field.Min > *field.Max {
+			errs.add(prefix+".min", "tidak boleh lebih besar dari max")
+		}
+		if field.MaxLength != nil && *field.MaxLength <= 0 {
+			errs.add(prefix+".max_length", "harus lebih dari 0")
+		}
+	}
+}
+
+func normalizeFieldDefinitions(fields []model.FieldDefinition) []model.FieldDefinition {
+	normalized := make([]model.FieldDefinition, 0, len(fields))
+	for _, field := range fields {
+		field.Key = strings.TrimSpace(field.Key)
+		field.Label = strings.TrimSpace(field.Label)
+		if field.Type != model.FieldTypeEnum {
+			field.Enum = nil
+		}
+		normalized = append(normalized, field)
+	}
+	return normalized
+}
+
+func isValidFieldType(fieldType model.FieldType) bool {
+	for _, t := range validFieldTypes {
+		if fieldType == t {
+			return true
+		}
+	}
+	return false
+}
+
+// validateCustomFields memvalidasi details.custom_fields terhadap schema tipe custom
+func validateCustomFields(definition *model.AchievementTypeDefinition, values map[string]interface{}, errs *fieldErrors) {
+	known := make(map[string]bool)
+	for _, field := range definition.Fields {
+		known[field.Key] = true
+		name := "details.custom_fields." + field.Key
+
+		value, ok := values[field.Key]
+		if !ok || value == nil || isEmptyValue(value) {
+			if field.Required {
+				errs.add(name, "harus diisi")
+			}
+			continue
+		}
+
+		validateCustomFieldValue(name, &field, value, errs)
+	}
+
+	for key := range values {
+		if !known[key] {
+			errs.add("details.custom_fields."+key, "field tidak dikenal untuk tipe "+string(definition.Code))
+		}
+	}
+}
+
+func validateCustomFieldValue(name string, field *model.FieldDefinition, value interface{}, errs *fieldErrors) {
+	switch field.Type {
+	case model.FieldTypeString:
+		s, ok := value.(string)
+		if !ok {
+			errs.add(name, "harus berupa teks")
+			return
+		}
+		if field.MaxLength != nil && len([]rune(s)) > *field.MaxLength {
+			errs.add(name, fmt.Sprintf("maksimal %d karakter", *field.MaxLength))
+		}
+	case model.FieldTypeNumber, model.FieldTypeInteger:
+		n, ok := toFloat(value)
+		if !ok {
+			errs.add(name, "harus berupa angka")
+			return
+		}
+		if field.Type == model.FieldTypeInteger && n != math.Trunc(n) {
+			errs.add(name, "harus berupa bilangan bulat")
+			return
+		}
+		if field.Min != nil && n < *field.Min {
+			errs.add(name, fmt.Sprintf("minimal %v", *field.Min))
+		}
+		if field.Max != nil && n > *field.Max {
+			errs.add(name, fmt.Sprintf("maksimal %v", *field.Max))
+		}
+	case model.FieldTypeBoolean:
+		if _, ok := value.(bool); !ok {
+			errs.add(name, "harus berupa true atau false")
+		}
+	case model.FieldTypeDate:
+		s, ok := value.(string)
+		if !ok {
+			if dt, isDate := value.(primitive.DateTime); isDate {
+				validateDate(name, dt.Time(), errs)
+				return
+			}
+			errs.add(name, "harus berupa tanggal (YYYY-MM-DD)")
+			return
+		}
+		date, err := parseFieldDate(s)
+		if err != nil {
+			errs.add(name, "harus berupa tanggal (YYYY-MM-DD)")
+			return
+		}
+		validateDate(name, date, errs)
+	case model.FieldTypeEnum:
+		s, ok := value.(string)
+		if !ok || !containsString(field.Enum, s) {
+			errs.add(name, "tidak valid. Pilih: "+strings.Join(field.Enum, ", "))
+		}
+	case model.FieldTypeArray:
+		items, ok := toInterfaceSlice(value)
+		if !ok {
+			errs.add(name, "harus berupa daftar teks")
+			return
+		}
+		for _, item := range items {
+			if s, isString := item.(string); !isString || strings.TrimSpace(s) == "" {
+				errs.add(name, "setiap item harus berupa teks yang tidak kosong")
+				return
+			}
+		}
+	}
+}
+
+func parseFieldDate(value string) (time.Time, error) {
+	if date, err := time.Parse("2006-01-02", value); err == nil {
+		return date, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// toFloat menerima angka hasil decode JSON (float64) maupun BSON (int32/int64)
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+func toInterfaceSlice(value interface{}) ([]interface{}, bool) {
+	switch v := value.(type) {
+	case []interface{}:
+		return v, true
+	case primitive.A:
+		return []interface{}(v), true
+	case []string:
+		items := make([]interface{}, len(v))
+		for i, s := range v {
+			items[i] = s
+		}
+		return items, true
+	}
+	return nil, false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func toAchievementTypeResponse(definition *model.AchievementTypeDefinition) *AchievementTypeResponse {
+	createdAt := definition.CreatedAt
+	updatedAt := definition.UpdatedAt
+	fields := definition.Fields
+	if fields == nil {
+		fields = []model.FieldDefinition{}
+	}
+	return &AchievementTypeResponse{
+		Code:        definition.Code,
+		Name:        definition.Name,
+		Description: definition.Description,
+		BuiltIn:     false,
+		FieldsPath:  "details.custom_fields",
+		Fields:      fields,
+		IsActive:    definition.IsActive,
+		CreatedAt:   &createdAt,
+		UpdatedAt:   &updatedAt,
+	}
+}
+
+// builtInAchievementTypeResponses menjelaskan field details untuk tipe bawaan
+// (sesuai aturan di achievement_validator.go)
+func builtInAchievementTypeResponses() []AchievementTypeResponse {
+	eventDate := model.FieldDefinition{Key: "event_date", Label: "Tanggal Kegiatan", Type: model.FieldTypeDate}
+	location := model.FieldDefinition{Key: "location", Label: "Lokasi", Type: model.FieldTypeString}
+	organizer := model.FieldDefinition{Key: "organizer", Label: "Penyelenggara", Type: model.FieldTypeString}
+	score := model.FieldDefinition{Key: "score", Label: "Nilai", Type: model.FieldTypeNumber}
+
+	levels := make([]string, 0, len(validCompetitionLevels))
+	for _, l := range validCompetitionLevels {
+		levels = append(levels, string(l))
+	}
+	publicationTypes := make([]string, 0, len(validPublicationTypes))
+	for _, t := range validPublicationTypes {
+		publicationTypes = append(publicationTypes, string(t))
+	}
+
+	builtIn := func(code model.AchievementType, name string, fields ...model.FieldDefinition) AchievementTypeResponse {
+		return AchievementTypeResponse{
+			Code:       code,
+			Name:       name,
+			BuiltIn:    true,
+			FieldsPath: "details",
+			Fields:     fields,
+			IsActive:   true,
+		}
+	}
+
+	return []AchievementTypeResponse{
+		builtIn(model.AchievementTypeAcademic, "Akademik", eventDate, location, organizer, score),
+		builtIn(model.AchievementTypeCompetition, "Kompetisi",
+			model.FieldDefinition{Key: "competition_name", Label: "Nama Kompetisi", Type: model.FieldTypeString, Required: true},
+			model.FieldDefinition{Key: "competition_level", Label: "Tingkat", Type: model.FieldTypeEnum, Required: true, Enum: levels},
+			model.FieldDefinition{Key: "rank", Label: "Peringkat", Type: model.FieldTypeInteger},
+			model.FieldDefinition{Key: "medal_type", Label: "Medali", Type: model.FieldTypeString},
+			eventDate, location, organizer),
+		builtIn(model.AchievementTypeOrganization, "Organisasi",
+			model.FieldDefinition{Key: "organization_name", Label: "Nama Organisasi", Type: model.FieldTypeString, Required: true},
+			model.FieldDefinition{Key: "position", Label: "Jabatan", Type: model.FieldTypeString, Required: true},
+			model.FieldDefinition{Key: "period.start", Label: "Mulai Menjabat", Type: model.FieldTypeDate, Required: true},
+			model.FieldDefinition{Key: "period.end", Label: "Selesai Menjabat", Type: model.FieldTypeDate}),
+		builtIn(model.AchievementTypePublication, "Publikasi",
+			model.FieldDefinition{Key: "publication_title", Label: "Judul Publikasi", Type: model.FieldTypeString, Required: true},
+			model.FieldDefinition{Key: "authors", Label: "Penulis", Type: model.FieldTypeArray, Required: true},
+			model.FieldDefinition{Key: "publication_type", Label: "Jenis Publikasi", Type: model.FieldTypeEnum, Required: true, Enum: publicationTypes},
+			model.FieldDefinition{Key: "publisher", Label: "Penerbit", Type: model.FieldTypeString},
+			model.FieldDefinition{Key: "issn", Label: "ISSN", Type: model.FieldTypeString},
+			model.FieldDefinition{Key: "doi", Label: "DOI", Type: model.FieldTypeString},
+			eventDate),
+		builtIn(model.AchievementTypeCertification, "Sertifikasi",
+			model.FieldDefinition{Key: "certification_name", Label: "Nama Sertifikasi", Type: model.FieldTypeString, Required: true},
+			model.FieldDefinition{Key: "issued_by", Label: "Diterbitkan Oleh", Type: model.FieldTypeString, Required: true},
+			model.FieldDefinition{Key: "certification_number", Label: "Nomor Sertifikat", Type: model.FieldTypeString},
+			model.FieldDefinition{Key: "valid_until", Label: "Berlaku Sampai", Type: model.FieldTypeDate},
+			eventDate),
+		builtIn(model.AchievementTypeOther, "Lainnya", eventDate, location, organizer),
+	}
+}
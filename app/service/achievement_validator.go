@@ -0,0 +1,252 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+)
+
+// FieldError kesalahan validasi untuk satu field (nama field mengikuti JSON request)
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError kumpulan kesalahan validasi yang dikembalikan ke client secara terstruktur
+type ValidationError struct {
+	Errors []FieldError `json:"errors"`
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Errors) == 1 {
+		return "validasi gagal: " + e.Errors[0].Field + " " + e.Errors[0].Message
+	}
+	return "validasi gagal, periksa kembali data prestasi"
+}
+
+type fieldErrors []FieldError
+
+func (f *fieldErrors) add(field, message string) {
+	*f = append(*f, FieldError{Field: field, Message: message})
+}
+
+func (f fieldErrors) err() error {
+	if len(f) == 0 {
+		return nil
+	}
+	return &ValidationError{Errors: f}
+}
+
+var builtInAchievementTypes = []model.AchievementType{
+	model.AchievementTypeAcademic,
+	model.AchievementTypeCompetition,
+	model.AchievementTypeOrganization,
+	model.AchievementTypePublication,
+	model.AchievementTypeCertification,
+	model.AchievementTypeOther,
+}
+
+var validCompetitionLevels = []model.CompetitionLevel{
+	model.CompetitionLevelInternational,
+	model.CompetitionLevelNational,
+	model.CompetitionLevelRegional,
+	model.CompetitionLevelLocal,
+}
+
+var validPublicationTypes = []model.PublicationType{
+	model.PublicationTypeJournal,
+	model.PublicationTypeConference,
+	model.PublicationTypeBook,
+}
+
+// earliestEventDate batas bawah tanggal yang masih masuk akal untuk prestasi mahasiswa
+var earliestEventDate = time.Date(1950, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func isBuiltInAchievementType(achievementType model.AchievementType) bool {
+	for _, t := range builtInAchievementTypes {
+		if achievementType == t {
+			return true
+		}
+	}
+	return false
+}
+
+// validateAchievement memvalidasi field umum dan details sesuai tipe prestasi.
+// customType berisi definisi tipe custom jika achievement_type bukan tipe bawaan.
+func validateAchievement(achievement *model.Achievement, customType *model.AchievementTypeDefinition) error {
+	var errs fieldErrors
+
+	if strings.TrimSpace(achievement.Title) == "" {
+		errs.add("title", "harus diisi")
+	}
+	if strings.TrimSpace(achievement.Description) == "" {
+		errs.add("description", "harus diisi")
+	}
+
+	if achievement.AchievementType == "" {
+		errs.add("achievement_type", "harus diisi")
+	} else if !isBuiltInAchievementType(achievement.AchievementType) && customType == nil {
+		errs.add("achievement_type", "tidak valid. Pilih tipe bawaan atau tipe dari GET /api/v1/achievement-types")
+	}
+
+	validateCommonDetails(&achievement.Details, &errs)
+
+	switch achievement.AchievementType {
+	case model.AchievementTypeCompetition:
+		validateCompetitionDetails(&achievement.Details, &errs)
+	case model.AchievementTypePublication:
+		validatePublicationDetails(&achievement.Details, &errs)
+	case model.AchievementTypeOrganization:
+		validateOrganizationDetails(&achievement.Details, &errs)
+	case model.AchievementTypeCertification:
+		validateCertificationDetails(&achievement.Details, &errs)
+	}
+
+	if customType != nil {
+		validateCustomFields(customType, achievement.Details.CustomFields, &errs)
+	}
+
+	return errs.err()
+}
+
+func validateCommonDetails(details *model.AchievementDetails, errs *fieldErrors) {
+	if details.EventDate != nil {
+		validateDate("details.event_date", *details.EventDate, errs)
+	}
+	if details.Score != nil && *details.Score < 0 {
+		errs.add("details.score", "tidak boleh negatif")
+	}
+}
+
+func validateCompetitionDetails(details *model.AchievementDetails, errs *fieldErrors) {
+	if isBlank(details.CompetitionName) {
+		errs.add("details.competition_name", "harus diisi untuk prestasi kompetisi")
+	}
+
+	if details.CompetitionLevel == nil || *details.CompetitionLevel == "" {
+		errs.add("details.competition_level", "harus diisi untuk prestasi kompetisi")
+	} else if !isValidCompetitionLevel(*details.CompetitionLevel) {
+		errs.add("details.competition_level", "tidak valid. Pilih: international, national, regional, local")
+	}
+
+	if details.Rank != nil && *details.Rank <= 0 {
+		errs.add("details.rank", "harus lebih dari 0")
+	}
+}
+
+func validatePublicationDetails(details *model.AchievementDetails, errs *fieldErrors) {
+	if isBlank(details.PublicationTitle) {
+		errs.add("details.publication_title", "harus diisi untuk prestasi publikasi")
+	}
+
+	if len(details.Authors) == 0 {
+		errs.add("details.authors", "minimal satu penulis")
+	}
+	for _, author := range details.Authors {
+		if strings.TrimSpace(author) == "" {
+			errs.add("details.authors", "nama penulis tidak boleh kosong")
+			break
+		}
+	}
+
+	if details.PublicationType == nil || *details.PublicationType == "" {
+		errs.add("details.publication_type", "harus diisi untuk prestasi publikasi")
+	} else if !isValidPublicationType(*details.PublicationType) {
+		errs.add("details.publication_type", "tidak valid. Pilih: journal, conference, book")
+	}
+
+	if details.ISSN != nil && strings.TrimSpace(*details.ISSN) != "" && !isValidISSN(*details.ISSN) {
+		errs.add("details.issn", "format atau check digit ISSN tidak valid")
+	}
+}
+
+func validateOrganizationDetails(details *model.AchievementDetails, errs *fieldErrors) {
+	if isBlank(details.OrganizationName) {
+		errs.add("details.organization_name", "harus diisi untuk prestasi organisasi")
+	}
+	if isBlank(details.Position) {
+		errs.add("details.position", "harus diisi untuk prestasi organisasi")
+	}
+
+	if details.Period == nil || details.Period.Start.IsZero() {
+		errs.add("details.period.start", "harus diisi untuk prestasi organisasi")
+		return
+	}
+
+	validateDate("details.period.start", details.Period.Start, errs)
+	// End kosong berarti masih menjabat
+	if !details.Period.End.IsZero() && details.Period.End.Before(details.Period.Start) {
+		errs.add("details.period.end", "tidak boleh sebelum tanggal mulai")
+	}
+}
+
+func validateCertificationDetails(details *model.AchievementDetails, errs *fieldErrors) {
+	if isBlank(details.CertificationName) {
+		errs.add("details.certification_name", "harus diisi untuk prestasi sertifikasi")
+	}
+	if isBlank(details.IssuedBy) {
+		errs.add("details.issued_by", "harus diisi untuk prestasi sertifikasi")
+	}
+	if details.ValidUntil != nil && details.EventDate != nil && details.ValidUntil.Before(*details.EventDate) {
+		errs.add("details.valid_until", "tidak boleh sebelum tanggal terbit (event_date)")
+	}
+}
+
+// validateDate memastikan tanggal tidak di masa depan dan tidak terlalu lampau
+func validateDate(field string, date time.Time, errs *fieldErrors) {
+	if date.Before(earliestEventDate) {
+		errs.add(field, "tanggal tidak masuk akal")
+		return
+	}
+	// Toleransi satu hari untuk perbedaan zona waktu
+	if date.After(time.Now().Add(24 * time.Hour)) {
+		errs.add(field, "tidak boleh di masa depan")
+	}
+}
+
+func isValidCompetitionLevel(level model.CompetitionLevel) bool {
+	for _, l := range validCompetitionLevels {
+		if level == l {
+			return true
+		}
+	}
+	return false
+}
+
+func isValidPublicationType(publicationType model.PublicationType) bool {
+	for _, t := range validPublicationTypes {
+		if publicationType == t {
+			return true
+		}
+	}
+	return false
+}
+
+// isValidISSN memeriksa format NNNN-NNNC dan check digit modulo 11
+func isValidISSN(issn string) bool {
+	value := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(issn), "-", ""))
+	if len(value) != 8 {
+		return false
+	}
+
+	sum := 0
+	for i := 0; i < 7; i++ {
+		c := value[i]
+		if c < '0' || c > '9' {
+			return false
+		}
+		sum += int(c-'0') * (8 - i)
+	}
+
+	check := (11 - sum%11) % 11
+	last := value[7]
+	if check == 10 {
+		return last == 'X'
+	}
+	return last == byte('0'+check)
+}
+
+func isBlank(value *string) bool {
+	return value == nil || strings.TrimSpace(*value) == ""
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+)
+
+func TestIsValidISSN(t *testing.T) {
+	tests := []struct {
+		issn string
+		want bool
+	}{
+		{"0378-5955", true},
+		{"03785955", true},
+		{" 2049-3630 ", true},
+		{"2434-561X", true},
+		{"2434-561x", true},
+		{"0378-5954", false},
+		{"2434-5610", false},
+		{"0378-595", false},
+		{"0378-59555", false},
+		{"03A8-5955", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := isValidISSN(tt.issn); got != tt.want {
+			t.Errorf("isValidISSN(%q) = %v, want %v", tt.issn, got, tt.want)
+		}
+	}
+}
+
+func TestValidateAchievement(t *testing.T) {
+	national := model.CompetitionLevelNational
+	unknownLevel := model.CompetitionLevel("galaxy")
+	journal := model.PublicationTypeJournal
+	future := time.Now().AddDate(0, 1, 0)
+	past := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	ancient := time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC)
+	zero := 0
+	negative := -1.0
+
+	tests := []struct {
+		name        string
+		achievement model.Achievement
+		customType  *model.AchievementTypeDefinition
+		wantFields  []string
+	}{
+		{
+			name: "competition lengkap",
+			achievement: model.Achievement{
+				AchievementType: model.AchievementTypeCompetition,
+				Title:           "Juara 1 Gemastik",
+				Description:     "Kompetisi nasional",
+				Details: model.AchievementDetails{
+					CompetitionName:  strPtr("Gemastik"),
+					CompetitionLevel: &national,
+					EventDate:        &past,
+				},
+			},
+		},
+		{
+			name:        "field umum kosong",
+			achievement: model.Achievement{},
+			wantFields:  []string{"title", "description", "achievement_type"},
+		},
+		{
+			name: "tipe tidak dikenal tanpa definisi custom",
+			achievement: model.Achievement{
+				AchievementType: "hackathon",
+				Title:           "Hackathon",
+				Description:     "Hackathon kampus",
+			},
+			wantFields: []string{"achievement_type"},
+		},
+		{
+			name: "competition tanpa nama, level tidak valid dan rank 0",
+			achievement: model.Achievement{
+				AchievementType: model.AchievementTypeCompetition,
+				Title:           "Lomba",
+				Description:     "Lomba",
+				Details: model.AchievementDetails{
+					CompetitionLevel: &unknownLevel,
+					Rank:             &zero,
+				},
+			},
+			wantFields: []string{"details.competition_name", "details.competition_level", "details.rank"},
+		},
+		{
+			name: "publication dengan ISSN salah dan penulis kosong",
+			achievement: model.Achievement{
+				AchievementType: model.AchievementTypePublication,
+				Title:           "Paper",
+				Description:     "Paper jurnal",
+				Details: model.AchievementDetails{
+					PublicationTitle: strPtr("Deteksi Plagiarisme"),
+					PublicationType:  &journal,
+					Authors:          []string{"Sari", " "},
+					ISSN:             strPtr("0378-5954"),
+				},
+			},
+			wantFields: []string{"details.authors", "details.issn"},
+		},
+		{
+			name: "organization periode terbalik",
+			achievement: model.Achievement{
+				AchievementType: model.AchievementTypeOrganization,
+				Title:           "Ketua BEM",
+				Description:     "Organisasi",
+				Details: model.AchievementDetails{
+					OrganizationName: strPtr("BEM"),
+					Position:         strPtr("Ketua"),
+					Period: &model.Period{
+						Start: past,
+						End:   past.AddDate(0, -1, 0),
+					},
+				},
+			},
+			wantFields: []string{"details.period.end"},
+		},
+		{
+			name: "organization tanpa periode",
+			achievement: model.Achievement{
+				AchievementType: model.AchievementTypeOrganization,
+				Title:           "Ketua BEM",
+				Description:     "Organisasi",
+				Details: model.AchievementDetails{
+					OrganizationName: strPtr("BEM"),
+					Position:         strPtr("Ketua"),
+				},
+			},
+			wantFields: []string{"details.period.start"},
+		},
+		{
+			name: "certification kedaluwarsa sebelum terbit",
+			achievement: model.Achievement{
+				AchievementType: model.AchievementTypeCertification,
+				Title:           "Sertifikasi",
+				Description:     "Sertifikasi cloud",
+				Details: model.AchievementDetails{
+					CertificationName: strPtr("Cloud Practitioner"),
+					IssuedBy:          strPtr("AWS"),
+					EventDate:         &past,
+					ValidUntil:        &ancient,
+				},
+			},
+			wantFields: []string{"details.valid_until"},
+		},
+		{
+			name: "tanggal di masa depan dan skor negatif",
+			achievement: model.Achievement{
+				AchievementType: model.AchievementTypeOther,
+				Title:           "Lainnya",
+				Description:     "Lainnya",
+				Details: model.AchievementDetails{
+					EventDate: &future,
+					Score:     &negative,
+				},
+			},
+			wantFields: []string{"details.event_date", "details.score"},
+		},
+		{
+			name: "tanggal terlalu lampau",
+			achievement: model.Achievement{
+				AchievementType: model.AchievementTypeAcademic,
+				Title:           "IPK",
+				Description:     "IPK terbaik",
+				Details:         model.AchievementDetails{EventDate: &ancient},
+			},
+			wantFields: []string{"details.event_date"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAchievement(&tt.achievement, tt.customType)
+			if len(tt.wantFields) == 0 {
+				if err != nil {
+					t.Fatalf("validateAchievement() error = %v, want nil", err)
+				}
+				return
+			}
+
+			validationErr, ok := err.(*ValidationError)
+			if !ok {
+				t.Fatalf("validateAchievement() error = %v, want *ValidationError", err)
+			}
+			got := map[string]bool{}
+			for _, fieldErr := range validationErr.Errors {
+				got[fieldErr.Field] = true
+			}
+			for _, field := range tt.wantFields {
+				if !got[field] {
+					t.Errorf("field %s tidak dilaporkan, errors = %+v", field, validationErr.Errors)
+				}
+			}
+			if len(got) != len(tt.wantFields) {
+				t.Errorf("errors = %+v, want field %v", validationErr.Errors, tt.wantFields)
+			}
+		})
+	}
+}
+
+func strPtr(value string) *string {
+	return &value
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/repository"
+)
+
+type ApprovalChainService interface {
+	GetAllChains(ctx context.Context) ([]model.ApprovalChain, error)
+	CreateChain(ctx context.Context, req *ApprovalChainRequest) (*model.ApprovalChain, error)
+	UpdateChain(ctx context.Context, chainID uuid.UUID, req *ApprovalChainRequest) (*model.ApprovalChain, error)
+	DeleteChain(ctx context.Context, chainID uuid.UUID) error
+	ResolveStages(ctx context.Context, achievement *model.Achievement) (model.ApprovalStages, error)
+}
+
+type approvalChainService struct {
+	chainRepo repository.ApprovalChainRepository
+}
+
+func NewApprovalChainService(chainRepo repository.ApprovalChainRepository) ApprovalChainService {
+	return &approvalChainService{
+		chainRepo: chainRepo,
+	}
+}
+
+type ApprovalChainRequest struct {
+	Name             string                  `json:"name"`
+	AchievementType  model.AchievementType   `json:"achievement_type"`
+	CompetitionLevel *model.CompetitionLevel `json:"competition_level,omitempty"`
+	Stages           model.ApprovalStages    `json:"stages"`
+	IsActive         *bool                   `json:"is_active,omitempty"`
+}
+
+// defaultApprovalStages dipakai jika tidak ada chain yang cocok: cukup dosen wali
+var defaultApprovalStages = model.ApprovalStages{model.ApprovalStageAdvisor}
+
+var validApprovalStages = []model.ApprovalStage{
+	model.ApprovalStageAdvisor,
+	model.ApprovalStageProgramHead,
+	model.ApprovalStageStudentAffairs,
+}
+
+func (s *approvalChainService) GetAllChains(ctx context.Context) ([]model.ApprovalChain, error) {
+	chains, err := s.chainRepo.FindAllChains(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil approval chain: %v", err)
+	}
+	return chains, nil
+}
+
+func (s *approvalChainService) CreateChain(ctx context.Context, req *ApprovalChainRequest) (*model.ApprovalChain, error) {
+	if err := validateApprovalChainRequest(req); err != nil {
+		return nil, err
+	}
+
+	chain := &model.ApprovalChain{IsActive: true}
+	applyApprovalChainRequest(chain, req)
+
+	if err := s.chainRepo.CreateChain(ctx, chain); err != nil {
+		return nil, fmt.Errorf("gagal menyimpan approval chain: %v", err)
+	}
+
+	// GORM mengabaikan nilai false untuk kolom dengan default, simpan ulang secara eksplisit
+	if !chain.IsActive {
+		if err := s.chainRepo.UpdateChain(ctx, chain); err != nil {
+			return nil, fmt.Errorf("gagal menyimpan approval chain: %v", err)
+		}
+	}
+	return chain, nil
+}
+
+func (s *approvalChainService) UpdateChain(ctx context.Context, chainID uuid.UUID, req *ApprovalChainRequest) (*model.ApprovalChain, error) {
+	chain, err := s.chainRepo.FindChainByID(ctx, chainID)
+	if err != nil {
+		return nil, errors.New("approval chain tidak ditemukan")
+	}
+
+	if err := validateApprovalChainRequest(req); err != nil {
+		return nil, err
+	}
+
+	applyApprovalChainRequest(chain, req)
+
+	if err := s.chainRepo.UpdateChain(ctx, chain); err != nil {
+		return nil, fmt.Errorf("gagal mengupdate approval chain: %v", err)
+	}
+	return chain, nil
+}
+
+func (s *approvalChainService) DeleteChain(ctx context.Context, chainID uuid.UUID) error {
+	if _, err := s.chainRepo.FindChainByID(ctx, chainID); err != nil {
+		return errors.New("approval chain tidak ditemukan")
+	}
+
+	if err := s.chainRepo.DeleteChain(ctx, chainID); err != nil {
+		return fmt.Errorf("gagal menghapus approval chain: %v", err)
+	}
+	return nil
+}
+
+// ResolveStages memilih chain aktif untuk achievement. Chain dengan tingkat kompetisi
+// yang cocok lebih diutamakan daripada chain tanpa tingkat.
+func (s *approvalChainService) ResolveStages(ctx context.Context, achievement *model.Achievement) (model.ApprovalStages, error) {
+	chains, err := s.chainRepo.FindActiveChainsByType(ctx, achievement.AchievementType)
+	if err != nil {
+		return nil, fmt.Errorf("gagal memuat approval chain: %v", err)
+	}
+
+	var generic *model.ApprovalChain
+	for i := range chains {
+		chain := &chains[i]
+		if chain.CompetitionLevel == nil {
+			if generic == nil {
+				generic = chain
+			}
+			continue
+		}
+		level := achievement.Details.CompetitionLevel
+		if level != nil && strings.EqualFold(string(*chain.CompetitionLevel), string(*level)) {
+			return chain.Stages, nil
+		}
+	}
+
+	if generic != nil {
+		return generic.Stages, nil
+	}
+	return defaultApprovalStages, nil
+}
+
+func validateApprovalChainRequest(req *ApprovalChainRequest) error {
+	if strings.TrimSpace(req.Name) == "" {
+		return errors.New("name harus diisi")
+	}
+	if req.AchievementType == "" {
+		return errors.New("achievement_type harus diisi")
+	}
+	if len(req.Stages) == 0 {
+		return errors.New("stages minimal satu tahap")
+	}
+	// Tahap pertama selalu dosen wali karena verifikasi awal dilakukan oleh dosen wali
+	if req.Stages[0] != model.ApprovalStageAdvisor {
+		return errors.New("tahap pertama harus advisor")
+	}
+
+	seen := make(map[model.ApprovalStage]bool)
+	for _, stage := range req.Stages {
+		if !isValidApprovalStage(stage) {
+			return fmt.Errorf("stage %s tidak valid. Pilih: advisor, program_head, student_affairs", stage)
+		}
+		if seen[stage] {
+			return fmt.Errorf("stage %s duplikat", stage)
+		}
+		seen[stage] = true
+	}
+	return nil
+}
+
+func applyApprovalChainRequest(chain *model.ApprovalChain, req *ApprovalChainRequest) {
+	chain.Name = strings.TrimSpace(req.Name)
+	chain.AchievementType = req.AchievementType
+	chain.CompetitionLevel = req.CompetitionLevel
+	chain.Stages = req.Stages
+	if req.IsActive != nil {
+		chain.IsActive = *req.IsActive
+	}
+}
+
+func isValidApprovalStage(stage model.ApprovalStage) bool {
+	for _, s := range validApprovalStages {
+		if stage == s {
+			return true
+		}
+	}
+	return false
+}
+
+// isPendingStage true jika reference sedang menunggu persetujuan pada tahap tersebut.
+// Reference lama tanpa current_stage dianggap berada di tahap dosen wali.
+func isPendingStage(reference *model.AchievementReference, stage model.ApprovalStage) bool {
+	if reference.Status != model.StatusSubmitted {
+		return false
+	}
+	if reference.CurrentStage == nil {
+		return stage == model.ApprovalStageAdvisor
+	}
+	return *reference.CurrentStage == stage
+}
+
+func approvalStageLabel(stage model.ApprovalStage) string {
+	switch stage {
+	case model.ApprovalStageAdvisor:
+		return "dosen wali"
+	case model.ApprovalStageProgramHead:
+		return "ketua program studi"
+	case model.ApprovalStageStudentAffairs:
+		return "bagian kemahasiswaan"
+	}
+	return string(stage)
+}
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/repository"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/storage"
+)
+
+type AttachmentService interface {
+	Open(ctx context.Context, attachment model.Attachment, rangeHeader string) (*AttachmentFile, error)
+	SignURL(achievementID string, attachment model.Attachment) (*SignedAttachmentURL, error)
+	OpenSigned(ctx context.Context, achievementID string, attachmentID string, expires string, signature string, rangeHeader string) (*AttachmentFile, error)
+}
+
+type attachmentService struct {
+	achievementRepo repository.AchievementRepository
+	blobStore       storage.BlobStore
+	secret          []byte
+	baseURL         string
+	urlTTL          time.Duration
+}
+
+// NewAttachmentService membuat service unduh attachment. secret menandatangani URL
+// sementara (HMAC-SHA256) dengan masa berlaku urlTTL; urlTTL 0 atau secret kosong =
+// URL sementara nonaktif.
+func NewAttachmentService(
+	achievementRepo repository.AchievementRepository,
+	blobStore storage.BlobStore,
+	secret string,
+	baseURL string,
+	urlTTL time.Duration,
+) AttachmentService {
+	if secret == "" {
+		urlTTL = 0
+	}
+	return &attachmentService{
+		achievementRepo: achievementRepo,
+		blobStore:       blobStore,
+		secret:          []byte(secret),
+		baseURL:         strings.TrimRight(baseURL, "/"),
+		urlTTL:          urlTTL,
+	}
+}
+
+// AttachmentFile isi attachment yang siap dikirim. Jika Partial, Content hanya berisi
+// Length byte mulai dari Offset dari total Size.
+type AttachmentFile struct {
+	FileName    string
+	ContentType string
+	Size        int64
+	Offset      int64
+	Length      int64
+	Partial     bool
+	Content     io.ReadCloser
+}
+
+type SignedAttachmentURL struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// RangeNotSatisfiableError header Range di luar ukuran file (HTTP 416)
+type RangeNotSatisfiableError struct {
+	Size int64
+}
+
+func (e *RangeNotSatisfiableError) Error() string {
+	return fmt.Sprintf("range tidak dapat dipenuhi, ukuran file %d byte", e.Size)
+}
+
+func (s *attachmentService) Open(ctx context.Context, attachment model.Attachment, rangeHeader string) (*AttachmentFile, error) {
+	if attachment.Quarantined {
+		return nil, errors.New("attachment dikarantina karena terdeteksi malware")
+	}
+
+	key, err := attachmentKey(attachment.FileURL)
+	if err != nil {
+		return nil, err
+	}
+
+	file := &AttachmentFile{FileName: attachment.FileName}
+	if file.FileName == "" {
+		file.FileName = key[strings.LastIndex(key, "/")+1:]
+	}
+
+	if rangeHeader == "" {
+		content, info, err := s.blobStore.Get(ctx, key)
+		if err != nil {
+			return nil, attachmentStoreError(err)
+		}
+		file.ContentType = attachmentContentType(attachment, info)
+		file.Size = info.Size
+		file.Length = info.Size
+		file.Content = content
+		return file, nil
+	}
+
+	info, err := s.blobStore.Stat(ctx, key)
+	if err != nil {
+		return nil, attachmentStoreError(err)
+	}
+	file.ContentType = attachmentContentType(attachment, info)
+	file.Size = info.Size
+
+	offset, length, partial, err := parseByteRange(rangeHeader, info.Size)
+	if err != nil {
+		return nil, err
+	}
+	file.Offset = offset
+	file.Length = length
+	file.Partial = partial
+
+	var content io.ReadCloser
+	if partial {
+		content, _, err = s.blobStore.GetRange(ctx, key, offset, length)
+	} else {
+		content, _, err = s.blobStore.Get(ctx, key)
+	}
+	if err != nil {
+		return nil, attachmentStoreError(err)
+	}
+	file.Content = content
+	return file, nil
+}
+
+// attachmentContentType tipe MIME yang terdeteksi dari isi file saat upload lebih dipercaya
+// daripada metadata backend, yang untuk file lama hanya ditebak dari ekstensi
+func attachmentContentType(attachment model.Attachment, info *storage.BlobInfo) string {
+	if attachment.FileType != "" {
+		return attachment.FileType
+	}
+	return info.ContentType
+}
+
+func (s *attachmentService) SignURL(achievementID string, attachment model.Attachment) (*SignedAttachmentURL, error) {
+	if s.urlTTL <= 0 {
+		return nil, errors.New("URL sementara attachment tidak diaktifkan")
+	}
+
+	expiresAt := time.Now().Add(s.urlTTL).Truncate(time.Second)
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+	query := url.Values{}
+	query.Set("expires", expires)
+	id := attachmentID(attachment)
+	query.Set("signature", s.sign(achievementID, id, attachment.FileURL, expires))
+
+	return &SignedAttachmentURL{
+		URL:       fmt.Sprintf("%s/files/attachments/%s/%s?%s", s.baseURL, url.PathEscape(achievementID), url.PathEscape(id), query.Encode()),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func (s *attachmentService) OpenSigned(ctx context.Context, achievementID string, id string, expires string, signature string, rangeHeader string) (*AttachmentFile, error) {
+	invalid := errors.New("URL attachment tidak valid atau sudah kedaluwarsa")
+	if s.urlTTL <= 0 {
+		return nil, invalid
+	}
+
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return nil, invalid
+	}
+
+	achievement, err := s.achievementRepo.FindAchievementByID(ctx, achievementID)
+	if err != nil {
+		return nil, invalid
+	}
+	index := findAttachmentIndex(achievement.Attachments, id)
+	if index < 0 {
+		return nil, invalid
+	}
+	attachment := achievement.Attachments[index]
+
+	// FileURL ikut ditandatangani agar URL tidak berlaku lagi jika isi attachment diganti
+	expected := s.sign(achievementID, id, attachment.FileURL, expires)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return nil, invalid
+	}
+
+	return s.Open(ctx, attachment, rangeHeader)
+}
+
+func (s *attachmentService) sign(achievementID string, id string, fileURL string, expires string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(strings.Join([]string{"attachment", achievementID, id, fileURL, expires}, "\n")))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func attachmentStoreError(err error) error {
+	if errors.Is(err, storage.ErrBlobNotFound) {
+		return errors.New("file attachment tidak ditemukan")
+	}
+	return fmt.Errorf("gagal membaca file attachment: %v", err)
+}
+
+// parseByteRange membaca header Range satu rentang ("bytes=0-99", "bytes=100-", "bytes=-500").
+// Header yang tidak dikenali atau berisi beberapa rentang diabaikan sehingga seluruh file dikirim.
+func parseByteRange(header string, size int64) (offset, length int64, partial bool, err error) {
+	spec, ok := strings.CutPrefix(strings.TrimSpace(header), "bytes=")
+	if !ok || strings.Contains(spec, ",") {
+		return 0, size, false, nil
+	}
+	startText, endText, ok := strings.Cut(strings.TrimSpace(spec), "-")
+	if !ok {
+		return 0, size, false, nil
+	}
+
+	if startText == "" {
+		suffix, parseErr := strconv.ParseInt(endText, 10, 64)
+		if parseErr != nil || suffix < 0 {
+			return 0, size, false, nil
+		}
+		if suffix == 0 || size == 0 {
+			return 0, 0, false, &RangeNotSatisfiableError{Size: size}
+		}
+		if suffix > size {
+			suffix = size
+		}
+		return size - suffix, suffix, true, nil
+	}
+
+	start, parseErr := strconv.ParseInt(startText, 10, 64)
+	if parseErr != nil || start < 0 {
+		return 0, size, false, nil
+	}
+	end := size - 1
+	if endText != "" {
+		end, parseErr = strconv.ParseInt(endText, 10, 64)
+		if parseErr != nil || end < start {
+			return 0, size, false, nil
+		}
+		if end > size-1 {
+			end = size - 1
+		}
+	}
+	if start >= size {
+		return 0, 0, false, &RangeNotSatisfiableError{Size: size}
+	}
+	return start, end - start + 1, true, nil
+}
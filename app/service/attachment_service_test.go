@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/repository"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// fakeAchievementRepository achievement di memori. Method yang tidak di-override memakai
+// interface tersemat yang nil dan akan panic jika dipanggil test.
+type fakeAchievementRepository struct {
+	repository.AchievementRepository
+	achievements map[string]*model.Achievement
+	references   []model.AchievementReference
+}
+
+func (r *fakeAchievementRepository) FindAchievementByID(ctx context.Context, id string) (*model.Achievement, error) {
+	achievement, ok := r.achievements[id]
+	if !ok {
+		return nil, errors.New("achievement tidak ditemukan")
+	}
+	copied := *achievement
+	copied.Attachments = append([]model.Attachment{}, achievement.Attachments...)
+	copied.Members = append([]model.TeamMember(nil), achievement.Members...)
+	return &copied, nil
+}
+
+func (r *fakeAchievementRepository) UpdateAchievement(ctx context.Context, id string, achievement *model.Achievement) error {
+	if _, ok := r.achievements[id]; !ok {
+		return errors.New("achievement tidak ditemukan")
+	}
+	copied := *achievement
+	copied.Attachments = append([]model.Attachment{}, achievement.Attachments...)
+	copied.Members = append([]model.TeamMember(nil), achievement.Members...)
+	r.achievements[id] = &copied
+	return nil
+}
+
+func TestParseByteRange(t *testing.T) {
+	tests := []struct {
+		name           string
+		header         string
+		size           int64
+		wantOffset     int64
+		wantLength     int64
+		wantPartial    bool
+		wantNotSatisfy bool
+	}{
+		{"tanpa header", "", 1000, 0, 1000, false, false},
+		{"rentang tertutup", "bytes=0-99", 1000, 0, 100, true, false},
+		{"rentang di tengah", "bytes=200-299", 1000, 200, 100, true, false},
+		{"terbuka sampai akhir", "bytes=900-", 1000, 900, 100, true, false},
+		{"akhir melewati ukuran dipotong", "bytes=900-5000", 1000, 900, 100, true, false},
+		{"suffix", "bytes=-100", 1000, 900, 100, true, false},
+		{"suffix lebih besar dari file", "bytes=-5000", 1000, 0, 1000, true, false},
+		{"suffix nol", "bytes=-0", 1000, 0, 0, false, true},
+		{"awal di luar ukuran", "bytes=1000-", 1000, 0, 0, false, true},
+		{"awal jauh di luar ukuran", "bytes=5000-6000", 1000, 0, 0, false, true},
+		{"file kosong", "bytes=-10", 0, 0, 0, false, true},
+		{"beberapa rentang diabaikan", "bytes=0-1,5-6", 1000, 0, 1000, false, false},
+		{"unit lain diabaikan", "items=0-1", 1000, 0, 1000, false, false},
+		{"akhir sebelum awal diabaikan", "bytes=500-100", 1000, 0, 1000, false, false},
+		{"bukan angka diabaikan", "bytes=a-b", 1000, 0, 1000, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			offset, length, partial, err := parseByteRange(tt.header, tt.size)
+			var notSatisfiable *RangeNotSatisfiableError
+			if tt.wantNotSatisfy {
+				if !errors.As(err, &notSatisfiable) || notSatisfiable.Size != tt.size {
+					t.Fatalf("parseByteRange() error = %v, want RangeNotSatisfiableError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseByteRange() error = %v", err)
+			}
+			if offset != tt.wantOffset || length != tt.wantLength || partial != tt.wantPartial {
+				t.Errorf("parseByteRange() = %d, %d, %v; want %d, %d, %v", offset, length, partial, tt.wantOffset, tt.wantLength, tt.wantPartial)
+			}
+		})
+	}
+}
+
+func TestSignedAttachmentURL(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewLocalStore(t.TempDir())
+	if err := store.Put(ctx, "achievements/a_1.pdf", strings.NewReader("%PDF-1.7 isi"), 12, "application/pdf"); err != nil {
+		t.Fatal(err)
+	}
+
+	achievementID := primitive.NewObjectID().Hex()
+	attachment := model.Attachment{ID: "att1", FileName: "sertifikat.pdf", FileURL: "achievements/a_1.pdf", FileType: "application/pdf"}
+	repo := &fakeAchievementRepository{achievements: map[string]*model.Achievement{
+		achievementID: {Attachments: []model.Attachment{attachment}},
+	}}
+	service := NewAttachmentService(repo, store, "kunci-url", "https://prestasi.example.ac.id/", 5*time.Minute)
+
+	signed, err := service.SignURL(achievementID, attachment)
+	if err != nil {
+		t.Fatalf("SignURL() error = %v", err)
+	}
+	if !strings.HasPrefix(signed.URL, "https://prestasi.example.ac.id/files/attachments/"+achievementID+"/att1?") {
+		t.Errorf("SignURL() URL = %s", signed.URL)
+	}
+	parsed, err := url.Parse(signed.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expires, signature := parsed.Query().Get("expires"), parsed.Query().Get("signature")
+
+	file, err := service.OpenSigned(ctx, achievementID, "att1", expires, signature, "bytes=-3")
+	if err != nil {
+		t.Fatalf("OpenSigned() error = %v", err)
+	}
+	data, _ := io.ReadAll(file.Content)
+	file.Content.Close()
+	if string(data) != "isi" || !file.Partial || file.ContentType != "application/pdf" {
+		t.Errorf("OpenSigned() = %q, %+v", data, file)
+	}
+
+	past := strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10)
+	otherKey := NewAttachmentService(repo, store, "kunci-lain", "", 5*time.Minute).(*attachmentService)
+	tests := []struct {
+		name      string
+		id        string
+		expires   string
+		signature string
+	}{
+		{"signature diubah", "att1", expires, signature[:len(signature)-2] + "AA"},
+		{"expires diperpanjang", "att1", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10), signature},
+		{"sudah kedaluwarsa", "att1", past, service.(*attachmentService).sign(achievementID, "att1", attachment.FileURL, past)},
+		{"expires bukan angka", "att1", "besok", signature},
+		{"attachment lain", "att2", expires, signature},
+		{"kunci berbeda", "att1", expires, otherKey.sign(achievementID, "att1", attachment.FileURL, expires)},
+		{"tanpa signature", "att1", expires, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.OpenSigned(ctx, achievementID, tt.id, tt.expires, tt.signature, ""); err == nil || err.Error() != "URL attachment tidak valid atau sudah kedaluwarsa" {
+				t.Errorf("OpenSigned() error = %v, want URL tidak valid", err)
+			}
+		})
+	}
+
+	// Mengganti isi attachment (FileURL baru) membatalkan URL yang sudah dibagikan
+	repo.achievements[achievementID].Attachments[0].FileURL = "achievements/a_2.pdf"
+	if _, err := service.OpenSigned(ctx, achievementID, "att1", expires, signature, ""); err == nil {
+		t.Error("OpenSigned() setelah attachment diganti seharusnya ditolak")
+	}
+}
+
+func TestSignedAttachmentURLDisabled(t *testing.T) {
+	attachment := model.Attachment{ID: "att1", FileURL: "achievements/a_1.pdf"}
+	for name, service := range map[string]AttachmentService{
+		"TTL nol":       NewAttachmentService(&fakeAchievementRepository{}, storage.NewLocalStore(t.TempDir()), "kunci-url", "", 0),
+		"secret kosong": NewAttachmentService(&fakeAchievementRepository{}, storage.NewLocalStore(t.TempDir()), "", "", time.Minute),
+	} {
+		if _, err := service.SignURL("id", attachment); err == nil {
+			t.Errorf("%s: SignURL() seharusnya error", name)
+		}
+		if _, err := service.OpenSigned(context.Background(), "id", "att1", "9999999999", "x", ""); err == nil {
+			t.Errorf("%s: OpenSigned() seharusnya error", name)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// issuerKey kunci Ed25519 institusi untuk menandatangani Verifiable Credential.
+// Identitas penerbit memakai did:web dari PUBLIC_BASE_URL.
+type issuerKey struct {
+	private ed25519.PrivateKey
+	public  ed25519.PublicKey
+	did     string
+}
+
+// loadIssuerKey membaca kunci PKCS#8 PEM dari path; jika belum ada, kunci baru dibuat dan
+// disimpan sehingga tetap sama setelah restart
+func loadIssuerKey(path, baseURL string) (*issuerKey, error) {
+	did, err := didWebFromURL(baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return generateIssuerKey(path, did)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, fmt.Errorf("file %s bukan kunci PEM PKCS#8", path)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	private, ok := parsed.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("kunci di %s bukan kunci Ed25519", path)
+	}
+	return &issuerKey{private: private, public: private.Public().(ed25519.PublicKey), did: did}, nil
+}
+
+func generateIssuerKey(path, did string) (*issuerKey, error) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600); err != nil {
+		return nil, err
+	}
+	fmt.Printf("Kunci institusi baru dibuat di %s\n", path)
+	return &issuerKey{private: private, public: public, did: did}, nil
+}
+
+// didWebFromURL mengubah URL menjadi did:web, misalnya https://host:8080/app -> did:web:host%3A8080:app
+func didWebFromURL(raw string) (string, error) {
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Host == "" {
+		return "", errors.New("PUBLIC_BASE_URL tidak valid untuk did:web")
+	}
+	did := "did:web:" + strings.ReplaceAll(parsed.Host, ":", "%3A")
+	for _, segment := range strings.Split(strings.Trim(parsed.Path, "/"), "/") {
+		if segment != "" {
+			did += ":" + url.PathEscape(segment)
+		}
+	}
+	return did, nil
+}
+
+func (k *issuerKey) keyID() string {
+	return k.did + "#key-1"
+}
+
+type DIDDocument struct {
+	Context            []string                `json:"@context"`
+	ID                 string                  `json:"id"`
+	VerificationMethod []DIDVerificationMethod `json:"verificationMethod"`
+	AssertionMethod    []string                `json:"assertionMethod"`
+}
+
+type DIDVerificationMethod struct {
+	ID           string            `json:"id"`
+	Type         string            `json:"type"`
+	Controller   string            `json:"controller"`
+	PublicKeyJwk map[string]string `json:"publicKeyJwk"`
+}
+
+func (k *issuerKey) didDocument() *DIDDocument {
+	return &DIDDocument{
+		Context: []string{"https://www.w3.org/ns/did/v1", "https://w3id.org/security/suites/jws-2020/v1"},
+		ID:      k.did,
+		VerificationMethod: []DIDVerificationMethod{{
+			ID:         k.keyID(),
+			Type:       "JsonWebKey2020",
+			Controller: k.did,
+			PublicKeyJwk: map[string]string{
+				"kty": "OKP",
+				"crv": "Ed25519",
+				"x":   base64.RawURLEncoding.EncodeToString(k.public),
+			},
+		}},
+		AssertionMethod: []string{k.keyID()},
+	}
+}
@@ -0,0 +1,631 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/repository"
+)
+
+const openBadgesContext = "https://w3id.org/openbadges/v2"
+
+// badgeInvalidatedReason alasan pencabutan otomatis saat prestasi tidak lagi terverifikasi
+const badgeInvalidatedReason = "Prestasi tidak lagi berstatus terverifikasi"
+
+type BadgeService interface {
+	IssueBadges(ctx context.Context, achievementID string) error
+	RevokeBadges(ctx context.Context, achievementID string, studentID *uuid.UUID, reason string) error
+	GetBadge(ctx context.Context, userID uuid.UUID, achievementID string, nim string) (*BadgeResponse, error)
+	GetIssuer() *OpenBadgeIssuer
+	GetBadgeClass(ctx context.Context, achievementID string) (*OpenBadgeClass, error)
+	RenderBadgeImage(ctx context.Context, achievementID string) ([]byte, error)
+	GetAssertion(ctx context.Context, assertionID string) (*OpenBadgeAssertion, error)
+	GetCredential(ctx context.Context, assertionID string) (string, error)
+	GetRevocationList(ctx context.Context) (*OpenBadgeRevocationList, error)
+	VerifyBadge(ctx context.Context, assertionID string) (*BadgeVerification, error)
+	GetDIDDocument() (*DIDDocument, error)
+}
+
+type badgeService struct {
+	badgeRepo       repository.BadgeRepository
+	achievementRepo repository.AchievementRepository
+	studentRepo     repository.StudentRepository
+	baseURL         string
+	issuerName      string
+	issuerEmail     string
+	signer          *issuerKey // nil = Verifiable Credential tidak diterbitkan
+}
+
+// NewBadgeService membuat service Open Badges. Jika issueCredential aktif, setiap assertion juga
+// diterbitkan sebagai W3C Verifiable Credential (JWT) yang ditandatangani kunci institusi di keyPath.
+func NewBadgeService(
+	badgeRepo repository.BadgeRepository,
+	achievementRepo repository.AchievementRepository,
+	studentRepo repository.StudentRepository,
+	baseURL string,
+	issuerName string,
+	issuerEmail string,
+	issueCredential bool,
+	keyPath string,
+) BadgeService {
+	s := &badgeService{
+		badgeRepo:       badgeRepo,
+		achievementRepo: achievementRepo,
+		studentRepo:     studentRepo,
+		baseURL:         strings.TrimRight(baseURL, "/"),
+		issuerName:      issuerName,
+		issuerEmail:     issuerEmail,
+	}
+	if issueCredential {
+		key, err := loadIssuerKey(keyPath, s.baseURL)
+		if err != nil {
+			fmt.Printf("Warning: Kunci institusi tidak dapat dimuat, Verifiable Credential dinonaktifkan: %v\n", err)
+		} else {
+			s.signer = key
+		}
+	}
+	return s
+}
+
+// Open Badges 2.0 (https://www.imsglobal.org/sites/default/files/Badges/OBv2p0Final/index.html)
+type OpenBadgeIssuer struct {
+	Context        string `json:"@context"`
+	Type           string `json:"type"`
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	URL            string `json:"url"`
+	Email          string `json:"email,omitempty"`
+	RevocationList string `json:"revocationList"`
+}
+
+type OpenBadgeClass struct {
+	Context     string            `json:"@context"`
+	Type        string            `json:"type"`
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Image       string            `json:"image"`
+	Criteria    OpenBadgeCriteria `json:"criteria"`
+	Issuer      string            `json:"issuer"`
+	Tags        []string          `json:"tags,omitempty"`
+}
+
+type OpenBadgeCriteria struct {
+	Narrative string `json:"narrative"`
+}
+
+type OpenBadgeRecipient struct {
+	Type     string `json:"type"`
+	Hashed   bool   `json:"hashed"`
+	Salt     string `json:"salt"`
+	Identity string `json:"identity"`
+}
+
+type OpenBadgeVerification struct {
+	Type string `json:"type"`
+}
+
+// OpenBadgeAssertion assertion hosted; assertion yang dicabut hanya berisi id, revoked dan alasan
+type OpenBadgeAssertion struct {
+	Context          string                 `json:"@context"`
+	Type             string                 `json:"type,omitempty"`
+	ID               string                 `json:"id"`
+	Recipient        *OpenBadgeRecipient    `json:"recipient,omitempty"`
+	Badge            string                 `json:"badge,omitempty"`
+	Verification     *OpenBadgeVerification `json:"verification,omitempty"`
+	IssuedOn         string                 `json:"issuedOn,omitempty"`
+	Narrative        string                 `json:"narrative,omitempty"`
+	Revoked          bool                   `json:"revoked,omitempty"`
+	RevocationReason string                 `json:"revocationReason,omitempty"`
+}
+
+type OpenBadgeRevocationList struct {
+	Context           string                `json:"@context"`
+	Type              string                `json:"type"`
+	ID                string                `json:"id"`
+	Issuer            string                `json:"issuer"`
+	RevokedAssertions []OpenBadgeRevocation `json:"revokedAssertions"`
+}
+
+type OpenBadgeRevocation struct {
+	ID               string `json:"id"`
+	RevocationReason string `json:"revocationReason,omitempty"`
+}
+
+// BadgeResponse data badge untuk peserta, termasuk tautan untuk menambahkan ke LinkedIn
+type BadgeResponse struct {
+	AssertionID   string              `json:"assertion_id"`
+	AssertionURL  string              `json:"assertion_url"`
+	BadgeClassURL string              `json:"badge_class_url"`
+	IssuerURL     string              `json:"issuer_url"`
+	VerifyURL     string              `json:"verify_url"`
+	LinkedInURL   string              `json:"linkedin_url"`
+	Assertion     *OpenBadgeAssertion `json:"assertion"`
+	Credential    string              `json:"credential,omitempty"` // Verifiable Credential dalam format JWT
+	IssuedOn      time.Time           `json:"issued_on"`
+}
+
+type BadgeVerification struct {
+	Valid              bool       `json:"valid"`
+	Status             string     `json:"status"` // valid, revoked, achievement_unverified, invalid_credential
+	Message            string     `json:"message"`
+	AssertionID        string     `json:"assertion_id"`
+	BadgeName          string     `json:"badge_name,omitempty"`
+	RecipientName      string     `json:"recipient_name,omitempty"`
+	IssuedOn           time.Time  `json:"issued_on"`
+	RevokedAt          *time.Time `json:"revoked_at,omitempty"`
+	RevocationReason   string     `json:"revocation_reason,omitempty"`
+	CredentialVerified *bool      `json:"credential_verified,omitempty"` // nil jika assertion tidak memiliki Verifiable Credential
+}
+
+func (s *badgeService) issuerURL() string {
+	return s.baseURL + "/badges/issuer"
+}
+
+func (s *badgeService) badgeClassURL(achievementID string) string {
+	return s.baseURL + "/badges/classes/" + achievementID
+}
+
+func (s *badgeService) assertionURL(assertionID uuid.UUID) string {
+	return s.baseURL + "/badges/assertions/" + assertionID.String()
+}
+
+func (s *badgeService) revocationListURL() string {
+	return s.baseURL + "/badges/revocations"
+}
+
+// IssueBadges menerbitkan assertion untuk setiap peserta achievement terverifikasi yang belum
+// memiliki assertion aktif
+func (s *badgeService) IssueBadges(ctx context.Context, achievementID string) error {
+	achievement, err := s.achievementRepo.FindAchievementByID(ctx, achievementID)
+	if err != nil {
+		return errors.New("achievement tidak ditemukan")
+	}
+	if achievement.Status != model.StatusVerified {
+		return errors.New("badge hanya diterbitkan untuk achievement terverifikasi")
+	}
+
+	references, err := s.achievementRepo.FindReferencesByMongoID(ctx, achievementID)
+	if err != nil {
+		return fmt.Errorf("gagal memuat reference: %v", err)
+	}
+	for i := range references {
+		student := &references[i].Student
+		if _, err := s.badgeRepo.FindActiveAssertion(ctx, achievementID, student.ID); err == nil {
+			continue
+		}
+		if _, err := s.issue(ctx, achievement, student); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *badgeService) issue(ctx context.Context, achievement *model.Achievement, student *model.Student) (*model.BadgeAssertion, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("gagal membuat salt recipient: %v", err)
+	}
+
+	assertion := &model.BadgeAssertion{
+		ID:                 uuid.New(),
+		MongoAchievementID: achievement.ID.Hex(),
+		StudentID:          student.ID,
+		RecipientSalt:      hex.EncodeToString(salt),
+		IssuedOn:           time.Now().Truncate(time.Second),
+	}
+	assertion.RecipientIdentity = hashRecipient(student.User.Email, assertion.RecipientSalt)
+
+	if s.signer != nil {
+		credential, err := s.signCredential(achievement, assertion)
+		if err != nil {
+			return nil, fmt.Errorf("gagal menandatangani Verifiable Credential: %v", err)
+		}
+		assertion.Credential = credential
+	}
+
+	if err := s.badgeRepo.CreateAssertion(ctx, assertion); err != nil {
+		return nil, fmt.Errorf("gagal menyimpan badge: %v", err)
+	}
+	return assertion, nil
+}
+
+// hashRecipient identity recipient Open Badges: sha256$hex(email + salt)
+func hashRecipient(email, salt string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(email)) + salt))
+	return "sha256$" + hex.EncodeToString(sum[:])
+}
+
+func (s *badgeService) RevokeBadges(ctx context.Context, achievementID string, studentID *uuid.UUID, reason string) error {
+	if err := s.badgeRepo.RevokeAssertions(ctx, achievementID, studentID, reason, time.Now()); err != nil {
+		return fmt.Errorf("gagal mencabut badge: %v", err)
+	}
+	return nil
+}
+
+// GetBadge badge aktif milik mahasiswa yang login, atau milik mahasiswa dengan NIM tertentu
+// (default pembuat achievement) untuk dosen dan admin. Achievement terverifikasi yang belum
+// memiliki badge dibuatkan saat itu juga.
+func (s *badgeService) GetBadge(ctx context.Context, userID uuid.UUID, achievementID string, nim string) (*BadgeResponse, error) {
+	achievement, err := s.achievementRepo.FindAchievementByID(ctx, achievementID)
+	if err != nil {
+		return nil, errors.New("achievement tidak ditemukan")
+	}
+
+	var student *model.Student
+	if own, err := s.studentRepo.FindStudentByUserID(ctx, userID); err == nil {
+		student = own
+	} else if nim != "" {
+		student, err = s.studentRepo.FindStudentByStudentID(ctx, nim)
+		if err != nil {
+			return nil, errors.New("mahasiswa dengan NIM " + nim + " tidak ditemukan")
+		}
+	} else {
+		studentUUID, err := uuid.Parse(achievement.StudentID)
+		if err != nil {
+			return nil, errors.New("student ID tidak valid")
+		}
+		student, err = s.studentRepo.FindStudentByID(ctx, studentUUID)
+		if err != nil {
+			return nil, errors.New("student tidak ditemukan")
+		}
+	}
+
+	if !isParticipant(achievement, student.ID.String()) {
+		return nil, errors.New("mahasiswa bukan peserta achievement ini")
+	}
+	if achievement.Status != model.StatusVerified {
+		return nil, errors.New("badge hanya tersedia untuk achievement terverifikasi")
+	}
+
+	assertion, err := s.badgeRepo.FindActiveAssertion(ctx, achievementID, student.ID)
+	if err != nil {
+		assertion, err = s.issue(ctx, achievement, student)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &BadgeResponse{
+		AssertionID:   assertion.ID.String(),
+		AssertionURL:  s.assertionURL(assertion.ID),
+		BadgeClassURL: s.badgeClassURL(achievementID),
+		IssuerURL:     s.issuerURL(),
+		VerifyURL:     s.assertionURL(assertion.ID) + "/verify",
+		LinkedInURL:   s.linkedInURL(achievement, assertion),
+		Assertion:     s.mapAssertion(assertion),
+		Credential:    assertion.Credential,
+		IssuedOn:      assertion.IssuedOn,
+	}, nil
+}
+
+// linkedInURL tautan "Add to profile" LinkedIn untuk bagian Licenses & Certifications
+func (s *badgeService) linkedInURL(achievement *model.Achievement, assertion *model.BadgeAssertion) string {
+	query := url.Values{}
+	query.Set("startTask", "CERTIFICATION_NAME")
+	query.Set("name", achievement.Title)
+	query.Set("organizationName", s.issuerName)
+	query.Set("issueYear", strconv.Itoa(assertion.IssuedOn.Year()))
+	query.Set("issueMonth", strconv.Itoa(int(assertion.IssuedOn.Month())))
+	query.Set("certUrl", s.assertionURL(assertion.ID)+"/verify")
+	query.Set("certId", assertion.ID.String())
+	return "https://www.linkedin.com/profile/add?" + query.Encode()
+}
+
+func (s *badgeService) GetIssuer() *OpenBadgeIssuer {
+	return &OpenBadgeIssuer{
+		Context:        openBadgesContext,
+		Type:           "Issuer",
+		ID:             s.issuerURL(),
+		Name:           s.issuerName,
+		URL:            s.baseURL,
+		Email:          s.issuerEmail,
+		RevocationList: s.revocationListURL(),
+	}
+}
+
+// GetBadgeClass badge class hosted, dibentuk dari data achievement terverifikasi
+func (s *badgeService) GetBadgeClass(ctx context.Context, achievementID string) (*OpenBadgeClass, error) {
+	achievement, err := s.achievementRepo.FindAchievementByID(ctx, achievementID)
+	if err != nil || achievement.Status != model.StatusVerified {
+		return nil, errors.New("badge class tidak ditemukan")
+	}
+
+	description := strings.TrimSpace(achievement.Description)
+	if description == "" {
+		description = achievement.Title
+	}
+	return &OpenBadgeClass{
+		Context:     openBadgesContext,
+		Type:        "BadgeClass",
+		ID:          s.badgeClassURL(achievementID),
+		Name:        achievement.Title,
+		Description: description,
+		Image:       s.badgeClassURL(achievementID) + "/image",
+		Criteria:    OpenBadgeCriteria{Narrative: badgeCriteria(achievement)},
+		Issuer:      s.issuerURL(),
+		Tags:        achievement.Tags,
+	}, nil
+}
+
+func badgeCriteria(achievement *model.Achievement) string {
+	return fmt.Sprintf("Prestasi kategori %s yang telah diajukan mahasiswa dan diverifikasi oleh dosen wali.", achievement.AchievementType)
+}
+
+// RenderBadgeImage gambar SVG badge class
+func (s *badgeService) RenderBadgeImage(ctx context.Context, achievementID string) ([]byte, error) {
+	achievement, err := s.achievementRepo.FindAchievementByID(ctx, achievementID)
+	if err != nil || achievement.Status != model.StatusVerified {
+		return nil, errors.New("badge class tidak ditemukan")
+	}
+	return renderBadgeSVG(achievement.Title, s.issuerName), nil
+}
+
+// GetAssertion hosted assertion. Assertion yang dicabut (atau prestasinya tidak lagi
+// terverifikasi) dikembalikan dengan Revoked = true.
+func (s *badgeService) GetAssertion(ctx context.Context, assertionID string) (*OpenBadgeAssertion, error) {
+	assertion, err := s.loadAssertion(ctx, assertionID)
+	if err != nil {
+		return nil, err
+	}
+	return s.mapAssertion(assertion), nil
+}
+
+// loadAssertion memuat assertion dan mencabutnya jika prestasi tidak lagi terverifikasi
+func (s *badgeService) loadAssertion(ctx context.Context, assertionID string) (*model.BadgeAssertion, error) {
+	id, err := uuid.Parse(assertionID)
+	if err != nil {
+		return nil, errors.New("badge tidak ditemukan")
+	}
+	assertion, err := s.badgeRepo.FindAssertionByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("badge tidak ditemukan")
+	}
+
+	if assertion.RevokedAt == nil {
+		achievement, err := s.achievementRepo.FindAchievementByID(ctx, assertion.MongoAchievementID)
+		if err != nil || achievement.Status != model.StatusVerified {
+			now := time.Now()
+			if err := s.badgeRepo.RevokeAssertions(ctx, assertion.MongoAchievementID, &assertion.StudentID, badgeInvalidatedReason, now); err != nil {
+				fmt.Printf("Warning: Gagal mencabut badge: %v\n", err)
+			}
+			assertion.RevokedAt = &now
+			assertion.RevocationReason = badgeInvalidatedReason
+		}
+	}
+	return assertion, nil
+}
+
+func (s *badgeService) mapAssertion(assertion *model.BadgeAssertion) *OpenBadgeAssertion {
+	if assertion.RevokedAt != nil {
+		return &OpenBadgeAssertion{
+			Context:          openBadgesContext,
+			ID:               s.assertionURL(assertion.ID),
+			Revoked:          true,
+			RevocationReason: assertion.RevocationReason,
+		}
+	}
+	return &OpenBadgeAssertion{
+		Context: openBadgesContext,
+		Type:    "Assertion",
+		ID:      s.assertionURL(assertion.ID),
+		Recipient: &OpenBadgeRecipient{
+			Type:     "email",
+			Hashed:   true,
+			Salt:     assertion.RecipientSalt,
+			Identity: assertion.RecipientIdentity,
+		},
+		Badge:        s.badgeClassURL(assertion.MongoAchievementID),
+		Verification: &OpenBadgeVerification{Type: "hosted"},
+		IssuedOn:     assertion.IssuedOn.UTC().Format(time.RFC3339),
+		Narrative:    "Diterbitkan otomatis saat prestasi diverifikasi.",
+	}
+}
+
+func (s *badgeService) GetCredential(ctx context.Context, assertionID string) (string, error) {
+	assertion, err := s.loadAssertion(ctx, assertionID)
+	if err != nil {
+		return "", err
+	}
+	if assertion.Credential == "" {
+		return "", errors.New("badge ini tidak memiliki Verifiable Credential")
+	}
+	return assertion.Credential, nil
+}
+
+func (s *badgeService) GetRevocationList(ctx context.Context) (*OpenBadgeRevocationList, error) {
+	assertions, err := s.badgeRepo.FindRevokedAssertions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("gagal memuat daftar pencabutan: %v", err)
+	}
+
+	list := &OpenBadgeRevocationList{
+		Context:           openBadgesContext,
+		Type:              "RevocationList",
+		ID:                s.revocationListURL(),
+		Issuer:            s.issuerURL(),
+		RevokedAssertions: []OpenBadgeRevocation{},
+	}
+	for _, assertion := range assertions {
+		list.RevokedAssertions = append(list.RevokedAssertions, OpenBadgeRevocation{
+			ID:               s.assertionURL(assertion.ID),
+			RevocationReason: assertion.RevocationReason,
+		})
+	}
+	return list, nil
+}
+
+// VerifyBadge memeriksa status assertion dan tanda tangan Verifiable Credential-nya
+func (s *badgeService) VerifyBadge(ctx context.Context, assertionID string) (*BadgeVerification, error) {
+	assertion, err := s.loadAssertion(ctx, assertionID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BadgeVerification{
+		Valid:            true,
+		Status:           "valid",
+		Message:          "Badge asli dan prestasi terverifikasi",
+		AssertionID:      assertion.ID.String(),
+		IssuedOn:         assertion.IssuedOn,
+		RevokedAt:        assertion.RevokedAt,
+		RevocationReason: assertion.RevocationReason,
+	}
+	if achievement, err := s.achievementRepo.FindAchievementByID(ctx, assertion.MongoAchievementID); err == nil {
+		result.BadgeName = achievement.Title
+	}
+	if student, err := s.studentRepo.FindStudentByID(ctx, assertion.StudentID); err == nil {
+		result.RecipientName = student.User.FullName
+	}
+
+	if assertion.Credential != "" {
+		verified := s.verifyCredential(assertion)
+		result.CredentialVerified = &verified
+	}
+
+	switch {
+	case assertion.RevokedAt != nil && assertion.RevocationReason == badgeInvalidatedReason:
+		result.Valid, result.Status = false, "achievement_unverified"
+		result.Message = "Prestasi pada badge ini tidak lagi berstatus terverifikasi"
+	case assertion.RevokedAt != nil:
+		result.Valid, result.Status = false, "revoked"
+		result.Message = "Badge sudah dicabut"
+	case result.CredentialVerified != nil && !*result.CredentialVerified:
+		result.Valid, result.Status = false, "invalid_credential"
+		result.Message = "Tanda tangan Verifiable Credential tidak valid"
+	}
+	return result, nil
+}
+
+// W3C Verifiable Credential (VC Data Model 1.1, format JWT) berisi OpenBadgeCredential
+type credentialClaims struct {
+	VC map[string]interface{} `json:"vc"`
+	jwt.RegisteredClaims
+}
+
+func (s *badgeService) signCredential(achievement *model.Achievement, assertion *model.BadgeAssertion) (string, error) {
+	issuedOn := assertion.IssuedOn.UTC().Format(time.RFC3339)
+	credentialID := "urn:uuid:" + assertion.ID.String()
+	description := strings.TrimSpace(achievement.Description)
+	if description == "" {
+		description = achievement.Title
+	}
+
+	vc := map[string]interface{}{
+		"@context": []string{
+			"https://www.w3.org/2018/credentials/v1",
+			"https://purl.imsglobal.org/spec/ob/v3p0/context-3.0.3.json",
+		},
+		"id":           credentialID,
+		"type":         []string{"VerifiableCredential", "OpenBadgeCredential"},
+		"issuer":       map[string]interface{}{"id": s.signer.did, "type": []string{"Profile"}, "name": s.issuerName, "url": s.baseURL},
+		"issuanceDate": issuedOn,
+		"name":         achievement.Title,
+		"credentialSubject": map[string]interface{}{
+			"type": []string{"AchievementSubject"},
+			"identifier": []map[string]interface{}{{
+				"type":         "IdentityObject",
+				"identityHash": assertion.RecipientIdentity,
+				"identityType": "emailAddress",
+				"hashed":       true,
+				"salt":         assertion.RecipientSalt,
+			}},
+			"achievement": map[string]interface{}{
+				"id":          s.badgeClassURL(achievement.ID.Hex()),
+				"type":        []string{"Achievement"},
+				"name":        achievement.Title,
+				"description": description,
+				"criteria":    map[string]string{"narrative": badgeCriteria(achievement)},
+				"image":       map[string]string{"id": s.badgeClassURL(achievement.ID.Hex()) + "/image", "type": "Image"},
+			},
+		},
+		"credentialStatus": map[string]string{
+			"id":   s.assertionURL(assertion.ID),
+			"type": "1EdTechRevocationList",
+		},
+	}
+
+	claims := credentialClaims{
+		VC: vc,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    s.signer.did,
+			ID:        credentialID,
+			NotBefore: jwt.NewNumericDate(assertion.IssuedOn),
+			IssuedAt:  jwt.NewNumericDate(assertion.IssuedOn),
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	token.Header["kid"] = s.signer.keyID()
+	return token.SignedString(s.signer.private)
+}
+
+func (s *badgeService) verifyCredential(assertion *model.BadgeAssertion) bool {
+	if s.signer == nil {
+		return false
+	}
+	claims := &credentialClaims{}
+	token, err := jwt.ParseWithClaims(assertion.Credential, claims, func(token *jwt.Token) (interface{}, error) {
+		return s.signer.public, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodEdDSA.Alg()}))
+	if err != nil || !token.Valid {
+		return false
+	}
+	return claims.ID == "urn:uuid:"+assertion.ID.String()
+}
+
+// GetDIDDocument dokumen did:web institusi untuk verifikasi Verifiable Credential oleh wallet
+func (s *badgeService) GetDIDDocument() (*DIDDocument, error) {
+	if s.signer == nil {
+		return nil, errors.New("Verifiable Credential tidak diaktifkan")
+	}
+	return s.signer.didDocument(), nil
+}
+
+// renderBadgeSVG gambar badge sederhana berisi judul prestasi
+func renderBadgeSVG(title, issuer string) []byte {
+	lines := []string{}
+	current := ""
+	for _, word := range strings.Fields(title) {
+		if current != "" && len(current)+1+len(word) > 18 {
+			lines = append(lines, current)
+			current = word
+			continue
+		}
+		if current != "" {
+			current += " "
+		}
+		current += word
+	}
+	if current != "" {
+		lines = append(lines, current)
+	}
+	if len(lines) > 3 {
+		lines = append(lines[:2], strings.TrimSpace(lines[2])+"...")
+	}
+
+	var b strings.Builder
+	b.WriteString(`<svg xmlns="http://www.w3.org/2000/svg" width="400" height="400" viewBox="0 0 400 400">`)
+	b.WriteString(`<polygon points="200,10 364,105 364,295 200,390 36,295 36,105" fill="#1f3c88"/>`)
+	b.WriteString(`<polygon points="200,30 347,115 347,285 200,370 53,285 53,115" fill="none" stroke="#f5c542" stroke-width="6"/>`)
+	b.WriteString(`<text x="200" y="120" font-family="Helvetica, Arial, sans-serif" font-size="18" fill="#f5c542" text-anchor="middle">PRESTASI TERVERIFIKASI</text>`)
+	y := 200 - (len(lines)-1)*14
+	for _, line := range lines {
+		fmt.Fprintf(&b, `<text x="200" y="%d" font-family="Helvetica, Arial, sans-serif" font-size="24" font-weight="bold" fill="#ffffff" text-anchor="middle">%s</text>`, y, html.EscapeString(line))
+		y += 30
+	}
+	fmt.Fprintf(&b, `<text x="200" y="310" font-family="Helvetica, Arial, sans-serif" font-size="14" fill="#ffffff" text-anchor="middle">%s</text>`, html.EscapeString(issuer))
+	b.WriteString(`</svg>`)
+	return []byte(b.String())
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/repository"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/storage"
+)
+
+type BlobMigrationService interface {
+	MigrateBlobs(ctx context.Context, req *BlobMigrationRequest) (*BlobMigrationReport, error)
+}
+
+type blobMigrationService struct {
+	achievementRepo repository.AchievementRepository
+	versionRepo     repository.AchievementVersionRepository
+	source          storage.BlobStore
+	target          storage.BlobStore
+}
+
+// NewBlobMigrationService memindahkan file attachment dari source ke target dan menulis
+// ulang FileURL pada achievement (termasuk yang dihapus) dan seluruh versinya
+func NewBlobMigrationService(
+	achievementRepo repository.AchievementRepository,
+	versionRepo repository.AchievementVersionRepository,
+	source storage.BlobStore,
+	target storage.BlobStore,
+) BlobMigrationService {
+	return &blobMigrationService{
+		achievementRepo: achievementRepo,
+		versionRepo:     versionRepo,
+		source:          source,
+		target:          target,
+	}
+}
+
+type BlobMigrationRequest struct {
+	DryRun       bool // hanya laporan, tidak ada file yang disalin atau dokumen yang diubah
+	DeleteSource bool // hapus file di source setelah seluruh dokumen yang merujuknya diperbarui
+}
+
+type BlobMigrationReport struct {
+	DryRun    bool                   `json:"dry_run"`
+	Documents int                    `json:"documents"` // achievement dan versi yang memiliki attachment
+	Files     int                    `json:"files"`     // FileURL unik yang dirujuk
+	Copied    int                    `json:"copied"`
+	Skipped   int                    `json:"skipped"`   // sudah ada di target
+	Rewritten int                    `json:"rewritten"` // dokumen yang FileURL-nya ditulis ulang
+	Deleted   int                    `json:"deleted"`
+	Failures  []BlobMigrationFailure `json:"failures"`
+}
+
+type BlobMigrationFailure struct {
+	FileURL  string `json:"file_url"`
+	Document string `json:"document,omitempty"`
+	Error    string `json:"error"`
+}
+
+type blobMigrationFile struct {
+	key        string
+	err        error
+	updateFail bool // ada dokumen yang gagal diperbarui, file source tidak boleh dihapus
+}
+
+func (s *blobMigrationService) MigrateBlobs(ctx context.Context, req *BlobMigrationRequest) (*BlobMigrationReport, error) {
+	if req.DeleteSource && s.source.Backend() == s.target.Backend() {
+		return nil, errors.New("hapus file source hanya dapat dipakai jika backend asal dan tujuan berbeda")
+	}
+
+	achievements, err := s.achievementRepo.FindAchievementsWithAttachments(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("gagal memuat achievements: %v", err)
+	}
+	versions, err := s.versionRepo.FindVersionsWithAttachments(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("gagal memuat versi achievement: %v", err)
+	}
+
+	report := &BlobMigrationReport{DryRun: req.DryRun, Failures: []BlobMigrationFailure{}}
+	files := make(map[string]*blobMigrationFile)
+	order := []string{}
+
+	migrate := func(document string, attachments []model.Attachment, update func([]model.Attachment) error) {
+		report.Documents++
+		rewritten := make([]model.Attachment, len(attachments))
+		copy(rewritten, attachments)
+		changed := false
+		referenced := []*blobMigrationFile{}
+
+		for i, attachment := range attachments {
+			file, ok := files[attachment.FileURL]
+			if !ok {
+				file = s.migrateFile(ctx, attachment.FileURL, req.DryRun, report)
+				files[attachment.FileURL] = file
+				order = append(order, attachment.FileURL)
+			}
+			if file.err != nil {
+				continue
+			}
+			referenced = append(referenced, file)
+			if attachment.FileURL != file.key {
+				rewritten[i].FileURL = file.key
+				changed = true
+			}
+		}
+
+		if !changed {
+			return
+		}
+		if !req.DryRun {
+			if err := update(rewritten); err != nil {
+				report.Failures = append(report.Failures, BlobMigrationFailure{Document: document, Error: err.Error()})
+				for _, file := range referenced {
+					file.updateFail = true
+				}
+				return
+			}
+		}
+		report.Rewritten++
+	}
+
+	for i := range achievements {
+		achievement := &achievements[i]
+		migrate("achievement "+achievement.ID.Hex(), achievement.Attachments, func(attachments []model.Attachment) error {
+			return s.achievementRepo.UpdateAttachments(ctx, achievement.ID, attachments)
+		})
+	}
+	for i := range versions {
+		version := &versions[i]
+		migrate(fmt.Sprintf("versi %d achievement %s", version.Version, version.AchievementID), version.Attachments, func(attachments []model.Attachment) error {
+			return s.versionRepo.UpdateVersionAttachments(ctx, version.ID, attachments)
+		})
+	}
+
+	// Key yang sama bisa dirujuk beberapa FileURL (path lama dan key baru), hapus sekali saja
+	if req.DeleteSource && !req.DryRun {
+		deleted := make(map[string]bool)
+		for _, fileURL := range order {
+			file := files[fileURL]
+			if file.err != nil || file.updateFail || deleted[file.key] {
+				continue
+			}
+			deleted[file.key] = true
+			if err := s.source.Delete(ctx, file.key); err != nil {
+				report.Failures = append(report.Failures, BlobMigrationFailure{FileURL: fileURL, Error: "gagal menghapus file source: " + err.Error()})
+				continue
+			}
+			report.Deleted++
+		}
+	}
+
+	return report, nil
+}
+
+// migrateFile menyalin satu file ke target jika belum ada di sana
+func (s *blobMigrationService) migrateFile(ctx context.Context, fileURL string, dryRun bool, report *BlobMigrationReport) *blobMigrationFile {
+	report.Files++
+	file := &blobMigrationFile{}
+	fail := func(err error) *blobMigrationFile {
+		file.err = err
+		report.Failures = append(report.Failures, BlobMigrationFailure{FileURL: fileURL, Error: err.Error()})
+		return file
+	}
+
+	key, err := attachmentKey(fileURL)
+	if err != nil {
+		return fail(err)
+	}
+	file.key = key
+
+	if _, err := s.target.Stat(ctx, key); err == nil {
+		report.Skipped++
+		return file
+	} else if !errors.Is(err, storage.ErrBlobNotFound) {
+		return fail(err)
+	}
+
+	if dryRun {
+		if _, err := s.source.Stat(ctx, key); err != nil {
+			return fail(err)
+		}
+		report.Copied++
+		return file
+	}
+
+	content, info, err := s.source.Get(ctx, key)
+	if err != nil {
+		return fail(err)
+	}
+	defer content.Close()
+
+	if err := s.target.Put(ctx, key, content, info.Size, info.ContentType); err != nil {
+		return fail(err)
+	}
+	report.Copied++
+	return file
+}
@@ -0,0 +1,423 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/repository"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/helper"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/storage"
+)
+
+// certificateKeyPrefix awalan key blob store untuk PDF sertifikat. FilePath lama
+// (uploads/certificates/...) dipetakan attachmentKey ke key yang sama.
+const certificateKeyPrefix = "certificates/"
+
+// certificateCodeAlphabet base32 Crockford, tanpa karakter yang mudah tertukar (I, L, O, U)
+const certificateCodeAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+type CertificateService interface {
+	IssueCertificates(ctx context.Context, achievementID string, verifierID uuid.UUID) error
+	GetCertificate(ctx context.Context, userID uuid.UUID, achievementID string, nim string) (*model.Certificate, error)
+	VerifyCertificate(ctx context.Context, code string) (*CertificateVerification, error)
+	RevokeCertificates(ctx context.Context, achievementID string, studentID *uuid.UUID) error
+	OpenCertificate(ctx context.Context, certificate *model.Certificate) (io.ReadCloser, int64, error)
+}
+
+type certificateService struct {
+	certificateRepo repository.CertificateRepository
+	achievementRepo repository.AchievementRepository
+	typeRepo        repository.AchievementTypeRepository
+	studentRepo     repository.StudentRepository
+	userRepo        repository.UserRepository
+	blobStore       storage.BlobStore
+	secret          []byte
+	baseURL         string
+}
+
+// NewCertificateService membuat service sertifikat. PDF disimpan di blobStore, secret dipakai
+// untuk menandatangani sertifikat (HMAC-SHA256) dan baseURL menjadi awalan URL verifikasi pada QR code.
+func NewCertificateService(
+	certificateRepo repository.CertificateRepository,
+	achievementRepo repository.AchievementRepository,
+	typeRepo repository.AchievementTypeRepository,
+	studentRepo repository.StudentRepository,
+	userRepo repository.UserRepository,
+	blobStore storage.BlobStore,
+	secret string,
+	baseURL string,
+) CertificateService {
+	return &certificateService{
+		certificateRepo: certificateRepo,
+		achievementRepo: achievementRepo,
+		typeRepo:        typeRepo,
+		studentRepo:     studentRepo,
+		userRepo:        userRepo,
+		blobStore:       blobStore,
+		secret:          []byte(secret),
+		baseURL:         strings.TrimRight(baseURL, "/"),
+	}
+}
+
+type CertificateVerification struct {
+	Valid        bool      `json:"valid"`
+	Status       string    `json:"status"` // valid, revoked, invalid_signature, achievement_unverified
+	Message      string    `json:"message"`
+	Code         string    `json:"code"`
+	StudentName  string    `json:"student_name"`
+	StudentNIM   string    `json:"student_nim"`
+	ProgramStudy string    `json:"program_study,omitempty"`
+	Title        string    `json:"title"`
+	VerifierName string    `json:"verifier_name"`
+	VerifiedAt   time.Time `json:"verified_at"`
+	IssuedAt     time.Time `json:"issued_at"`
+}
+
+// IssueCertificates membuat sertifikat untuk setiap peserta achievement terverifikasi.
+// Sertifikat lama milik peserta yang sama ditandai digantikan.
+func (s *certificateService) IssueCertificates(ctx context.Context, achievementID string, verifierID uuid.UUID) error {
+	achievement, err := s.achievementRepo.FindAchievementByID(ctx, achievementID)
+	if err != nil {
+		return errors.New("achievement tidak ditemukan")
+	}
+	if achievement.Status != model.StatusVerified {
+		return errors.New("sertifikat hanya dibuat untuk achievement terverifikasi")
+	}
+
+	verifier, err := s.userRepo.FindUserByID(ctx, verifierID)
+	if err != nil {
+		return errors.New("verifikator tidak ditemukan")
+	}
+
+	references, err := s.achievementRepo.FindReferencesByMongoID(ctx, achievementID)
+	if err != nil {
+		return fmt.Errorf("gagal memuat reference: %v", err)
+	}
+
+	typeName := achievementTypeName(ctx, s.typeRepo, achievement.AchievementType)
+	for i := range references {
+		if _, err := s.issue(ctx, achievement, &references[i].Student, verifier, typeName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *certificateService) issue(ctx context.Context, achievement *model.Achievement, student *model.Student, verifier *model.User, typeName string) (*model.Certificate, error) {
+	code, err := generateCertificateCode()
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuat kode sertifikat: %v", err)
+	}
+
+	certificate := &model.Certificate{
+		Code:               code,
+		MongoAchievementID: achievement.ID.Hex(),
+		StudentID:          student.ID,
+		StudentName:        student.User.FullName,
+		StudentNIM:         student.StudentID,
+		ProgramStudy:       student.ProgramStudy,
+		Title:              achievement.Title,
+		AchievementType:    typeName,
+		VerifiedBy:         verifier.ID,
+		VerifierName:       verifier.FullName,
+		VerifiedAt:         time.Now().Truncate(time.Second),
+		FilePath:           certificateKeyPrefix + code + ".pdf",
+	}
+	certificate.Signature = s.sign(certificate)
+
+	if _, err := s.storeCertificate(ctx, certificate); err != nil {
+		return nil, err
+	}
+
+	if err := s.certificateRepo.RevokeCertificates(ctx, certificate.MongoAchievementID, &student.ID, time.Now()); err != nil {
+		fmt.Printf("Warning: Gagal menandai sertifikat lama: %v\n", err)
+	}
+	if err := s.certificateRepo.CreateCertificate(ctx, certificate); err != nil {
+		if err := s.blobStore.Delete(ctx, certificate.FilePath); err != nil {
+			fmt.Printf("Warning: Gagal menghapus PDF sertifikat %s: %v\n", certificate.Code, err)
+		}
+		return nil, fmt.Errorf("gagal menyimpan sertifikat: %v", err)
+	}
+	return certificate, nil
+}
+
+// storeCertificate membuat PDF sertifikat dan menyimpannya ke blob store
+func (s *certificateService) storeCertificate(ctx context.Context, certificate *model.Certificate) ([]byte, error) {
+	key, err := attachmentKey(certificate.FilePath)
+	if err != nil {
+		return nil, fmt.Errorf("path sertifikat tidak valid: %v", err)
+	}
+	content, err := s.renderCertificate(certificate)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuat PDF sertifikat: %v", err)
+	}
+	if err := s.blobStore.Put(ctx, key, bytes.NewReader(content), int64(len(content)), "application/pdf"); err != nil {
+		return nil, fmt.Errorf("gagal menyimpan sertifikat: %v", err)
+	}
+	return content, nil
+}
+
+// OpenCertificate membuka PDF sertifikat dari blob store. PDF yang belum ada di backend
+// (misalnya setelah pindah ke S3) dibuat ulang dari data sertifikat yang tersimpan.
+func (s *certificateService) OpenCertificate(ctx context.Context, certificate *model.Certificate) (io.ReadCloser, int64, error) {
+	key, err := attachmentKey(certificate.FilePath)
+	if err != nil {
+		return nil, 0, fmt.Errorf("path sertifikat tidak valid: %v", err)
+	}
+
+	content, info, err := s.blobStore.Get(ctx, key)
+	if err == nil {
+		return content, info.Size, nil
+	}
+	if !errors.Is(err, storage.ErrBlobNotFound) {
+		return nil, 0, fmt.Errorf("gagal membaca sertifikat: %v", err)
+	}
+
+	rendered, err := s.storeCertificate(ctx, certificate)
+	if err != nil {
+		return nil, 0, err
+	}
+	return io.NopCloser(bytes.NewReader(rendered)), int64(len(rendered)), nil
+}
+
+// RevokeCertificates mencabut sertifikat aktif achievement yang tidak lagi terverifikasi atau
+// berpindah pemilik; studentID nil = semua peserta
+func (s *certificateService) RevokeCertificates(ctx context.Context, achievementID string, studentID *uuid.UUID) error {
+	if err := s.certificateRepo.RevokeCertificates(ctx, achievementID, studentID, time.Now()); err != nil {
+		return fmt.Errorf("gagal mencabut sertifikat: %v", err)
+	}
+	return nil
+}
+
+// GetCertificate sertifikat aktif milik mahasiswa yang login, atau milik mahasiswa dengan
+// NIM tertentu (default pembuat achievement) untuk dosen dan admin. Achievement terverifikasi
+// yang belum memiliki sertifikat dibuatkan saat itu juga.
+func (s *certificateService) GetCertificate(ctx context.Context, userID uuid.UUID, achievementID string, nim string) (*model.Certificate, error) {
+	achievement, err := s.achievementRepo.FindAchievementByID(ctx, achievementID)
+	if err != nil {
+		return nil, errors.New("achievement tidak ditemukan")
+	}
+
+	var student *model.Student
+	if own, err := s.studentRepo.FindStudentByUserID(ctx, userID); err == nil {
+		student = own
+	} else if nim != "" {
+		student, err = s.studentRepo.FindStudentByStudentID(ctx, nim)
+		if err != nil {
+			return nil, errors.New("mahasiswa dengan NIM " + nim + " tidak ditemukan")
+		}
+	} else {
+		studentUUID, err := uuid.Parse(achievement.StudentID)
+		if err != nil {
+			return nil, errors.New("student ID tidak valid")
+		}
+		student, err = s.studentRepo.FindStudentByID(ctx, studentUUID)
+		if err != nil {
+			return nil, errors.New("student tidak ditemukan")
+		}
+	}
+
+	if !isParticipant(achievement, student.ID.String()) {
+		return nil, errors.New("mahasiswa bukan peserta achievement ini")
+	}
+
+	if certificate, err := s.certificateRepo.FindActiveCertificate(ctx, achievementID, student.ID); err == nil {
+		return certificate, nil
+	}
+
+	if achievement.Status != model.StatusVerified {
+		return nil, errors.New("sertifikat hanya tersedia untuk achievement terverifikasi")
+	}
+
+	reference, err := s.achievementRepo.FindReferenceByMongoID(ctx, achievementID)
+	if err != nil || reference.VerifiedBy == nil {
+		return nil, errors.New("sertifikat tidak ditemukan")
+	}
+	verifier, err := s.userRepo.FindUserByID(ctx, *reference.VerifiedBy)
+	if err != nil {
+		return nil, errors.New("verifikator tidak ditemukan")
+	}
+	return s.issue(ctx, achievement, student, verifier, achievementTypeName(ctx, s.typeRepo, achievement.AchievementType))
+}
+
+// VerifyCertificate memeriksa keaslian sertifikat dari kode pada QR code
+func (s *certificateService) VerifyCertificate(ctx context.Context, code string) (*CertificateVerification, error) {
+	certificate, err := s.certificateRepo.FindCertificateByCode(ctx, normalizeCertificateCode(code))
+	if err != nil {
+		return nil, errors.New("sertifikat tidak ditemukan")
+	}
+
+	result := &CertificateVerification{
+		Valid:        true,
+		Status:       "valid",
+		Message:      "Sertifikat asli dan prestasi terverifikasi",
+		Code:         certificate.Code,
+		StudentName:  certificate.StudentName,
+		StudentNIM:   certificate.StudentNIM,
+		ProgramStudy: certificate.ProgramStudy,
+		Title:        certificate.Title,
+		VerifierName: certificate.VerifierName,
+		VerifiedAt:   certificate.VerifiedAt,
+		IssuedAt:     certificate.CreatedAt,
+	}
+
+	expected := s.sign(certificate)
+	switch {
+	case !hmac.Equal([]byte(expected), []byte(certificate.Signature)):
+		result.Valid, result.Status = false, "invalid_signature"
+		result.Message = "Tanda tangan sertifikat tidak cocok, data sertifikat telah diubah"
+	case certificate.RevokedAt != nil:
+		result.Valid, result.Status = false, "revoked"
+		result.Message = "Sertifikat sudah dicabut atau digantikan oleh sertifikat yang lebih baru"
+	default:
+		achievement, err := s.achievementRepo.FindAchievementByID(ctx, certificate.MongoAchievementID)
+		if err != nil || achievement.Status != model.StatusVerified {
+			result.Valid, result.Status = false, "achievement_unverified"
+			result.Message = "Prestasi pada sertifikat ini tidak lagi berstatus terverifikasi"
+		}
+	}
+	return result, nil
+}
+
+// sign menghitung HMAC-SHA256 atas data yang tercetak di sertifikat
+func (s *certificateService) sign(certificate *model.Certificate) string {
+	payload := strings.Join([]string{
+		certificate.Code,
+		certificate.MongoAchievementID,
+		certificate.StudentID.String(),
+		certificate.StudentNIM,
+		certificate.StudentName,
+		certificate.Title,
+		certificate.VerifierName,
+		strconv.FormatInt(certificate.VerifiedAt.Unix(), 10),
+	}, "\n")
+
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *certificateService) verificationURL(code string) string {
+	return s.baseURL + "/verify/" + code
+}
+
+func (s *certificateService) renderCertificate(certificate *model.Certificate) ([]byte, error) {
+	verifyURL := s.verificationURL(certificate.Code)
+	qr, err := helper.EncodeQR(verifyURL)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := helper.NewPDFDocument()
+	doc.SetInfo("Sertifikat Verifikasi Prestasi "+certificate.Code, certificate.Title,
+		"certificate:"+certificate.Code+" signature:"+certificate.Signature)
+
+	// A4 landscape
+	page := doc.AddPage(helper.PageA4Height, helper.PageA4Width)
+	width, height := page.Width(), page.Height()
+	center := width / 2
+
+	page.Rect(24, 24, width-48, height-48, 2)
+	page.Rect(32, 32, width-64, height-64, 0.5)
+
+	page.TextCenter(center, 100, 26, true, "SERTIFIKAT VERIFIKASI PRESTASI")
+	page.TextCenter(center, 124, 11, false, "Nomor: "+formatCertificateCode(certificate.Code))
+
+	page.TextCenter(center, 170, 12, false, "Diberikan kepada")
+	page.TextCenter(center, 200, 22, true, certificate.StudentName)
+	identity := "NIM " + certificate.StudentNIM
+	if certificate.ProgramStudy != "" {
+		identity += " - Program Studi " + certificate.ProgramStudy
+	}
+	page.TextCenter(center, 220, 11, false, identity)
+
+	page.TextCenter(center, 256, 12, false, "atas prestasi")
+	y := 284.0
+	for _, line := range helper.WrapText(certificate.Title, 16, true, width-240) {
+		page.TextCenter(center, y, 16, true, line)
+		y += 20
+	}
+	if certificate.AchievementType != "" {
+		page.TextCenter(center, y, 11, false, "Kategori: "+certificate.AchievementType)
+		y += 16
+	}
+
+	page.TextCenter(center, y+20, 12, false, fmt.Sprintf("Telah diverifikasi oleh %s pada %s",
+		certificate.VerifierName, formatIndonesianDate(certificate.VerifiedAt)))
+
+	qrSize := 110.0
+	qrX, qrY := width-60-qrSize, height-60-qrSize
+	page.QRCode(qrX, qrY, qrSize, qr)
+	page.TextCenter(qrX+qrSize/2, qrY+qrSize+10, 7, false, "Pindai untuk verifikasi keaslian")
+
+	page.Text(60, height-96, 9, true, "Verifikasi keaslian:")
+	page.Text(60, height-84, 9, false, verifyURL)
+	page.Text(60, height-68, 8, false, "Tanda tangan digital (HMAC-SHA256):")
+	page.Text(60, height-58, 7, false, certificate.Signature)
+
+	return doc.Bytes()
+}
+
+// achievementTypeName nama tampilan tipe prestasi bawaan atau custom
+func achievementTypeName(ctx context.Context, typeRepo repository.AchievementTypeRepository, achievementType model.AchievementType) string {
+	for _, builtIn := range builtInAchievementTypeResponses() {
+		if builtIn.Code == achievementType {
+			return builtIn.Name
+		}
+	}
+	if definition, err := typeRepo.FindTypeByCode(ctx, achievementType); err == nil {
+		return definition.Name
+	}
+	return string(achievementType)
+}
+
+func generateCertificateCode() (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	code := make([]byte, len(random))
+	for i, b := range random {
+		code[i] = certificateCodeAlphabet[int(b)%len(certificateCodeAlphabet)]
+	}
+	return string(code), nil
+}
+
+// normalizeCertificateCode menerima kode dengan atau tanpa tanda hubung seperti yang tercetak
+func normalizeCertificateCode(code string) string {
+	return strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+}
+
+// formatCertificateCode mengelompokkan kode per empat karakter agar mudah dibaca
+func formatCertificateCode(code string) string {
+	groups := []string{}
+	for i := 0; i < len(code); i += 4 {
+		end := i + 4
+		if end > len(code) {
+			end = len(code)
+		}
+		groups = append(groups, code[i:end])
+	}
+	return strings.Join(groups, "-")
+}
+
+var indonesianMonths = []string{
+	"Januari", "Februari", "Maret", "April", "Mei", "Juni",
+	"Juli", "Agustus", "September", "Oktober", "November", "Desember",
+}
+
+func formatIndonesianDate(t time.Time) string {
+	return fmt.Sprintf("%d %s %d", t.Day(), indonesianMonths[t.Month()-1], t.Year())
+}
@@ -0,0 +1,274 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/repository"
+)
+
+// defaultExpiryReminderDays jendela pengingat masa berlaku sertifikasi jika konfigurasi kosong
+var defaultExpiryReminderDays = []int{90, 30, 7}
+
+type CertificationService interface {
+	GetExpiring(ctx context.Context, userID uuid.UUID, withinDays int, includeExpired bool) ([]ExpiringCertification, error)
+	CheckExpiry(ctx context.Context) (*ExpiryCheckResult, error)
+	StartExpiryMonitor(interval time.Duration)
+}
+
+type certificationService struct {
+	achievementRepo     repository.AchievementRepository
+	lecturerRepo        repository.LecturerRepository
+	userRepo            repository.UserRepository
+	notificationService NotificationService
+	reminderDays        []int
+}
+
+// NewCertificationService membuat service masa berlaku sertifikasi. reminderDays adalah
+// jendela pengingat dalam hari sebelum validUntil (misalnya 90, 30, 7).
+func NewCertificationService(
+	achievementRepo repository.AchievementRepository,
+	lecturerRepo repository.LecturerRepository,
+	userRepo repository.UserRepository,
+	notificationService NotificationService,
+	reminderDays []int,
+) CertificationService {
+	days := []int{}
+	for _, day := range reminderDays {
+		if day > 0 && !containsInt(days, day) {
+			days = append(days, day)
+		}
+	}
+	if len(days) == 0 {
+		days = defaultExpiryReminderDays
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(days)))
+
+	return &certificationService{
+		achievementRepo:     achievementRepo,
+		lecturerRepo:        lecturerRepo,
+		userRepo:            userRepo,
+		notificationService: notificationService,
+		reminderDays:        days,
+	}
+}
+
+type ExpiringCertification struct {
+	AchievementID       string       `json:"achievement_id"`
+	Title               string       `json:"title"`
+	CertificationName   string       `json:"certification_name,omitempty"`
+	IssuedBy            string       `json:"issued_by,omitempty"`
+	CertificationNumber string       `json:"certification_number,omitempty"`
+	ValidUntil          time.Time    `json:"valid_until"`
+	DaysRemaining       int          `json:"days_remaining"` // Negatif jika sudah berakhir
+	Expired             bool         `json:"expired"`
+	Student             *StudentInfo `json:"student"`
+}
+
+type ExpiryCheckResult struct {
+	Checked  int `json:"checked"`
+	Notified int `json:"notified"`
+}
+
+// GetExpiring daftar sertifikasi mahasiswa bimbingan (dosen wali) atau seluruh mahasiswa (admin)
+// yang berakhir dalam withinDays hari, paling cepat berakhir di urutan pertama
+func (s *certificationService) GetExpiring(ctx context.Context, userID uuid.UUID, withinDays int, includeExpired bool) ([]ExpiringCertification, error) {
+	if withinDays <= 0 {
+		withinDays = s.reminderDays[0]
+	}
+
+	inScope, err := s.studentScope(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	to := now.AddDate(0, 0, withinDays)
+	from := &now
+	if includeExpired {
+		from = nil
+	}
+
+	achievements, err := s.achievementRepo.FindCertificationsValidUntil(ctx, from, &to)
+	if err != nil {
+		return nil, fmt.Errorf("gagal memuat sertifikasi: %v", err)
+	}
+
+	items := []ExpiringCertification{}
+	for i := range achievements {
+		achievement := &achievements[i]
+		references, err := s.achievementRepo.FindReferencesByMongoID(ctx, achievement.ID.Hex())
+		if err != nil {
+			fmt.Printf("Warning: Gagal memuat reference %s: %v\n", achievement.ID.Hex(), err)
+			continue
+		}
+
+		for _, reference := range references {
+			if inScope != nil && !inScope[reference.StudentID] {
+				continue
+			}
+			student := reference.Student
+			items = append(items, ExpiringCertification{
+				AchievementID:       achievement.ID.Hex(),
+				Title:               achievement.Title,
+				CertificationName:   stringValue(achievement.Details.CertificationName),
+				IssuedBy:            stringValue(achievement.Details.IssuedBy),
+				CertificationNumber: stringValue(achievement.Details.CertificationNumber),
+				ValidUntil:          *achievement.Details.ValidUntil,
+				DaysRemaining:       daysUntil(*achievement.Details.ValidUntil, now),
+				Expired:             achievement.Details.ValidUntil.Before(now),
+				Student: &StudentInfo{
+					ID:           student.ID.String(),
+					StudentID:    student.StudentID,
+					FullName:     student.User.FullName,
+					ProgramStudy: student.ProgramStudy,
+					AcademicYear: student.AcademicYear,
+				},
+			})
+		}
+	}
+	return items, nil
+}
+
+// studentScope nil berarti seluruh mahasiswa (admin)
+func (s *certificationService) studentScope(ctx context.Context, userID uuid.UUID) (map[uuid.UUID]bool, error) {
+	user, err := s.userRepo.FindUserByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("user tidak ditemukan")
+	}
+	if strings.Contains(strings.ToLower(user.Role.Name), "admin") {
+		return nil, nil
+	}
+
+	lecturer, err := s.lecturerRepo.FindLecturerByUserID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("hanya dosen wali atau admin yang dapat melihat sertifikasi yang akan berakhir")
+	}
+	advisees, err := s.lecturerRepo.FindAdvisees(ctx, lecturer.ID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal memuat mahasiswa bimbingan: %v", err)
+	}
+
+	scope := make(map[uuid.UUID]bool, len(advisees))
+	for _, advisee := range advisees {
+		scope[advisee.ID] = true
+	}
+	return scope, nil
+}
+
+// CheckExpiry mengirim pengingat ke mahasiswa untuk sertifikasi yang memasuki jendela
+// pengingat. Setiap jendela hanya dikirim sekali; jika sertifikasi baru ditemukan saat sisa
+// 20 hari, hanya pengingat 30 hari yang dikirim. Perubahan validUntil (perpanjangan)
+// mengulang pengingat dari awal.
+func (s *certificationService) CheckExpiry(ctx context.Context) (*ExpiryCheckResult, error) {
+	now := time.Now()
+	to := now.AddDate(0, 0, s.reminderDays[0])
+
+	achievements, err := s.achievementRepo.FindCertificationsValidUntil(ctx, &now, &to)
+	if err != nil {
+		return nil, fmt.Errorf("gagal memuat sertifikasi: %v", err)
+	}
+
+	result := &ExpiryCheckResult{}
+	for i := range achievements {
+		achievement := &achievements[i]
+		validUntil := *achievement.Details.ValidUntil
+		remaining := daysUntil(validUntil, now)
+		window := s.reminderWindow(remaining)
+		if window == 0 {
+			continue
+		}
+
+		references, err := s.achievementRepo.FindReferencesByMongoID(ctx, achievement.ID.Hex())
+		if err != nil {
+			fmt.Printf("Warning: Gagal memuat reference %s: %v\n", achievement.ID.Hex(), err)
+			continue
+		}
+
+		for j := range references {
+			reference := &references[j]
+			result.Checked++
+
+			if reference.ExpiryReminderFor != nil && reference.ExpiryReminderFor.Equal(validUntil) &&
+				reference.ExpiryReminderDays != nil && *reference.ExpiryReminderDays <= window {
+				continue
+			}
+
+			reference.ExpiryReminderDays = &window
+			reference.ExpiryReminderFor = &validUntil
+			if err := s.achievementRepo.UpdateReference(ctx, reference); err != nil {
+				fmt.Printf("Warning: Gagal menandai pengingat sertifikasi %s: %v\n", achievement.ID.Hex(), err)
+				continue
+			}
+
+			message := fmt.Sprintf("Sertifikasi \"%s\" berlaku hingga %s (%d hari lagi). Perbarui sertifikasi dan data prestasi anda sebelum masa berlakunya habis.",
+				firstNonEmpty(stringValue(achievement.Details.CertificationName), achievement.Title), formatIndonesianDate(validUntil), remaining)
+			if err := s.notificationService.Notify(ctx, reference.Student.UserID, model.NotificationCertificationExpiring, "Masa berlaku sertifikasi akan berakhir", message, achievement.ID.Hex()); err != nil {
+				fmt.Printf("Warning: Gagal mengirim notifikasi: %v\n", err)
+				continue
+			}
+			result.Notified++
+		}
+	}
+
+	return result, nil
+}
+
+// reminderWindow jendela terkecil yang sudah dimasuki, 0 jika belum masuk jendela mana pun
+func (s *certificationService) reminderWindow(remaining int) int {
+	window := 0
+	for _, day := range s.reminderDays {
+		if remaining <= day {
+			window = day
+		}
+	}
+	return window
+}
+
+// StartExpiryMonitor menjalankan CheckExpiry secara berkala di background
+func (s *certificationService) StartExpiryMonitor(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+			result, err := s.CheckExpiry(ctx)
+			cancel()
+			if err != nil {
+				fmt.Printf("Warning: Pemeriksaan masa berlaku sertifikasi gagal: %v\n", err)
+				continue
+			}
+			if result.Notified > 0 {
+				fmt.Printf("Masa berlaku sertifikasi: %d pengingat dikirim\n", result.Notified)
+			}
+		}
+	}()
+}
+
+// certificationExpired true jika achievement sertifikasi dengan masa berlaku yang sudah lewat
+func certificationExpired(achievement *model.Achievement, now time.Time) bool {
+	return achievement.AchievementType == model.AchievementTypeCertification &&
+		achievement.Details.ValidUntil != nil && achievement.Details.ValidUntil.Before(now)
+}
+
+// daysUntil sisa hari (dibulatkan ke atas) hingga t, negatif jika sudah lewat
+func daysUntil(t time.Time, now time.Time) int {
+	return int(math.Ceil(t.Sub(now).Hours() / 24))
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
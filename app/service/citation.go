@@ -0,0 +1,563 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+	"golang.org/x/text/unicode/norm"
+)
+
+// citationEntry satu entri BibTeX atau RIS sebelum dipetakan ke achievement.
+// Nama field memakai nama field BibTeX (lowercase); tag RIS diterjemahkan ke nama tersebut.
+type citationEntry struct {
+	Key     string
+	Type    string // article, inproceedings, book, ...
+	Fields  map[string]string
+	Authors []string
+	Tags    []string
+}
+
+// Pemetaan tipe entri ke PublicationType. Field Publisher menyimpan nama jurnal untuk
+// journal, nama prosiding untuk conference dan penerbit untuk book.
+var bibTeXPublicationTypes = map[string]model.PublicationType{
+	"article":       model.PublicationTypeJournal,
+	"inproceedings": model.PublicationTypeConference,
+	"conference":    model.PublicationTypeConference,
+	"proceedings":   model.PublicationTypeConference,
+	"book":          model.PublicationTypeBook,
+	"inbook":        model.PublicationTypeBook,
+	"incollection":  model.PublicationTypeBook,
+}
+
+var risPublicationTypes = map[string]string{
+	"JOUR":   "article",
+	"EJOUR":  "article",
+	"MGZN":   "article",
+	"CONF":   "inproceedings",
+	"CPAPER": "inproceedings",
+	"BOOK":   "book",
+	"EBOOK":  "book",
+	"CHAP":   "incollection",
+	"ECHAP":  "incollection",
+	"EDBOOK": "book",
+}
+
+// risFields tag RIS yang dipakai beserta nama field BibTeX padanannya
+var risFields = map[string]string{
+	"TI": "title", "T1": "title",
+	"JO": "journal", "JF": "journal", "JA": "journal", "T2": "booktitle",
+	"PB": "publisher",
+	"SN": "issn",
+	"DO": "doi",
+	"PY": "year", "Y1": "year", "DA": "date",
+	"CY": "address",
+	"AB": "abstract", "N2": "abstract",
+	"ID": "key",
+}
+
+var bibTeXMonths = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+// parseBibTeX membaca entri BibTeX. @comment, @preamble dan @string dilewati; makro
+// @string tidak diekspansi kecuali nama bulan.
+func parseBibTeX(data string) ([]citationEntry, error) {
+	p := &bibTeXParser{data: data}
+	var entries []citationEntry
+
+	for {
+		at := strings.IndexByte(p.data[p.pos:], '@')
+		if at < 0 {
+			return entries, nil
+		}
+		p.pos += at + 1
+
+		entryType := strings.ToLower(p.identifier())
+		p.skipSpace()
+		if p.pos >= len(p.data) || (p.data[p.pos] != '{' && p.data[p.pos] != '(') {
+			continue
+		}
+		closing := byte('}')
+		if p.data[p.pos] == '(' {
+			closing = ')'
+		}
+		p.pos++
+
+		if entryType == "comment" || entryType == "preamble" || entryType == "string" {
+			p.skipBlock(closing)
+			continue
+		}
+
+		line := p.line()
+		entry, err := p.entry(entryType, closing)
+		if err != nil {
+			return nil, fmt.Errorf("BibTeX baris %d: %v", line, err)
+		}
+		entries = append(entries, *entry)
+	}
+}
+
+type bibTeXParser struct {
+	data string
+	pos  int
+}
+
+func (p *bibTeXParser) line() int {
+	return strings.Count(p.data[:p.pos], "\n") + 1
+}
+
+func (p *bibTeXParser) skipSpace() {
+	for p.pos < len(p.data) && unicode.IsSpace(rune(p.data[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *bibTeXParser) identifier() string {
+	start := p.pos
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+		if unicode.IsSpace(rune(c)) || strings.IndexByte("{}()=,#\"", c) >= 0 {
+			break
+		}
+		p.pos++
+	}
+	return p.data[start:p.pos]
+}
+
+// skipBlock melompati isi sampai penutup entri, memperhatikan kurung kurawal bersarang
+func (p *bibTeXParser) skipBlock(closing byte) {
+	depth := 0
+	for ; p.pos < len(p.data); p.pos++ {
+		switch c := p.data[p.pos]; {
+		case c == '{':
+			depth++
+		case c == '}' && depth > 0:
+			depth--
+		case c == closing && depth == 0:
+			p.pos++
+			return
+		}
+	}
+}
+
+func (p *bibTeXParser) entry(entryType string, closing byte) (*citationEntry, error) {
+	p.skipSpace()
+	entry := &citationEntry{
+		Key:    strings.TrimSpace(p.identifier()),
+		Type:   entryType,
+		Fields: make(map[string]string),
+	}
+
+	for {
+		p.skipSpace()
+		for p.pos < len(p.data) && p.data[p.pos] == ',' {
+			p.pos++
+			p.skipSpace()
+		}
+		if p.pos >= len(p.data) {
+			return nil, errors.New("entri " + entry.Key + " tidak ditutup")
+		}
+		if p.data[p.pos] == closing {
+			p.pos++
+			break
+		}
+
+		name := strings.ToLower(p.identifier())
+		p.skipSpace()
+		if name == "" || p.pos >= len(p.data) || p.data[p.pos] != '=' {
+			return nil, fmt.Errorf("format field tidak valid pada entri %s", entry.Key)
+		}
+		p.pos++
+
+		value, err := p.value()
+		if err != nil {
+			return nil, fmt.Errorf("field %s pada entri %s: %v", name, entry.Key, err)
+		}
+		entry.Fields[name] = value
+	}
+
+	if author := entry.Fields["author"]; author != "" {
+		for _, name := range bibTeXAuthorSeparator.Split(author, -1) {
+			if name = cleanLaTeX(name); name != "" {
+				entry.Authors = append(entry.Authors, name)
+			}
+		}
+	}
+	entry.Tags = splitImportList(cleanLaTeX(entry.Fields["keywords"]))
+	for name, value := range entry.Fields {
+		if name != "author" {
+			entry.Fields[name] = cleanLaTeX(value)
+		}
+	}
+	return entry, nil
+}
+
+// value membaca nilai field: {..}, "..", angka atau makro, dapat disambung dengan #
+func (p *bibTeXParser) value() (string, error) {
+	var sb strings.Builder
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.data) {
+			return "", errors.New("nilai tidak lengkap")
+		}
+
+		switch p.data[p.pos] {
+		case '{':
+			start := p.pos + 1
+			depth := 0
+			for ; p.pos < len(p.data); p.pos++ {
+				if p.data[p.pos] == '{' {
+					depth++
+				} else if p.data[p.pos] == '}' {
+					depth--
+					if depth == 0 {
+						break
+					}
+				}
+			}
+			if p.pos >= len(p.data) {
+				return "", errors.New("kurung kurawal tidak seimbang")
+			}
+			sb.WriteString(p.data[start:p.pos])
+			p.pos++
+		case '"':
+			start := p.pos + 1
+			depth := 0
+			for p.pos++; p.pos < len(p.data); p.pos++ {
+				c := p.data[p.pos]
+				if c == '{' {
+					depth++
+				} else if c == '}' {
+					depth--
+				} else if c == '"' && depth == 0 && p.data[p.pos-1] != '\\' {
+					break
+				}
+			}
+			if p.pos >= len(p.data) {
+				return "", errors.New("tanda kutip tidak ditutup")
+			}
+			sb.WriteString(p.data[start:p.pos])
+			p.pos++
+		default:
+			macro := p.identifier()
+			if macro == "" {
+				return "", errors.New("nilai tidak valid")
+			}
+			if month, ok := bibTeXMonths[strings.ToLower(macro)]; ok {
+				macro = strconv.Itoa(month)
+			}
+			sb.WriteString(macro)
+		}
+
+		p.skipSpace()
+		if p.pos < len(p.data) && p.data[p.pos] == '#' {
+			p.pos++
+			continue
+		}
+		return sb.String(), nil
+	}
+}
+
+var latexAccents = map[byte]string{
+	'"': "\u0308", '\'': "\u0301", '`': "\u0300", '^': "\u0302", '~': "\u0303", '=': "\u0304", '.': "\u0307",
+}
+
+var bibTeXAuthorSeparator = regexp.MustCompile(`\s+and\s+`)
+
+var latexAccentPattern = regexp.MustCompile("\\\\([\"'`^~=.])\\s*\\{?\\s*([A-Za-z])\\}?")
+
+// cleanLaTeX mengubah markup LaTeX umum pada nilai BibTeX menjadi teks biasa
+func cleanLaTeX(value string) string {
+	value = latexAccentPattern.ReplaceAllStringFunc(value, func(match string) string {
+		parts := latexAccentPattern.FindStringSubmatch(match)
+		return parts[2] + latexAccents[parts[1][0]]
+	})
+	value = strings.NewReplacer(
+		`\&`, "&", `\%`, "%", `\$`, "$", `\#`, "#", `\_`, "_",
+		"{", "", "}", "", "~", " ", "---", "—", "--", "–",
+	).Replace(value)
+	return norm.NFC.String(strings.Join(strings.Fields(value), " "))
+}
+
+// parseRIS membaca entri RIS ("TY  - JOUR" ... "ER  - ")
+func parseRIS(data string) ([]citationEntry, error) {
+	var entries []citationEntry
+	var current *citationEntry
+
+	for i, raw := range strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n") {
+		line := strings.TrimRight(strings.TrimPrefix(raw, "\ufeff"), " \t\r")
+		if len(line) < 5 || line[2:5] != "  -" {
+			// Baris lanjutan untuk abstrak panjang
+			if current != nil && strings.TrimSpace(line) != "" && current.Fields["abstract"] != "" {
+				current.Fields["abstract"] += " " + strings.TrimSpace(line)
+			}
+			continue
+		}
+		tag := line[:2]
+		value := strings.TrimSpace(line[5:])
+
+		if tag == "TY" {
+			if current != nil {
+				return nil, fmt.Errorf("RIS baris %d: entri sebelumnya belum ditutup dengan ER", i+1)
+			}
+			entryType, ok := risPublicationTypes[strings.ToUpper(value)]
+			if !ok {
+				entryType = strings.ToLower(value)
+			}
+			current = &citationEntry{Type: entryType, Fields: make(map[string]string)}
+			continue
+		}
+		if current == nil {
+			continue
+		}
+
+		switch tag {
+		case "ER":
+			entries = append(entries, *current)
+			current = nil
+		case "AU", "A1":
+			current.Authors = append(current.Authors, value)
+		case "KW":
+			current.Tags = append(current.Tags, value)
+		default:
+			if field, ok := risFields[tag]; ok && current.Fields[field] == "" {
+				current.Fields[field] = value
+			}
+		}
+	}
+
+	if current != nil {
+		return nil, errors.New("RIS: entri terakhir belum ditutup dengan ER")
+	}
+	for i := range entries {
+		entries[i].Key = entries[i].Fields["key"]
+		delete(entries[i].Fields, "key")
+	}
+	return entries, nil
+}
+
+// citationToRequest memetakan entri BibTeX/RIS menjadi draft achievement publikasi
+func citationToRequest(entry *citationEntry) (*CreateAchievementRequest, error) {
+	publicationType, ok := bibTeXPublicationTypes[entry.Type]
+	if !ok {
+		return nil, fmt.Errorf("tipe entri %s tidak didukung. Gunakan article, inproceedings atau book", entry.Type)
+	}
+
+	title := entry.Fields["title"]
+	details := model.AchievementDetails{
+		PublicationType:  &publicationType,
+		PublicationTitle: optionalString(title),
+		Authors:          entry.Authors,
+		ISSN:             optionalString(entry.Fields["issn"]),
+		DOI:              optionalString(normalizeDOI(entry.Fields["doi"])),
+		Location:         optionalString(entry.Fields["address"]),
+		EventDate:        citationDate(entry.Fields),
+	}
+
+	venue := entry.Fields["publisher"]
+	switch publicationType {
+	case model.PublicationTypeJournal:
+		venue = firstNonEmpty(entry.Fields["journal"], venue)
+	case model.PublicationTypeConference:
+		venue = firstNonEmpty(entry.Fields["booktitle"], entry.Fields["journal"], venue)
+	}
+	details.Publisher = optionalString(venue)
+
+	description := entry.Fields["abstract"]
+	if description == "" && venue != "" {
+		description = "Dipublikasikan di " + venue
+		if details.EventDate != nil {
+			description += fmt.Sprintf(" (%d)", details.EventDate.Year())
+		}
+	}
+
+	return &CreateAchievementRequest{
+		AchievementType: model.AchievementTypePublication,
+		Title:           title,
+		Description:     description,
+		Details:         details,
+		Tags:            entry.Tags,
+	}, nil
+}
+
+// citationDate tanggal terbit dari year/month (BibTeX) atau DA/PY "YYYY/MM/DD/" (RIS)
+func citationDate(fields map[string]string) *time.Time {
+	year, month, day := 0, 1, 1
+	for _, value := range []string{fields["date"], fields["year"]} {
+		parts := strings.FieldsFunc(value, func(r rune) bool { return r == '/' || r == '-' })
+		if len(parts) == 0 {
+			continue
+		}
+		if y, err := strconv.Atoi(strings.TrimSpace(parts[0])); err == nil && y > 0 {
+			year = y
+			if len(parts) > 1 {
+				if m, err := strconv.Atoi(parts[1]); err == nil && m >= 1 && m <= 12 {
+					month = m
+				}
+			}
+			if len(parts) > 2 {
+				if d, err := strconv.Atoi(parts[2]); err == nil && d >= 1 && d <= 31 {
+					day = d
+				}
+			}
+			break
+		}
+	}
+	if year == 0 {
+		return nil
+	}
+	if m, err := strconv.Atoi(fields["month"]); err == nil && m >= 1 && m <= 12 {
+		month = m
+	} else if m, ok := bibTeXMonths[strings.ToLower(truncate(fields["month"], 3))]; ok {
+		month = m
+	}
+	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	return &date
+}
+
+func optionalString(value string) *string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func truncate(value string, n int) string {
+	if len(value) > n {
+		return value[:n]
+	}
+	return value
+}
+
+// writeBibTeX menulis achievement publikasi sebagai entri BibTeX
+func writeBibTeX(sb *strings.Builder, achievement *model.Achievement) {
+	details := &achievement.Details
+	entryType := "misc"
+	venueField := "publisher"
+	if details.PublicationType != nil {
+		switch *details.PublicationType {
+		case model.PublicationTypeJournal:
+			entryType, venueField = "article", "journal"
+		case model.PublicationTypeConference:
+			entryType, venueField = "inproceedings", "booktitle"
+		case model.PublicationTypeBook:
+			entryType = "book"
+		}
+	}
+
+	fmt.Fprintf(sb, "@%s{%s,\n", entryType, citationKey(achievement))
+	field := func(name, value string) {
+		if strings.TrimSpace(value) != "" {
+			fmt.Fprintf(sb, "  %s = {%s},\n", name, escapeBibTeX(value))
+		}
+	}
+
+	field("author", strings.Join(details.Authors, " and "))
+	field("title", firstNonEmpty(stringValue(details.PublicationTitle), achievement.Title))
+	field(venueField, stringValue(details.Publisher))
+	if date := achievementDate(*details); date != nil {
+		field("year", strconv.Itoa(date.Year()))
+		fmt.Fprintf(sb, "  month = %s,\n", strings.ToLower(date.Month().String()[:3]))
+	}
+	field("issn", stringValue(details.ISSN))
+	field("doi", stringValue(details.DOI))
+	field("address", stringValue(details.Location))
+	field("keywords", strings.Join(achievement.Tags, ", "))
+	sb.WriteString("}\n\n")
+}
+
+// writeRIS menulis achievement publikasi sebagai entri RIS (baris diakhiri CRLF)
+func writeRIS(sb *strings.Builder, achievement *model.Achievement) {
+	details := &achievement.Details
+	entryType, venueTag := "GEN", "PB"
+	if details.PublicationType != nil {
+		switch *details.PublicationType {
+		case model.PublicationTypeJournal:
+			entryType, venueTag = "JOUR", "JO"
+		case model.PublicationTypeConference:
+			entryType, venueTag = "CONF", "T2"
+		case model.PublicationTypeBook:
+			entryType = "BOOK"
+		}
+	}
+
+	tag := func(name, value string) {
+		if value = strings.Join(strings.Fields(value), " "); value != "" {
+			fmt.Fprintf(sb, "%s  - %s\r\n", name, value)
+		}
+	}
+
+	tag("TY", entryType)
+	tag("ID", citationKey(achievement))
+	for _, author := range details.Authors {
+		tag("AU", author)
+	}
+	tag("TI", firstNonEmpty(stringValue(details.PublicationTitle), achievement.Title))
+	tag(venueTag, stringValue(details.Publisher))
+	if date := achievementDate(*details); date != nil {
+		tag("PY", strconv.Itoa(date.Year()))
+		tag("DA", date.Format("2006/01/02/"))
+	}
+	tag("SN", stringValue(details.ISSN))
+	tag("DO", stringValue(details.DOI))
+	tag("CY", stringValue(details.Location))
+	for _, keyword := range achievement.Tags {
+		tag("KW", keyword)
+	}
+	sb.WriteString("ER  - \r\n\r\n")
+}
+
+// citationKey kunci entri: nama belakang penulis pertama + tahun + akhiran ID achievement
+func citationKey(achievement *model.Achievement) string {
+	name := "anon"
+	if len(achievement.Details.Authors) > 0 {
+		author := achievement.Details.Authors[0]
+		if i := strings.Index(author, ","); i >= 0 {
+			author = author[:i]
+		} else if fields := strings.Fields(author); len(fields) > 0 {
+			author = fields[len(fields)-1]
+		}
+		var sb strings.Builder
+		for _, r := range norm.NFD.String(author) {
+			if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+				sb.WriteRune(unicode.ToLower(r))
+			}
+		}
+		if sb.Len() > 0 {
+			name = sb.String()
+		}
+	}
+
+	year := ""
+	if date := achievementDate(achievement.Details); date != nil {
+		year = strconv.Itoa(date.Year())
+	}
+	id := achievement.ID.Hex()
+	return name + year + "_" + id[len(id)-6:]
+}
+
+func escapeBibTeX(value string) string {
+	value = strings.NewReplacer(`\`, "", "&", `\&`, "%", `\%`, "$", `\$`, "#", `\#`, "_", `\_`).Replace(value)
+	// Kurung kurawal yang tidak seimbang merusak entri, buang saja
+	if strings.Count(value, "{") != strings.Count(value, "}") {
+		value = strings.NewReplacer("{", "", "}", "").Replace(value)
+	}
+	return strings.Join(strings.Fields(value), " ")
+}
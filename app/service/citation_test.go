@@ -0,0 +1,166 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+)
+
+func TestParseBibTeX(t *testing.T) {
+	data := `
+@comment{diekspor dari Zotero}
+@string{jsi = "Jurnal Sistem Informasi"}
+
+@article{sari2024deteksi,
+  title     = {Deteksi {Plagiarisme} pada Karya Ilmiah Mahasiswa},
+  author    = {Sari, Dewi and M{\"u}ller, Jan and Hern\'{a}ndez, Ana},
+  journal   = "Jurnal " # "Sistem Informasi",
+  year      = 2024,
+  month     = mar,
+  issn      = {0378-5955},
+  doi       = {https://doi.org/10.1000/JSI.2024.01},
+  keywords  = {plagiarisme, NLP, teks},
+}
+
+@inproceedings(putra2023,
+  title = "Sistem Rekomendasi \& Personalisasi",
+  author = "Putra, Andi",
+  booktitle = {Prosiding SNATI},
+  year = {2023}
+)
+`
+
+	entries, err := parseBibTeX(data)
+	if err != nil {
+		t.Fatalf("parseBibTeX() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("parseBibTeX() = %d entri, want 2", len(entries))
+	}
+
+	article := entries[0]
+	if article.Key != "sari2024deteksi" || article.Type != "article" {
+		t.Errorf("key/type = %s/%s", article.Key, article.Type)
+	}
+	wantFields := map[string]string{
+		"title":   "Deteksi Plagiarisme pada Karya Ilmiah Mahasiswa",
+		"journal": "Jurnal Sistem Informasi",
+		"year":    "2024",
+		"month":   "3",
+		"issn":    "0378-5955",
+	}
+	for name, want := range wantFields {
+		if got := article.Fields[name]; got != want {
+			t.Errorf("field %s = %q, want %q", name, got, want)
+		}
+	}
+	wantAuthors := []string{"Sari, Dewi", "Müller, Jan", "Hernández, Ana"}
+	if !reflect.DeepEqual(article.Authors, wantAuthors) {
+		t.Errorf("authors = %q, want %q", article.Authors, wantAuthors)
+	}
+	if len(article.Tags) != 3 {
+		t.Errorf("tags = %q, want 3 tag", article.Tags)
+	}
+
+	proceedings := entries[1]
+	if proceedings.Type != "inproceedings" || proceedings.Fields["title"] != "Sistem Rekomendasi & Personalisasi" {
+		t.Errorf("entri kedua = %+v", proceedings)
+	}
+
+	req, err := citationToRequest(&article)
+	if err != nil {
+		t.Fatalf("citationToRequest() error = %v", err)
+	}
+	if req.AchievementType != model.AchievementTypePublication ||
+		*req.Details.PublicationType != model.PublicationTypeJournal ||
+		*req.Details.Publisher != "Jurnal Sistem Informasi" ||
+		*req.Details.DOI != "10.1000/jsi.2024.01" {
+		t.Errorf("citationToRequest() = %+v", req.Details)
+	}
+	if date := req.Details.EventDate; date == nil || date.Year() != 2024 || date.Month() != 3 {
+		t.Errorf("event_date = %v, want Maret 2024", date)
+	}
+}
+
+func TestParseBibTeXErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"entri tidak ditutup", "@article{a,\n title = {Judul}", "tidak ditutup"},
+		{"kurung tidak seimbang", "@article{a,\n title = {Judul {bersarang}\n}", "baris 1"},
+		{"kutip tidak ditutup", "@article{a, title = \"Judul }", "tanda kutip tidak ditutup"},
+		{"field tanpa =", "@article{a, title {Judul}}", "format field tidak valid"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseBibTeX(tt.data)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("parseBibTeX() error = %v, want memuat %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRIS(t *testing.T) {
+	data := "\ufeffTY  - JOUR\r\n" +
+		"ID  - wijaya2022\r\n" +
+		"TI  - Analisis Sentimen Ulasan Aplikasi\r\n" +
+		"AU  - Wijaya, Budi\r\n" +
+		"AU  - Lestari, Citra\r\n" +
+		"JO  - Jurnal Informatika\r\n" +
+		"PY  - 2022\r\n" +
+		"DA  - 2022/07/15/\r\n" +
+		"KW  - sentimen\r\n" +
+		"AB  - Penelitian ini menganalisis\r\n" +
+		"ulasan pengguna.\r\n" +
+		"ER  - \r\n" +
+		"TY  - CHAP\r\n" +
+		"TI  - Bab Buku\r\n" +
+		"ER  - \r\n"
+
+	entries, err := parseRIS(data)
+	if err != nil {
+		t.Fatalf("parseRIS() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("parseRIS() = %d entri, want 2", len(entries))
+	}
+
+	entry := entries[0]
+	if entry.Key != "wijaya2022" || entry.Type != "article" {
+		t.Errorf("key/type = %s/%s", entry.Key, entry.Type)
+	}
+	if !reflect.DeepEqual(entry.Authors, []string{"Wijaya, Budi", "Lestari, Citra"}) {
+		t.Errorf("authors = %q", entry.Authors)
+	}
+	if entry.Fields["abstract"] != "Penelitian ini menganalisis ulasan pengguna." {
+		t.Errorf("abstract = %q", entry.Fields["abstract"])
+	}
+	if _, ok := entry.Fields["key"]; ok {
+		t.Error("field key seharusnya dipindah ke Key")
+	}
+	if date := citationDate(entry.Fields); date == nil || date.Month() != 7 || date.Day() != 15 {
+		t.Errorf("citationDate() = %v, want 15 Juli 2022", date)
+	}
+	if entries[1].Type != "incollection" {
+		t.Errorf("type CHAP = %s, want incollection", entries[1].Type)
+	}
+
+	if _, err := parseRIS("TY  - JOUR\nTI  - Tanpa penutup\n"); err == nil {
+		t.Error("parseRIS() tanpa ER seharusnya error")
+	}
+	if _, err := parseRIS("TY  - JOUR\nTY  - BOOK\nER  - \n"); err == nil {
+		t.Error("parseRIS() dengan TY bersarang seharusnya error")
+	}
+}
+
+func TestCitationToRequestUnsupportedType(t *testing.T) {
+	if _, err := citationToRequest(&citationEntry{Type: "misc", Fields: map[string]string{}}); err == nil {
+		t.Error("citationToRequest() tipe misc seharusnya error")
+	}
+}
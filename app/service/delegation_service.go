@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/repository"
+)
+
+type DelegationService interface {
+	GetDelegations(ctx context.Context, userID uuid.UUID) ([]model.AdvisorDelegation, error)
+	CreateDelegation(ctx context.Context, userID uuid.UUID, req *DelegationRequest) (*model.AdvisorDelegation, error)
+	DeleteDelegation(ctx context.Context, userID uuid.UUID, delegationID uuid.UUID) error
+}
+
+type delegationService struct {
+	delegationRepo repository.DelegationRepository
+	lecturerRepo   repository.LecturerRepository
+	userRepo       repository.UserRepository
+}
+
+func NewDelegationService(
+	delegationRepo repository.DelegationRepository,
+	lecturerRepo repository.LecturerRepository,
+	userRepo repository.UserRepository,
+) DelegationService {
+	return &delegationService{
+		delegationRepo: delegationRepo,
+		lecturerRepo:   lecturerRepo,
+		userRepo:       userRepo,
+	}
+}
+
+type DelegationRequest struct {
+	LecturerID *uuid.UUID `json:"lecturer_id,omitempty"` // Wajib untuk admin, dosen otomatis dirinya sendiri
+	DelegateID uuid.UUID  `json:"delegate_id"`
+	StartDate  string     `json:"start_date"` // YYYY-MM-DD atau RFC3339
+	EndDate    string     `json:"end_date"`   // Tanggal saja berarti sampai akhir hari tersebut
+	Reason     string     `json:"reason"`
+}
+
+// currentActor mengembalikan record dosen jika user adalah dosen, atau nil jika admin
+func (s *delegationService) currentActor(ctx context.Context, userID uuid.UUID) (*model.Lecturer, error) {
+	if lecturer, err := s.lecturerRepo.FindLecturerByUserID(ctx, userID); err == nil {
+		return lecturer, nil
+	}
+
+	user, err := s.userRepo.FindUserByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("user tidak ditemukan")
+	}
+	if !strings.Contains(strings.ToLower(user.Role.Name), "admin") {
+		return nil, errors.New("hanya dosen wali atau admin yang dapat mengelola delegasi")
+	}
+	return nil, nil
+}
+
+func (s *delegationService) GetDelegations(ctx context.Context, userID uuid.UUID) ([]model.AdvisorDelegation, error) {
+	lecturer, err := s.currentActor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var delegations []model.AdvisorDelegation
+	if lecturer != nil {
+		delegations, err = s.delegationRepo.FindDelegationsByLecturer(ctx, lecturer.ID)
+	} else {
+		delegations, err = s.delegationRepo.FindAllDelegations(ctx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil delegasi: %v", err)
+	}
+	return delegations, nil
+}
+
+func (s *delegationService) CreateDelegation(ctx context.Context, userID uuid.UUID, req *DelegationRequest) (*model.AdvisorDelegation, error) {
+	actor, err := s.currentActor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Dosen hanya dapat mendelegasikan dirinya sendiri
+	var lecturerID uuid.UUID
+	if actor != nil {
+		if req.LecturerID != nil && *req.LecturerID != actor.ID {
+			return nil, errors.New("dosen hanya dapat mendelegasikan tugas verifikasinya sendiri")
+		}
+		lecturerID = actor.ID
+	} else {
+		if req.LecturerID == nil {
+			return nil, errors.New("lecturer_id harus diisi")
+		}
+		lecturerID = *req.LecturerID
+	}
+
+	if _, err := s.lecturerRepo.FindLecturerByID(ctx, lecturerID); err != nil {
+		return nil, errors.New("dosen wali tidak ditemukan")
+	}
+	if req.DelegateID == uuid.Nil {
+		return nil, errors.New("delegate_id harus diisi")
+	}
+	if req.DelegateID == lecturerID {
+		return nil, errors.New("dosen pengganti tidak boleh sama dengan dosen wali")
+	}
+	if _, err := s.lecturerRepo.FindLecturerByID(ctx, req.DelegateID); err != nil {
+		return nil, errors.New("dosen pengganti tidak ditemukan")
+	}
+
+	startDate, err := parseFieldDate(strings.TrimSpace(req.StartDate))
+	if err != nil {
+		return nil, errors.New("start_date tidak valid. Gunakan format YYYY-MM-DD")
+	}
+	endDate, err := parseDelegationEndDate(strings.TrimSpace(req.EndDate))
+	if err != nil {
+		return nil, errors.New("end_date tidak valid. Gunakan format YYYY-MM-DD")
+	}
+	if endDate.Before(startDate) {
+		return nil, errors.New("end_date tidak boleh sebelum start_date")
+	}
+
+	overlapping, err := s.delegationRepo.FindOverlappingDelegations(ctx, lecturerID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("gagal memeriksa delegasi: %v", err)
+	}
+	if len(overlapping) > 0 {
+		return nil, errors.New("sudah ada delegasi lain pada rentang tanggal tersebut")
+	}
+
+	delegation := &model.AdvisorDelegation{
+		LecturerID: lecturerID,
+		DelegateID: req.DelegateID,
+		StartDate:  startDate,
+		EndDate:    endDate,
+		Reason:     strings.TrimSpace(req.Reason),
+		CreatedBy:  userID,
+	}
+	if err := s.delegationRepo.CreateDelegation(ctx, delegation); err != nil {
+		return nil, fmt.Errorf("gagal menyimpan delegasi: %v", err)
+	}
+
+	return s.delegationRepo.FindDelegationByID(ctx, delegation.ID)
+}
+
+func (s *delegationService) DeleteDelegation(ctx context.Context, userID uuid.UUID, delegationID uuid.UUID) error {
+	actor, err := s.currentActor(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	delegation, err := s.delegationRepo.FindDelegationByID(ctx, delegationID)
+	if err != nil {
+		return errors.New("delegasi tidak ditemukan")
+	}
+
+	if actor != nil && delegation.LecturerID != actor.ID {
+		return errors.New("anda hanya dapat menghapus delegasi milik anda sendiri")
+	}
+
+	if err := s.delegationRepo.DeleteDelegation(ctx, delegationID); err != nil {
+		return fmt.Errorf("gagal menghapus delegasi: %v", err)
+	}
+	return nil
+}
+
+// parseDelegationEndDate tanggal tanpa jam berlaku sampai akhir hari tersebut
+func parseDelegationEndDate(value string) (time.Time, error) {
+	if date, err := time.Parse("2006-01-02", value); err == nil {
+		return date.Add(24*time.Hour - time.Nanosecond), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
@@ -0,0 +1,669 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/repository"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/helper"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultImportBatchSize = 100
+	maxImportBatchSize     = 1000
+	maxImportRows          = 5000
+)
+
+// Status baris pada laporan import
+const (
+	ImportRowValid    = "valid"
+	ImportRowImported = "imported"
+	ImportRowFailed   = "failed"
+)
+
+// importFields nama kolom default file import. Kolom tambahan untuk tipe custom ditulis
+// dengan prefix "custom.", misalnya custom.nama_hibah.
+var importFields = []string{
+	"nim", "achievement_type", "title", "description", "tags",
+	"competition_name", "competition_level", "rank", "medal_type",
+	"event_date", "location", "organizer", "score",
+	"publication_type", "publication_title", "authors", "publisher", "issn", "doi",
+	"organization_name", "position", "period_start", "period_end",
+	"certification_name", "issued_by", "certification_number", "valid_until",
+}
+
+var requiredImportFields = []string{"nim", "achievement_type", "title"}
+
+type ImportService interface {
+	ImportAchievements(ctx context.Context, userID uuid.UUID, req *ImportAchievementsRequest) (*ImportReport, error)
+}
+
+type importService struct {
+	achievementRepo  repository.AchievementRepository
+	historyRepo      repository.AchievementHistoryRepository
+	typeRepo         repository.AchievementTypeRepository
+	studentRepo      repository.StudentRepository
+	userRepo         repository.UserRepository
+	pointRuleService PointRuleService
+}
+
+func NewImportService(
+	achievementRepo repository.AchievementRepository,
+	historyRepo repository.AchievementHistoryRepository,
+	typeRepo repository.AchievementTypeRepository,
+	studentRepo repository.StudentRepository,
+	userRepo repository.UserRepository,
+	pointRuleService PointRuleService,
+) ImportService {
+	return &importService{
+		achievementRepo:  achievementRepo,
+		historyRepo:      historyRepo,
+		typeRepo:         typeRepo,
+		studentRepo:      studentRepo,
+		userRepo:         userRepo,
+		pointRuleService: pointRuleService,
+	}
+}
+
+// ImportAchievementsRequest isi file beserta opsi import
+type ImportAchievementsRequest struct {
+	FileName  string
+	Content   []byte
+	Format    string                  // csv atau xlsx; kosong = dari ekstensi FileName
+	Sheet     string                  // khusus XLSX; kosong = sheet pertama
+	Mapping   map[string]string       // field import -> judul kolom di file
+	Status    model.AchievementStatus // draft (default) atau verified
+	DryRun    bool
+	BatchSize int
+}
+
+type ImportReport struct {
+	DryRun       bool              `json:"dry_run"`
+	Status       string            `json:"status"`
+	TotalRows    int               `json:"total_rows"`
+	ValidRows    int               `json:"valid_rows"`
+	ImportedRows int               `json:"imported_rows"`
+	FailedRows   int               `json:"failed_rows"`
+	Rows         []ImportRowResult `json:"rows"`
+}
+
+type ImportRowResult struct {
+	Row           int          `json:"row"` // nomor baris di file, baris 1 adalah header
+	NIM           string       `json:"nim"`
+	Title         string       `json:"title"`
+	Status        string       `json:"status"`
+	AchievementID string       `json:"achievement_id,omitempty"`
+	Errors        []FieldError `json:"errors,omitempty"`
+}
+
+// importEntry baris valid yang siap disimpan
+type importEntry struct {
+	result      int // indeks pada ImportReport.Rows
+	achievement *model.Achievement
+	student     *model.Student
+	notes       string
+}
+
+func (s *importService) isAdmin(ctx context.Context, userID uuid.UUID) bool {
+	user, err := s.userRepo.FindUserByID(ctx, userID)
+	return err == nil && strings.Contains(strings.ToLower(user.Role.Name), "admin")
+}
+
+// ImportAchievements membaca file CSV/XLSX, memvalidasi setiap baris dengan aturan yang sama
+// seperti POST /achievements, lalu menyimpan baris valid per batch. Baris yang gagal tidak
+// menghentikan import; alasannya dilaporkan per baris. Dengan DryRun tidak ada yang disimpan.
+func (s *importService) ImportAchievements(ctx context.Context, userID uuid.UUID, req *ImportAchievementsRequest) (*ImportReport, error) {
+	if !s.isAdmin(ctx, userID) {
+		return nil, errors.New("hanya admin yang dapat mengimpor prestasi")
+	}
+
+	status := req.Status
+	if status == "" {
+		status = model.StatusDraft
+	}
+	if status != model.StatusDraft && status != model.StatusVerified {
+		return nil, errors.New("status import tidak valid. Pilih: draft, verified")
+	}
+
+	batchSize := req.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultImportBatchSize
+	}
+	if batchSize > maxImportBatchSize {
+		batchSize = maxImportBatchSize
+	}
+
+	rows, err := readImportRows(req)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("file import kosong")
+	}
+
+	columns, err := resolveImportColumns(rows[0], req.Mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ImportReport{
+		DryRun: req.DryRun,
+		Status: string(status),
+		Rows:   []ImportRowResult{},
+	}
+
+	students := make(map[string]*model.Student)
+	customTypes := make(map[model.AchievementType]*model.AchievementTypeDefinition)
+	var entries []importEntry
+
+	for i := 1; i < len(rows); i++ {
+		if isBlankRow(rows[i]) {
+			continue
+		}
+		if report.TotalRows == maxImportRows {
+			return nil, fmt.Errorf("maksimal %d baris per import", maxImportRows)
+		}
+		report.TotalRows++
+
+		result := ImportRowResult{
+			Row:   i + 1,
+			NIM:   cellValue(rows[i], columns, "nim"),
+			Title: cellValue(rows[i], columns, "title"),
+		}
+
+		entry, errs := s.parseImportRow(ctx, rows[i], columns, status, students, customTypes)
+		if len(errs) > 0 {
+			result.Status = ImportRowFailed
+			result.Errors = errs
+			report.Rows = append(report.Rows, result)
+			continue
+		}
+
+		result.Status = ImportRowValid
+		entry.notes = "Achievement diimpor dari " + req.FileName
+		if status == model.StatusVerified {
+			points, rule, err := s.pointRuleService.CalculatePoints(ctx, entry.achievement)
+			if err != nil {
+				result.Status = ImportRowFailed
+				result.Errors = []FieldError{{Field: "points", Message: err.Error()}}
+				report.Rows = append(report.Rows, result)
+				continue
+			}
+			entry.achievement.Points = points
+			entry.notes = fmt.Sprintf("%s sebagai terverifikasi (poin: %.2f%s)", entry.notes, points, pointRuleNote(rule))
+		}
+		entry.result = len(report.Rows)
+		report.Rows = append(report.Rows, result)
+		entries = append(entries, *entry)
+	}
+
+	report.ValidRows = len(entries)
+	if !req.DryRun {
+		for start := 0; start < len(entries); start += batchSize {
+			end := start + batchSize
+			if end > len(entries) {
+				end = len(entries)
+			}
+			s.saveImportBatch(ctx, userID, entries[start:end], status, report.Rows)
+		}
+	}
+
+	for _, row := range report.Rows {
+		switch row.Status {
+		case ImportRowImported:
+			report.ImportedRows++
+		case ImportRowFailed:
+			report.FailedRows++
+		}
+	}
+	return report, nil
+}
+
+// saveImportBatch menyimpan dokumen Mongo, reference dan history satu batch. Jika reference
+// gagal disimpan, dokumen Mongo batch tersebut dihapus kembali agar tidak ada data yatim.
+func (s *importService) saveImportBatch(ctx context.Context, userID uuid.UUID, batch []importEntry, status model.AchievementStatus, results []ImportRowResult) {
+	now := time.Now()
+
+	achievements := make([]*model.Achievement, len(batch))
+	for i := range batch {
+		batch[i].achievement.ID = primitive.NewObjectID()
+		achievements[i] = batch[i].achievement
+	}
+
+	fail := func(message string) {
+		for _, entry := range batch {
+			s.achievementRepo.PurgeAchievement(ctx, entry.achievement.ID.Hex())
+			results[entry.result].Status = ImportRowFailed
+			results[entry.result].Errors = []FieldError{{Field: "row", Message: message}}
+		}
+	}
+
+	if err := s.achievementRepo.CreateAchievements(ctx, achievements); err != nil {
+		fail(fmt.Sprintf("gagal menyimpan achievement: %v", err))
+		return
+	}
+
+	references := make([]model.AchievementReference, len(batch))
+	for i, entry := range batch {
+		references[i] = model.AchievementReference{
+			ID:                 uuid.New(),
+			StudentID:          entry.student.ID,
+			MongoAchievementID: entry.achievement.ID.Hex(),
+			Status:             status,
+		}
+		if status == model.StatusVerified {
+			references[i].SubmittedAt = &now
+			references[i].VerifiedAt = &now
+			references[i].VerifiedBy = &userID
+		}
+	}
+	if err := s.achievementRepo.CreateReferences(ctx, references); err != nil {
+		fail(fmt.Sprintf("gagal menyimpan reference: %v", err))
+		return
+	}
+
+	histories := make([]model.AchievementHistory, len(batch))
+	for i, entry := range batch {
+		histories[i] = model.AchievementHistory{
+			AchievementRefID:   references[i].ID,
+			MongoAchievementID: entry.achievement.ID.Hex(),
+			OldStatus:          nil,
+			NewStatus:          status,
+			ChangedBy:          userID,
+			Notes:              entry.notes,
+			IsOverride:         status == model.StatusVerified, // Melewati alur persetujuan
+		}
+	}
+	if err := s.historyRepo.CreateHistories(ctx, histories); err != nil {
+		fmt.Printf("Warning: Gagal membuat history import: %v\n", err)
+	}
+
+	// Sertifikat dan badge untuk achievement terverifikasi diterbitkan saat pertama kali diminta
+	for _, entry := range batch {
+		results[entry.result].Status = ImportRowImported
+		results[entry.result].AchievementID = entry.achievement.ID.Hex()
+	}
+}
+
+// parseImportRow mengubah satu baris menjadi achievement dan memvalidasinya
+func (s *importService) parseImportRow(
+	ctx context.Context,
+	row []string,
+	columns map[string]int,
+	status model.AchievementStatus,
+	students map[string]*model.Student,
+	customTypes map[model.AchievementType]*model.AchievementTypeDefinition,
+) (*importEntry, []FieldError) {
+	var errs fieldErrors
+
+	achievement := &model.Achievement{
+		AchievementType: model.AchievementType(strings.ToLower(cellValue(row, columns, "achievement_type"))),
+		Title:           cellValue(row, columns, "title"),
+		Description:     cellValue(row, columns, "description"),
+		Attachments:     []model.Attachment{},
+		Tags:            splitImportList(cellValue(row, columns, "tags")),
+		Status:          status,
+	}
+	if achievement.Tags == nil {
+		achievement.Tags = []string{}
+	}
+
+	nim := cellValue(row, columns, "nim")
+	student, cached := students[nim]
+	if !cached && nim != "" {
+		found, err := s.studentRepo.FindStudentByStudentID(ctx, nim)
+		if err == nil {
+			student = found
+		}
+		students[nim] = student
+	}
+	if nim == "" {
+		errs.add("nim", "harus diisi")
+	} else if student == nil {
+		errs.add("nim", "mahasiswa dengan NIM "+nim+" tidak ditemukan")
+	} else {
+		achievement.StudentID = student.ID.String()
+	}
+
+	// Tipe custom harus aktif, sama seperti saat membuat achievement
+	var customType *model.AchievementTypeDefinition
+	if achievement.AchievementType != "" && !isBuiltInAchievementType(achievement.AchievementType) {
+		definition, cached := customTypes[achievement.AchievementType]
+		if !cached {
+			found, err := s.typeRepo.FindTypeByCode(ctx, achievement.AchievementType)
+			if err == nil && found.IsActive {
+				definition = found
+			}
+			customTypes[achievement.AchievementType] = definition
+		}
+		customType = definition
+	}
+
+	// Urut sesuai posisi kolom agar laporan error konsisten antar import
+	keys := make([]string, 0, len(columns))
+	for key := range columns {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return columns[keys[i]] < columns[keys[j]] })
+
+	for _, key := range keys {
+		index := columns[key]
+		if index >= len(row) {
+			continue
+		}
+		value := strings.TrimSpace(row[index])
+		if value == "" {
+			continue
+		}
+		if err := setImportDetail(&achievement.Details, key, value, customType); err != nil {
+			errs.add(importFieldPath(key), err.Error())
+		}
+	}
+
+	if err := validateAchievement(achievement, customType); err != nil {
+		var validationErr *ValidationError
+		if errors.As(err, &validationErr) {
+			errs = append(errs, validationErr.Errors...)
+		} else {
+			errs.add("row", err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return &importEntry{achievement: achievement, student: student}, nil
+}
+
+// setImportDetail mengisi satu kolom details. Kolom umum (nim, title, dst.) diabaikan di sini.
+func setImportDetail(details *model.AchievementDetails, key, value string, customType *model.AchievementTypeDefinition) error {
+	switch key {
+	case "competition_name":
+		details.CompetitionName = &value
+	case "competition_level":
+		level := model.CompetitionLevel(strings.ToLower(value))
+		details.CompetitionLevel = &level
+	case "rank":
+		rank, err := parseImportInt(value)
+		if err != nil {
+			return err
+		}
+		details.Rank = &rank
+	case "medal_type":
+		details.MedalType = &value
+	case "event_date":
+		date, err := parseImportDate(value)
+		if err != nil {
+			return err
+		}
+		details.EventDate = &date
+	case "location":
+		details.Location = &value
+	case "organizer":
+		details.Organizer = &value
+	case "score":
+		score, err := parseImportFloat(value)
+		if err != nil {
+			return err
+		}
+		details.Score = &score
+	case "publication_type":
+		publicationType := model.PublicationType(strings.ToLower(value))
+		details.PublicationType = &publicationType
+	case "publication_title":
+		details.PublicationTitle = &value
+	case "authors":
+		details.Authors = splitImportList(value)
+	case "publisher":
+		details.Publisher = &value
+	case "issn":
+		details.ISSN = &value
+	case "doi":
+		details.DOI = &value
+	case "organization_name":
+		details.OrganizationName = &value
+	case "position":
+		details.Position = &value
+	case "period_start", "period_end":
+		date, err := parseImportDate(value)
+		if err != nil {
+			return err
+		}
+		if details.Period == nil {
+			details.Period = &model.Period{}
+		}
+		if key == "period_start" {
+			details.Period.Start = date
+		} else {
+			details.Period.End = date
+		}
+	case "certification_name":
+		details.CertificationName = &value
+	case "issued_by":
+		details.IssuedBy = &value
+	case "certification_number":
+		details.CertificationNumber = &value
+	case "valid_until":
+		date, err := parseImportDate(value)
+		if err != nil {
+			return err
+		}
+		details.ValidUntil = &date
+	default:
+		if !strings.HasPrefix(key, "custom.") {
+			return nil
+		}
+		customKey := strings.TrimPrefix(key, "custom.")
+		parsed, err := parseImportCustomValue(customKey, value, customType)
+		if err != nil {
+			return err
+		}
+		if details.CustomFields == nil {
+			details.CustomFields = make(map[string]interface{})
+		}
+		details.CustomFields[customKey] = parsed
+	}
+	return nil
+}
+
+// parseImportCustomValue mengubah teks sel sesuai tipe field pada schema tipe custom.
+// Field yang tidak dikenal disimpan sebagai teks dan dilaporkan oleh validasi schema.
+func parseImportCustomValue(key, value string, customType *model.AchievementTypeDefinition) (interface{}, error) {
+	if customType == nil {
+		return value, nil
+	}
+	for _, field := range customType.Fields {
+		if field.Key != key {
+			continue
+		}
+		switch field.Type {
+		case model.FieldTypeNumber, model.FieldTypeInteger:
+			return parseImportFloat(value)
+		case model.FieldTypeBoolean:
+			switch strings.ToLower(value) {
+			case "true", "ya", "yes", "1":
+				return true, nil
+			case "false", "tidak", "no", "0":
+				return false, nil
+			}
+			return nil, errors.New("harus berupa true atau false")
+		case model.FieldTypeDate:
+			date, err := parseImportDate(value)
+			if err != nil {
+				return nil, err
+			}
+			return date.Format("2006-01-02"), nil
+		case model.FieldTypeArray:
+			return splitImportList(value), nil
+		}
+		return value, nil
+	}
+	return value, nil
+}
+
+func readImportRows(req *ImportAchievementsRequest) ([][]string, error) {
+	format := strings.ToLower(req.Format)
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(req.FileName)), ".")
+	}
+
+	switch format {
+	case "csv":
+		rows, err := helper.ReadCSV(req.Content)
+		if err != nil {
+			return nil, fmt.Errorf("gagal membaca CSV: %v", err)
+		}
+		return rows, nil
+	case "xlsx":
+		// Header ikut dihitung sebagai baris
+		return helper.ReadXLSX(req.Content, req.Sheet, maxImportRows+1)
+	}
+	return nil, errors.New("format file tidak didukung, gunakan CSV atau XLSX")
+}
+
+// resolveImportColumns mencocokkan header file dengan field import. Header yang tidak
+// dipetakan dipakai apa adanya jika namanya sama dengan field import.
+func resolveImportColumns(header []string, mapping map[string]string) (map[string]int, error) {
+	fields := make(map[string]string, len(mapping))
+	mapped := make(map[string]string, len(mapping))
+	for field, column := range mapping {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if !isImportField(field) {
+			return nil, fmt.Errorf("field mapping tidak dikenal: %s", field)
+		}
+		fields[field] = column
+		mapped[strings.ToLower(strings.TrimSpace(column))] = field
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		field, ok := mapped[name]
+		if !ok {
+			if _, remapped := fields[name]; remapped || !isImportField(name) {
+				continue
+			}
+			field = name
+		}
+		if _, exists := columns[field]; exists {
+			return nil, fmt.Errorf("field %s dipetakan ke lebih dari satu kolom", field)
+		}
+		columns[field] = i
+	}
+
+	for field, column := range fields {
+		if _, ok := columns[field]; !ok {
+			return nil, fmt.Errorf("kolom %q untuk field %s tidak ditemukan", column, field)
+		}
+	}
+	for _, field := range requiredImportFields {
+		if _, ok := columns[field]; !ok {
+			return nil, fmt.Errorf("kolom wajib %s tidak ditemukan di header", field)
+		}
+	}
+	return columns, nil
+}
+
+func isImportField(field string) bool {
+	if strings.HasPrefix(field, "custom.") {
+		return len(field) > len("custom.")
+	}
+	return containsString(importFields, field)
+}
+
+// importFieldPath nama field pada laporan error, mengikuti nama field JSON POST /achievements
+func importFieldPath(key string) string {
+	switch key {
+	case "nim", "achievement_type", "title", "description", "tags":
+		return key
+	case "period_start":
+		return "details.period.start"
+	case "period_end":
+		return "details.period.end"
+	}
+	if strings.HasPrefix(key, "custom.") {
+		return "details.custom_fields." + strings.TrimPrefix(key, "custom.")
+	}
+	return "details." + key
+}
+
+func cellValue(row []string, columns map[string]int, field string) string {
+	index, ok := columns[field]
+	if !ok || index >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[index])
+}
+
+func isBlankRow(row []string) bool {
+	for _, value := range row {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// splitImportList memecah daftar dalam satu sel. Titik koma diutamakan karena nama penulis
+// dapat mengandung koma ("Santoso, B.").
+func splitImportList(value string) []string {
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+	separator := ","
+	if strings.Contains(value, ";") {
+		separator = ";"
+	}
+	var items []string
+	for _, item := range strings.Split(value, separator) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseImportDate menerima YYYY-MM-DD, DD/MM/YYYY, RFC3339 atau serial number tanggal Excel
+func parseImportDate(value string) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02", "02/01/2006", "2/1/2006", "02-01-2006", time.RFC3339, "2006-01-02 15:04:05"} {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	if serial, err := strconv.ParseFloat(value, 64); err == nil && serial > 0 {
+		return helper.ExcelSerialToTime(serial), nil
+	}
+	return time.Time{}, errors.New("harus berupa tanggal (YYYY-MM-DD atau DD/MM/YYYY)")
+}
+
+// parseImportFloat menerima desimal dengan titik maupun koma
+func parseImportFloat(value string) (float64, error) {
+	if !strings.Contains(value, ".") {
+		value = strings.Replace(value, ",", ".", 1)
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, errors.New("harus berupa angka")
+	}
+	return number, nil
+}
+
+func parseImportInt(value string) (int, error) {
+	number, err := parseImportFloat(value)
+	if err != nil || number != float64(int(number)) {
+		return 0, errors.New("harus berupa bilangan bulat")
+	}
+	return int(number), nil
+}
@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+)
+
+// ErrMetadataNotFound DOI tidak terdaftar pada resolver
+var ErrMetadataNotFound = errors.New("metadata DOI tidak ditemukan")
+
+// PublicationMetadata metadata publikasi hasil resolusi DOI. Publisher mengikuti konvensi
+// AchievementDetails: nama jurnal/prosiding untuk journal/conference, penerbit untuk book.
+type PublicationMetadata struct {
+	DOI             string                `json:"doi"`
+	Title           string                `json:"title"`
+	Authors         []string              `json:"authors"`
+	Publisher       string                `json:"publisher,omitempty"`
+	ISSN            []string              `json:"issn,omitempty"`
+	PublishedAt     *time.Time            `json:"published_at,omitempty"`
+	PublicationType model.PublicationType `json:"publication_type,omitempty"`
+	Source          string                `json:"source"`
+}
+
+// MetadataResolver sumber metadata DOI (Crossref, DataCite, mock lokal, dst.)
+type MetadataResolver interface {
+	Resolve(ctx context.Context, doi string) (*PublicationMetadata, error)
+}
+
+// crossrefResolver memakai REST API Crossref (GET {baseURL}/works/{doi}). baseURL dapat
+// diarahkan ke server mock lokal yang mengembalikan format respons yang sama.
+type crossrefResolver struct {
+	baseURL string
+	mailto  string
+	client  *http.Client
+}
+
+func NewCrossrefResolver(baseURL, mailto string) MetadataResolver {
+	return &crossrefResolver{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		mailto:  mailto,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type crossrefDate struct {
+	DateParts [][]int `json:"date-parts"`
+}
+
+func (d crossrefDate) time() *time.Time {
+	if len(d.DateParts) == 0 || len(d.DateParts[0]) == 0 || d.DateParts[0][0] == 0 {
+		return nil
+	}
+	parts := append(append([]int{}, d.DateParts[0]...), 1, 1)
+	month, day := parts[1], parts[2]
+	if month < 1 || month > 12 {
+		month = 1
+	}
+	if day < 1 || day > 31 {
+		day = 1
+	}
+	date := time.Date(parts[0], time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	return &date
+}
+
+type crossrefWork struct {
+	Message struct {
+		DOI            string   `json:"DOI"`
+		Type           string   `json:"type"`
+		Title          []string `json:"title"`
+		ContainerTitle []string `json:"container-title"`
+		Publisher      string   `json:"publisher"`
+		ISSN           []string `json:"ISSN"`
+		Author         []struct {
+			Given  string `json:"given"`
+			Family string `json:"family"`
+			Name   string `json:"name"` // penulis organisasi
+		} `json:"author"`
+		Issued          crossrefDate `json:"issued"`
+		PublishedPrint  crossrefDate `json:"published-print"`
+		PublishedOnline crossrefDate `json:"published-online"`
+	} `json:"message"`
+}
+
+var crossrefTypes = map[string]model.PublicationType{
+	"journal-article":     model.PublicationTypeJournal,
+	"proceedings-article": model.PublicationTypeConference,
+	"book":                model.PublicationTypeBook,
+	"book-chapter":        model.PublicationTypeBook,
+	"monograph":           model.PublicationTypeBook,
+	"edited-book":         model.PublicationTypeBook,
+}
+
+// markupPattern tag JATS/HTML yang kadang ada di judul Crossref (<i>, <sub>, ...)
+var markupPattern = regexp.MustCompile(`<[^>]+>`)
+
+func (r *crossrefResolver) Resolve(ctx context.Context, doi string) (*PublicationMetadata, error) {
+	segments := strings.Split(doi, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.baseURL+"/works/"+strings.Join(segments, "/"), nil)
+	if err != nil {
+		return nil, err
+	}
+	// Crossref memprioritaskan klien yang mencantumkan kontak (polite pool)
+	userAgent := "SistemPelaporanPrestasi/1.0"
+	if r.mailto != "" {
+		userAgent += " (mailto:" + r.mailto + ")"
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("gagal menghubungi resolver DOI: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrMetadataNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("resolver DOI mengembalikan status %d", resp.StatusCode)
+	}
+
+	var work crossrefWork
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 5<<20)).Decode(&work); err != nil {
+		return nil, fmt.Errorf("respons resolver DOI tidak valid: %v", err)
+	}
+
+	message := &work.Message
+	metadata := &PublicationMetadata{
+		DOI:             normalizeDOI(firstNonEmpty(message.DOI, doi)),
+		PublicationType: crossrefTypes[message.Type],
+		ISSN:            message.ISSN,
+		Source:          "crossref",
+		Authors:         []string{},
+	}
+	if len(message.Title) > 0 {
+		metadata.Title = cleanMarkup(message.Title[0])
+	}
+	for _, author := range message.Author {
+		name := strings.TrimSpace(author.Given + " " + author.Family)
+		if name == "" {
+			name = strings.TrimSpace(author.Name)
+		}
+		if name != "" {
+			metadata.Authors = append(metadata.Authors, name)
+		}
+	}
+
+	metadata.Publisher = message.Publisher
+	if len(message.ContainerTitle) > 0 && metadata.PublicationType != model.PublicationTypeBook {
+		metadata.Publisher = cleanMarkup(message.ContainerTitle[0])
+	}
+
+	for _, date := range []crossrefDate{message.PublishedPrint, message.PublishedOnline, message.Issued} {
+		if published := date.time(); published != nil {
+			metadata.PublishedAt = published
+			break
+		}
+	}
+	return metadata, nil
+}
+
+func cleanMarkup(value string) string {
+	return strings.Join(strings.Fields(markupPattern.ReplaceAllString(value, "")), " ")
+}
+
+// cachedMetadataResolver menyimpan hasil resolusi di disk. Entri yang masih berlaku tidak
+// meminta ulang; jika resolver gagal (misalnya server offline) entri kedaluwarsa tetap dipakai.
+// next nil berarti mode offline: hanya isi cache yang dipakai.
+type cachedMetadataResolver struct {
+	next MetadataResolver
+	dir  string
+	ttl  time.Duration
+}
+
+type metadataCacheEntry struct {
+	FetchedAt time.Time            `json:"fetched_at"`
+	NotFound  bool                 `json:"not_found,omitempty"`
+	Metadata  *PublicationMetadata `json:"metadata,omitempty"`
+}
+
+func NewCachedMetadataResolver(next MetadataResolver, dir string, ttl time.Duration) MetadataResolver {
+	return &cachedMetadataResolver{
+		next: next,
+		dir:  dir,
+		ttl:  ttl,
+	}
+}
+
+func (r *cachedMetadataResolver) Resolve(ctx context.Context, doi string) (*PublicationMetadata, error) {
+	path := r.path(doi)
+	cached := r.read(path)
+	if cached != nil && (r.next == nil || r.ttl <= 0 || time.Since(cached.FetchedAt) < r.ttl) {
+		return cached.result()
+	}
+	if r.next == nil {
+		return nil, errors.New("metadata DOI belum tersedia di cache offline")
+	}
+
+	metadata, err := r.next.Resolve(ctx, doi)
+	if err != nil && !errors.Is(err, ErrMetadataNotFound) {
+		if cached != nil {
+			return cached.result()
+		}
+		return nil, err
+	}
+
+	entry := &metadataCacheEntry{FetchedAt: time.Now(), Metadata: metadata, NotFound: err != nil}
+	if writeErr := r.write(path, entry); writeErr != nil {
+		fmt.Printf("Warning: Gagal menyimpan cache metadata DOI: %v\n", writeErr)
+	}
+	return entry.result()
+}
+
+func (e *metadataCacheEntry) result() (*PublicationMetadata, error) {
+	if e.NotFound || e.Metadata == nil {
+		return nil, ErrMetadataNotFound
+	}
+	return e.Metadata, nil
+}
+
+func (r *cachedMetadataResolver) path(doi string) string {
+	sum := sha256.Sum256([]byte(normalizeDOI(doi)))
+	return filepath.Join(r.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (r *cachedMetadataResolver) read(path string) *metadataCacheEntry {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var entry metadataCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil
+	}
+	return &entry
+}
+
+func (r *cachedMetadataResolver) write(path string, entry *metadataCacheEntry) error {
+	if err := os.MkdirAll(r.dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	// Tulis ke file sementara lalu rename agar request paralel tidak membaca file setengah jadi
+	tmp, err := os.CreateTemp(r.dir, "*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
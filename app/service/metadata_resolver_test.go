@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+)
+
+const crossrefTestWork = `{
+  "status": "ok",
+  "message": {
+    "DOI": "10.1000/JSI.2024.01",
+    "type": "journal-article",
+    "title": ["Deteksi <i>Plagiarisme</i>\n  pada Karya Ilmiah"],
+    "container-title": ["Jurnal Sistem Informasi"],
+    "publisher": "Universitas Contoh",
+    "ISSN": ["0378-5955"],
+    "author": [
+      {"given": "Dewi", "family": "Sari"},
+      {"name": "Tim Riset Informatika"},
+      {"given": "", "family": ""}
+    ],
+    "issued": {"date-parts": [[2024]]},
+    "published-online": {"date-parts": [[2024, 3, 15]]}
+  }
+}`
+
+// crossrefTestServer server mock API Crossref. Path selain /works/10.1000/jsi.2024.01 dijawab 404,
+// status memaksa semua request dijawab dengan status tersebut.
+func crossrefTestServer(t *testing.T, hits *int32, status *int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(hits, 1)
+		if !strings.Contains(r.Header.Get("User-Agent"), "mailto:admin@example.ac.id") {
+			t.Errorf("User-Agent = %q, tidak memuat mailto", r.Header.Get("User-Agent"))
+		}
+		if code := atomic.LoadInt32(status); code != 0 {
+			w.WriteHeader(int(code))
+			return
+		}
+		if !strings.EqualFold(r.URL.Path, "/works/10.1000/jsi.2024.01") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(crossrefTestWork))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCrossrefResolverResolve(t *testing.T) {
+	var hits, status int32
+	server := crossrefTestServer(t, &hits, &status)
+	resolver := NewCrossrefResolver(server.URL+"/", "admin@example.ac.id")
+
+	metadata, err := resolver.Resolve(context.Background(), "10.1000/JSI.2024.01")
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if metadata.DOI != "10.1000/jsi.2024.01" || metadata.Title != "Deteksi Plagiarisme pada Karya Ilmiah" {
+		t.Errorf("DOI/judul = %q/%q", metadata.DOI, metadata.Title)
+	}
+	if metadata.PublicationType != model.PublicationTypeJournal || metadata.Publisher != "Jurnal Sistem Informasi" {
+		t.Errorf("tipe/publisher = %q/%q, want journal/nama jurnal", metadata.PublicationType, metadata.Publisher)
+	}
+	if len(metadata.Authors) != 2 || metadata.Authors[0] != "Dewi Sari" || metadata.Authors[1] != "Tim Riset Informatika" {
+		t.Errorf("authors = %q", metadata.Authors)
+	}
+	// published-online lebih lengkap daripada issued dan didahulukan
+	if date := metadata.PublishedAt; date == nil || !date.Equal(time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("published_at = %v, want 15 Maret 2024", date)
+	}
+	if metadata.Source != "crossref" {
+		t.Errorf("source = %q", metadata.Source)
+	}
+}
+
+func TestCrossrefResolverErrors(t *testing.T) {
+	var hits, status int32
+	server := crossrefTestServer(t, &hits, &status)
+	resolver := NewCrossrefResolver(server.URL, "admin@example.ac.id")
+
+	if _, err := resolver.Resolve(context.Background(), "10.1000/tidak-ada"); !errors.Is(err, ErrMetadataNotFound) {
+		t.Errorf("Resolve() DOI tidak terdaftar error = %v, want ErrMetadataNotFound", err)
+	}
+
+	atomic.StoreInt32(&status, http.StatusServiceUnavailable)
+	_, err := resolver.Resolve(context.Background(), "10.1000/jsi.2024.01")
+	if err == nil || errors.Is(err, ErrMetadataNotFound) || !strings.Contains(err.Error(), "503") {
+		t.Errorf("Resolve() server gagal error = %v, want error status 503", err)
+	}
+
+	server.Close()
+	if _, err := resolver.Resolve(context.Background(), "10.1000/jsi.2024.01"); err == nil || errors.Is(err, ErrMetadataNotFound) {
+		t.Errorf("Resolve() server mati error = %v, want error koneksi", err)
+	}
+}
+
+func TestCachedMetadataResolver(t *testing.T) {
+	var hits, status int32
+	server := crossrefTestServer(t, &hits, &status)
+	dir := t.TempDir()
+	resolver := NewCachedMetadataResolver(NewCrossrefResolver(server.URL, "admin@example.ac.id"), dir, time.Hour)
+	cache := resolver.(*cachedMetadataResolver)
+	ctx := context.Background()
+
+	// Resolve kedua (dengan penulisan DOI lain) dilayani dari cache
+	for _, doi := range []string{"10.1000/jsi.2024.01", "https://doi.org/10.1000/JSI.2024.01"} {
+		if metadata, err := resolver.Resolve(ctx, doi); err != nil || metadata.Title == "" {
+			t.Fatalf("Resolve(%q) = %+v, %v", doi, metadata, err)
+		}
+	}
+	if got := atomic.LoadInt32(&hits); got != 1 {
+		t.Errorf("request ke resolver = %d, want 1", got)
+	}
+
+	// Negative caching: DOI tidak terdaftar tidak diminta ulang sebelum TTL habis
+	for i := 0; i < 2; i++ {
+		if _, err := resolver.Resolve(ctx, "10.1000/tidak-ada"); !errors.Is(err, ErrMetadataNotFound) {
+			t.Fatalf("Resolve() DOI tidak terdaftar error = %v", err)
+		}
+	}
+	if got := atomic.LoadInt32(&hits); got != 2 {
+		t.Errorf("request ke resolver = %d, want 2 (hasil 404 di-cache)", got)
+	}
+
+	// Entri kedaluwarsa diminta ulang
+	expire := func(doi string) {
+		path := cache.path(doi)
+		entry := cache.read(path)
+		entry.FetchedAt = time.Now().Add(-2 * time.Hour)
+		if err := cache.write(path, entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expire("10.1000/jsi.2024.01")
+	if _, err := resolver.Resolve(ctx, "10.1000/jsi.2024.01"); err != nil {
+		t.Fatalf("Resolve() setelah TTL error = %v", err)
+	}
+	if got := atomic.LoadInt32(&hits); got != 3 {
+		t.Errorf("request ke resolver = %d, want 3 (entri kedaluwarsa diminta ulang)", got)
+	}
+
+	// Resolver gagal: entri kedaluwarsa tetap dipakai, DOI baru mengembalikan error
+	expire("10.1000/jsi.2024.01")
+	atomic.StoreInt32(&status, http.StatusInternalServerError)
+	if metadata, err := resolver.Resolve(ctx, "10.1000/jsi.2024.01"); err != nil || metadata.Title == "" {
+		t.Errorf("Resolve() saat resolver gagal = %+v, %v; want isi cache lama", metadata, err)
+	}
+	if _, err := resolver.Resolve(ctx, "10.1000/baru"); err == nil || errors.Is(err, ErrMetadataNotFound) {
+		t.Errorf("Resolve() DOI baru saat resolver gagal error = %v", err)
+	}
+
+	// Mode offline hanya membaca cache, tanpa memedulikan TTL
+	offline := NewCachedMetadataResolver(nil, dir, time.Nanosecond)
+	if _, err := offline.Resolve(ctx, "10.1000/jsi.2024.01"); err != nil {
+		t.Errorf("Resolve() offline dari cache error = %v", err)
+	}
+	if _, err := offline.Resolve(ctx, "10.1000/belum-di-cache"); err == nil {
+		t.Error("Resolve() offline tanpa cache seharusnya error")
+	}
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/repository"
+)
+
+type NotificationService interface {
+	GetNotifications(ctx context.Context, userID uuid.UUID, unreadOnly bool) ([]model.Notification, error)
+	MarkAsRead(ctx context.Context, userID uuid.UUID, notificationID uuid.UUID) error
+	MarkAllAsRead(ctx context.Context, userID uuid.UUID) error
+	Notify(ctx context.Context, userID uuid.UUID, notificationType model.NotificationType, title, message, achievementID string) error
+}
+
+type notificationService struct {
+	notificationRepo repository.NotificationRepository
+}
+
+func NewNotificationService(notificationRepo repository.NotificationRepository) NotificationService {
+	return &notificationService{
+		notificationRepo: notificationRepo,
+	}
+}
+
+func (s *notificationService) GetNotifications(ctx context.Context, userID uuid.UUID, unreadOnly bool) ([]model.Notification, error) {
+	notifications, err := s.notificationRepo.FindNotificationsByUserID(ctx, userID, unreadOnly)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil notifikasi: %v", err)
+	}
+	return notifications, nil
+}
+
+func (s *notificationService) MarkAsRead(ctx context.Context, userID uuid.UUID, notificationID uuid.UUID) error {
+	if err := s.notificationRepo.MarkAsRead(ctx, userID, notificationID); err != nil {
+		return errors.New("notifikasi tidak ditemukan")
+	}
+	return nil
+}
+
+func (s *notificationService) MarkAllAsRead(ctx context.Context, userID uuid.UUID) error {
+	if err := s.notificationRepo.MarkAllAsRead(ctx, userID); err != nil {
+		return fmt.Errorf("gagal mengupdate notifikasi: %v", err)
+	}
+	return nil
+}
+
+func (s *notificationService) Notify(ctx context.Context, userID uuid.UUID, notificationType model.NotificationType, title, message, achievementID string) error {
+	notification := &model.Notification{
+		UserID:             userID,
+		Type:               notificationType,
+		Title:              title,
+		Message:            message,
+		MongoAchievementID: achievementID,
+	}
+	if err := s.notificationRepo.CreateNotification(ctx, notification); err != nil {
+		return fmt.Errorf("gagal menyimpan notifikasi: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,311 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/repository"
+)
+
+type PointRuleService interface {
+	GetAllRules(ctx context.Context) ([]model.PointRule, error)
+	CreateRule(ctx context.Context, req *PointRuleRequest) (*model.PointRule, error)
+	UpdateRule(ctx context.Context, ruleID uuid.UUID, req *PointRuleRequest) (*model.PointRule, error)
+	DeleteRule(ctx context.Context, ruleID uuid.UUID) error
+	PreviewPoints(ctx context.Context, req *PointPreviewRequest) (*PointPreviewResponse, error)
+	CalculatePoints(ctx context.Context, achievement *model.Achievement) (float64, *model.PointRule, error)
+	RecalculatePoints(ctx context.Context, dryRun bool) (*PointRecalculationResult, error)
+}
+
+type pointRuleService struct {
+	ruleRepo        repository.PointRuleRepository
+	achievementRepo repository.AchievementRepository
+}
+
+func NewPointRuleService(
+	ruleRepo repository.PointRuleRepository,
+	achievementRepo repository.AchievementRepository,
+) PointRuleService {
+	return &pointRuleService{
+		ruleRepo:        ruleRepo,
+		achievementRepo: achievementRepo,
+	}
+}
+
+type PointRuleRequest struct {
+	Name             string                  `json:"name"`
+	AchievementType  model.AchievementType   `json:"achievement_type"`
+	CompetitionLevel *model.CompetitionLevel `json:"competition_level,omitempty"`
+	Rank             *int                    `json:"rank,omitempty"`
+	MedalType        *string                 `json:"medal_type,omitempty"`
+	PublicationType  *model.PublicationType  `json:"publication_type,omitempty"`
+	Position         *string                 `json:"position,omitempty"`
+	Points           float64                 `json:"points"`
+	Priority         int                     `json:"priority"`
+	IsActive         *bool                   `json:"is_active,omitempty"`
+}
+
+type PointPreviewRequest struct {
+	AchievementType model.AchievementType    `json:"achievement_type"`
+	Details         model.AchievementDetails `json:"details"`
+}
+
+type PointPreviewResponse struct {
+	Points      float64          `json:"points"`
+	MatchedRule *model.PointRule `json:"matched_rule,omitempty"`
+}
+
+type PointRecalculationResult struct {
+	DryRun    bool                `json:"dry_run"`
+	Processed int                 `json:"processed"`
+	Changed   int                 `json:"changed"`
+	Failed    int                 `json:"failed"`
+	Changes   []PointChangeResult `json:"changes"`
+}
+
+type PointChangeResult struct {
+	AchievementID string  `json:"achievement_id"`
+	Status        string  `json:"status"`
+	OldPoints     float64 `json:"old_points"`
+	NewPoints     float64 `json:"new_points"`
+	RuleName      string  `json:"rule_name,omitempty"`
+	Error         string  `json:"error,omitempty"`
+}
+
+func (s *pointRuleService) GetAllRules(ctx context.Context) ([]model.PointRule, error) {
+	rules, err := s.ruleRepo.FindAllRules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil aturan poin: %v", err)
+	}
+	return rules, nil
+}
+
+func (s *pointRuleService) CreateRule(ctx context.Context, req *PointRuleRequest) (*model.PointRule, error) {
+	if err := validatePointRuleRequest(req); err != nil {
+		return nil, err
+	}
+
+	rule := &model.PointRule{IsActive: true}
+	applyPointRuleRequest(rule, req)
+
+	if err := s.ruleRepo.CreateRule(ctx, rule); err != nil {
+		return nil, fmt.Errorf("gagal menyimpan aturan poin: %v", err)
+	}
+
+	// GORM mengabaikan nilai false untuk kolom dengan default, simpan ulang secara eksplisit
+	if !rule.IsActive {
+		if err := s.ruleRepo.UpdateRule(ctx, rule); err != nil {
+			return nil, fmt.Errorf("gagal menyimpan aturan poin: %v", err)
+		}
+	}
+	return rule, nil
+}
+
+func (s *pointRuleService) UpdateRule(ctx context.Context, ruleID uuid.UUID, req *PointRuleRequest) (*model.PointRule, error) {
+	rule, err := s.ruleRepo.FindRuleByID(ctx, ruleID)
+	if err != nil {
+		return nil, errors.New("aturan poin tidak ditemukan")
+	}
+
+	if err := validatePointRuleRequest(req); err != nil {
+		return nil, err
+	}
+
+	applyPointRuleRequest(rule, req)
+
+	if err := s.ruleRepo.UpdateRule(ctx, rule); err != nil {
+		return nil, fmt.Errorf("gagal mengupdate aturan poin: %v", err)
+	}
+	return rule, nil
+}
+
+func (s *pointRuleService) DeleteRule(ctx context.Context, ruleID uuid.UUID) error {
+	if _, err := s.ruleRepo.FindRuleByID(ctx, ruleID); err != nil {
+		return errors.New("aturan poin tidak ditemukan")
+	}
+
+	if err := s.ruleRepo.DeleteRule(ctx, ruleID); err != nil {
+		return fmt.Errorf("gagal menghapus aturan poin: %v", err)
+	}
+	return nil
+}
+
+func (s *pointRuleService) PreviewPoints(ctx context.Context, req *PointPreviewRequest) (*PointPreviewResponse, error) {
+	if req.AchievementType == "" {
+		return nil, errors.New("achievement_type harus diisi")
+	}
+
+	achievement := &model.Achievement{
+		AchievementType: req.AchievementType,
+		Details:         req.Details,
+	}
+
+	points, rule, err := s.CalculatePoints(ctx, achievement)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PointPreviewResponse{
+		Points:      points,
+		MatchedRule: rule,
+	}, nil
+}
+
+// CalculatePoints memilih aturan aktif paling spesifik yang cocok dengan achievement.
+// Jika tidak ada aturan yang cocok, poin bernilai 0.
+func (s *pointRuleService) CalculatePoints(ctx context.Context, achievement *model.Achievement) (float64, *model.PointRule, error) {
+	rules, err := s.ruleRepo.FindActiveRulesByType(ctx, achievement.AchievementType)
+	if err != nil {
+		return 0, nil, fmt.Errorf("gagal memuat aturan poin: %v", err)
+	}
+
+	var best *model.PointRule
+	bestSpecificity := -1
+	for i := range rules {
+		rule := &rules[i]
+		matched, specificity := matchPointRule(rule, &achievement.Details)
+		if !matched {
+			continue
+		}
+		// rules sudah terurut berdasarkan priority, jadi yang pertama menang jika seri
+		if specificity > bestSpecificity {
+			best = rule
+			bestSpecificity = specificity
+		}
+	}
+
+	if best == nil {
+		return 0, nil, nil
+	}
+	return best.Points, best, nil
+}
+
+// RecalculatePoints menghitung ulang poin seluruh achievement yang ada.
+// Achievement yang belum diverifikasi selalu bernilai 0 poin.
+func (s *pointRuleService) RecalculatePoints(ctx context.Context, dryRun bool) (*PointRecalculationResult, error) {
+	achievements, err := s.achievementRepo.FindAllAchievements(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("gagal memuat achievements: %v", err)
+	}
+
+	result := &PointRecalculationResult{
+		DryRun:  dryRun,
+		Changes: []PointChangeResult{},
+	}
+
+	for i := range achievements {
+		achievement := &achievements[i]
+		result.Processed++
+
+		change := PointChangeResult{
+			AchievementID: achievement.ID.Hex(),
+			Status:        string(achievement.Status),
+			OldPoints:     achievement.Points,
+		}
+
+		if achievement.Status == model.StatusVerified {
+			points, rule, err := s.CalculatePoints(ctx, achievement)
+			if err != nil {
+				change.Error = err.Error()
+				result.Failed++
+				result.Changes = append(result.Changes, change)
+				continue
+			}
+			change.NewPoints = points
+			if rule != nil {
+				change.RuleName = rule.Name
+			}
+		}
+
+		if change.NewPoints == change.OldPoints {
+			continue
+		}
+
+		if !dryRun {
+			if err := s.achievementRepo.UpdateAchievementPoints(ctx, achievement.ID.Hex(), change.NewPoints); err != nil {
+				change.Error = err.Error()
+				result.Failed++
+				result.Changes = append(result.Changes, change)
+				continue
+			}
+		}
+
+		result.Changed++
+		result.Changes = append(result.Changes, change)
+	}
+
+	return result, nil
+}
+
+// matchPointRule mengembalikan apakah aturan cocok dan berapa kriteria yang terpenuhi
+func matchPointRule(rule *model.PointRule, details *model.AchievementDetails) (bool, int) {
+	specificity := 0
+
+	if rule.CompetitionLevel != nil {
+		if details.CompetitionLevel == nil || !strings.EqualFold(string(*rule.CompetitionLevel), string(*details.CompetitionLevel)) {
+			return false, 0
+		}
+		specificity++
+	}
+	if rule.Rank != nil {
+		if details.Rank == nil || *rule.Rank != *details.Rank {
+			return false, 0
+		}
+		specificity++
+	}
+	if rule.MedalType != nil {
+		if details.MedalType == nil || !strings.EqualFold(strings.TrimSpace(*rule.MedalType), strings.TrimSpace(*details.MedalType)) {
+			return false, 0
+		}
+		specificity++
+	}
+	if rule.PublicationType != nil {
+		if details.PublicationType == nil || !strings.EqualFold(string(*rule.PublicationType), string(*details.PublicationType)) {
+			return false, 0
+		}
+		specificity++
+	}
+	if rule.Position != nil {
+		if details.Position == nil || !strings.EqualFold(strings.TrimSpace(*rule.Position), strings.TrimSpace(*details.Position)) {
+			return false, 0
+		}
+		specificity++
+	}
+
+	return true, specificity
+}
+
+func validatePointRuleRequest(req *PointRuleRequest) error {
+	if strings.TrimSpace(req.Name) == "" {
+		return errors.New("name harus diisi")
+	}
+	if req.AchievementType == "" {
+		return errors.New("achievement_type harus diisi")
+	}
+	if req.Points < 0 {
+		return errors.New("points tidak boleh negatif")
+	}
+	if req.Rank != nil && *req.Rank < 1 {
+		return errors.New("rank harus lebih dari 0")
+	}
+	return nil
+}
+
+func applyPointRuleRequest(rule *model.PointRule, req *PointRuleRequest) {
+	rule.Name = strings.TrimSpace(req.Name)
+	rule.AchievementType = req.AchievementType
+	rule.CompetitionLevel = req.CompetitionLevel
+	rule.Rank = req.Rank
+	rule.MedalType = req.MedalType
+	rule.PublicationType = req.PublicationType
+	rule.Position = req.Position
+	rule.Points = req.Points
+	rule.Priority = req.Priority
+	if req.IsActive != nil {
+		rule.IsActive = *req.IsActive
+	}
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+)
+
+// fakePointRuleRepository aturan aktif di memori, sudah terurut berdasarkan priority
+type fakePointRuleRepository struct {
+	rules []model.PointRule
+}
+
+func (r *fakePointRuleRepository) CreateRule(ctx context.Context, rule *model.PointRule) error {
+	return nil
+}
+
+func (r *fakePointRuleRepository) FindRuleByID(ctx context.Context, id uuid.UUID) (*model.PointRule, error) {
+	return nil, nil
+}
+
+func (r *fakePointRuleRepository) FindAllRules(ctx context.Context) ([]model.PointRule, error) {
+	return r.rules, nil
+}
+
+func (r *fakePointRuleRepository) FindActiveRulesByType(ctx context.Context, achievementType model.AchievementType) ([]model.PointRule, error) {
+	var rules []model.PointRule
+	for _, rule := range r.rules {
+		if rule.IsActive && rule.AchievementType == achievementType {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+func (r *fakePointRuleRepository) UpdateRule(ctx context.Context, rule *model.PointRule) error {
+	return nil
+}
+
+func (r *fakePointRuleRepository) DeleteRule(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func TestMatchPointRule(t *testing.T) {
+	national := model.CompetitionLevelNational
+	international := model.CompetitionLevelInternational
+	journal := model.PublicationTypeJournal
+	first, second := 1, 2
+
+	tests := []struct {
+		name            string
+		rule            model.PointRule
+		details         model.AchievementDetails
+		wantMatch       bool
+		wantSpecificity int
+	}{
+		{
+			name:            "aturan tanpa kriteria cocok untuk semua",
+			rule:            model.PointRule{},
+			details:         model.AchievementDetails{},
+			wantMatch:       true,
+			wantSpecificity: 0,
+		},
+		{
+			name:            "level dan rank cocok",
+			rule:            model.PointRule{CompetitionLevel: &national, Rank: &first},
+			details:         model.AchievementDetails{CompetitionLevel: &national, Rank: &first},
+			wantMatch:       true,
+			wantSpecificity: 2,
+		},
+		{
+			name:      "rank berbeda",
+			rule:      model.PointRule{CompetitionLevel: &national, Rank: &first},
+			details:   model.AchievementDetails{CompetitionLevel: &national, Rank: &second},
+			wantMatch: false,
+		},
+		{
+			name:      "level kosong pada achievement",
+			rule:      model.PointRule{CompetitionLevel: &international},
+			details:   model.AchievementDetails{},
+			wantMatch: false,
+		},
+		{
+			name:            "medali dan jabatan tidak peka huruf besar dan spasi",
+			rule:            model.PointRule{MedalType: strPtr("Gold"), Position: strPtr("Ketua ")},
+			details:         model.AchievementDetails{MedalType: strPtr(" gold"), Position: strPtr("KETUA")},
+			wantMatch:       true,
+			wantSpecificity: 2,
+		},
+		{
+			name:            "tipe publikasi",
+			rule:            model.PointRule{PublicationType: &journal},
+			details:         model.AchievementDetails{PublicationType: &journal},
+			wantMatch:       true,
+			wantSpecificity: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matched, specificity := matchPointRule(&tt.rule, &tt.details)
+			if matched != tt.wantMatch {
+				t.Fatalf("matchPointRule() matched = %v, want %v", matched, tt.wantMatch)
+			}
+			if matched && specificity != tt.wantSpecificity {
+				t.Errorf("matchPointRule() specificity = %d, want %d", specificity, tt.wantSpecificity)
+			}
+		})
+	}
+}
+
+func TestCalculatePointsSpecificity(t *testing.T) {
+	national := model.CompetitionLevelNational
+	first, second := 1, 2
+
+	repo := &fakePointRuleRepository{rules: []model.PointRule{
+		{Name: "Kompetisi umum", AchievementType: model.AchievementTypeCompetition, Points: 5, IsActive: true},
+		{Name: "Nasional", AchievementType: model.AchievementTypeCompetition, CompetitionLevel: &national, Points: 20, IsActive: true},
+		{Name: "Juara 1 nasional", AchievementType: model.AchievementTypeCompetition, CompetitionLevel: &national, Rank: &first, Points: 50, IsActive: true},
+		{Name: "Juara 1 nasional (cadangan)", AchievementType: model.AchievementTypeCompetition, CompetitionLevel: &national, Rank: &first, Points: 45, IsActive: true},
+		{Name: "Juara 1 nonaktif", AchievementType: model.AchievementTypeCompetition, CompetitionLevel: &national, Rank: &first, MedalType: strPtr("gold"), Points: 100, IsActive: false},
+	}}
+	service := NewPointRuleService(repo, nil)
+
+	tests := []struct {
+		name        string
+		achievement model.Achievement
+		wantPoints  float64
+		wantRule    string
+	}{
+		{
+			name: "aturan paling spesifik menang, seri dimenangkan priority",
+			achievement: model.Achievement{
+				AchievementType: model.AchievementTypeCompetition,
+				Details:         model.AchievementDetails{CompetitionLevel: &national, Rank: &first, MedalType: strPtr("gold")},
+			},
+			wantPoints: 50,
+			wantRule:   "Juara 1 nasional",
+		},
+		{
+			name: "rank lain jatuh ke aturan level",
+			achievement: model.Achievement{
+				AchievementType: model.AchievementTypeCompetition,
+				Details:         model.AchievementDetails{CompetitionLevel: &national, Rank: &second},
+			},
+			wantPoints: 20,
+			wantRule:   "Nasional",
+		},
+		{
+			name:        "tanpa detail memakai aturan umum",
+			achievement: model.Achievement{AchievementType: model.AchievementTypeCompetition},
+			wantPoints:  5,
+			wantRule:    "Kompetisi umum",
+		},
+		{
+			name:        "tipe tanpa aturan bernilai 0",
+			achievement: model.Achievement{AchievementType: model.AchievementTypePublication},
+			wantPoints:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			points, rule, err := service.CalculatePoints(context.Background(), &tt.achievement)
+			if err != nil {
+				t.Fatalf("CalculatePoints() error = %v", err)
+			}
+			if points != tt.wantPoints {
+				t.Errorf("CalculatePoints() points = %v, want %v", points, tt.wantPoints)
+			}
+			ruleName := ""
+			if rule != nil {
+				ruleName = rule.Name
+			}
+			if ruleName != tt.wantRule {
+				t.Errorf("CalculatePoints() rule = %q, want %q", ruleName, tt.wantRule)
+			}
+		})
+	}
+}
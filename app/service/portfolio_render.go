@@ -0,0 +1,175 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/helper"
+)
+
+// portfolioFilterSummary keterangan filter yang dipakai, ditampilkan pada kepala dokumen
+func portfolioFilterSummary(data *portfolio) string {
+	parts := []string{}
+	switch {
+	case data.Request.From != nil && data.Request.To != nil:
+		parts = append(parts, "Periode "+formatIndonesianDate(*data.Request.From)+" - "+formatIndonesianDate(*data.Request.To))
+	case data.Request.From != nil:
+		parts = append(parts, "Sejak "+formatIndonesianDate(*data.Request.From))
+	case data.Request.To != nil:
+		parts = append(parts, "Hingga "+formatIndonesianDate(*data.Request.To))
+	}
+	if len(data.Request.Types) > 0 {
+		types := []string{}
+		for _, t := range data.Request.Types {
+			types = append(types, string(t))
+		}
+		parts = append(parts, "Tipe: "+strings.Join(types, ", "))
+	}
+	return strings.Join(parts, " | ")
+}
+
+// renderPortfolioPDF menyusun portofolio PDF. Lampiran yang disematkan ikut sebagai
+// embedded file pada dokumen.
+func renderPortfolioPDF(data *portfolio) ([]byte, error) {
+	doc := helper.NewPDFDocument()
+	doc.SetInfo("Portofolio Prestasi - "+data.Student.User.FullName, "Portofolio prestasi mahasiswa terverifikasi", "portfolio:"+data.Student.StudentID)
+
+	w := &pdfWriter{doc: doc, margin: 50}
+	w.newPage()
+	contentWidth := w.page.Width() - 2*w.margin
+
+	w.page.Text(w.margin, w.y, 18, true, "Portofolio Prestasi Mahasiswa")
+	w.y += 22
+	w.page.Text(w.margin, w.y, 12, true, data.Student.User.FullName)
+	w.y += 15
+	w.page.Text(w.margin, w.y, 9, false, fmt.Sprintf("NIM %s | %s | Angkatan %s", data.Student.StudentID, data.Student.ProgramStudy, data.Student.AcademicYear))
+	w.y += 12
+	if summary := portfolioFilterSummary(data); summary != "" {
+		w.page.Text(w.margin, w.y, 8, false, summary)
+		w.y += 11
+	}
+	w.page.Text(w.margin, w.y, 8, false, fmt.Sprintf("%d prestasi terverifikasi, total %g poin. Dibuat %s.", data.Total, data.TotalPoints, formatIndonesianDate(data.GeneratedAt)))
+	w.y += 8
+	w.page.Line(w.margin, w.y, w.page.Width()-w.margin, w.y, 0.8)
+	w.y += 22
+
+	if len(data.Sections) == 0 {
+		w.paragraph("Tidak ada prestasi terverifikasi yang sesuai dengan filter.", 10, false, contentWidth)
+	}
+
+	for _, section := range data.Sections {
+		w.heading(section.Label)
+		for _, item := range section.Items {
+			w.ensure(50)
+			for _, line := range helper.WrapText(item.Achievement.Title, 10.5, true, contentWidth) {
+				w.ensure(14)
+				w.page.Text(w.margin, w.y, 10.5, true, line)
+				w.y += 13
+			}
+			w.page.SetGray(0.35)
+			w.page.Text(w.margin, w.y, 8, false, formatIndonesianDate(item.Date))
+			w.page.SetGray(0)
+			w.y += 12
+
+			if item.Achievement.Description != "" {
+				w.paragraph(item.Achievement.Description, 9, false, contentWidth)
+				w.y += 2
+			}
+			for _, fact := range item.Facts {
+				lines := helper.WrapText(fact.Value, 8.5, false, contentWidth-120)
+				w.ensure(float64(len(lines)) * 11)
+				w.page.Text(w.margin+10, w.y, 8.5, true, fact.Label)
+				for _, line := range lines {
+					w.page.Text(w.margin+120, w.y, 8.5, false, line)
+					w.y += 11
+				}
+			}
+
+			for _, file := range item.Attachments {
+				note := "Lampiran: " + file.FileName
+				if file.Data != nil {
+					note += " (disematkan pada dokumen ini)"
+					doc.AttachFile(item.Achievement.ID+"-"+file.FileName, file.FileType, file.Data, file.UploadedAt)
+				}
+				w.ensure(11)
+				w.page.Text(w.margin+10, w.y, 8, false, note)
+				w.y += 11
+			}
+			w.y += 10
+		}
+		w.y += 4
+	}
+
+	for i, page := range w.pages {
+		page.SetGray(0.4)
+		page.Text(w.margin, page.Height()-28, 7, false, "Portofolio "+data.Student.User.FullName+" ("+data.Student.StudentID+")")
+		page.TextRight(page.Width()-w.margin, page.Height()-28, 7, false, fmt.Sprintf("Halaman %d dari %d", i+1, len(w.pages)))
+		page.SetGray(0)
+	}
+
+	return doc.Bytes()
+}
+
+// portfolioHTMLTemplate HTML dengan namespace Office agar dapat dibuka dan disimpan
+// sebagai DOCX oleh Microsoft Word maupun LibreOffice
+var portfolioHTMLTemplate = template.Must(template.New("portfolio").Funcs(template.FuncMap{
+	"date":    formatIndonesianDate,
+	"dataURI": func(file portfolioAttachment) template.URL { return template.URL(attachmentDataURI(file)) },
+	"isImage": func(file portfolioAttachment) bool { return strings.HasPrefix(file.FileType, "image/") },
+}).Parse(`<!DOCTYPE html>
+<html xmlns:o="urn:schemas-microsoft-com:office:office" xmlns:w="urn:schemas-microsoft-com:office:word" xmlns="http://www.w3.org/TR/REC-html40" lang="id">
+<head>
+<meta charset="utf-8">
+<meta name="ProgId" content="Word.Document">
+<title>Portofolio Prestasi - {{.Student.User.FullName}}</title>
+<style>
+@page { size: 21cm 29.7cm; margin: 2cm; }
+body { font-family: Calibri, Arial, sans-serif; font-size: 11pt; color: #222; }
+h1 { font-size: 18pt; margin: 0 0 4pt 0; }
+h2 { font-size: 13pt; border-bottom: 1px solid #999; padding-bottom: 2pt; margin-top: 18pt; }
+h3 { font-size: 11pt; margin: 10pt 0 2pt 0; }
+.meta { color: #555; font-size: 9pt; margin: 0; }
+table.facts { border-collapse: collapse; margin: 4pt 0 4pt 8pt; font-size: 9.5pt; }
+table.facts td { padding: 1pt 8pt 1pt 0; vertical-align: top; }
+table.facts td.label { font-weight: bold; width: 120pt; }
+.attachments { font-size: 9pt; margin: 2pt 0 0 8pt; }
+.attachments img { max-width: 14cm; display: block; margin: 4pt 0; }
+</style>
+</head>
+<body>
+<h1>Portofolio Prestasi Mahasiswa</h1>
+<p><b>{{.Student.User.FullName}}</b><br>NIM {{.Student.StudentID}} | {{.Student.ProgramStudy}} | Angkatan {{.Student.AcademicYear}}</p>
+{{with .Filter}}<p class="meta">{{.}}</p>{{end}}
+<p class="meta">{{.Total}} prestasi terverifikasi, total {{.TotalPoints}} poin. Dibuat {{date .GeneratedAt}}.</p>
+{{if not .Sections}}<p>Tidak ada prestasi terverifikasi yang sesuai dengan filter.</p>{{end}}
+{{range .Sections}}
+<h2>{{.Label}}</h2>
+{{range .Items}}
+<h3>{{.Achievement.Title}}</h3>
+<p class="meta">{{date .Date}}</p>
+{{with .Achievement.Description}}<p>{{.}}</p>{{end}}
+<table class="facts">
+{{range .Facts}}<tr><td class="label">{{.Label}}</td><td>{{.Value}}</td></tr>
+{{end}}</table>
+{{if .Attachments}}<div class="attachments">
+{{range .Attachments}}{{if .Data}}{{if isImage .}}<img src="{{dataURI .}}" alt="{{.FileName}}">{{else}}<p>Lampiran: <a href="{{dataURI .}}" download="{{.FileName}}">{{.FileName}}</a></p>{{end}}{{else}}<p>Lampiran: {{.FileName}}</p>{{end}}
+{{end}}</div>{{end}}
+{{end}}
+{{end}}
+</body>
+</html>
+`))
+
+func renderPortfolioHTML(data *portfolio) ([]byte, error) {
+	var buf bytes.Buffer
+	err := portfolioHTMLTemplate.Execute(&buf, struct {
+		*portfolio
+		Filter string
+	}{data, portfolioFilterSummary(data)})
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,509 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/repository"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/storage"
+)
+
+const (
+	PortfolioFormatPDF  = "pdf"
+	PortfolioFormatHTML = "html"
+	PortfolioFormatJSON = "json"
+)
+
+// maxPortfolioAttachmentBytes batas total ukuran lampiran yang disematkan dalam satu ekspor
+const maxPortfolioAttachmentBytes = 25 << 20
+
+type PortfolioService interface {
+	ExportPortfolio(ctx context.Context, studentID uuid.UUID, req *PortfolioExportRequest) (*PortfolioFile, error)
+}
+
+type portfolioService struct {
+	studentService StudentService
+	typeRepo       repository.AchievementTypeRepository
+	blobStore      storage.BlobStore
+}
+
+// NewPortfolioService membuat service ekspor portofolio. Data prestasi diambil melalui
+// StudentService.GetStudentAchievements sehingga sama dengan GET /students/:id/achievements.
+func NewPortfolioService(studentService StudentService, typeRepo repository.AchievementTypeRepository, blobStore storage.BlobStore) PortfolioService {
+	return &portfolioService{
+		studentService: studentService,
+		typeRepo:       typeRepo,
+		blobStore:      blobStore,
+	}
+}
+
+type PortfolioExportRequest struct {
+	Format           string                  // pdf, html, atau json (JSON Resume)
+	From             *time.Time              // Tanggal prestasi paling awal (inklusif)
+	To               *time.Time              // Tanggal prestasi paling akhir (inklusif)
+	Types            []model.AchievementType // Kosong = semua tipe
+	EmbedAttachments bool                    // Sematkan isi file lampiran ke hasil ekspor
+}
+
+// PortfolioFile hasil ekspor siap diunduh
+type PortfolioFile struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+}
+
+type portfolio struct {
+	Student     *model.Student
+	Request     *PortfolioExportRequest
+	Sections    []portfolioSection
+	Total       int
+	TotalPoints float64
+	GeneratedAt time.Time
+}
+
+type portfolioSection struct {
+	Type  model.AchievementType
+	Label string
+	Items []portfolioItem
+}
+
+type portfolioItem struct {
+	Achievement AchievementResponse
+	Date        time.Time
+	Facts       []portfolioFact
+	Attachments []portfolioAttachment
+}
+
+type portfolioFact struct {
+	Label string
+	Value string
+}
+
+type portfolioAttachment struct {
+	FileName   string
+	FileType   string
+	UploadedAt time.Time
+	Data       []byte // Kosong jika lampiran tidak disematkan
+}
+
+func (s *portfolioService) ExportPortfolio(ctx context.Context, studentID uuid.UUID, req *PortfolioExportRequest) (*PortfolioFile, error) {
+	format := strings.ToLower(strings.TrimSpace(req.Format))
+	if format == "" {
+		format = PortfolioFormatPDF
+	}
+	if format != PortfolioFormatPDF && format != PortfolioFormatHTML && format != PortfolioFormatJSON {
+		return nil, errors.New("format tidak valid. Pilih: pdf, html, json")
+	}
+	if req.From != nil && req.To != nil && req.To.Before(*req.From) {
+		return nil, errors.New("tanggal akhir tidak boleh sebelum tanggal awal")
+	}
+
+	student, err := s.studentService.GetStudentByID(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	achievements, err := s.studentService.GetStudentAchievements(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.buildPortfolio(ctx, student, achievements, req)
+	if err != nil {
+		return nil, err
+	}
+
+	fileName := "portofolio-" + student.StudentID
+	switch format {
+	case PortfolioFormatHTML:
+		content, err := renderPortfolioHTML(data)
+		if err != nil {
+			return nil, fmt.Errorf("gagal membuat HTML portofolio: %v", err)
+		}
+		return &PortfolioFile{FileName: fileName + ".html", ContentType: "text/html; charset=utf-8", Content: content}, nil
+	case PortfolioFormatJSON:
+		content, err := json.MarshalIndent(buildJSONResume(data), "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("gagal membuat JSON Resume: %v", err)
+		}
+		return &PortfolioFile{FileName: fileName + ".json", ContentType: "application/json", Content: content}, nil
+	}
+
+	content, err := renderPortfolioPDF(data)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuat PDF portofolio: %v", err)
+	}
+	return &PortfolioFile{FileName: fileName + ".pdf", ContentType: "application/pdf", Content: content}, nil
+}
+
+// buildPortfolio menyaring prestasi terverifikasi sesuai opsi lalu mengelompokkan per tipe
+func (s *portfolioService) buildPortfolio(ctx context.Context, student *model.Student, achievements []AchievementResponse, req *PortfolioExportRequest) (*portfolio, error) {
+	types := make(map[model.AchievementType]bool)
+	for _, t := range req.Types {
+		types[t] = true
+	}
+
+	data := &portfolio{Student: student, Request: req, GeneratedAt: time.Now()}
+	sections := make(map[model.AchievementType]*portfolioSection)
+	order := []model.AchievementType{}
+	embedded := 0
+
+	for _, achievement := range achievements {
+		if achievement.Status != model.StatusVerified {
+			continue
+		}
+		if len(types) > 0 && !types[achievement.AchievementType] {
+			continue
+		}
+
+		date := portfolioDate(&achievement)
+		if req.From != nil && date.Before(*req.From) {
+			continue
+		}
+		if req.To != nil && !date.Before(req.To.AddDate(0, 0, 1)) {
+			continue
+		}
+
+		item := portfolioItem{Achievement: achievement, Date: date, Facts: portfolioFacts(&achievement)}
+		for _, attachment := range achievement.Attachments {
+			file := portfolioAttachment{FileName: attachment.FileName, FileType: attachment.FileType, UploadedAt: attachment.UploadedAt}
+			if req.EmbedAttachments && !attachment.Quarantined {
+				content, err := s.readAttachment(ctx, attachment.FileURL)
+				if err != nil {
+					fmt.Printf("Warning: Gagal membaca lampiran %s: %v\n", attachment.FileURL, err)
+				} else {
+					embedded += len(content)
+					if embedded > maxPortfolioAttachmentBytes {
+						return nil, fmt.Errorf("total ukuran lampiran melebihi batas ekspor %d MB, ekspor tanpa lampiran atau persempit filter", maxPortfolioAttachmentBytes>>20)
+					}
+					file.Data = content
+				}
+			}
+			item.Attachments = append(item.Attachments, file)
+		}
+
+		section, ok := sections[achievement.AchievementType]
+		if !ok {
+			section = &portfolioSection{
+				Type:  achievement.AchievementType,
+				Label: achievementTypeName(ctx, s.typeRepo, achievement.AchievementType),
+			}
+			sections[achievement.AchievementType] = section
+			order = append(order, achievement.AchievementType)
+		}
+		section.Items = append(section.Items, item)
+		data.Total++
+		data.TotalPoints += achievement.Points
+	}
+
+	// Tipe bawaan mengikuti urutan standar, tipe custom setelahnya
+	rank := make(map[model.AchievementType]int)
+	for i, builtIn := range builtInAchievementTypeResponses() {
+		rank[builtIn.Code] = i + 1
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		ri, rj := rank[order[i]], rank[order[j]]
+		if ri == 0 || rj == 0 {
+			return ri != 0 && rj == 0
+		}
+		return ri < rj
+	})
+
+	for _, t := range order {
+		section := sections[t]
+		sort.SliceStable(section.Items, func(i, j int) bool {
+			return section.Items[i].Date.After(section.Items[j].Date)
+		})
+		data.Sections = append(data.Sections, *section)
+	}
+	return data, nil
+}
+
+// portfolioDate tanggal kegiatan, jika kosong memakai tanggal verifikasi atau tanggal dibuat
+func portfolioDate(achievement *AchievementResponse) time.Time {
+	if date := achievementDate(achievement.Details); date != nil {
+		return *date
+	}
+	if achievement.Reference != nil && achievement.Reference.VerifiedAt != nil {
+		return *achievement.Reference.VerifiedAt
+	}
+	return achievement.CreatedAt
+}
+
+// portfolioFacts rincian prestasi yang ditampilkan pada PDF dan HTML
+func portfolioFacts(achievement *AchievementResponse) []portfolioFact {
+	details := achievement.Details
+	facts := []portfolioFact{}
+	add := func(label string, value *string) {
+		if value != nil && strings.TrimSpace(*value) != "" {
+			facts = append(facts, portfolioFact{Label: label, Value: strings.TrimSpace(*value)})
+		}
+	}
+
+	add("Kompetisi", details.CompetitionName)
+	if details.CompetitionLevel != nil {
+		facts = append(facts, portfolioFact{Label: "Tingkat", Value: competitionLevelLabels[*details.CompetitionLevel].ID})
+	}
+	if details.Rank != nil {
+		facts = append(facts, portfolioFact{Label: "Peringkat", Value: fmt.Sprintf("%d", *details.Rank)})
+	}
+	add("Medali", details.MedalType)
+
+	if details.PublicationType != nil {
+		facts = append(facts, portfolioFact{Label: "Jenis Publikasi", Value: publicationTypeLabels[*details.PublicationType].ID})
+	}
+	add("Judul Publikasi", details.PublicationTitle)
+	if len(details.Authors) > 0 {
+		facts = append(facts, portfolioFact{Label: "Penulis", Value: strings.Join(details.Authors, ", ")})
+	}
+	add("Penerbit", details.Publisher)
+	add("ISSN", details.ISSN)
+	add("DOI", details.DOI)
+
+	add("Organisasi", details.OrganizationName)
+	add("Jabatan", details.Position)
+	if details.Period != nil {
+		period := formatIndonesianDate(details.Period.Start)
+		if !details.Period.End.IsZero() {
+			period += " - " + formatIndonesianDate(details.Period.End)
+		}
+		facts = append(facts, portfolioFact{Label: "Periode", Value: period})
+	}
+
+	add("Sertifikasi", details.CertificationName)
+	add("Diterbitkan Oleh", details.IssuedBy)
+	add("Nomor Sertifikat", details.CertificationNumber)
+	if details.ValidUntil != nil {
+		facts = append(facts, portfolioFact{Label: "Berlaku Hingga", Value: formatIndonesianDate(*details.ValidUntil)})
+	}
+
+	if details.EventDate != nil {
+		facts = append(facts, portfolioFact{Label: "Tanggal", Value: formatIndonesianDate(*details.EventDate)})
+	}
+	add("Lokasi", details.Location)
+	add("Penyelenggara", details.Organizer)
+	if details.Score != nil {
+		facts = append(facts, portfolioFact{Label: "Nilai", Value: fmt.Sprintf("%g", *details.Score)})
+	}
+	if len(achievement.Members) > 0 {
+		names := []string{}
+		for _, member := range achievement.Members {
+			name := member.StudentID
+			if member.FullName != "" {
+				name = member.FullName
+			}
+			names = append(names, name)
+		}
+		facts = append(facts, portfolioFact{Label: "Anggota Tim", Value: strings.Join(names, ", ")})
+	}
+	if len(achievement.Tags) > 0 {
+		facts = append(facts, portfolioFact{Label: "Tag", Value: strings.Join(achievement.Tags, ", ")})
+	}
+	facts = append(facts, portfolioFact{Label: "Poin", Value: fmt.Sprintf("%g", achievement.Points)})
+	return facts
+}
+
+// readAttachment membaca isi file lampiran dari blob store
+func (s *portfolioService) readAttachment(ctx context.Context, fileURL string) ([]byte, error) {
+	key, err := attachmentKey(fileURL)
+	if err != nil {
+		return nil, err
+	}
+	content, _, err := s.blobStore.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer content.Close()
+	return io.ReadAll(content)
+}
+
+func attachmentDataURI(file portfolioAttachment) string {
+	fileType := file.FileType
+	if fileType == "" {
+		fileType = "application/octet-stream"
+	}
+	return "data:" + fileType + ";base64," + base64.StdEncoding.EncodeToString(file.Data)
+}
+
+// JSON Resume (https://jsonresume.org/schema). Prestasi dipetakan ke bagian yang paling
+// sesuai; lampiran disimpan pada field tambahan "attachments".
+type jsonResume struct {
+	Schema       string                  `json:"$schema"`
+	Basics       jsonResumeBasics        `json:"basics"`
+	Education    []jsonResumeEducation   `json:"education"`
+	Volunteer    []jsonResumeVolunteer   `json:"volunteer,omitempty"`
+	Awards       []jsonResumeAward       `json:"awards,omitempty"`
+	Certificates []jsonResumeCertificate `json:"certificates,omitempty"`
+	Publications []jsonResumePublication `json:"publications,omitempty"`
+	Projects     []jsonResumeProject     `json:"projects,omitempty"`
+	Meta         jsonResumeMeta          `json:"meta"`
+}
+
+type jsonResumeBasics struct {
+	Name    string `json:"name"`
+	Label   string `json:"label,omitempty"`
+	Email   string `json:"email,omitempty"`
+	Summary string `json:"summary,omitempty"`
+}
+
+type jsonResumeEducation struct {
+	Institution string `json:"institution,omitempty"`
+	Area        string `json:"area,omitempty"`
+	StudyType   string `json:"studyType,omitempty"`
+	StartDate   string `json:"startDate,omitempty"`
+}
+
+type jsonResumeVolunteer struct {
+	Organization string                 `json:"organization"`
+	Position     string                 `json:"position,omitempty"`
+	StartDate    string                 `json:"startDate,omitempty"`
+	EndDate      string                 `json:"endDate,omitempty"`
+	Summary      string                 `json:"summary,omitempty"`
+	Attachments  []jsonResumeAttachment `json:"attachments,omitempty"`
+}
+
+type jsonResumeAward struct {
+	Title       string                 `json:"title"`
+	Date        string                 `json:"date,omitempty"`
+	Awarder     string                 `json:"awarder,omitempty"`
+	Summary     string                 `json:"summary,omitempty"`
+	Attachments []jsonResumeAttachment `json:"attachments,omitempty"`
+}
+
+type jsonResumeCertificate struct {
+	Name        string                 `json:"name"`
+	Date        string                 `json:"date,omitempty"`
+	Issuer      string                 `json:"issuer,omitempty"`
+	Attachments []jsonResumeAttachment `json:"attachments,omitempty"`
+}
+
+type jsonResumePublication struct {
+	Name        string                 `json:"name"`
+	Publisher   string                 `json:"publisher,omitempty"`
+	ReleaseDate string                 `json:"releaseDate,omitempty"`
+	URL         string                 `json:"url,omitempty"`
+	Summary     string                 `json:"summary,omitempty"`
+	Attachments []jsonResumeAttachment `json:"attachments,omitempty"`
+}
+
+type jsonResumeProject struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	StartDate   string                 `json:"startDate,omitempty"`
+	Keywords    []string               `json:"keywords,omitempty"`
+	Type        string                 `json:"type,omitempty"`
+	Attachments []jsonResumeAttachment `json:"attachments,omitempty"`
+}
+
+type jsonResumeAttachment struct {
+	FileName string `json:"fileName"`
+	FileType string `json:"fileType,omitempty"`
+	Data     string `json:"data,omitempty"` // data URI base64, hanya jika lampiran disematkan
+}
+
+type jsonResumeMeta struct {
+	Version      string `json:"version"`
+	LastModified string `json:"lastModified"`
+}
+
+func buildJSONResume(data *portfolio) *jsonResume {
+	const dateLayout = "2006-01-02"
+	value := func(v *string) string {
+		if v == nil {
+			return ""
+		}
+		return *v
+	}
+
+	resume := &jsonResume{
+		Schema: "https://raw.githubusercontent.com/jsonresume/resume-schema/v1.0.0/schema.json",
+		Basics: jsonResumeBasics{
+			Name:  data.Student.User.FullName,
+			Label: "Mahasiswa " + data.Student.ProgramStudy,
+			Email: data.Student.User.Email,
+		},
+		Education: []jsonResumeEducation{{
+			Area:      data.Student.ProgramStudy,
+			StartDate: data.Student.AcademicYear,
+		}},
+		Meta: jsonResumeMeta{Version: "v1.0.0", LastModified: data.GeneratedAt.Format(time.RFC3339)},
+	}
+
+	for _, section := range data.Sections {
+		for _, item := range section.Items {
+			achievement := item.Achievement
+			details := achievement.Details
+			date := item.Date.Format(dateLayout)
+
+			attachments := []jsonResumeAttachment{}
+			for _, file := range item.Attachments {
+				entry := jsonResumeAttachment{FileName: file.FileName, FileType: file.FileType}
+				if file.Data != nil {
+					entry.Data = attachmentDataURI(file)
+				}
+				attachments = append(attachments, entry)
+			}
+
+			switch achievement.AchievementType {
+			case model.AchievementTypeCompetition, model.AchievementTypeAcademic:
+				awarder := value(details.Organizer)
+				if awarder == "" {
+					awarder = value(details.CompetitionName)
+				}
+				resume.Awards = append(resume.Awards, jsonResumeAward{
+					Title: achievement.Title, Date: date, Awarder: awarder,
+					Summary: achievement.Description, Attachments: attachments,
+				})
+			case model.AchievementTypeOrganization:
+				entry := jsonResumeVolunteer{
+					Organization: value(details.OrganizationName), Position: value(details.Position),
+					StartDate: date, Summary: achievement.Description, Attachments: attachments,
+				}
+				if entry.Organization == "" {
+					entry.Organization = achievement.Title
+				}
+				if details.Period != nil && !details.Period.End.IsZero() {
+					entry.EndDate = details.Period.End.Format(dateLayout)
+				}
+				resume.Volunteer = append(resume.Volunteer, entry)
+			case model.AchievementTypePublication:
+				entry := jsonResumePublication{
+					Name: achievement.Title, Publisher: value(details.Publisher), ReleaseDate: date,
+					Summary: achievement.Description, Attachments: attachments,
+				}
+				if details.PublicationTitle != nil && *details.PublicationTitle != "" {
+					entry.Name = *details.PublicationTitle
+				}
+				if details.DOI != nil && *details.DOI != "" {
+					entry.URL = "https://doi.org/" + *details.DOI
+				}
+				resume.Publications = append(resume.Publications, entry)
+			case model.AchievementTypeCertification:
+				name := value(details.CertificationName)
+				if name == "" {
+					name = achievement.Title
+				}
+				resume.Certificates = append(resume.Certificates, jsonResumeCertificate{
+					Name: name, Date: date, Issuer: value(details.IssuedBy), Attachments: attachments,
+				})
+			default:
+				resume.Projects = append(resume.Projects, jsonResumeProject{
+					Name: achievement.Title, Description: achievement.Description, StartDate: date,
+					Keywords: achievement.Tags, Type: section.Label, Attachments: attachments,
+				})
+			}
+		}
+	}
+
+	resume.Basics.Summary = fmt.Sprintf("%d prestasi terverifikasi dengan total %g poin.", data.Total, data.TotalPoints)
+	return resume
+}
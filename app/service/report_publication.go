@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+)
+
+// PublicationExportRequest filter ekspor publikasi untuk keperluan akreditasi
+type PublicationExportRequest struct {
+	Format       string // bib atau ris
+	ProgramStudy string // kosong = semua program studi
+	Year         int    // tahun terbit, 0 = semua tahun
+	Status       string // kosong = verified; "all" = semua status
+}
+
+// ReportFile hasil ekspor laporan dalam bentuk file
+type ReportFile struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+}
+
+// ExportPublications mengekspor achievement publikasi mahasiswa dalam cakupan user
+// (mahasiswa: milik sendiri, dosen: mahasiswa bimbingan, admin: semua) sebagai BibTeX atau RIS.
+// Publikasi tim hanya ditulis sekali meskipun beberapa anggotanya masuk cakupan.
+func (s *reportService) ExportPublications(ctx context.Context, userID uuid.UUID, req *PublicationExportRequest) (*ReportFile, error) {
+	format := strings.ToLower(req.Format)
+	if format != "bib" && format != "ris" {
+		return nil, errors.New("format ekspor tidak valid. Pilih: bib, ris")
+	}
+
+	status := strings.ToLower(req.Status)
+	if status == "" {
+		status = string(model.StatusVerified)
+	}
+	switch model.AchievementStatus(status) {
+	case model.StatusDraft, model.StatusSubmitted, model.StatusVerified, model.StatusRejected:
+	default:
+		if status != "all" {
+			return nil, errors.New("status tidak valid. Pilih: draft, submitted, verified, rejected, all")
+		}
+	}
+
+	studentIDs, err := s.getStudentIDsByRole(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	inScope := make(map[string]bool, len(studentIDs))
+	for _, id := range studentIDs {
+		inScope[id] = true
+	}
+
+	if req.ProgramStudy != "" {
+		students, err := s.studentRepo.FindAllStudents(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("gagal memuat students: %v", err)
+		}
+		for _, student := range students {
+			if !strings.EqualFold(strings.TrimSpace(student.ProgramStudy), strings.TrimSpace(req.ProgramStudy)) {
+				delete(inScope, student.ID.String())
+			}
+		}
+	}
+
+	achievements, err := s.achievementRepo.FindAllAchievements(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("gagal memuat achievements: %v", err)
+	}
+
+	var publications []*model.Achievement
+	for i := range achievements {
+		achievement := &achievements[i]
+		if achievement.AchievementType != model.AchievementTypePublication {
+			continue
+		}
+		if status != "all" && string(achievement.Status) != status {
+			continue
+		}
+		if req.Year != 0 {
+			date := achievementDate(achievement.Details)
+			if date == nil || date.Year() != req.Year {
+				continue
+			}
+		}
+		for _, participantID := range achievement.ParticipantIDs() {
+			if inScope[participantID] {
+				publications = append(publications, achievement)
+				break
+			}
+		}
+	}
+
+	// Terbaru lebih dulu, sama seperti daftar publikasi pada borang akreditasi
+	sort.SliceStable(publications, func(i, j int) bool {
+		a, b := timeValue(achievementDate(publications[i].Details)), timeValue(achievementDate(publications[j].Details))
+		if !a.Equal(b) {
+			return a.After(b)
+		}
+		return publications[i].Title < publications[j].Title
+	})
+
+	var sb strings.Builder
+	file := &ReportFile{FileName: "publications." + format}
+	if format == "bib" {
+		file.ContentType = "application/x-bibtex; charset=utf-8"
+		for _, achievement := range publications {
+			writeBibTeX(&sb, achievement)
+		}
+	} else {
+		file.ContentType = "application/x-research-info-systems; charset=utf-8"
+		for _, achievement := range publications {
+			writeRIS(&sb, achievement)
+		}
+	}
+	file.Content = []byte(sb.String())
+	return file, nil
+}
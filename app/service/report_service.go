@@ -7,12 +7,14 @@ import (
 	"strings"
 
 	"github.com/google/uuid"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
 	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/repository"
 )
 
 type ReportService interface {
 	GetStatistics(ctx context.Context, userID uuid.UUID) (*StatisticsResponse, error)
 	GetStudentStatistics(ctx context.Context, userID uuid.UUID, studentID uuid.UUID) (*StudentStatisticsResponse, error)
+	ExportPublications(ctx context.Context, userID uuid.UUID, req *PublicationExportRequest) (*ReportFile, error)
 }
 
 type reportService struct {
@@ -44,6 +46,7 @@ type StatisticsResponse struct {
 	TotalByPeriod                []PeriodStatResponse     `json:"total_by_period"`
 	TopStudents                  []TopStudentStatResponse `json:"top_students"`
 	CompetitionLevelDistribution map[string]int64         `json:"competition_level_distribution"`
+	ExpiredCertifications        int64                    `json:"expired_certifications"` // Sertifikasi terverifikasi yang masa berlakunya sudah lewat
 }
 
 type PeriodStatResponse struct {
@@ -66,6 +69,7 @@ type StudentStatisticsResponse struct {
 	CompetitionLevelDistribution map[string]int64     `json:"competition_level_distribution"`
 	TotalPoints                  float64              `json:"total_points"`
 	TotalAchievements            int64                `json:"total_achievements"`
+	ExpiredCertifications        int64                `json:"expired_certifications"`
 }
 
 func (s *reportService) checkRole(ctx context.Context, userID uuid.UUID) (string, error) {
@@ -155,6 +159,7 @@ func (s *reportService) GetStatistics(ctx context.Context, userID uuid.UUID) (*S
 		TotalByPeriod:                []PeriodStatResponse{},
 		TopStudents:                  []TopStudentStatResponse{},
 		CompetitionLevelDistribution: stats.CompetitionLevelDistribution,
+		ExpiredCertifications:        stats.ExpiredCertifications,
 	}
 
 	for _, period := range stats.TotalByPeriod {
@@ -226,6 +231,10 @@ func (s *reportService) GetStudentStatistics(ctx context.Context, userID uuid.UU
 	achievements, err := s.achievementRepo.FindAchievementsByStudentID(ctx, studentID.String())
 	if err == nil {
 		for _, achievement := range achievements {
+			// Hanya hitung prestasi yang sudah selesai seluruh tahap persetujuan
+			if achievement.Status != model.StatusVerified {
+				continue
+			}
 			totalPoints += achievement.Points
 			totalAchievements++
 		}
@@ -239,6 +248,7 @@ func (s *reportService) GetStudentStatistics(ctx context.Context, userID uuid.UU
 		CompetitionLevelDistribution: stats.CompetitionLevelDistribution,
 		TotalPoints:                  totalPoints,
 		TotalAchievements:            totalAchievements,
+		ExpiredCertifications:        stats.ExpiredCertifications,
 	}
 
 	for _, period := range stats.TotalByPeriod {
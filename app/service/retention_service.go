@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/repository"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/storage"
+)
+
+// defaultAchievementRetention masa pemulihan achievement yang dihapus jika konfigurasi tidak valid
+const defaultAchievementRetention = 30 * 24 * time.Hour
+
+type RetentionService interface {
+	RestoreAchievement(ctx context.Context, userID uuid.UUID, achievementID string) (*AchievementResponse, error)
+	PurgeExpired(ctx context.Context) (int, error)
+	StartPurgeMonitor(interval time.Duration)
+}
+
+type retentionService struct {
+	achievementService AchievementService
+	achievementRepo    repository.AchievementRepository
+	historyRepo        repository.AchievementHistoryRepository
+	versionRepo        repository.AchievementVersionRepository
+	studentRepo        repository.StudentRepository
+	userRepo           repository.UserRepository
+	blobStore          storage.BlobStore
+	retention          time.Duration
+}
+
+func NewRetentionService(
+	achievementService AchievementService,
+	achievementRepo repository.AchievementRepository,
+	historyRepo repository.AchievementHistoryRepository,
+	versionRepo repository.AchievementVersionRepository,
+	studentRepo repository.StudentRepository,
+	userRepo repository.UserRepository,
+	blobStore storage.BlobStore,
+	retention time.Duration,
+) RetentionService {
+	if retention <= 0 {
+		retention = defaultAchievementRetention
+	}
+	return &retentionService{
+		achievementService: achievementService,
+		achievementRepo:    achievementRepo,
+		historyRepo:        historyRepo,
+		versionRepo:        versionRepo,
+		studentRepo:        studentRepo,
+		userRepo:           userRepo,
+		blobStore:          blobStore,
+		retention:          retention,
+	}
+}
+
+// RestoreAchievement memulihkan achievement yang dihapus selama masa retensi belum lewat.
+// Hanya pembuat achievement atau admin yang dapat memulihkan.
+func (s *retentionService) RestoreAchievement(ctx context.Context, userID uuid.UUID, achievementID string) (*AchievementResponse, error) {
+	achievement, err := s.achievementRepo.FindDeletedAchievementByID(ctx, achievementID)
+	if err != nil {
+		return nil, errors.New("achievement terhapus tidak ditemukan")
+	}
+
+	if student, err := s.studentRepo.FindStudentByUserID(ctx, userID); err == nil {
+		if achievement.StudentID != student.ID.String() {
+			return nil, errors.New("anda tidak memiliki akses untuk memulihkan achievement ini")
+		}
+	} else {
+		user, err := s.userRepo.FindUserByID(ctx, userID)
+		if err != nil || !strings.Contains(strings.ToLower(user.Role.Name), "admin") {
+			return nil, errors.New("hanya pemilik achievement atau admin yang dapat memulihkan")
+		}
+	}
+
+	if achievement.DeletedAt == nil || time.Since(*achievement.DeletedAt) > s.retention {
+		return nil, errors.New("masa pemulihan achievement sudah berakhir")
+	}
+
+	if err := s.achievementRepo.RestoreAchievement(ctx, achievementID); err != nil {
+		return nil, fmt.Errorf("gagal memulihkan achievement: %v", err)
+	}
+	if err := s.achievementRepo.RestoreReferences(ctx, achievementID); err != nil {
+		return nil, fmt.Errorf("gagal memulihkan reference: %v", err)
+	}
+
+	if reference, err := s.achievementRepo.FindReferenceByMongoID(ctx, achievementID); err == nil {
+		oldStatus := achievement.Status
+		history := &model.AchievementHistory{
+			AchievementRefID:   reference.ID,
+			MongoAchievementID: achievementID,
+			OldStatus:          &oldStatus,
+			NewStatus:          achievement.Status,
+			ChangedBy:          userID,
+			Notes:              "Achievement dipulihkan",
+		}
+		if err := s.historyRepo.CreateHistory(ctx, history); err != nil {
+			fmt.Printf("Warning: Gagal membuat history: %v\n", err)
+		}
+	}
+
+	return s.achievementService.GetAchievementByID(ctx, userID, achievementID)
+}
+
+// PurgeExpired menghapus permanen achievement yang masa retensinya sudah lewat beserta
+// reference, history, versi dan file attachment lokalnya
+func (s *retentionService) PurgeExpired(ctx context.Context) (int, error) {
+	achievements, err := s.achievementRepo.FindAchievementsDeletedBefore(ctx, time.Now().Add(-s.retention))
+	if err != nil {
+		return 0, fmt.Errorf("gagal memuat achievement terhapus: %v", err)
+	}
+
+	purged := 0
+	for i := range achievements {
+		if err := s.purgeAchievement(ctx, &achievements[i]); err != nil {
+			fmt.Printf("Warning: Gagal menghapus permanen achievement %s: %v\n", achievements[i].ID.Hex(), err)
+			continue
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+func (s *retentionService) purgeAchievement(ctx context.Context, achievement *model.Achievement) error {
+	achievementID := achievement.ID.Hex()
+
+	// History lebih dulu karena mereferensikan achievement_references
+	if err := s.historyRepo.DeleteHistoriesByMongoAchievementID(ctx, achievementID); err != nil {
+		return fmt.Errorf("gagal menghapus history: %v", err)
+	}
+	if err := s.achievementRepo.PurgeReferencesByMongoID(ctx, achievementID); err != nil {
+		return fmt.Errorf("gagal menghapus reference: %v", err)
+	}
+
+	files := make(map[string]bool)
+	for _, attachment := range achievement.Attachments {
+		files[attachment.FileURL] = true
+	}
+	if versions, err := s.versionRepo.FindVersionsByAchievementID(ctx, achievementID); err == nil {
+		for _, version := range versions {
+			for _, attachment := range version.Attachments {
+				files[attachment.FileURL] = true
+			}
+		}
+	}
+	if err := s.versionRepo.DeleteVersionsByAchievementID(ctx, achievementID); err != nil {
+		return fmt.Errorf("gagal menghapus versi: %v", err)
+	}
+
+	if err := s.achievementRepo.PurgeAchievement(ctx, achievementID); err != nil {
+		return fmt.Errorf("gagal menghapus dokumen: %v", err)
+	}
+
+	for fileURL := range files {
+		s.removeAttachment(ctx, fileURL)
+	}
+	return nil
+}
+
+// removeAttachment menghapus file attachment dari blob store; FileURL yang tidak dapat
+// dipetakan ke key (misalnya path absolut) tidak pernah dihapus
+func (s *retentionService) removeAttachment(ctx context.Context, fileURL string) {
+	key, err := attachmentKey(fileURL)
+	if err != nil {
+		return
+	}
+	if err := s.blobStore.Delete(ctx, key); err != nil {
+		fmt.Printf("Warning: Gagal menghapus file %s: %v\n", key, err)
+	}
+}
+
+// StartPurgeMonitor menjalankan PurgeExpired secara berkala di background
+func (s *retentionService) StartPurgeMonitor(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+			purged, err := s.PurgeExpired(ctx)
+			cancel()
+			if err != nil {
+				fmt.Printf("Warning: Purge achievement gagal: %v\n", err)
+				continue
+			}
+			if purged > 0 {
+				fmt.Printf("Purge achievement: %d achievement dihapus permanen\n", purged)
+			}
+		}
+	}()
+}
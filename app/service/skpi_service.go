@@ -0,0 +1,546 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/repository"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/helper"
+)
+
+const (
+	SKPIStatusDraft = "draft"
+	SKPIStatusFinal = "final"
+)
+
+type SKPIService interface {
+	GetSKPI(ctx context.Context, userID uuid.UUID, studentID uuid.UUID) (*SKPIResponse, error)
+	RenderSKPIPDF(skpi *SKPIResponse) ([]byte, error)
+	LockSKPI(ctx context.Context, userID uuid.UUID, studentID uuid.UUID, req *LockSKPIRequest) (*SKPIResponse, error)
+}
+
+type skpiService struct {
+	skpiRepo        repository.SKPIRepository
+	achievementRepo repository.AchievementRepository
+	certificateRepo repository.CertificateRepository
+	studentRepo     repository.StudentRepository
+	lecturerRepo    repository.LecturerRepository
+	userRepo        repository.UserRepository
+	template        *SKPITemplate
+}
+
+// NewSKPIService membuat generator SKPI. templatePath opsional, kosong = template default.
+func NewSKPIService(
+	skpiRepo repository.SKPIRepository,
+	achievementRepo repository.AchievementRepository,
+	certificateRepo repository.CertificateRepository,
+	studentRepo repository.StudentRepository,
+	lecturerRepo repository.LecturerRepository,
+	userRepo repository.UserRepository,
+	templatePath string,
+) SKPIService {
+	return &skpiService{
+		skpiRepo:        skpiRepo,
+		achievementRepo: achievementRepo,
+		certificateRepo: certificateRepo,
+		studentRepo:     studentRepo,
+		lecturerRepo:    lecturerRepo,
+		userRepo:        userRepo,
+		template:        loadSKPITemplate(templatePath),
+	}
+}
+
+type LockSKPIRequest struct {
+	Number string `json:"number,omitempty"` // Nomor SKPI resmi, kosong = dibuat otomatis
+}
+
+type SKPIResponse struct {
+	Number            string        `json:"number"`
+	Status            string        `json:"status"` // draft atau final
+	Institution       BilingualText `json:"institution"`
+	Title             BilingualText `json:"title"`
+	Introduction      BilingualText `json:"introduction"`
+	Student           SKPIStudent   `json:"student"`
+	Sections          []SKPISection `json:"sections"`
+	TotalAchievements int           `json:"total_achievements"`
+	TotalPoints       float64       `json:"total_points"`
+	SignatoryTitle    BilingualText `json:"signatory_title"`
+	SignatoryName     string        `json:"signatory_name,omitempty"`
+	Footer            BilingualText `json:"footer"`
+	GeneratedAt       time.Time     `json:"generated_at"`
+	LockedAt          *time.Time    `json:"locked_at,omitempty"`
+}
+
+type SKPIStudent struct {
+	ID           string `json:"id"`
+	NIM          string `json:"nim"`
+	FullName     string `json:"full_name"`
+	ProgramStudy string `json:"program_study"`
+	AcademicYear string `json:"academic_year"`
+}
+
+type SKPISection struct {
+	Category string        `json:"category"`
+	Label    BilingualText `json:"label"`
+	Items    []SKPIItem    `json:"items"`
+}
+
+type SKPIItem struct {
+	AchievementID   string        `json:"achievement_id"`
+	Title           string        `json:"title"`
+	Detail          BilingualText `json:"detail"`
+	Date            *time.Time    `json:"date,omitempty"`
+	VerifiedAt      *time.Time    `json:"verified_at,omitempty"`
+	Points          float64       `json:"points"`
+	CertificateCode string        `json:"certificate_code,omitempty"`
+}
+
+// authorizeStudentAccess mahasiswa hanya dapat melihat SKPI miliknya, dosen wali untuk
+// mahasiswa bimbingannya, dan admin untuk semua mahasiswa
+func (s *skpiService) authorizeStudentAccess(ctx context.Context, userID uuid.UUID, student *model.Student) error {
+	if own, err := s.studentRepo.FindStudentByUserID(ctx, userID); err == nil {
+		if own.ID != student.ID {
+			return errors.New("anda tidak memiliki akses ke SKPI mahasiswa ini")
+		}
+		return nil
+	}
+	if lecturer, err := s.lecturerRepo.FindLecturerByUserID(ctx, userID); err == nil {
+		if student.AdvisorID == nil || *student.AdvisorID != lecturer.ID {
+			return errors.New("anda bukan dosen wali dari mahasiswa ini")
+		}
+		return nil
+	}
+	if !s.isAdmin(ctx, userID) {
+		return errors.New("anda tidak memiliki akses ke SKPI mahasiswa ini")
+	}
+	return nil
+}
+
+func (s *skpiService) isAdmin(ctx context.Context, userID uuid.UUID) bool {
+	user, err := s.userRepo.FindUserByID(ctx, userID)
+	return err == nil && strings.Contains(strings.ToLower(user.Role.Name), "admin")
+}
+
+// GetSKPI mengembalikan versi final jika sudah dikunci, selain itu draft dari prestasi
+// terverifikasi saat ini
+func (s *skpiService) GetSKPI(ctx context.Context, userID uuid.UUID, studentID uuid.UUID) (*SKPIResponse, error) {
+	student, err := s.studentRepo.FindStudentByID(ctx, studentID)
+	if err != nil {
+		return nil, errors.New("mahasiswa tidak ditemukan")
+	}
+	if err := s.authorizeStudentAccess(ctx, userID, student); err != nil {
+		return nil, err
+	}
+
+	if document, err := s.skpiRepo.FindSKPIDocumentByStudentID(ctx, student.ID); err == nil {
+		var skpi SKPIResponse
+		if err := json.Unmarshal([]byte(document.Content), &skpi); err != nil {
+			return nil, fmt.Errorf("gagal memuat SKPI final: %v", err)
+		}
+		return &skpi, nil
+	}
+
+	return s.generate(ctx, student)
+}
+
+// LockSKPI membekukan SKPI saat kelulusan. Setelah dikunci, prestasi baru tidak lagi
+// mengubah isi SKPI.
+func (s *skpiService) LockSKPI(ctx context.Context, userID uuid.UUID, studentID uuid.UUID, req *LockSKPIRequest) (*SKPIResponse, error) {
+	if !s.isAdmin(ctx, userID) {
+		return nil, errors.New("hanya admin yang dapat mengunci SKPI")
+	}
+
+	student, err := s.studentRepo.FindStudentByID(ctx, studentID)
+	if err != nil {
+		return nil, errors.New("mahasiswa tidak ditemukan")
+	}
+	if _, err := s.skpiRepo.FindSKPIDocumentByStudentID(ctx, student.ID); err == nil {
+		return nil, errors.New("SKPI mahasiswa ini sudah dikunci")
+	}
+
+	skpi, err := s.generate(ctx, student)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	skpi.Status = SKPIStatusFinal
+	skpi.LockedAt = &now
+	if number := strings.TrimSpace(req.Number); number != "" {
+		skpi.Number = number
+	}
+
+	content, err := json.Marshal(skpi)
+	if err != nil {
+		return nil, fmt.Errorf("gagal menyimpan SKPI: %v", err)
+	}
+
+	document := &model.SKPIDocument{
+		StudentID: student.ID,
+		Number:    skpi.Number,
+		Content:   string(content),
+		LockedBy:  userID,
+		LockedAt:  now,
+	}
+	if err := s.skpiRepo.CreateSKPIDocument(ctx, document); err != nil {
+		return nil, fmt.Errorf("gagal menyimpan SKPI: %v", err)
+	}
+	return skpi, nil
+}
+
+func (s *skpiService) generate(ctx context.Context, student *model.Student) (*SKPIResponse, error) {
+	achievements, err := s.achievementRepo.FindAchievementsByStudentID(ctx, student.ID.String())
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil data prestasi: %v", err)
+	}
+
+	references, err := s.achievementRepo.FindReferencesByStudentIDs(ctx, []uuid.UUID{student.ID})
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil data reference: %v", err)
+	}
+	verifiedAt := make(map[string]*time.Time)
+	for _, reference := range references {
+		verifiedAt[reference.MongoAchievementID] = reference.VerifiedAt
+	}
+
+	now := time.Now()
+	skpi := &SKPIResponse{
+		Number:       fmt.Sprintf("SKPI/%s/%d", student.StudentID, now.Year()),
+		Status:       SKPIStatusDraft,
+		Institution:  s.template.Institution,
+		Title:        s.template.Title,
+		Introduction: s.template.Introduction,
+		Student: SKPIStudent{
+			ID:           student.ID.String(),
+			NIM:          student.StudentID,
+			FullName:     student.User.FullName,
+			ProgramStudy: student.ProgramStudy,
+			AcademicYear: student.AcademicYear,
+		},
+		Sections:       []SKPISection{},
+		SignatoryTitle: s.template.SignatoryTitle,
+		SignatoryName:  s.template.SignatoryName,
+		Footer:         s.template.Footer,
+		GeneratedAt:    now,
+	}
+
+	sections := make(map[string]*SKPISection)
+	for i := range achievements {
+		achievement := &achievements[i]
+		if achievement.Status != model.StatusVerified {
+			continue
+		}
+
+		category := s.template.categoryFor(achievement.AchievementType)
+		if category == nil {
+			category = &SKPICategory{Code: "other", Label: BilingualText{ID: "Prestasi Lainnya", EN: "Other Achievements"}}
+		}
+		section, ok := sections[category.Code]
+		if !ok {
+			section = &SKPISection{Category: category.Code, Label: category.Label, Items: []SKPIItem{}}
+			sections[category.Code] = section
+		}
+
+		item := SKPIItem{
+			AchievementID: achievement.ID.Hex(),
+			Title:         achievement.Title,
+			Detail:        skpiDetail(achievement),
+			Date:          achievementDate(achievement.Details),
+			VerifiedAt:    verifiedAt[achievement.ID.Hex()],
+			Points:        achievement.Points,
+		}
+		if certificate, err := s.certificateRepo.FindActiveCertificate(ctx, item.AchievementID, student.ID); err == nil {
+			item.CertificateCode = certificate.Code
+		}
+
+		section.Items = append(section.Items, item)
+		skpi.TotalAchievements++
+		skpi.TotalPoints += achievement.Points
+	}
+
+	// Urutan kategori mengikuti template, prestasi terbaru lebih dulu
+	for _, category := range append(s.template.Categories, SKPICategory{Code: "other"}) {
+		section, ok := sections[category.Code]
+		if !ok {
+			continue
+		}
+		delete(sections, category.Code)
+		sort.SliceStable(section.Items, func(i, j int) bool {
+			return timeValue(section.Items[i].Date).After(timeValue(section.Items[j].Date))
+		})
+		skpi.Sections = append(skpi.Sections, *section)
+	}
+
+	return skpi, nil
+}
+
+// skpiDetail ringkasan dwibahasa sesuai tipe prestasi
+func skpiDetail(achievement *model.Achievement) BilingualText {
+	details := achievement.Details
+	value := func(v *string) string {
+		if v == nil {
+			return ""
+		}
+		return strings.TrimSpace(*v)
+	}
+	join := func(parts ...string) string {
+		filled := []string{}
+		for _, part := range parts {
+			if part != "" {
+				filled = append(filled, part)
+			}
+		}
+		return strings.Join(filled, ", ")
+	}
+
+	switch achievement.AchievementType {
+	case model.AchievementTypeCompetition:
+		var rankID, rankEN, levelID, levelEN string
+		if details.Rank != nil {
+			rankID = fmt.Sprintf("Peringkat %d", *details.Rank)
+			rankEN = fmt.Sprintf("Rank %d", *details.Rank)
+		}
+		if details.CompetitionLevel != nil {
+			label := competitionLevelLabels[*details.CompetitionLevel]
+			levelID = "tingkat " + label.ID
+			levelEN = label.EN + " level"
+		}
+		name := value(details.CompetitionName)
+		return BilingualText{
+			ID: join(name, rankID, levelID, value(details.MedalType)),
+			EN: join(name, rankEN, levelEN, value(details.MedalType)),
+		}
+	case model.AchievementTypeOrganization:
+		period := ""
+		if details.Period != nil {
+			period = fmt.Sprintf("%d - %d", details.Period.Start.Year(), details.Period.End.Year())
+			if details.Period.End.IsZero() {
+				period = fmt.Sprintf("%d", details.Period.Start.Year())
+			}
+		}
+		position, organization := value(details.Position), value(details.OrganizationName)
+		return BilingualText{
+			ID: join(strings.TrimSpace(position+" di "+organization), period),
+			EN: join(strings.TrimSpace(position+" at "+organization), period),
+		}
+	case model.AchievementTypePublication:
+		var typeID, typeEN string
+		if details.PublicationType != nil {
+			label := publicationTypeLabels[*details.PublicationType]
+			typeID, typeEN = label.ID, label.EN
+		}
+		title := value(details.PublicationTitle)
+		return BilingualText{
+			ID: join(typeID, title, value(details.Publisher)),
+			EN: join(typeEN, title, value(details.Publisher)),
+		}
+	case model.AchievementTypeCertification:
+		name, issuer := value(details.CertificationName), value(details.IssuedBy)
+		return BilingualText{
+			ID: join(name, "diterbitkan oleh "+issuer),
+			EN: join(name, "issued by "+issuer),
+		}
+	}
+
+	return BilingualText{
+		ID: join(achievement.Description, value(details.Organizer)),
+		EN: join(achievement.Description, value(details.Organizer)),
+	}
+}
+
+// achievementDate tanggal kegiatan atau awal periode organisasi
+func achievementDate(details model.AchievementDetails) *time.Time {
+	if details.EventDate != nil {
+		return details.EventDate
+	}
+	if details.Period != nil && !details.Period.Start.IsZero() {
+		start := details.Period.Start
+		return &start
+	}
+	return nil
+}
+
+func timeValue(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
+
+// RenderSKPIPDF menyusun PDF SKPI dari data dokumen (draft maupun final)
+func (s *skpiService) RenderSKPIPDF(skpi *SKPIResponse) ([]byte, error) {
+	doc := helper.NewPDFDocument()
+	doc.SetInfo(skpi.Title.ID+" - "+skpi.Student.FullName, skpi.Title.EN, "skpi:"+skpi.Number+" status:"+skpi.Status)
+
+	w := &pdfWriter{doc: doc, margin: 56}
+	w.newPage()
+	contentWidth := w.page.Width() - 2*w.margin
+	center := w.page.Width() / 2
+
+	if skpi.Status != SKPIStatusFinal {
+		w.page.SetGray(0.6)
+		w.page.TextRight(w.page.Width()-w.margin, 40, 10, true, "DRAFT")
+		w.page.SetGray(0)
+	}
+
+	w.page.TextCenter(center, w.y, 13, true, strings.ToUpper(skpi.Institution.ID))
+	w.y += 15
+	w.page.TextCenter(center, w.y, 10, false, skpi.Institution.EN)
+	w.y += 28
+	w.page.TextCenter(center, w.y, 16, true, strings.ToUpper(skpi.Title.ID))
+	w.y += 17
+	w.page.TextCenter(center, w.y, 11, false, skpi.Title.EN)
+	w.y += 16
+	w.page.TextCenter(center, w.y, 10, false, "Nomor / Number: "+skpi.Number)
+	w.y += 12
+	w.page.Line(w.margin, w.y, w.page.Width()-w.margin, w.y, 0.8)
+	w.y += 22
+
+	w.paragraph(skpi.Introduction.ID, 9, false, contentWidth)
+	w.paragraph(skpi.Introduction.EN, 8, false, contentWidth)
+	w.y += 8
+
+	w.heading("Informasi Pemegang SKPI / Information Identifying the Holder")
+	identity := []struct {
+		label BilingualText
+		value string
+	}{
+		{BilingualText{ID: "Nama Lengkap", EN: "Full Name"}, skpi.Student.FullName},
+		{BilingualText{ID: "Nomor Induk Mahasiswa", EN: "Student ID Number"}, skpi.Student.NIM},
+		{BilingualText{ID: "Program Studi", EN: "Study Program"}, skpi.Student.ProgramStudy},
+		{BilingualText{ID: "Tahun Angkatan", EN: "Year of Entry"}, skpi.Student.AcademicYear},
+	}
+	for _, row := range identity {
+		w.ensure(16)
+		w.page.Text(w.margin, w.y, 9, true, row.label.ID+" / "+row.label.EN)
+		w.page.Text(w.margin+210, w.y, 9, false, ": "+row.value)
+		w.y += 15
+	}
+	w.y += 10
+
+	w.heading("Prestasi dan Penghargaan / Achievements and Awards")
+	if len(skpi.Sections) == 0 {
+		w.paragraph("Belum ada prestasi terverifikasi. / No verified achievements yet.", 9, false, contentWidth)
+	}
+	for _, section := range skpi.Sections {
+		w.ensure(40)
+		w.page.Text(w.margin, w.y, 10, true, section.Label.ID+" / "+section.Label.EN)
+		w.y += 15
+
+		for i, item := range section.Items {
+			w.ensure(44)
+			number := fmt.Sprintf("%d.", i+1)
+			w.page.Text(w.margin+4, w.y, 9, true, number)
+			indent := w.margin + 20
+			for _, line := range helper.WrapText(item.Title, 9, true, contentWidth-20) {
+				w.ensure(12)
+				w.page.Text(indent, w.y, 9, true, line)
+				w.y += 11
+			}
+			if item.Detail.ID != "" {
+				for _, line := range helper.WrapText(item.Detail.ID, 8.5, false, contentWidth-20) {
+					w.ensure(11)
+					w.page.Text(indent, w.y, 8.5, false, line)
+					w.y += 10
+				}
+			}
+			if item.Detail.EN != "" && item.Detail.EN != item.Detail.ID {
+				w.page.SetGray(0.35)
+				for _, line := range helper.WrapText(item.Detail.EN, 8, false, contentWidth-20) {
+					w.ensure(11)
+					w.page.Text(indent, w.y, 8, false, line)
+					w.y += 10
+				}
+				w.page.SetGray(0)
+			}
+
+			meta := []string{}
+			if item.Date != nil {
+				meta = append(meta, "Tanggal / Date: "+item.Date.Format("02-01-2006"))
+			}
+			if item.CertificateCode != "" {
+				meta = append(meta, "Sertifikat / Certificate: "+formatCertificateCode(item.CertificateCode))
+			}
+			if len(meta) > 0 {
+				w.ensure(11)
+				w.page.Text(indent, w.y, 7.5, false, strings.Join(meta, "   "))
+				w.y += 10
+			}
+			w.y += 5
+		}
+		w.y += 6
+	}
+
+	w.ensure(110)
+	w.y += 10
+	w.page.Text(w.margin, w.y, 9, false, fmt.Sprintf("Jumlah prestasi / Total achievements: %d", skpi.TotalAchievements))
+	w.y += 30
+
+	signX := w.page.Width() - w.margin - 200
+	date := skpi.GeneratedAt
+	if skpi.LockedAt != nil {
+		date = *skpi.LockedAt
+	}
+	w.page.Text(signX, w.y, 9, false, formatIndonesianDate(date))
+	w.y += 12
+	w.page.Text(signX, w.y, 9, true, skpi.SignatoryTitle.ID)
+	w.y += 11
+	w.page.Text(signX, w.y, 8, false, skpi.SignatoryTitle.EN)
+	w.y += 50
+	if skpi.SignatoryName != "" {
+		w.page.Text(signX, w.y, 9, true, skpi.SignatoryName)
+	}
+
+	for _, page := range w.pages {
+		page.Line(w.margin, page.Height()-46, page.Width()-w.margin, page.Height()-46, 0.5)
+		page.Text(w.margin, page.Height()-34, 7, false, skpi.Footer.ID)
+		page.Text(w.margin, page.Height()-25, 7, false, skpi.Footer.EN)
+	}
+
+	return doc.Bytes()
+}
+
+// pdfWriter menulis konten mengalir dari atas ke bawah dan membuat halaman baru bila penuh
+type pdfWriter struct {
+	doc    *helper.PDFDocument
+	page   *helper.PDFPage
+	pages  []*helper.PDFPage
+	margin float64
+	y      float64
+}
+
+func (w *pdfWriter) newPage() {
+	w.page = w.doc.AddPage(helper.PageA4Width, helper.PageA4Height)
+	w.pages = append(w.pages, w.page)
+	w.y = w.margin + 10
+}
+
+// ensure membuat halaman baru jika sisa ruang kurang dari height
+func (w *pdfWriter) ensure(height float64) {
+	if w.y+height > w.page.Height()-w.margin-10 {
+		w.newPage()
+	}
+}
+
+func (w *pdfWriter) heading(text string) {
+	w.ensure(30)
+	w.page.Text(w.margin, w.y, 11, true, text)
+	w.y += 6
+	w.page.Line(w.margin, w.y, w.page.Width()-w.margin, w.y, 0.5)
+	w.y += 16
+}
+
+func (w *pdfWriter) paragraph(text string, size float64, bold bool, width float64) {
+	for _, line := range helper.WrapText(text, size, bold, width) {
+		w.ensure(size + 4)
+		w.page.Text(w.margin, w.y, size, bold, line)
+		w.y += size + 3
+	}
+}
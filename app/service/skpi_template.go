@@ -0,0 +1,108 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+)
+
+// BilingualText label dwibahasa Indonesia/Inggris untuk SKPI
+type BilingualText struct {
+	ID string `json:"id"`
+	EN string `json:"en"`
+}
+
+// SKPICategory kelompok prestasi pada SKPI. Types kosong berarti kategori penampung
+// untuk tipe yang tidak dipetakan ke kategori lain.
+type SKPICategory struct {
+	Code  string        `json:"code"`
+	Label BilingualText `json:"label"`
+	Types []string      `json:"types"`
+}
+
+// SKPITemplate teks tetap dan pengelompokan kategori SKPI. Dapat diganti melalui file JSON
+// (SKPI_TEMPLATE) tanpa mengubah kode.
+type SKPITemplate struct {
+	Institution    BilingualText  `json:"institution"`
+	Title          BilingualText  `json:"title"`
+	Introduction   BilingualText  `json:"introduction"`
+	Categories     []SKPICategory `json:"categories"`
+	SignatoryTitle BilingualText  `json:"signatory_title"`
+	SignatoryName  string         `json:"signatory_name"`
+	Footer         BilingualText  `json:"footer"`
+}
+
+func defaultSKPITemplate() *SKPITemplate {
+	return &SKPITemplate{
+		Institution: BilingualText{ID: "Universitas", EN: "University"},
+		Title:       BilingualText{ID: "Surat Keterangan Pendamping Ijazah", EN: "Diploma Supplement"},
+		Introduction: BilingualText{
+			ID: "Surat Keterangan Pendamping Ijazah ini memuat prestasi pemegang ijazah yang telah diverifikasi oleh institusi.",
+			EN: "This Diploma Supplement lists the achievements of the diploma holder that have been verified by the institution.",
+		},
+		Categories: []SKPICategory{
+			{Code: "academic", Label: BilingualText{ID: "Prestasi Akademik", EN: "Academic Achievements"}, Types: []string{string(model.AchievementTypeAcademic)}},
+			{Code: "competition", Label: BilingualText{ID: "Kompetisi dan Penghargaan", EN: "Competitions and Awards"}, Types: []string{string(model.AchievementTypeCompetition)}},
+			{Code: "organization", Label: BilingualText{ID: "Pengalaman Organisasi", EN: "Organizational Experience"}, Types: []string{string(model.AchievementTypeOrganization)}},
+			{Code: "publication", Label: BilingualText{ID: "Karya Ilmiah dan Publikasi", EN: "Scientific Works and Publications"}, Types: []string{string(model.AchievementTypePublication)}},
+			{Code: "certification", Label: BilingualText{ID: "Sertifikasi Keahlian", EN: "Professional Certifications"}, Types: []string{string(model.AchievementTypeCertification)}},
+			{Code: "other", Label: BilingualText{ID: "Prestasi Lainnya", EN: "Other Achievements"}},
+		},
+		SignatoryTitle: BilingualText{ID: "Wakil Dekan Bidang Kemahasiswaan", EN: "Vice Dean for Student Affairs"},
+		Footer: BilingualText{
+			ID: "Dokumen ini diterbitkan oleh Sistem Pelaporan Prestasi Mahasiswa.",
+			EN: "This document is issued by the Student Achievement Reporting System.",
+		},
+	}
+}
+
+// loadSKPITemplate membaca template dari file JSON, field yang kosong memakai nilai default
+func loadSKPITemplate(path string) *SKPITemplate {
+	template := defaultSKPITemplate()
+	if path == "" {
+		return template
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Printf("Warning: Gagal membaca template SKPI %s, memakai template default: %v\n", path, err)
+		return template
+	}
+	if err := json.Unmarshal(data, template); err != nil {
+		fmt.Printf("Warning: Template SKPI %s tidak valid, memakai template default: %v\n", path, err)
+		return defaultSKPITemplate()
+	}
+	return template
+}
+
+// categoryFor kategori untuk tipe prestasi; tipe yang tidak dipetakan masuk kategori penampung
+func (t *SKPITemplate) categoryFor(achievementType model.AchievementType) *SKPICategory {
+	var fallback *SKPICategory
+	for i := range t.Categories {
+		category := &t.Categories[i]
+		if len(category.Types) == 0 && fallback == nil {
+			fallback = category
+		}
+		for _, code := range category.Types {
+			if code == string(achievementType) {
+				return category
+			}
+		}
+	}
+	return fallback
+}
+
+var competitionLevelLabels = map[model.CompetitionLevel]BilingualText{
+	model.CompetitionLevelInternational: {ID: "Internasional", EN: "International"},
+	model.CompetitionLevelNational:      {ID: "Nasional", EN: "National"},
+	model.CompetitionLevelRegional:      {ID: "Regional", EN: "Regional"},
+	model.CompetitionLevelLocal:         {ID: "Lokal", EN: "Local"},
+}
+
+var publicationTypeLabels = map[model.PublicationType]BilingualText{
+	model.PublicationTypeJournal:    {ID: "Jurnal", EN: "Journal"},
+	model.PublicationTypeConference: {ID: "Konferensi", EN: "Conference"},
+	model.PublicationTypeBook:       {ID: "Buku", EN: "Book"},
+}
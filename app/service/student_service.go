@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
@@ -137,6 +138,11 @@ func (s *studentService) mapToAchievementResponse(ctx context.Context, achieveme
 			VerifiedAt:    reference.VerifiedAt,
 			VerifiedBy:    verifiedBy,
 			RejectionNote: reference.RejectionNote,
+			ApprovalStages: reference.ApprovalStages,
+			CurrentStage:  reference.CurrentStage,
+			StageStartedAt: reference.StageStartedAt,
+			SLAFlaggedAt:  reference.SLAFlaggedAt,
+			EscalatedAt:   reference.EscalatedAt,
 		}
 	}
 
@@ -148,11 +154,13 @@ func (s *studentService) mapToAchievementResponse(ctx context.Context, achieveme
 		Title:           achievement.Title,
 		Description:     achievement.Description,
 		Details:         achievement.Details,
-		Attachments:     achievement.Attachments,
+		Attachments:     withAttachmentIDs(achievement.Attachments),
 		Tags:            achievement.Tags,
+		Members:         mapTeamMembers(ctx, s.studentRepo, achievement.Members),
 		Points:          achievement.Points,
 		Status:          achievement.Status,
 		Reference:       refInfo,
+		Expired:         certificationExpired(achievement, time.Now()),
 		CreatedAt:       achievement.CreatedAt,
 		UpdatedAt:       achievement.UpdatedAt,
 	}
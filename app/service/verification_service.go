@@ -0,0 +1,347 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/repository"
+)
+
+type SLAStatus string
+
+const (
+	SLAOnTime    SLAStatus = "on_time"
+	SLAOverdue   SLAStatus = "overdue"
+	SLAEscalated SLAStatus = "escalated"
+)
+
+// defaultVerificationSLA dipakai jika VERIFICATION_SLA tidak valid
+const defaultVerificationSLA = 72 * time.Hour
+
+type VerificationService interface {
+	GetQueue(ctx context.Context, userID uuid.UUID, stage model.ApprovalStage) ([]VerificationQueueItem, error)
+	CheckSLA(ctx context.Context) (*SLACheckResult, error)
+	StartSLAMonitor(interval time.Duration)
+}
+
+type verificationService struct {
+	achievementService  AchievementService
+	achievementRepo     repository.AchievementRepository
+	lecturerRepo        repository.LecturerRepository
+	userRepo            repository.UserRepository
+	notificationService NotificationService
+	sla                 time.Duration
+	escalationAfter     time.Duration
+	alternateVerifier   string
+}
+
+// NewVerificationService membuat service antrian verifikasi. sla adalah batas waktu setiap
+// tahap persetujuan, escalationAfter batas kedua sebelum dieskalasi (0 = tanpa eskalasi), dan
+// alternateVerifier username verifikator pengganti tahap dosen wali (kosong = eskalasi ke admin).
+func NewVerificationService(
+	achievementService AchievementService,
+	achievementRepo repository.AchievementRepository,
+	lecturerRepo repository.LecturerRepository,
+	userRepo repository.UserRepository,
+	notificationService NotificationService,
+	sla time.Duration,
+	escalationAfter time.Duration,
+	alternateVerifier string,
+) VerificationService {
+	if sla <= 0 {
+		sla = defaultVerificationSLA
+	}
+	return &verificationService{
+		achievementService:  achievementService,
+		achievementRepo:     achievementRepo,
+		lecturerRepo:        lecturerRepo,
+		userRepo:            userRepo,
+		notificationService: notificationService,
+		sla:                 sla,
+		escalationAfter:     escalationAfter,
+		alternateVerifier:   strings.TrimSpace(alternateVerifier),
+	}
+}
+
+type VerificationQueueItem struct {
+	AchievementResponse
+	Stage          model.ApprovalStage `json:"stage"`
+	SubmittedAt    *time.Time          `json:"submitted_at,omitempty"`
+	StageStartedAt *time.Time          `json:"stage_started_at,omitempty"`
+	AgeHours       float64             `json:"age_hours"` // Lama menunggu pada tahap ini
+	DueAt          *time.Time          `json:"due_at,omitempty"`
+	SLAStatus      SLAStatus           `json:"sla_status"`
+}
+
+type SLACheckResult struct {
+	Checked   int `json:"checked"`
+	Flagged   int `json:"flagged"`
+	Escalated int `json:"escalated"`
+}
+
+// GetQueue antrian persetujuan satu tahap, yang paling lama menunggu di urutan pertama.
+// Lama menunggu dihitung sejak tahap tersebut dimulai.
+func (s *verificationService) GetQueue(ctx context.Context, userID uuid.UUID, stage model.ApprovalStage) ([]VerificationQueueItem, error) {
+	achievements, err := s.achievementService.GetApprovalQueue(ctx, userID, stage)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	items := make([]VerificationQueueItem, 0, len(achievements))
+	for _, achievement := range achievements {
+		item := VerificationQueueItem{
+			AchievementResponse: achievement,
+			Stage:               stage,
+			SLAStatus:           SLAOnTime,
+		}
+
+		if reference := achievement.Reference; reference != nil && reference.SubmittedAt != nil {
+			startedAt := reference.SubmittedAt
+			if reference.StageStartedAt != nil {
+				startedAt = reference.StageStartedAt
+			}
+			age := now.Sub(*startedAt)
+			dueAt := startedAt.Add(s.sla)
+			item.SubmittedAt = reference.SubmittedAt
+			item.StageStartedAt = startedAt
+			item.AgeHours = float64(int(age.Hours()*10)) / 10
+			item.DueAt = &dueAt
+			item.SLAStatus = s.slaStatus(age, reference.EscalatedAt)
+		}
+
+		items = append(items, item)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].AgeHours > items[j].AgeHours
+	})
+	return items, nil
+}
+
+func (s *verificationService) slaStatus(age time.Duration, escalatedAt *time.Time) SLAStatus {
+	if escalatedAt != nil || (s.escalationAfter > 0 && age >= s.escalationAfter) {
+		return SLAEscalated
+	}
+	if age >= s.sla {
+		return SLAOverdue
+	}
+	return SLAOnTime
+}
+
+// CheckSLA menandai achievement yang melewati SLA pada tahap persetujuan mana pun dan memberi
+// tahu approver tahap tersebut, lalu mengeskalasi setelah batas kedua terlewati. Lama menunggu
+// dihitung sejak tahap dimulai. Setiap reference hanya dinotifikasi sekali per tahap.
+func (s *verificationService) CheckSLA(ctx context.Context) (*SLACheckResult, error) {
+	result := &SLACheckResult{}
+	for _, stage := range validApprovalStages {
+		if err := s.checkStageSLA(ctx, stage, result); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func (s *verificationService) checkStageSLA(ctx context.Context, stage model.ApprovalStage, result *SLACheckResult) error {
+	references, err := s.achievementRepo.FindPendingReferencesByStage(ctx, stage, nil)
+	if err != nil {
+		return fmt.Errorf("gagal memuat antrian %s: %v", approvalStageLabel(stage), err)
+	}
+
+	now := time.Now()
+	titles := make(map[string]string)
+
+	for i := range references {
+		reference := &references[i]
+		if reference.SubmittedAt == nil {
+			continue
+		}
+		// Anggota tim yang sudah dikonfirmasi tidak lagi menunggu dosen walinya
+		if stage == model.ApprovalStageAdvisor && reference.VerifiedAt != nil {
+			continue
+		}
+		result.Checked++
+
+		startedAt := reference.SubmittedAt
+		if reference.StageStartedAt != nil {
+			startedAt = reference.StageStartedAt
+		}
+		age := now.Sub(*startedAt)
+		title, ok := titles[reference.MongoAchievementID]
+		if !ok {
+			if achievement, err := s.achievementRepo.FindAchievementByID(ctx, reference.MongoAchievementID); err == nil {
+				title = achievement.Title
+			}
+			titles[reference.MongoAchievementID] = title
+		}
+
+		if s.escalationAfter > 0 && age >= s.escalationAfter && reference.EscalatedAt == nil {
+			if err := s.escalate(ctx, reference, stage, title, age, now); err != nil {
+				fmt.Printf("Warning: Gagal mengeskalasi verifikasi %s: %v\n", reference.MongoAchievementID, err)
+				continue
+			}
+			result.Escalated++
+			continue
+		}
+
+		if age >= s.sla && reference.SLAFlaggedAt == nil {
+			if err := s.flagOverdue(ctx, reference, stage, title, age, now); err != nil {
+				fmt.Printf("Warning: Gagal menandai verifikasi %s: %v\n", reference.MongoAchievementID, err)
+				continue
+			}
+			result.Flagged++
+		}
+	}
+	return nil
+}
+
+func (s *verificationService) flagOverdue(ctx context.Context, reference *model.AchievementReference, stage model.ApprovalStage, title string, age time.Duration, now time.Time) error {
+	reference.SLAFlaggedAt = &now
+	if err := s.achievementRepo.UpdateReference(ctx, reference); err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("Prestasi \"%s\" milik %s sudah menunggu persetujuan %s selama %s, melewati batas %s.",
+		title, reference.Student.User.FullName, approvalStageLabel(stage), formatWaitDuration(age), formatWaitDuration(s.sla))
+	for _, approver := range s.stageApprovers(ctx, stage, &reference.Student) {
+		if err := s.notificationService.Notify(ctx, approver, model.NotificationSLAOverdue, "Verifikasi prestasi melewati batas waktu", message, reference.MongoAchievementID); err != nil {
+			fmt.Printf("Warning: Gagal mengirim notifikasi: %v\n", err)
+		}
+	}
+	return nil
+}
+
+// stageApprovers user yang bertanggung jawab atas tahap: dosen wali mahasiswa, atau dosen
+// dengan izin tahap tersebut yang departemennya sama dengan program studi mahasiswa
+func (s *verificationService) stageApprovers(ctx context.Context, stage model.ApprovalStage, student *model.Student) []uuid.UUID {
+	if stage == model.ApprovalStageAdvisor {
+		if student.AdvisorID == nil {
+			return nil
+		}
+		advisor, err := s.lecturerRepo.FindLecturerByID(ctx, *student.AdvisorID)
+		if err != nil {
+			return nil
+		}
+		return []uuid.UUID{advisor.UserID}
+	}
+
+	lecturers, err := s.lecturerRepo.FindAllLecturers(ctx)
+	if err != nil {
+		fmt.Printf("Warning: Gagal memuat dosen: %v\n", err)
+		return nil
+	}
+	departmentLecturers := make(map[uuid.UUID]bool)
+	for _, lecturer := range lecturers {
+		if sameDepartment(lecturer.Department, student.ProgramStudy) {
+			departmentLecturers[lecturer.UserID] = true
+		}
+	}
+	if len(departmentLecturers) == 0 {
+		return nil
+	}
+
+	users, err := s.userRepo.FindAllUsers(ctx)
+	if err != nil {
+		fmt.Printf("Warning: Gagal memuat user: %v\n", err)
+		return nil
+	}
+	permission := "approvals:" + string(stage)
+	approvers := []uuid.UUID{}
+	for _, user := range users {
+		if !user.IsActive || !departmentLecturers[user.ID] {
+			continue
+		}
+		for _, granted := range user.Role.Permissions {
+			if strings.EqualFold(granted.Name, permission) {
+				approvers = append(approvers, user.ID)
+				break
+			}
+		}
+	}
+	return approvers
+}
+
+func (s *verificationService) escalate(ctx context.Context, reference *model.AchievementReference, stage model.ApprovalStage, title string, age time.Duration, now time.Time) error {
+	recipients, alternate := s.escalationRecipients(ctx, stage)
+
+	reference.EscalatedAt = &now
+	if reference.SLAFlaggedAt == nil {
+		reference.SLAFlaggedAt = &now
+	}
+	if alternate != nil {
+		reference.EscalatedTo = &alternate.ID
+	}
+	if err := s.achievementRepo.UpdateReference(ctx, reference); err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("Prestasi \"%s\" milik %s belum disetujui %s selama %s dan dieskalasi kepada anda.",
+		title, reference.Student.User.FullName, approvalStageLabel(stage), formatWaitDuration(age))
+	for _, recipient := range recipients {
+		if err := s.notificationService.Notify(ctx, recipient, model.NotificationEscalation, "Eskalasi verifikasi prestasi", message, reference.MongoAchievementID); err != nil {
+			fmt.Printf("Warning: Gagal mengirim notifikasi: %v\n", err)
+		}
+	}
+	return nil
+}
+
+// escalationRecipients verifikator pengganti jika dikonfigurasi, selain itu seluruh admin aktif.
+// Verifikator pengganti hanya menggantikan dosen wali; tahap lain dieskalasi ke admin yang
+// dapat memproses semua tahap.
+func (s *verificationService) escalationRecipients(ctx context.Context, stage model.ApprovalStage) ([]uuid.UUID, *model.User) {
+	if s.alternateVerifier != "" && stage == model.ApprovalStageAdvisor {
+		user, err := s.userRepo.FindUserByUsername(ctx, s.alternateVerifier)
+		if err == nil && user.IsActive {
+			return []uuid.UUID{user.ID}, user
+		}
+		fmt.Printf("Warning: Verifikator pengganti %s tidak ditemukan, eskalasi ke admin\n", s.alternateVerifier)
+	}
+
+	users, err := s.userRepo.FindAllUsers(ctx)
+	if err != nil {
+		fmt.Printf("Warning: Gagal memuat admin: %v\n", err)
+		return nil, nil
+	}
+
+	recipients := []uuid.UUID{}
+	for _, user := range users {
+		if user.IsActive && strings.Contains(strings.ToLower(user.Role.Name), "admin") {
+			recipients = append(recipients, user.ID)
+		}
+	}
+	return recipients, nil
+}
+
+// StartSLAMonitor menjalankan CheckSLA secara berkala di background
+func (s *verificationService) StartSLAMonitor(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			result, err := s.CheckSLA(ctx)
+			cancel()
+			if err != nil {
+				fmt.Printf("Warning: Pemeriksaan SLA verifikasi gagal: %v\n", err)
+				continue
+			}
+			if result.Flagged > 0 || result.Escalated > 0 {
+				fmt.Printf("SLA verifikasi: %d ditandai, %d dieskalasi\n", result.Flagged, result.Escalated)
+			}
+		}
+	}()
+}
+
+func formatWaitDuration(d time.Duration) string {
+	hours := int(d.Hours())
+	if hours >= 24 {
+		return fmt.Sprintf("%d hari %d jam", hours/24, hours%24)
+	}
+	return fmt.Sprintf("%d jam", hours)
+}
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/repository"
+)
+
+func (r *fakeAchievementRepository) FindPendingReferencesByStage(ctx context.Context, stage model.ApprovalStage, studentIDs []uuid.UUID) ([]model.AchievementReference, error) {
+	var references []model.AchievementReference
+	for _, reference := range r.references {
+		if reference.Status == model.StatusSubmitted && reference.CurrentStage != nil && *reference.CurrentStage == stage {
+			references = append(references, reference)
+		}
+	}
+	return references, nil
+}
+
+func (r *fakeLecturerRepository) FindLecturerByID(ctx context.Context, id uuid.UUID) (*model.Lecturer, error) {
+	for _, lecturer := range r.lecturers {
+		if lecturer.ID == id {
+			return lecturer, nil
+		}
+	}
+	return nil, errors.New("lecturer tidak ditemukan")
+}
+
+func (r *fakeLecturerRepository) FindAllLecturers(ctx context.Context) ([]model.Lecturer, error) {
+	lecturers := make([]model.Lecturer, 0, len(r.lecturers))
+	for _, lecturer := range r.lecturers {
+		lecturers = append(lecturers, *lecturer)
+	}
+	return lecturers, nil
+}
+
+// fakeUserDirectory daftar user lengkap dengan role dan permission-nya
+type fakeUserDirectory struct {
+	repository.UserRepository
+	users []model.User
+}
+
+func (r *fakeUserDirectory) FindAllUsers(ctx context.Context) ([]model.User, error) {
+	return r.users, nil
+}
+
+func (r *fakeUserDirectory) FindUserByUsername(ctx context.Context, username string) (*model.User, error) {
+	for i := range r.users {
+		if r.users[i].Username == username {
+			return &r.users[i], nil
+		}
+	}
+	return nil, errors.New("user tidak ditemukan")
+}
+
+type sentNotification struct {
+	userID           uuid.UUID
+	notificationType model.NotificationType
+	achievementID    string
+}
+
+type fakeNotificationService struct {
+	NotificationService
+	sent []sentNotification
+}
+
+func (s *fakeNotificationService) Notify(ctx context.Context, userID uuid.UUID, notificationType model.NotificationType, title, message, achievementID string) error {
+	s.sent = append(s.sent, sentNotification{userID: userID, notificationType: notificationType, achievementID: achievementID})
+	return nil
+}
+
+func (s *fakeNotificationService) recipients(notificationType model.NotificationType, achievementID string) map[uuid.UUID]bool {
+	recipients := make(map[uuid.UUID]bool)
+	for _, notification := range s.sent {
+		if notification.notificationType == notificationType && notification.achievementID == achievementID {
+			recipients[notification.userID] = true
+		}
+	}
+	return recipients
+}
+
+type fakeApprovalQueueService struct {
+	AchievementService
+	queues map[model.ApprovalStage][]AchievementResponse
+}
+
+func (s *fakeApprovalQueueService) GetApprovalQueue(ctx context.Context, userID uuid.UUID, stage model.ApprovalStage) ([]AchievementResponse, error) {
+	return s.queues[stage], nil
+}
+
+// slaFixture dosen dan user Informatika serta Sistem Informasi untuk pemeriksaan SLA
+type slaFixture struct {
+	repo          *fakeAchievementRepository
+	lecturers     *fakeLecturerRepository
+	users         *fakeUserDirectory
+	notifications *fakeNotificationService
+}
+
+func newSLAFixture() *slaFixture {
+	return &slaFixture{
+		repo:          &fakeAchievementRepository{achievements: map[string]*model.Achievement{}},
+		lecturers:     &fakeLecturerRepository{lecturers: map[uuid.UUID]*model.Lecturer{}},
+		users:         &fakeUserDirectory{},
+		notifications: &fakeNotificationService{},
+	}
+}
+
+// addUser mendaftarkan user aktif; department diisi jika user juga dosen
+func (f *slaFixture) addUser(username, roleName, department string, permissions ...string) (uuid.UUID, *model.Lecturer) {
+	user := model.User{ID: uuid.New(), Username: username, IsActive: true, Role: model.Role{Name: roleName}}
+	for _, permission := range permissions {
+		user.Role.Permissions = append(user.Role.Permissions, model.Permission{Name: permission})
+	}
+	f.users.users = append(f.users.users, user)
+
+	if department == "" {
+		return user.ID, nil
+	}
+	lecturer := &model.Lecturer{ID: uuid.New(), UserID: user.ID, Department: department}
+	f.lecturers.lecturers[user.ID] = lecturer
+	return user.ID, lecturer
+}
+
+// addPending menambahkan reference yang menunggu stage sejak stageStartedAt (nil = sejak submit)
+func (f *slaFixture) addPending(student model.Student, stage model.ApprovalStage, submittedAt time.Time, stageStartedAt *time.Time) string {
+	mongoID := uuid.NewString()
+	f.repo.achievements[mongoID] = &model.Achievement{Title: "Prestasi " + string(stage)}
+	f.repo.references = append(f.repo.references, model.AchievementReference{
+		ID:                 uuid.New(),
+		StudentID:          student.ID,
+		Student:            student,
+		MongoAchievementID: mongoID,
+		Status:             model.StatusSubmitted,
+		ApprovalStages:     validApprovalStages,
+		CurrentStage:       &stage,
+		SubmittedAt:        &submittedAt,
+		StageStartedAt:     stageStartedAt,
+	})
+	return mongoID
+}
+
+func (f *slaFixture) reference(mongoID string) model.AchievementReference {
+	for _, reference := range f.repo.references {
+		if reference.MongoAchievementID == mongoID {
+			return reference
+		}
+	}
+	return model.AchievementReference{}
+}
+
+func (f *slaFixture) service(escalationAfter time.Duration, alternateVerifier string) VerificationService {
+	return NewVerificationService(nil, f.repo, f.lecturers, f.users, f.notifications, 72*time.Hour, escalationAfter, alternateVerifier)
+}
+
+func TestCheckSLAEveryApprovalStage(t *testing.T) {
+	f := newSLAFixture()
+	advisorUser, advisor := f.addUser("dosenwali", "Dosen Wali", "Informatika", "achievements:verify")
+	programHead, _ := f.addUser("kaprodi", "Dosen Kaprodi", "Informatika", "approvals:program_head")
+	otherProgramHead, _ := f.addUser("kaprodisi", "Dosen Kaprodi", "Sistem Informasi", "approvals:program_head")
+	viceDean, _ := f.addUser("wadek", "Dosen Wakil Dekan III", "Informatika", "approvals:student_affairs")
+	student := model.Student{ID: uuid.New(), ProgramStudy: "Informatika", AdvisorID: &advisor.ID}
+
+	now := time.Now()
+	longAgo := now.Add(-240 * time.Hour)
+	overdueStart := now.Add(-100 * time.Hour)
+	recentStart := now.Add(-time.Hour)
+
+	advisorOverdue := f.addPending(student, model.ApprovalStageAdvisor, overdueStart, nil)
+	programHeadOverdue := f.addPending(student, model.ApprovalStageProgramHead, longAgo, &overdueStart)
+	studentAffairsRecent := f.addPending(student, model.ApprovalStageStudentAffairs, longAgo, &recentStart)
+
+	result, err := f.service(0, "").CheckSLA(context.Background())
+	if err != nil {
+		t.Fatalf("CheckSLA() error = %v", err)
+	}
+	if result.Checked != 3 || result.Flagged != 2 || result.Escalated != 0 {
+		t.Errorf("CheckSLA() = %+v, want 3 diperiksa, 2 ditandai", result)
+	}
+
+	if f.reference(advisorOverdue).SLAFlaggedAt == nil {
+		t.Error("tahap dosen wali yang melewati SLA tidak ditandai")
+	}
+	if got := f.notifications.recipients(model.NotificationSLAOverdue, advisorOverdue); len(got) != 1 || !got[advisorUser] {
+		t.Errorf("notifikasi tahap dosen wali = %v, want hanya dosen wali", got)
+	}
+
+	if f.reference(programHeadOverdue).SLAFlaggedAt == nil {
+		t.Error("tahap ketua program studi yang melewati SLA tidak ditandai")
+	}
+	got := f.notifications.recipients(model.NotificationSLAOverdue, programHeadOverdue)
+	if len(got) != 1 || !got[programHead] || got[otherProgramHead] || got[viceDean] {
+		t.Errorf("notifikasi tahap ketua program studi = %v, want hanya kaprodi departemen mahasiswa", got)
+	}
+
+	// Sudah lama diajukan tetapi baru masuk tahap kemahasiswaan: belum melewati SLA
+	if f.reference(studentAffairsRecent).SLAFlaggedAt != nil {
+		t.Error("tahap kemahasiswaan yang baru dimulai tidak boleh ditandai")
+	}
+	if got := f.notifications.recipients(model.NotificationSLAOverdue, studentAffairsRecent); len(got) != 0 {
+		t.Errorf("notifikasi tahap kemahasiswaan = %v, want tidak ada", got)
+	}
+
+	// Pemeriksaan berikutnya tidak mengirim notifikasi ulang
+	sent := len(f.notifications.sent)
+	if result, err := f.service(0, "").CheckSLA(context.Background()); err != nil || result.Flagged != 0 {
+		t.Errorf("CheckSLA() kedua = %+v, %v; want tidak ada yang ditandai", result, err)
+	}
+	if len(f.notifications.sent) != sent {
+		t.Errorf("notifikasi terkirim ulang: %d, want %d", len(f.notifications.sent), sent)
+	}
+}
+
+func TestCheckSLAEscalationByStage(t *testing.T) {
+	f := newSLAFixture()
+	_, advisor := f.addUser("dosenwali", "Dosen Wali", "Informatika", "achievements:verify")
+	alternate, _ := f.addUser("pengganti", "Dosen Wali", "Informatika", "achievements:verify")
+	admin, _ := f.addUser("admin", "Admin", "")
+	student := model.Student{ID: uuid.New(), ProgramStudy: "Informatika", AdvisorID: &advisor.ID}
+
+	started := time.Now().Add(-130 * time.Hour)
+	advisorPending := f.addPending(student, model.ApprovalStageAdvisor, started, nil)
+	programHeadPending := f.addPending(student, model.ApprovalStageProgramHead, started, &started)
+
+	result, err := f.service(120*time.Hour, "pengganti").CheckSLA(context.Background())
+	if err != nil {
+		t.Fatalf("CheckSLA() error = %v", err)
+	}
+	if result.Escalated != 2 {
+		t.Errorf("CheckSLA() = %+v, want 2 dieskalasi", result)
+	}
+
+	if reference := f.reference(advisorPending); reference.EscalatedTo == nil || *reference.EscalatedTo != alternate {
+		t.Errorf("tahap dosen wali dieskalasi ke %v, want verifikator pengganti", reference.EscalatedTo)
+	}
+	if got := f.notifications.recipients(model.NotificationEscalation, advisorPending); len(got) != 1 || !got[alternate] {
+		t.Errorf("eskalasi tahap dosen wali = %v, want verifikator pengganti", got)
+	}
+
+	// Verifikator pengganti hanya menggantikan dosen wali
+	reference := f.reference(programHeadPending)
+	if reference.EscalatedAt == nil || reference.EscalatedTo != nil {
+		t.Errorf("tahap ketua program studi escalated_at = %v escalated_to = %v, want dieskalasi ke admin", reference.EscalatedAt, reference.EscalatedTo)
+	}
+	if got := f.notifications.recipients(model.NotificationEscalation, programHeadPending); len(got) != 1 || !got[admin] {
+		t.Errorf("eskalasi tahap ketua program studi = %v, want admin", got)
+	}
+}
+
+func TestGetQueueMeasuresStageAge(t *testing.T) {
+	now := time.Now()
+	submittedAt := now.Add(-200 * time.Hour)
+	stageStartedAt := now.Add(-10 * time.Hour)
+	stage := model.ApprovalStageProgramHead
+
+	achievements := &fakeApprovalQueueService{queues: map[model.ApprovalStage][]AchievementResponse{
+		stage: {
+			{ID: "baru-masuk-tahap", Reference: &AchievementReferenceInfo{SubmittedAt: &submittedAt, StageStartedAt: &stageStartedAt, CurrentStage: &stage}},
+			{ID: "tanpa-awal-tahap", Reference: &AchievementReferenceInfo{SubmittedAt: &submittedAt, CurrentStage: &stage}},
+		},
+	}}
+	service := NewVerificationService(achievements, nil, nil, nil, nil, 72*time.Hour, 0, "")
+
+	items, err := service.GetQueue(context.Background(), uuid.New(), stage)
+	if err != nil {
+		t.Fatalf("GetQueue() error = %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("GetQueue() = %d item, want 2", len(items))
+	}
+
+	// Yang paling lama menunggu pada tahap ini di urutan pertama
+	if items[0].ID != "tanpa-awal-tahap" || items[0].SLAStatus != SLAOverdue {
+		t.Errorf("item pertama = %s %s, want tanpa-awal-tahap overdue", items[0].ID, items[0].SLAStatus)
+	}
+	if item := items[1]; item.Stage != stage || item.SLAStatus != SLAOnTime || item.AgeHours < 9.9 || item.AgeHours > 10.1 {
+		t.Errorf("item baru masuk tahap = tahap %s %s umur %.1f jam, want on_time sekitar 10 jam", item.Stage, item.SLAStatus, item.AgeHours)
+	}
+	if items[1].DueAt == nil || !items[1].DueAt.Equal(stageStartedAt.Add(72*time.Hour)) {
+		t.Errorf("due_at = %v, want 72 jam setelah awal tahap", items[1].DueAt)
+	}
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/repository"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/service"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/config"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/database"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/storage"
+)
+
+const usage = `Usage: go run ./cmd/achievement <command> [flags]
+
+Commands:
+  recalculate-points   Recalculate achievement points using the active point rules
+  import               Import achievements from a CSV or XLSX file
+  migrate-blobs        Move attachment files between storage backends and rewrite their URLs
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Print(usage)
+		os.Exit(2)
+	}
+
+	config.LoadEnv()
+
+	var err error
+	switch os.Args[1] {
+	case "recalculate-points":
+		err = runRecalculatePoints(os.Args[2:])
+	case "import":
+		err = runImport(os.Args[2:])
+	case "migrate-blobs":
+		err = runMigrateBlobs(os.Args[2:])
+	case "-h", "--help", "help":
+		fmt.Print(usage)
+		return
+	default:
+		fmt.Printf("Unknown command: %s\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		log.Fatalf("%s failed: %v", os.Args[1], err)
+	}
+}
+
+func connect() {
+	database.Connect()
+	database.ConnectMongoDB(config.MongoURI, config.MongoDBName)
+}
+
+func runRecalculatePoints(args []string) error {
+	flags := flag.NewFlagSet("recalculate-points", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "report changes without writing them")
+	verbose := flags.Bool("verbose", false, "print every changed achievement")
+	flags.Parse(args)
+
+	connect()
+	defer database.DisconnectMongoDB()
+
+	achievementRepo := repository.NewAchievementRepository(database.DB, database.MongoDB)
+	pointRuleRepo := repository.NewPointRuleRepository(database.DB)
+	pointRuleService := service.NewPointRuleService(pointRuleRepo, achievementRepo)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	result, err := pointRuleService.RecalculatePoints(ctx, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	if *verbose {
+		for _, change := range result.Changes {
+			if change.Error != "" {
+				log.Printf("%s (%s): error: %s", change.AchievementID, change.Status, change.Error)
+				continue
+			}
+			log.Printf("%s (%s): %.2f -> %.2f %s", change.AchievementID, change.Status, change.OldPoints, change.NewPoints, change.RuleName)
+		}
+	}
+
+	log.Printf("Processed: %d, changed: %d, failed: %d, dry run: %v", result.Processed, result.Changed, result.Failed, result.DryRun)
+	return nil
+}
+
+func runImport(args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	file := flags.String("file", "", "CSV or XLSX file to import (required)")
+	mapping := flags.String("mapping", "", "column mapping as a JSON object or a path to a JSON file, e.g. {\"nim\":\"NIM Mahasiswa\"}")
+	sheet := flags.String("sheet", "", "XLSX sheet name (default: first sheet)")
+	status := flags.String("status", "draft", "status of imported achievements: draft or verified")
+	username := flags.String("user", "admin", "admin username recorded as the author in history")
+	batchSize := flags.Int("batch-size", 100, "number of achievements saved per batch")
+	dryRun := flags.Bool("dry-run", false, "validate rows and report without writing them")
+	verbose := flags.Bool("verbose", false, "print every row, not only failed ones")
+	flags.Parse(args)
+
+	if *file == "" {
+		return errors.New("-file is required")
+	}
+	content, err := os.ReadFile(*file)
+	if err != nil {
+		return err
+	}
+
+	req := &service.ImportAchievementsRequest{
+		FileName:  filepath.Base(*file),
+		Content:   content,
+		Sheet:     *sheet,
+		Status:    model.AchievementStatus(*status),
+		DryRun:    *dryRun,
+		BatchSize: *batchSize,
+	}
+	if *mapping != "" {
+		data := []byte(*mapping)
+		if !strings.HasPrefix(strings.TrimSpace(*mapping), "{") {
+			if data, err = os.ReadFile(*mapping); err != nil {
+				return err
+			}
+		}
+		if err := json.Unmarshal(data, &req.Mapping); err != nil {
+			return fmt.Errorf("invalid mapping: %v", err)
+		}
+	}
+
+	connect()
+	defer database.DisconnectMongoDB()
+
+	achievementRepo := repository.NewAchievementRepository(database.DB, database.MongoDB)
+	historyRepo := repository.NewAchievementHistoryRepository(database.DB)
+	achievementTypeRepo := repository.NewAchievementTypeRepository(database.MongoDB)
+	studentRepo := repository.NewStudentRepository(database.DB)
+	userRepo := repository.NewUserRepository(database.DB)
+	pointRuleRepo := repository.NewPointRuleRepository(database.DB)
+	pointRuleService := service.NewPointRuleService(pointRuleRepo, achievementRepo)
+	importService := service.NewImportService(achievementRepo, historyRepo, achievementTypeRepo, studentRepo, userRepo, pointRuleService)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	user, err := userRepo.FindUserByUsername(ctx, *username)
+	if err != nil {
+		return fmt.Errorf("user %s not found", *username)
+	}
+
+	report, err := importService.ImportAchievements(ctx, user.ID, req)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range report.Rows {
+		if row.Status != service.ImportRowFailed {
+			if *verbose {
+				log.Printf("row %d (%s): %s %s", row.Row, row.NIM, row.Status, row.AchievementID)
+			}
+			continue
+		}
+		for _, fieldErr := range row.Errors {
+			log.Printf("row %d (%s): %s %s", row.Row, row.NIM, fieldErr.Field, fieldErr.Message)
+		}
+	}
+
+	log.Printf("Rows: %d, valid: %d, imported: %d, failed: %d, dry run: %v", report.TotalRows, report.ValidRows, report.ImportedRows, report.FailedRows, report.DryRun)
+	return nil
+}
+
+func runMigrateBlobs(args []string) error {
+	flags := flag.NewFlagSet("migrate-blobs", flag.ExitOnError)
+	from := flags.String("from", "local", "source storage backend: local or s3")
+	to := flags.String("to", config.StorageBackend, "target storage backend: local or s3 (default: STORAGE_BACKEND)")
+	fromDir := flags.String("from-dir", "", "source directory for the local backend (default: STORAGE_LOCAL_DIR)")
+	deleteSource := flags.Bool("delete-source", false, "delete source files once every document referencing them is rewritten")
+	dryRun := flags.Bool("dry-run", false, "report what would be copied and rewritten without changing anything")
+	flags.Parse(args)
+
+	sourceConfig := config.StorageConfig(*from)
+	if *fromDir != "" {
+		sourceConfig.LocalDir = *fromDir
+	}
+	source, err := storage.New(sourceConfig)
+	if err != nil {
+		return fmt.Errorf("source: %v", err)
+	}
+	target, err := storage.New(config.StorageConfig(*to))
+	if err != nil {
+		return fmt.Errorf("target: %v", err)
+	}
+
+	connect()
+	defer database.DisconnectMongoDB()
+
+	achievementRepo := repository.NewAchievementRepository(database.DB, database.MongoDB)
+	versionRepo := repository.NewAchievementVersionRepository(database.MongoDB)
+	migrationService := service.NewBlobMigrationService(achievementRepo, versionRepo, source, target)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Hour)
+	defer cancel()
+
+	report, err := migrationService.MigrateBlobs(ctx, &service.BlobMigrationRequest{
+		DryRun:       *dryRun,
+		DeleteSource: *deleteSource,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, failure := range report.Failures {
+		if failure.Document != "" {
+			log.Printf("%s: %s", failure.Document, failure.Error)
+			continue
+		}
+		log.Printf("%s: %s", failure.FileURL, failure.Error)
+	}
+
+	log.Printf("Documents: %d, files: %d, copied: %d, already present: %d, rewritten: %d, deleted: %d, failed: %d, dry run: %v",
+		report.Documents, report.Files, report.Copied, report.Skipped, report.Rewritten, report.Deleted, len(report.Failures), report.DryRun)
+	return nil
+}
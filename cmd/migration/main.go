@@ -17,6 +17,14 @@ import (
 
 const postgresSchemaSQL = `DROP EXTENSION IF EXISTS "uuid-ossp" CASCADE;
 
+DROP TABLE IF EXISTS badge_assertions CASCADE;
+DROP TABLE IF EXISTS skpi_documents CASCADE;
+DROP TABLE IF EXISTS certificates CASCADE;
+DROP TABLE IF EXISTS advisor_delegations CASCADE;
+DROP TABLE IF EXISTS notifications CASCADE;
+DROP TABLE IF EXISTS approval_chains CASCADE;
+DROP TABLE IF EXISTS point_rules CASCADE;
+DROP TABLE IF EXISTS achievement_histories CASCADE;
 DROP TABLE IF EXISTS achievement_references CASCADE;
 DROP TABLE IF EXISTS students CASCADE;
 DROP TABLE IF EXISTS lecturers CASCADE;
@@ -94,10 +102,126 @@ CREATE TABLE achievement_references (
     verified_at TIMESTAMP,
     verified_by UUID REFERENCES users(id) ON DELETE SET NULL,
     rejection_note TEXT,
+    approval_stages JSONB,
+    current_stage VARCHAR(30),
+    stage_started_at TIMESTAMP,
+    sla_flagged_at TIMESTAMP,
+    escalated_at TIMESTAMP,
+    escalated_to UUID REFERENCES users(id) ON DELETE SET NULL,
+    expiry_reminder_days INTEGER,
+    expiry_reminder_for TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    deleted_at TIMESTAMP
+);
+
+CREATE TABLE achievement_histories (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    achievement_ref_id UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+    mongo_achievement_id VARCHAR(24) NOT NULL,
+    old_status achievement_status,
+    new_status achievement_status NOT NULL,
+    changed_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    notes TEXT,
+    stage VARCHAR(30),
+    on_behalf_of UUID REFERENCES users(id) ON DELETE SET NULL,
+    is_override BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE point_rules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) NOT NULL,
+    achievement_type VARCHAR(50) NOT NULL,
+    competition_level VARCHAR(20),
+    rank INTEGER,
+    medal_type VARCHAR(20),
+    publication_type VARCHAR(20),
+    position VARCHAR(100),
+    points NUMERIC(10,2) NOT NULL,
+    priority INTEGER DEFAULT 0,
+    is_active BOOLEAN DEFAULT true,
     created_at TIMESTAMP DEFAULT NOW(),
     updated_at TIMESTAMP DEFAULT NOW()
 );
 
+CREATE TABLE approval_chains (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) NOT NULL,
+    achievement_type VARCHAR(50) NOT NULL,
+    competition_level VARCHAR(20),
+    stages JSONB NOT NULL,
+    is_active BOOLEAN DEFAULT true,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE notifications (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(30) NOT NULL,
+    title VARCHAR(200) NOT NULL,
+    message TEXT,
+    mongo_achievement_id VARCHAR(24),
+    read_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE advisor_delegations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    lecturer_id UUID NOT NULL REFERENCES lecturers(id) ON DELETE CASCADE,
+    delegate_id UUID NOT NULL REFERENCES lecturers(id) ON DELETE CASCADE,
+    start_date TIMESTAMP NOT NULL,
+    end_date TIMESTAMP NOT NULL,
+    reason TEXT,
+    created_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    CHECK (end_date >= start_date)
+);
+
+CREATE TABLE certificates (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    code VARCHAR(32) UNIQUE NOT NULL,
+    mongo_achievement_id VARCHAR(24) NOT NULL,
+    student_id UUID NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    student_name VARCHAR(100) NOT NULL,
+    student_nim VARCHAR(20) NOT NULL,
+    program_study VARCHAR(100),
+    title TEXT NOT NULL,
+    achievement_type VARCHAR(50),
+    verified_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    verifier_name VARCHAR(100) NOT NULL,
+    verified_at TIMESTAMP NOT NULL,
+    signature VARCHAR(64) NOT NULL,
+    file_path VARCHAR(255) NOT NULL,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE skpi_documents (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    student_id UUID UNIQUE NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    number VARCHAR(100) NOT NULL,
+    content JSONB NOT NULL,
+    locked_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    locked_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE badge_assertions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    mongo_achievement_id VARCHAR(24) NOT NULL,
+    student_id UUID NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    recipient_identity VARCHAR(100) NOT NULL,
+    recipient_salt VARCHAR(32) NOT NULL,
+    issued_on TIMESTAMP NOT NULL,
+    credential TEXT,
+    revoked_at TIMESTAMP,
+    revocation_reason TEXT,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
 CREATE INDEX idx_users_role_id ON users(role_id);
 CREATE INDEX idx_users_email ON users(email);
 CREATE INDEX idx_users_username ON users(username);
@@ -110,6 +234,19 @@ CREATE INDEX idx_lecturers_user_id ON lecturers(user_id);
 CREATE INDEX idx_achievement_references_student_id ON achievement_references(student_id);
 CREATE INDEX idx_achievement_references_status ON achievement_references(status);
 CREATE INDEX idx_achievement_references_verified_by ON achievement_references(verified_by);
+CREATE UNIQUE INDEX idx_achievement_references_mongo_student ON achievement_references(mongo_achievement_id, student_id);
+CREATE INDEX idx_achievement_references_current_stage ON achievement_references(current_stage);
+CREATE INDEX idx_achievement_references_deleted_at ON achievement_references(deleted_at);
+CREATE INDEX idx_achievement_histories_mongo_achievement_id ON achievement_histories(mongo_achievement_id);
+CREATE INDEX idx_point_rules_achievement_type ON point_rules(achievement_type);
+CREATE INDEX idx_approval_chains_achievement_type ON approval_chains(achievement_type);
+CREATE INDEX idx_notifications_user_id ON notifications(user_id);
+CREATE INDEX idx_advisor_delegations_lecturer_id ON advisor_delegations(lecturer_id);
+CREATE INDEX idx_advisor_delegations_delegate_id ON advisor_delegations(delegate_id, start_date, end_date);
+CREATE INDEX idx_certificates_mongo_achievement_id ON certificates(mongo_achievement_id);
+CREATE INDEX idx_certificates_student_id ON certificates(student_id);
+CREATE INDEX idx_badge_assertions_mongo_achievement_id ON badge_assertions(mongo_achievement_id);
+CREATE INDEX idx_badge_assertions_student_id ON badge_assertions(student_id);
 
 CREATE OR REPLACE FUNCTION update_updated_at_column()
 RETURNS TRIGGER AS $$
@@ -123,9 +260,26 @@ CREATE TRIGGER update_users_updated_at BEFORE UPDATE ON users
     FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
 
 CREATE TRIGGER update_achievement_references_updated_at BEFORE UPDATE ON achievement_references
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_point_rules_updated_at BEFORE UPDATE ON point_rules
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_approval_chains_updated_at BEFORE UPDATE ON approval_chains
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_advisor_delegations_updated_at BEFORE UPDATE ON advisor_delegations
     FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();`
 
-const postgresSeedDataSQL = `DELETE FROM achievement_references;
+const postgresSeedDataSQL = `DELETE FROM badge_assertions;
+DELETE FROM skpi_documents;
+DELETE FROM certificates;
+DELETE FROM advisor_delegations;
+DELETE FROM notifications;
+DELETE FROM approval_chains;
+DELETE FROM point_rules;
+DELETE FROM achievement_histories;
+DELETE FROM achievement_references;
 DELETE FROM students;
 DELETE FROM lecturers;
 DELETE FROM role_permissions;
@@ -136,7 +290,9 @@ DELETE FROM roles;
 INSERT INTO roles (name, description) VALUES
 ('Admin', 'Pengelola sistem dengan akses penuh'),
 ('Mahasiswa', 'Pelapor prestasi'),
-('Dosen Wali', 'Verifikator prestasi mahasiswa bimbingannya');
+('Dosen Wali', 'Verifikator prestasi mahasiswa bimbingannya'),
+('Dosen Kaprodi', 'Ketua program studi, menyetujui prestasi tingkat lanjut'),
+('Dosen Wakil Dekan III', 'Persetujuan akhir bidang kemahasiswaan');
 
 INSERT INTO permissions (name, resource, action, description) VALUES
 ('achievements:create', 'achievements', 'create', 'Membuat prestasi baru'),
@@ -151,7 +307,15 @@ INSERT INTO permissions (name, resource, action, description) VALUES
 ('user:manage', 'user', 'manage', 'Mengelola pengguna'),
 ('student:read', 'student', 'read', 'Membaca data mahasiswa'),
 ('student:update', 'student', 'update', 'Mengupdate data mahasiswa'),
-('lecturer:read', 'lecturer', 'read', 'Membaca data dosen');
+('lecturer:read', 'lecturer', 'read', 'Membaca data dosen'),
+('point_rules:manage', 'point_rules', 'manage', 'Mengelola aturan poin prestasi'),
+('achievement_types:manage', 'achievement_types', 'manage', 'Mengelola tipe prestasi custom'),
+('approval_chains:manage', 'approval_chains', 'manage', 'Mengelola approval chain prestasi'),
+('approvals:program_head', 'approvals', 'program_head', 'Persetujuan tahap ketua program studi'),
+('approvals:student_affairs', 'approvals', 'student_affairs', 'Persetujuan tahap kemahasiswaan'),
+('achievements:override', 'achievements', 'override', 'Override data dan status prestasi oleh admin'),
+('skpi:manage', 'skpi', 'manage', 'Mengunci SKPI final mahasiswa'),
+('achievements:import', 'achievements', 'import', 'Import massal prestasi dari CSV/XLSX');
 
 INSERT INTO role_permissions (role_id, permission_id)
 SELECT r.id, p.id
@@ -161,13 +325,21 @@ WHERE (r.name = 'Admin' AND p.name IN (
     'achievements:create', 'achievements:read', 'achievements:update', 
     'achievements:delete', 'achievements:verify', 'user:create', 
     'user:read', 'user:update', 'user:delete', 'user:manage',
-    'student:read', 'student:update', 'lecturer:read'
+    'student:read', 'student:update', 'lecturer:read', 'point_rules:manage',
+    'achievement_types:manage', 'approval_chains:manage', 'approvals:program_head',
+    'approvals:student_affairs', 'achievements:override', 'skpi:manage', 'achievements:import'
 ))
 OR (r.name = 'Mahasiswa' AND p.name IN (
     'achievements:create', 'achievements:read', 'achievements:update', 'achievements:delete'
 ))
 OR (r.name = 'Dosen Wali' AND p.name IN (
     'achievements:read', 'achievements:verify'
+))
+OR (r.name = 'Dosen Kaprodi' AND p.name IN (
+    'achievements:read', 'achievements:verify', 'approvals:program_head'
+))
+OR (r.name = 'Dosen Wakil Dekan III' AND p.name IN (
+    'achievements:read', 'approvals:student_affairs'
 ));
 
 INSERT INTO users (username, email, password_hash, full_name, role_id, is_active)
@@ -180,7 +352,33 @@ SELECT
     true
 FROM roles r
 WHERE r.name = 'Admin'
-LIMIT 1;`
+LIMIT 1;
+
+INSERT INTO point_rules (name, achievement_type, competition_level, rank, publication_type, position, points, priority) VALUES
+('Kompetisi internasional juara 1', 'competition', 'international', 1, NULL, NULL, 100, 0),
+('Kompetisi internasional juara 2', 'competition', 'international', 2, NULL, NULL, 90, 0),
+('Kompetisi internasional juara 3', 'competition', 'international', 3, NULL, NULL, 80, 0),
+('Kompetisi internasional peserta', 'competition', 'international', NULL, NULL, NULL, 50, 0),
+('Kompetisi nasional juara 1', 'competition', 'national', 1, NULL, NULL, 60, 0),
+('Kompetisi nasional juara 2', 'competition', 'national', 2, NULL, NULL, 50, 0),
+('Kompetisi nasional juara 3', 'competition', 'national', 3, NULL, NULL, 40, 0),
+('Kompetisi nasional peserta', 'competition', 'national', NULL, NULL, NULL, 25, 0),
+('Kompetisi regional juara 1', 'competition', 'regional', 1, NULL, NULL, 30, 0),
+('Kompetisi regional peserta', 'competition', 'regional', NULL, NULL, NULL, 15, 0),
+('Kompetisi lokal juara 1', 'competition', 'local', 1, NULL, NULL, 15, 0),
+('Kompetisi lokal peserta', 'competition', 'local', NULL, NULL, NULL, 5, 0),
+('Publikasi jurnal', 'publication', NULL, NULL, 'journal', NULL, 50, 0),
+('Publikasi konferensi', 'publication', NULL, NULL, 'conference', NULL, 30, 0),
+('Publikasi buku', 'publication', NULL, NULL, 'book', NULL, 40, 0),
+('Organisasi ketua', 'organization', NULL, NULL, NULL, 'Ketua', 20, 0),
+('Organisasi anggota/pengurus', 'organization', NULL, NULL, NULL, NULL, 10, 0),
+('Sertifikasi', 'certification', NULL, NULL, NULL, NULL, 15, 0),
+('Prestasi akademik', 'academic', NULL, NULL, NULL, NULL, 10, 0),
+('Prestasi lainnya', 'other', NULL, NULL, NULL, NULL, 5, 0);
+
+INSERT INTO approval_chains (name, achievement_type, competition_level, stages) VALUES
+('Kompetisi internasional', 'competition', 'international', '["advisor", "program_head", "student_affairs"]'),
+('Kompetisi nasional', 'competition', 'national', '["advisor", "program_head", "student_affairs"]');`
 
 func main() {
 	log.Println("Starting database migration...")
@@ -322,6 +520,22 @@ func runMongoMigrations(db *mongo.Database) error {
 		return err
 	}
 
+	if err := dropCollectionIfExists(ctx, db, "achievement_versions"); err != nil {
+		return err
+	}
+
+	if err := createAchievementVersionIndexes(ctx, db); err != nil {
+		return err
+	}
+
+	if err := dropCollectionIfExists(ctx, db, "achievement_types"); err != nil {
+		return err
+	}
+
+	if err := createAchievementTypeIndexes(ctx, db); err != nil {
+		return err
+	}
+
 	log.Println("MongoDB migrations completed")
 	return nil
 }
@@ -353,6 +567,10 @@ func createAchievementIndexes(ctx context.Context, db *mongo.Database) error {
 			Keys:    bson.D{{Key: "studentId", Value: 1}},
 			Options: options.Index().SetName("idx_student_id"),
 		},
+		{
+			Keys:    bson.D{{Key: "members.studentId", Value: 1}},
+			Options: options.Index().SetName("idx_members_student_id"),
+		},
 		{
 			Keys:    bson.D{{Key: "achievementType", Value: 1}},
 			Options: options.Index().SetName("idx_achievement_type"),
@@ -381,3 +599,46 @@ func createAchievementIndexes(ctx context.Context, db *mongo.Database) error {
 	log.Println("Created indexes for achievements collection")
 	return nil
 }
+
+func createAchievementVersionIndexes(ctx context.Context, db *mongo.Database) error {
+	collection := db.Collection("achievement_versions")
+
+	indexModels := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "achievementId", Value: 1},
+				{Key: "version", Value: 1},
+			},
+			Options: options.Index().SetName("idx_achievement_version").SetUnique(true),
+		},
+	}
+
+	if _, err := collection.Indexes().CreateMany(ctx, indexModels); err != nil {
+		return fmt.Errorf("create achievement version indexes: %w", err)
+	}
+
+	log.Println("Created indexes for achievement_versions collection")
+	return nil
+}
+
+func createAchievementTypeIndexes(ctx context.Context, db *mongo.Database) error {
+	collection := db.Collection("achievement_types")
+
+	indexModels := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "code", Value: 1}},
+			Options: options.Index().SetName("idx_achievement_type_code").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "isActive", Value: 1}},
+			Options: options.Index().SetName("idx_achievement_type_active"),
+		},
+	}
+
+	if _, err := collection.Indexes().CreateMany(ctx, indexModels); err != nil {
+		return fmt.Errorf("create achievement type indexes: %w", err)
+	}
+
+	log.Println("Created indexes for achievement_types collection")
+	return nil
+}
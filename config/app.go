@@ -8,14 +8,25 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/helmet"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/service"
 	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/route"
 	"go.mongodb.org/mongo-driver/mongo"
 	"gorm.io/gorm"
 )
 
-func SetupApp(db *gorm.DB, mongoDB *mongo.Database, jwtSecret string, jwtExpiry time.Duration) *fiber.App {
+// uploadBodyFiles jumlah file berukuran maksimal yang muat dalam satu request upload multipart
+const uploadBodyFiles = 5
+
+// multipartOverhead ruang tambahan untuk field form dan header multipart
+const multipartOverhead = 1 << 20
+
+func SetupApp(db *gorm.DB, mongoDB *mongo.Database, jwtSecret string, jwtExpiry time.Duration, opts route.Options) *fiber.App {
 	app := fiber.New(fiber.Config{
 		AppName: "Sistem Pelaporan Prestasi Mahasiswa",
+		// Default Fiber 4MB menolak seluruh request sebelum batas per jenis file diperiksa.
+		// File yang sedikit melebihi batasnya harus tetap sampai ke service agar hasil
+		// upload per file dapat menyebut file mana yang terlalu besar.
+		BodyLimit: int(service.MaxAttachmentSize(opts.AttachmentSizeLimits))*uploadBodyFiles + multipartOverhead,
 	})
 
 	app.Use(recover.New())
@@ -29,7 +40,7 @@ func SetupApp(db *gorm.DB, mongoDB *mongo.Database, jwtSecret string, jwtExpiry
 		Output: LoggerWriter,
 	}))
 
-	route.RegisterRoutes(app, db, mongoDB, jwtSecret, jwtExpiry)
+	route.RegisterRoutes(app, db, mongoDB, jwtSecret, jwtExpiry, opts)
 
 	return app
 }
@@ -20,6 +20,49 @@ var (
 	Environment string
 	MongoURI    string
 	MongoDBName string
+
+	VerificationSLA             string
+	VerificationEscalationAfter string
+	VerificationAlternate       string
+	SLACheckInterval            string
+
+	AchievementRetention string
+	PurgeInterval        string
+
+	CertificateSecret string
+	PublicBaseURL     string
+
+	SKPITemplatePath string
+
+	BadgeIssuerName           string
+	BadgeIssuerEmail          string
+	BadgeVerifiableCredential string
+	BadgeKeyPath              string
+
+	DOIResolverURL    string
+	DOIResolverMailto string
+	DOICacheDir       string
+	DOICacheTTL       string
+
+	CertificationReminderDays  string
+	CertificationCheckInterval string
+
+	StorageBackend  string
+	StorageLocalDir string
+	S3Endpoint      string
+	S3Region        string
+	S3Bucket        string
+	S3AccessKey     string
+	S3SecretKey     string
+	S3PathStyle     string
+
+	AttachmentURLTTL     string
+	AttachmentURLSecret  string
+	AttachmentSizeLimits string
+
+	ScannerBackend string
+	ClamdAddress   string
+	ClamdTimeout   string
 )
 
 // LoadEnv memuat environment variables dari .env file
@@ -48,6 +91,61 @@ func LoadEnv() {
 	// MongoDB configuration
 	MongoURI = getEnv("MONGO_URI", "mongodb://localhost:27017")
 	MongoDBName = getEnv("MONGO_DB_NAME", "achievement_db")
+
+	// Verification SLA configuration
+	VerificationSLA = getEnv("VERIFICATION_SLA", "72h")
+	VerificationEscalationAfter = getEnv("VERIFICATION_ESCALATION_AFTER", "168h") // "0" = tanpa eskalasi
+	VerificationAlternate = getEnv("VERIFICATION_ALTERNATE_VERIFIER", "")         // Username, kosong = admin
+	SLACheckInterval = getEnv("VERIFICATION_SLA_CHECK_INTERVAL", "1h")            // "0" = monitor nonaktif
+
+	// Retensi achievement yang dihapus
+	AchievementRetention = getEnv("ACHIEVEMENT_RETENTION", "720h") // Masa pemulihan, default 30 hari
+	PurgeInterval = getEnv("ACHIEVEMENT_PURGE_INTERVAL", "24h")    // "0" = purge otomatis nonaktif
+
+	// Sertifikat verifikasi prestasi
+	CertificateSecret = getEnv("CERTIFICATE_SECRET", "")                // Kunci HMAC tanda tangan sertifikat, wajib dan berbeda dari JWT_SECRET
+	PublicBaseURL = getEnv("PUBLIC_BASE_URL", "http://localhost:"+Port) // Awalan URL verifikasi pada QR code
+
+	// Template SKPI (file JSON), kosong = template bawaan
+	SKPITemplatePath = getEnv("SKPI_TEMPLATE", "")
+
+	// Open Badges dan Verifiable Credential
+	BadgeIssuerName = getEnv("BADGE_ISSUER_NAME", "Sistem Pelaporan Prestasi Mahasiswa")
+	BadgeIssuerEmail = getEnv("BADGE_ISSUER_EMAIL", "")
+	BadgeVerifiableCredential = getEnv("BADGE_VERIFIABLE_CREDENTIAL", "false") // "true" = terbitkan juga VC (JWT, Ed25519)
+	BadgeKeyPath = getEnv("BADGE_KEY_FILE", "keys/badge-issuer-ed25519.pem")   // Dibuat otomatis jika belum ada
+
+	// Resolver metadata DOI (API kompatibel Crossref)
+	DOIResolverURL = getEnv("DOI_RESOLVER_URL", "https://api.crossref.org") // "off" = hanya cache offline
+	DOIResolverMailto = getEnv("DOI_RESOLVER_MAILTO", BadgeIssuerEmail)     // Kontak untuk polite pool Crossref
+	DOICacheDir = getEnv("DOI_CACHE_DIR", "cache/doi")                      // "off" = tanpa cache
+	DOICacheTTL = getEnv("DOI_CACHE_TTL", "720h")                           // Umur cache sebelum diminta ulang
+
+	// Pengingat masa berlaku sertifikasi
+	CertificationReminderDays = getEnv("CERTIFICATION_REMINDER_DAYS", "90,30,7") // Jendela pengingat (hari sebelum validUntil)
+	CertificationCheckInterval = getEnv("CERTIFICATION_CHECK_INTERVAL", "24h")   // "0" = pengingat otomatis nonaktif
+
+	// Penyimpanan file attachment
+	StorageBackend = getEnv("STORAGE_BACKEND", "local") // local atau s3
+	StorageLocalDir = getEnv("STORAGE_LOCAL_DIR", "uploads")
+	S3Endpoint = getEnv("S3_ENDPOINT", "") // contoh: http://localhost:9000 untuk MinIO
+	S3Region = getEnv("S3_REGION", "us-east-1")
+	S3Bucket = getEnv("S3_BUCKET", "")
+	S3AccessKey = getEnv("S3_ACCESS_KEY", "")
+	S3SecretKey = getEnv("S3_SECRET_KEY", "")
+	S3PathStyle = getEnv("S3_PATH_STYLE", "true") // "false" = virtual-host bucket (AWS)
+
+	// URL sementara unduh attachment
+	AttachmentURLTTL = getEnv("ATTACHMENT_URL_TTL", "5m")     // "0" = URL sementara nonaktif
+	AttachmentURLSecret = getEnv("ATTACHMENT_URL_SECRET", "") // Kunci HMAC URL sementara, wajib jika aktif dan berbeda dari JWT_SECRET
+
+	// Batas ukuran attachment per jenis file dalam MB (jenis: pdf, jpeg, png, doc, docx)
+	AttachmentSizeLimits = getEnv("ATTACHMENT_SIZE_LIMITS", "pdf=10,jpeg=10,png=10,doc=10,docx=10")
+
+	// Pemindai malware attachment
+	ScannerBackend = getEnv("SCANNER_BACKEND", "none")             // none atau clamd
+	ClamdAddress = getEnv("CLAMD_ADDRESS", "tcp://localhost:3310") // atau unix:///var/run/clamav/clamd.ctl
+	ClamdTimeout = getEnv("CLAMD_TIMEOUT", "30s")
 }
 
 func getEnv(key, defaultValue string) string {
@@ -0,0 +1,17 @@
+package config
+
+import (
+	"time"
+
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/scanner"
+)
+
+// ScannerConfig pengaturan pemindai malware attachment dari environment
+func ScannerConfig() scanner.Config {
+	timeout, _ := time.ParseDuration(ClamdTimeout)
+	return scanner.Config{
+		Backend:      ScannerBackend,
+		ClamdAddress: ClamdAddress,
+		ClamdTimeout: timeout,
+	}
+}
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"strconv"
+
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/storage"
+)
+
+// StorageConfig pengaturan blob store attachment dari environment. backend kosong = STORAGE_BACKEND.
+func StorageConfig(backend string) storage.Config {
+	if backend == "" {
+		backend = StorageBackend
+	}
+	pathStyle, err := strconv.ParseBool(S3PathStyle)
+	if err != nil {
+		pathStyle = true
+	}
+	return storage.Config{
+		Backend:     backend,
+		LocalDir:    StorageLocalDir,
+		S3Endpoint:  S3Endpoint,
+		S3Region:    S3Region,
+		S3Bucket:    S3Bucket,
+		S3AccessKey: S3AccessKey,
+		S3SecretKey: S3SecretKey,
+		S3PathStyle: pathStyle,
+	}
+}
@@ -125,6 +125,14 @@ func AutoMigrate() {
 		&model.Student{},
 		&model.Lecturer{},
 		&model.AchievementReference{},
+		&model.AchievementHistory{},
+		&model.PointRule{},
+		&model.ApprovalChain{},
+		&model.Notification{},
+		&model.AdvisorDelegation{},
+		&model.Certificate{},
+		&model.SKPIDocument{},
+		&model.BadgeAssertion{},
 	)
 
 	// Jika terjadi error karena constraint tidak ada, abaikan
@@ -157,6 +165,14 @@ func AutoMigrate() {
 					&model.User{},
 					&model.Lecturer{},
 					&model.AchievementReference{},
+					&model.AchievementHistory{},
+					&model.PointRule{},
+					&model.ApprovalChain{},
+					&model.Notification{},
+					&model.AdvisorDelegation{},
+					&model.Certificate{},
+					&model.SKPIDocument{},
+					&model.BadgeAssertion{},
 				)
 				if err != nil {
 					errStr := strings.ToLower(err.Error())
@@ -0,0 +1,273 @@
+package helper
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strconv"
+)
+
+// Jenis file attachment yang dikenali dari isinya
+const (
+	FileTypePDF  = "pdf"
+	FileTypeJPEG = "jpeg"
+	FileTypePNG  = "png"
+	FileTypeDOC  = "doc"
+	FileTypeDOCX = "docx"
+)
+
+var fileTypeMIME = map[string]string{
+	FileTypePDF:  "application/pdf",
+	FileTypeJPEG: "image/jpeg",
+	FileTypePNG:  "image/png",
+	FileTypeDOC:  "application/msword",
+	FileTypeDOCX: "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+}
+
+var (
+	pdfMagic  = []byte("%PDF-")
+	jpegMagic = []byte{0xFF, 0xD8, 0xFF}
+	pngMagic  = []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1A, '\n'}
+	oleMagic  = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}
+	zipMagic  = []byte("PK\x03\x04")
+)
+
+// maxPDFStreamBytes batas ukuran object stream PDF setelah didekompresi saat diperiksa
+const maxPDFStreamBytes = 64 << 20
+
+// DetectFileType jenis file berdasarkan magic bytes, string kosong jika tidak dikenali.
+// DOCX dibedakan dari ZIP lain dengan memeriksa keberadaan word/document.xml.
+func DetectFileType(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, pdfMagic):
+		return FileTypePDF
+	case bytes.HasPrefix(data, jpegMagic):
+		return FileTypeJPEG
+	case bytes.HasPrefix(data, pngMagic):
+		return FileTypePNG
+	case bytes.HasPrefix(data, oleMagic):
+		return FileTypeDOC
+	case bytes.HasPrefix(data, zipMagic):
+		archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return ""
+		}
+		for _, file := range archive.File {
+			if file.Name == "word/document.xml" {
+				return FileTypeDOCX
+			}
+		}
+	}
+	return ""
+}
+
+// FileTypeMIME tipe MIME untuk jenis file dari DetectFileType
+func FileTypeMIME(fileType string) string {
+	if mime, ok := fileTypeMIME[fileType]; ok {
+		return mime
+	}
+	return "application/octet-stream"
+}
+
+// PDFHasJavaScript memeriksa apakah PDF memuat aksi JavaScript (key /JS atau /JavaScript).
+// Nama dengan escape #xx didekode dan object stream (/ObjStm) terkompresi ikut diperiksa,
+// karena keduanya cara umum menyembunyikan JavaScript dari pemindaian teks biasa.
+func PDFHasJavaScript(data []byte) bool {
+	if pdfHasJavaScriptName(data) {
+		return true
+	}
+
+	budget := int64(maxPDFStreamBytes)
+	for offset := 0; ; {
+		start := bytes.Index(data[offset:], []byte("stream"))
+		if start < 0 {
+			return false
+		}
+		start += offset
+		offset = start + len("stream")
+
+		// "endstream" juga mengandung "stream"
+		if start >= 3 && string(data[start-3:start]) == "end" {
+			continue
+		}
+		dictStart := bytes.LastIndex(data[:start], []byte("obj"))
+		if dictStart < 0 || !pdfHasName(data[dictStart:start], "ObjStm") {
+			continue
+		}
+
+		body := data[offset:]
+		if bytes.HasPrefix(body, []byte("\r\n")) {
+			body = body[2:]
+		} else if bytes.HasPrefix(body, []byte("\n")) {
+			body = body[1:]
+		}
+		if end := bytes.Index(body, []byte("endstream")); end >= 0 {
+			body = body[:end]
+		}
+
+		reader, err := zlib.NewReader(bytes.NewReader(body))
+		if err != nil {
+			continue
+		}
+		decoded, _ := io.ReadAll(io.LimitReader(reader, budget))
+		reader.Close()
+		budget -= int64(len(decoded))
+		if pdfHasJavaScriptName(decoded) {
+			return true
+		}
+		if budget <= 0 {
+			// Object stream berukuran tidak wajar, anggap berisiko
+			return true
+		}
+	}
+}
+
+func pdfHasJavaScriptName(data []byte) bool {
+	return pdfHasName(data, "JS") || pdfHasName(data, "JavaScript")
+}
+
+// pdfHasName mencari nama PDF (/Nama) yang sama persis setelah escape #xx didekode
+func pdfHasName(data []byte, name string) bool {
+	for i := 0; i < len(data); i++ {
+		if data[i] != '/' {
+			continue
+		}
+		j := i + 1
+		for j < len(data) && !pdfDelimiter(data[j]) {
+			j++
+		}
+		if pdfDecodeName(data[i+1:j]) == name {
+			return true
+		}
+		i = j - 1
+	}
+	return false
+}
+
+func pdfDelimiter(c byte) bool {
+	switch c {
+	case ' ', '\t', '\r', '\n', '\f', 0, '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
+
+func pdfDecodeName(raw []byte) string {
+	if bytes.IndexByte(raw, '#') < 0 {
+		return string(raw)
+	}
+	decoded := make([]byte, 0, len(raw))
+	for i := 0; i < len(raw); i++ {
+		if raw[i] == '#' && i+2 < len(raw) {
+			if value, err := strconv.ParseUint(string(raw[i+1:i+3]), 16, 8); err == nil {
+				decoded = append(decoded, byte(value))
+				i += 2
+				continue
+			}
+		}
+		decoded = append(decoded, raw[i])
+	}
+	return string(decoded)
+}
+
+// StripImageMetadata menghapus metadata EXIF (termasuk lokasi GPS), XMP, IPTC dan komentar
+// dari JPEG, serta chunk teks, eXIf dan tIME dari PNG. Data gambar tidak di-encode ulang.
+// Jenis file lain dikembalikan apa adanya.
+func StripImageMetadata(data []byte, fileType string) ([]byte, error) {
+	switch fileType {
+	case FileTypeJPEG:
+		return stripJPEGMetadata(data)
+	case FileTypePNG:
+		return stripPNGMetadata(data)
+	default:
+		return data, nil
+	}
+}
+
+func stripJPEGMetadata(data []byte) ([]byte, error) {
+	invalid := errors.New("struktur JPEG tidak valid")
+	if !bytes.HasPrefix(data, jpegMagic) {
+		return nil, invalid
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, 0xFF, 0xD8)
+	for i := 2; i < len(data); {
+		if data[i] != 0xFF {
+			return nil, invalid
+		}
+		// Byte 0xFF berulang adalah padding sebelum marker
+		for i+1 < len(data) && data[i+1] == 0xFF {
+			i++
+		}
+		if i+1 >= len(data) {
+			return nil, invalid
+		}
+		marker := data[i+1]
+
+		switch {
+		case marker == 0xD9: // EOI
+			return append(out, 0xFF, 0xD9), nil
+		case marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7): // marker tanpa panjang
+			out = append(out, 0xFF, marker)
+			i += 2
+			continue
+		}
+
+		if i+4 > len(data) {
+			return nil, invalid
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2 : i+4]))
+		if length < 2 || i+2+length > len(data) {
+			return nil, invalid
+		}
+
+		switch marker {
+		case 0xDA: // SOS: sisa file adalah data gambar
+			return append(out, data[i:]...), nil
+		case 0xE1, 0xED, 0xFE: // APP1 (EXIF/XMP), APP13 (IPTC), COM
+		default:
+			out = append(out, data[i:i+2+length]...)
+		}
+		i += 2 + length
+	}
+	return nil, invalid
+}
+
+var pngMetadataChunks = map[string]bool{
+	"tEXt": true,
+	"zTXt": true,
+	"iTXt": true,
+	"eXIf": true,
+	"tIME": true,
+}
+
+func stripPNGMetadata(data []byte) ([]byte, error) {
+	invalid := errors.New("struktur PNG tidak valid")
+	if !bytes.HasPrefix(data, pngMagic) {
+		return nil, invalid
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, pngMagic...)
+	for i := len(pngMagic); i+12 <= len(data); {
+		length := int(binary.BigEndian.Uint32(data[i : i+4]))
+		chunkType := string(data[i+4 : i+8])
+		end := i + 12 + length
+		if length < 0 || end > len(data) || end < i {
+			return nil, invalid
+		}
+		if !pngMetadataChunks[chunkType] {
+			out = append(out, data[i:end]...)
+		}
+		// Data setelah IEND dibuang
+		if chunkType == "IEND" {
+			return out, nil
+		}
+		i = end
+	}
+	return nil, invalid
+}
@@ -0,0 +1,219 @@
+package helper
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func TestDetectFileType(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"pdf", []byte("%PDF-1.7\n%âãÏÓ\n"), FileTypePDF},
+		{"jpeg", testJPEG(t), FileTypeJPEG},
+		{"png", testPNG(t), FileTypePNG},
+		{"doc", append([]byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}, make([]byte, 16)...), FileTypeDOC},
+		{"docx", testZip(t, "[Content_Types].xml", "word/document.xml"), FileTypeDOCX},
+		{"zip biasa bukan docx", testZip(t, "readme.txt"), ""},
+		{"zip rusak", []byte("PK\x03\x04rusak"), ""},
+		{"teks", []byte("<html><script>alert(1)</script>"), ""},
+		{"kosong", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectFileType(tt.data); got != tt.want {
+				t.Errorf("DetectFileType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPDFHasJavaScript(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want bool
+	}{
+		{"pdf bersih", []byte("%PDF-1.4\n1 0 obj\n<< /Type /Catalog /Pages 2 0 R >>\nendobj\n"), false},
+		{"aksi /JS", []byte("%PDF-1.4\n1 0 obj\n<< /S /JavaScript /JS (app.alert(1)) >>\nendobj\n"), true},
+		{"/JS tanpa spasi", []byte("%PDF-1.4\n1 0 obj<</S/JS(app.alert(1))>>endobj"), true},
+		{"nama di-escape #xx", []byte("%PDF-1.4\n1 0 obj\n<< /S /J#61vaScript /J#53 (x) >>\nendobj\n"), true},
+		{"nama mirip bukan /JS", []byte("%PDF-1.4\n1 0 obj\n<< /JSONData (x) /Font /JSans >>\nendobj\n"), false},
+		{"teks JS di dalam string", []byte("%PDF-1.4\n1 0 obj\n(Materi tentang JavaScript dan JS)\nendobj\n"), false},
+		{"JavaScript di object stream terkompresi", testPDFObjStm(t, "<< /S /JavaScript /JS (app.alert(1)) >>"), true},
+		{"object stream bersih", testPDFObjStm(t, "<< /Type /Page /Parent 2 0 R >>"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PDFHasJavaScript(tt.data); got != tt.want {
+				t.Errorf("PDFHasJavaScript() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripJPEGMetadata(t *testing.T) {
+	original := testJPEG(t)
+	exif := jpegSegment(0xE1, append([]byte("Exif\x00\x00"), []byte("GPSLatitude=-6.2")...))
+	comment := jpegSegment(0xFE, []byte("dibuat dengan ponsel"))
+	iptc := jpegSegment(0xED, []byte("Photoshop 3.0\x00"))
+
+	// SOI, metadata, lalu sisa file asli
+	withMetadata := append([]byte{0xFF, 0xD8}, exif...)
+	withMetadata = append(withMetadata, comment...)
+	withMetadata = append(withMetadata, iptc...)
+	withMetadata = append(withMetadata, original[2:]...)
+
+	stripped, err := stripJPEGMetadata(withMetadata)
+	if err != nil {
+		t.Fatalf("stripJPEGMetadata() error = %v", err)
+	}
+	if !bytes.Equal(stripped, original) {
+		t.Errorf("stripJPEGMetadata() menghasilkan %d byte, want %d byte file asli", len(stripped), len(original))
+	}
+	if bytes.Contains(stripped, []byte("GPSLatitude")) || bytes.Contains(stripped, []byte("ponsel")) {
+		t.Error("metadata masih ada setelah dibuang")
+	}
+	if _, err := jpeg.Decode(bytes.NewReader(stripped)); err != nil {
+		t.Errorf("JPEG hasil tidak dapat didekode: %v", err)
+	}
+
+	for name, data := range map[string][]byte{
+		"bukan jpeg":         []byte("GIF89a"),
+		"segmen terpotong":   append([]byte{0xFF, 0xD8, 0xFF, 0xE1, 0x10, 0x00}, make([]byte, 4)...),
+		"tanpa marker":       {0xFF, 0xD8, 0x00, 0x01},
+		"berakhir tanpa EOI": {0xFF, 0xD8},
+	} {
+		if _, err := stripJPEGMetadata(data); err == nil {
+			t.Errorf("stripJPEGMetadata(%s) seharusnya error", name)
+		}
+	}
+}
+
+func TestStripPNGMetadata(t *testing.T) {
+	original := testPNG(t)
+	ihdrEnd := len(pngMagic) + 12 + 13
+
+	var withMetadata []byte
+	withMetadata = append(withMetadata, original[:ihdrEnd]...)
+	withMetadata = append(withMetadata, pngChunk("tEXt", []byte("Author\x00Budi"))...)
+	withMetadata = append(withMetadata, pngChunk("eXIf", []byte("MM\x00*GPS"))...)
+	withMetadata = append(withMetadata, pngChunk("tIME", make([]byte, 7))...)
+	withMetadata = append(withMetadata, original[ihdrEnd:]...)
+	withMetadata = append(withMetadata, []byte("data setelah IEND")...)
+
+	stripped, err := stripPNGMetadata(withMetadata)
+	if err != nil {
+		t.Fatalf("stripPNGMetadata() error = %v", err)
+	}
+	if !bytes.Equal(stripped, original) {
+		t.Errorf("stripPNGMetadata() menghasilkan %d byte, want %d byte file asli", len(stripped), len(original))
+	}
+	if _, err := png.Decode(bytes.NewReader(stripped)); err != nil {
+		t.Errorf("PNG hasil tidak dapat didekode: %v", err)
+	}
+
+	truncated := append([]byte{}, original[:ihdrEnd]...)
+	truncated = append(truncated, pngChunk("IDAT", make([]byte, 64))[:20]...)
+	if _, err := stripPNGMetadata(truncated); err == nil {
+		t.Error("stripPNGMetadata() chunk terpotong seharusnya error")
+	}
+	if _, err := stripPNGMetadata(original[:ihdrEnd]); err == nil {
+		t.Error("stripPNGMetadata() tanpa IEND seharusnya error")
+	}
+}
+
+func TestStripImageMetadataOtherTypes(t *testing.T) {
+	data := []byte("%PDF-1.4")
+	got, err := StripImageMetadata(data, FileTypePDF)
+	if err != nil || !bytes.Equal(got, data) {
+		t.Errorf("StripImageMetadata(pdf) = %q, %v; want data apa adanya", got, err)
+	}
+}
+
+func testImage() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	for x := 0; x < 4; x++ {
+		for y := 0; y < 4; y++ {
+			img.Set(x, y, color.RGBA{uint8(x * 60), uint8(y * 60), 100, 255})
+		}
+	}
+	return img
+}
+
+func testJPEG(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, testImage(), nil); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func testPNG(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, testImage()); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func testZip(t *testing.T, names ...string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, name := range names {
+		file, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		file.Write([]byte("<xml/>"))
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// testPDFObjStm PDF dengan satu object stream (/ObjStm) terkompresi Flate berisi object
+func testPDFObjStm(t *testing.T, object string) []byte {
+	t.Helper()
+	var compressed bytes.Buffer
+	writer := zlib.NewWriter(&compressed)
+	writer.Write([]byte("5 0 " + object))
+	writer.Close()
+
+	var pdf bytes.Buffer
+	pdf.WriteString("%PDF-1.5\n4 0 obj\n<< /Type /ObjStm /N 1 /First 4 /Filter /FlateDecode >>\nstream\n")
+	pdf.Write(compressed.Bytes())
+	pdf.WriteString("\nendstream\nendobj\n%%EOF\n")
+	return pdf.Bytes()
+}
+
+func jpegSegment(marker byte, payload []byte) []byte {
+	segment := []byte{0xFF, marker, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	return append(segment, payload...)
+}
+
+func pngChunk(chunkType string, data []byte) []byte {
+	chunk := make([]byte, 8, 12+len(data))
+	binary.BigEndian.PutUint32(chunk, uint32(len(data)))
+	copy(chunk[4:], chunkType)
+	chunk = append(chunk, data...)
+	crc := crc32.ChecksumIEEE(chunk[4:])
+	return binary.BigEndian.AppendUint32(chunk, crc)
+}
@@ -0,0 +1,317 @@
+package helper
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Ukuran kertas A4 dalam satuan point
+const (
+	PageA4Width  = 595.28
+	PageA4Height = 841.89
+)
+
+// PDFDocument penulis PDF sederhana dengan font standar Helvetica, tanpa dependensi eksternal
+type PDFDocument struct {
+	title    string
+	subject  string
+	keywords string
+	pages    []*PDFPage
+	files    []pdfFile
+}
+
+// pdfFile lampiran yang disematkan ke dokumen (embedded file)
+type pdfFile struct {
+	name     string
+	mimeType string
+	data     []byte
+	modified time.Time
+}
+
+// PDFPage satu halaman PDF. Seluruh koordinat diukur dari kiri atas dalam satuan point.
+type PDFPage struct {
+	width   float64
+	height  float64
+	content bytes.Buffer
+}
+
+func NewPDFDocument() *PDFDocument {
+	return &PDFDocument{}
+}
+
+// SetInfo mengisi metadata dokumen (Title, Subject, Keywords)
+func (d *PDFDocument) SetInfo(title, subject, keywords string) {
+	d.title = title
+	d.subject = subject
+	d.keywords = keywords
+}
+
+// AttachFile menyematkan file ke dokumen; tampil di panel lampiran PDF reader
+func (d *PDFDocument) AttachFile(name, mimeType string, data []byte, modified time.Time) {
+	d.files = append(d.files, pdfFile{name: name, mimeType: mimeType, data: data, modified: modified})
+}
+
+func (d *PDFDocument) AddPage(width, height float64) *PDFPage {
+	page := &PDFPage{width: width, height: height}
+	d.pages = append(d.pages, page)
+	return page
+}
+
+func (p *PDFPage) Width() float64 {
+	return p.width
+}
+
+func (p *PDFPage) Height() float64 {
+	return p.height
+}
+
+// SetGray mengatur warna isi dan garis (0 = hitam, 1 = putih)
+func (p *PDFPage) SetGray(gray float64) {
+	fmt.Fprintf(&p.content, "%.3f g %.3f G\n", gray, gray)
+}
+
+// Text menulis teks dengan baseline di posisi y
+func (p *PDFPage) Text(x, y, size float64, bold bool, text string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(&p.content, "BT /%s %.2f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, p.height-y, pdfEscape(text))
+}
+
+// TextCenter menulis teks yang rata tengah terhadap posisi cx
+func (p *PDFPage) TextCenter(cx, y, size float64, bold bool, text string) {
+	p.Text(cx-TextWidth(text, size, bold)/2, y, size, bold, text)
+}
+
+// TextRight menulis teks yang rata kanan terhadap posisi x
+func (p *PDFPage) TextRight(x, y, size float64, bold bool, text string) {
+	p.Text(x-TextWidth(text, size, bold), y, size, bold, text)
+}
+
+func (p *PDFPage) Line(x1, y1, x2, y2, lineWidth float64) {
+	fmt.Fprintf(&p.content, "%.2f w %.2f %.2f m %.2f %.2f l S\n", lineWidth, x1, p.height-y1, x2, p.height-y2)
+}
+
+// Rect menggambar bingkai persegi dengan sudut kiri atas di (x, y)
+func (p *PDFPage) Rect(x, y, w, h, lineWidth float64) {
+	fmt.Fprintf(&p.content, "%.2f w %.2f %.2f %.2f %.2f re S\n", lineWidth, x, p.height-y-h, w, h)
+}
+
+// FillRect menggambar persegi terisi dengan sudut kiri atas di (x, y)
+func (p *PDFPage) FillRect(x, y, w, h float64) {
+	fmt.Fprintf(&p.content, "%.2f %.2f %.2f %.2f re f\n", x, p.height-y-h, w, h)
+}
+
+// QRCode menggambar matriks QR code berukuran size x size dengan quiet zone 4 modul
+func (p *PDFPage) QRCode(x, y, size float64, modules [][]bool) {
+	if len(modules) == 0 {
+		return
+	}
+	module := size / float64(len(modules)+8)
+	for row, line := range modules {
+		for col, dark := range line {
+			if dark {
+				p.FillRect(x+float64(col+4)*module, y+float64(row+4)*module, module, module)
+			}
+		}
+	}
+}
+
+// Bytes menghasilkan isi file PDF
+func (d *PDFDocument) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	offsets := []int{}
+	writeObject := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Objek 1-5 tetap, halaman mulai dari objek 6 (page lalu content stream),
+	// lampiran setelah halaman (filespec lalu embedded file stream)
+	kids := []string{}
+	for i := range d.pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 6+i*2))
+	}
+	firstFile := 6 + len(d.pages)*2
+	if len(d.files) == 0 {
+		writeObject("<< /Type /Catalog /Pages 2 0 R >>")
+	} else {
+		// Name tree harus terurut berdasarkan nama
+		order := make([]int, len(d.files))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(a, b int) bool { return d.files[order[a]].name < d.files[order[b]].name })
+
+		names := []string{}
+		for _, i := range order {
+			names = append(names, fmt.Sprintf("(%s) %d 0 R", pdfEscape(d.files[i].name), firstFile+i*2))
+		}
+		writeObject(fmt.Sprintf("<< /Type /Catalog /Pages 2 0 R /Names << /EmbeddedFiles << /Names [%s] >> >> /PageMode /UseAttachments >>", strings.Join(names, " ")))
+	}
+	writeObject(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	writeObject("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	writeObject("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	writeObject(fmt.Sprintf("<< /Title (%s) /Subject (%s) /Keywords (%s) /Producer (Sistem Pelaporan Prestasi Mahasiswa) /CreationDate (D:%s) >>",
+		pdfEscape(d.title), pdfEscape(d.subject), pdfEscape(d.keywords), time.Now().UTC().Format("20060102150405Z")))
+
+	for i, page := range d.pages {
+		compressed, err := pdfCompress(page.content.Bytes())
+		if err != nil {
+			return nil, err
+		}
+
+		writeObject(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			page.width, page.height, 7+i*2))
+		writeObject(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", len(compressed), compressed))
+	}
+
+	for i, file := range d.files {
+		compressed, err := pdfCompress(file.data)
+		if err != nil {
+			return nil, err
+		}
+
+		writeObject(fmt.Sprintf("<< /Type /Filespec /F (%s) /UF (%s) /EF << /F %d 0 R >> >>",
+			pdfEscape(file.name), pdfEscape(file.name), firstFile+i*2+1))
+		writeObject(fmt.Sprintf("<< /Type /EmbeddedFile /Subtype /%s /Length %d /Filter /FlateDecode /Params << /Size %d /ModDate (D:%s) >> >>\nstream\n%s\nendstream",
+			pdfName(file.mimeType), len(compressed), len(file.data), file.modified.UTC().Format("20060102150405Z"), compressed))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return buf.Bytes(), nil
+}
+
+func pdfCompress(data []byte) ([]byte, error) {
+	var compressed bytes.Buffer
+	writer := zlib.NewWriter(&compressed)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return compressed.Bytes(), nil
+}
+
+// pdfName mengubah MIME type menjadi name object PDF, misalnya application/pdf -> application#2Fpdf
+func pdfName(value string) string {
+	if value == "" {
+		value = "application/octet-stream"
+	}
+	var buf bytes.Buffer
+	for _, b := range []byte(value) {
+		if b < '!' || b > '~' || strings.IndexByte("#/()<>[]{}%", b) >= 0 {
+			fmt.Fprintf(&buf, "#%02X", b)
+			continue
+		}
+		buf.WriteByte(b)
+	}
+	return buf.String()
+}
+
+// winAnsiSpecial karakter di luar Latin-1 yang tersedia di WinAnsiEncoding
+var winAnsiSpecial = map[rune]byte{
+	'€': 0x80, '‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+}
+
+// pdfEncode mengubah teks ke WinAnsiEncoding; karakter yang tidak didukung menjadi '?'
+func pdfEncode(text string) []byte {
+	out := make([]byte, 0, len(text))
+	for _, r := range text {
+		switch {
+		case r == '\n' || r == '\r' || r == '\t':
+			out = append(out, ' ')
+		case r < 0x80 || (r >= 0xA0 && r <= 0xFF):
+			out = append(out, byte(r))
+		case winAnsiSpecial[r] != 0:
+			out = append(out, winAnsiSpecial[r])
+		default:
+			out = append(out, '?')
+		}
+	}
+	return out
+}
+
+func pdfEscape(text string) string {
+	var buf bytes.Buffer
+	for _, b := range pdfEncode(text) {
+		if b == '\\' || b == '(' || b == ')' {
+			buf.WriteByte('\\')
+		}
+		buf.WriteByte(b)
+	}
+	return buf.String()
+}
+
+// Lebar glyph ASCII 32-126 per 1000 unit (AFM Helvetica dan Helvetica-Bold)
+var (
+	helveticaWidths = []int{
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	}
+	helveticaBoldWidths = []int{
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	}
+)
+
+// TextWidth lebar teks dalam point untuk font Helvetica
+func TextWidth(text string, size float64, bold bool) float64 {
+	widths := helveticaWidths
+	if bold {
+		widths = helveticaBoldWidths
+	}
+	total := 0
+	for _, b := range pdfEncode(text) {
+		if b >= 32 && b <= 126 {
+			total += widths[b-32]
+		} else {
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// WrapText memecah teks per kata agar setiap baris tidak melebihi maxWidth
+func WrapText(text string, size float64, bold bool, maxWidth float64) []string {
+	lines := []string{}
+	current := ""
+	for _, word := range strings.Fields(text) {
+		candidate := word
+		if current != "" {
+			candidate = current + " " + word
+		}
+		if current != "" && TextWidth(candidate, size, bold) > maxWidth {
+			lines = append(lines, current)
+			current = word
+			continue
+		}
+		current = candidate
+	}
+	if current != "" {
+		lines = append(lines, current)
+	}
+	return lines
+}
@@ -0,0 +1,434 @@
+package helper
+
+import (
+	"errors"
+)
+
+// qrVersion parameter QR code untuk error correction level M
+type qrVersion struct {
+	totalCodewords int
+	ecPerBlock     int
+	dataBlocks     []int // Jumlah data codeword per blok
+	alignment      []int // Posisi pusat alignment pattern
+}
+
+// qrVersionsM versi 1-10, cukup untuk URL hingga 213 byte
+var qrVersionsM = []qrVersion{
+	{},
+	{26, 10, []int{16}, nil},
+	{44, 16, []int{28}, []int{6, 18}},
+	{70, 26, []int{44}, []int{6, 22}},
+	{100, 18, []int{32, 32}, []int{6, 26}},
+	{134, 24, []int{43, 43}, []int{6, 30}},
+	{172, 16, []int{27, 27, 27, 27}, []int{6, 34}},
+	{196, 18, []int{31, 31, 31, 31}, []int{6, 22, 38}},
+	{242, 22, []int{38, 38, 39, 39}, []int{6, 24, 42}},
+	{292, 22, []int{36, 36, 36, 37, 37}, []int{6, 26, 46}},
+	{346, 26, []int{43, 43, 43, 43, 44}, []int{6, 28, 50}},
+}
+
+var (
+	gfExp [512]byte
+	gfLog [256]byte
+)
+
+func init() {
+	x := 1
+	for i := 0; i < 255; i++ {
+		gfExp[i] = byte(x)
+		gfLog[x] = byte(i)
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= 0x11d
+		}
+	}
+	for i := 255; i < 512; i++ {
+		gfExp[i] = gfExp[i-255]
+	}
+}
+
+func gfMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gfExp[int(gfLog[a])+int(gfLog[b])]
+}
+
+// reedSolomon menghitung codeword error correction untuk satu blok data
+func reedSolomon(data []byte, ecLen int) []byte {
+	generator := []byte{1}
+	for i := 0; i < ecLen; i++ {
+		next := make([]byte, len(generator)+1)
+		for j, coef := range generator {
+			next[j] ^= coef
+			next[j+1] ^= gfMul(coef, gfExp[i])
+		}
+		generator = next
+	}
+
+	message := make([]byte, len(data)+ecLen)
+	copy(message, data)
+	for i := range data {
+		coef := message[i]
+		if coef == 0 {
+			continue
+		}
+		for j, g := range generator {
+			message[i+j] ^= gfMul(g, coef)
+		}
+	}
+	return message[len(data):]
+}
+
+type bitBuffer []bool
+
+func (b *bitBuffer) append(value, length int) {
+	for i := length - 1; i >= 0; i-- {
+		*b = append(*b, (value>>uint(i))&1 == 1)
+	}
+}
+
+// EncodeQR membuat matriks QR code (byte mode, error correction M) tanpa quiet zone.
+// Nilai true berarti modul gelap.
+func EncodeQR(text string) ([][]bool, error) {
+	data := []byte(text)
+
+	version := 0
+	for v := 1; v < len(qrVersionsM); v++ {
+		countBits := 8
+		if v >= 10 {
+			countBits = 16
+		}
+		capacity := sum(qrVersionsM[v].dataBlocks) * 8
+		if 4+countBits+len(data)*8 <= capacity {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, errors.New("data terlalu panjang untuk QR code")
+	}
+	info := qrVersionsM[version]
+	dataCapacity := sum(info.dataBlocks)
+
+	bits := bitBuffer{}
+	bits.append(0x4, 4)
+	if version >= 10 {
+		bits.append(len(data), 16)
+	} else {
+		bits.append(len(data), 8)
+	}
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+	terminator := dataCapacity*8 - len(bits)
+	if terminator > 4 {
+		terminator = 4
+	}
+	bits.append(0, terminator)
+	bits.append(0, (8-len(bits)%8)%8)
+	for pad := 0xEC; len(bits) < dataCapacity*8; pad ^= 0xEC ^ 0x11 {
+		bits.append(pad, 8)
+	}
+
+	codewords := make([]byte, dataCapacity)
+	for i, bit := range bits {
+		if bit {
+			codewords[i>>3] |= 1 << uint(7-i&7)
+		}
+	}
+
+	// Bagi ke blok, hitung error correction, lalu interleave
+	blocks := [][]byte{}
+	ecBlocks := [][]byte{}
+	offset := 0
+	maxData := 0
+	for _, size := range info.dataBlocks {
+		block := codewords[offset : offset+size]
+		offset += size
+		blocks = append(blocks, block)
+		ecBlocks = append(ecBlocks, reedSolomon(block, info.ecPerBlock))
+		if size > maxData {
+			maxData = size
+		}
+	}
+	final := make([]byte, 0, info.totalCodewords)
+	for i := 0; i < maxData; i++ {
+		for _, block := range blocks {
+			if i < len(block) {
+				final = append(final, block[i])
+			}
+		}
+	}
+	for i := 0; i < info.ecPerBlock; i++ {
+		for _, block := range ecBlocks {
+			final = append(final, block[i])
+		}
+	}
+
+	qr := newQRMatrix(version)
+	qr.drawFunctionPatterns(info.alignment)
+	qr.drawCodewords(final)
+
+	bestMask, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		qr.applyMask(mask)
+		qr.drawFormatBits(mask)
+		penalty := qr.penalty()
+		if bestPenalty < 0 || penalty < bestPenalty {
+			bestMask, bestPenalty = mask, penalty
+		}
+		qr.applyMask(mask) // XOR kedua mengembalikan kondisi semula
+	}
+	qr.applyMask(bestMask)
+	qr.drawFormatBits(bestMask)
+
+	return qr.modules, nil
+}
+
+func sum(values []int) int {
+	total := 0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
+
+type qrMatrix struct {
+	version    int
+	size       int
+	modules    [][]bool
+	isFunction [][]bool
+}
+
+func newQRMatrix(version int) *qrMatrix {
+	size := version*4 + 17
+	qr := &qrMatrix{version: version, size: size}
+	qr.modules = make([][]bool, size)
+	qr.isFunction = make([][]bool, size)
+	for i := 0; i < size; i++ {
+		qr.modules[i] = make([]bool, size)
+		qr.isFunction[i] = make([]bool, size)
+	}
+	return qr
+}
+
+func (qr *qrMatrix) setFunction(x, y int, dark bool) {
+	qr.modules[y][x] = dark
+	qr.isFunction[y][x] = true
+}
+
+func (qr *qrMatrix) drawFunctionPatterns(alignment []int) {
+	for i := 0; i < qr.size; i++ {
+		qr.setFunction(6, i, i%2 == 0)
+		qr.setFunction(i, 6, i%2 == 0)
+	}
+
+	qr.drawFinder(3, 3)
+	qr.drawFinder(qr.size-4, 3)
+	qr.drawFinder(3, qr.size-4)
+
+	last := len(alignment) - 1
+	for i, x := range alignment {
+		for j, y := range alignment {
+			// Alignment pattern tidak boleh menimpa finder pattern
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					qr.setFunction(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+				}
+			}
+		}
+	}
+
+	qr.drawFormatBits(0)
+	qr.drawVersion()
+}
+
+func (qr *qrMatrix) drawFinder(cx, cy int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			x, y := cx+dx, cy+dy
+			if x < 0 || x >= qr.size || y < 0 || y >= qr.size {
+				continue
+			}
+			dist := max(abs(dx), abs(dy))
+			qr.setFunction(x, y, dist != 2 && dist != 4)
+		}
+	}
+}
+
+func (qr *qrMatrix) drawFormatBits(mask int) {
+	// Error correction level M dikodekan sebagai 00
+	data := mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool { return (bits>>uint(i))&1 == 1 }
+
+	for i := 0; i <= 5; i++ {
+		qr.setFunction(8, i, bit(i))
+	}
+	qr.setFunction(8, 7, bit(6))
+	qr.setFunction(8, 8, bit(7))
+	qr.setFunction(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		qr.setFunction(14-i, 8, bit(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		qr.setFunction(qr.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		qr.setFunction(8, qr.size-15+i, bit(i))
+	}
+	qr.setFunction(8, qr.size-8, true)
+}
+
+func (qr *qrMatrix) drawVersion() {
+	if qr.version < 7 {
+		return
+	}
+	rem := qr.version
+	for i := 0; i < 12; i++ {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+	}
+	bits := qr.version<<12 | rem
+	for i := 0; i < 18; i++ {
+		dark := (bits>>uint(i))&1 == 1
+		a := qr.size - 11 + i%3
+		b := i / 3
+		qr.setFunction(a, b, dark)
+		qr.setFunction(b, a, dark)
+	}
+}
+
+func (qr *qrMatrix) drawCodewords(data []byte) {
+	i := 0
+	for right := qr.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < qr.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = qr.size - 1 - vert
+				}
+				if !qr.isFunction[y][x] && i < len(data)*8 {
+					qr.modules[y][x] = (data[i>>3]>>uint(7-i&7))&1 == 1
+					i++
+				}
+			}
+		}
+	}
+}
+
+func (qr *qrMatrix) applyMask(mask int) {
+	for y := 0; y < qr.size; y++ {
+		for x := 0; x < qr.size; x++ {
+			if qr.isFunction[y][x] {
+				continue
+			}
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert {
+				qr.modules[y][x] = !qr.modules[y][x]
+			}
+		}
+	}
+}
+
+// penalty skor aturan penalti standar QR untuk pemilihan mask
+func (qr *qrMatrix) penalty() int {
+	score := 0
+	size := qr.size
+	at := func(x, y int, horizontal bool) bool {
+		if horizontal {
+			return qr.modules[y][x]
+		}
+		return qr.modules[x][y]
+	}
+
+	finderA := []bool{true, false, true, true, true, false, true, false, false, false, false}
+	finderB := []bool{false, false, false, false, true, false, true, true, true, false, true}
+
+	for _, horizontal := range []bool{true, false} {
+		for y := 0; y < size; y++ {
+			run := 1
+			for x := 1; x < size; x++ {
+				if at(x, y, horizontal) == at(x-1, y, horizontal) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					score += 3 + run - 5
+				}
+				run = 1
+			}
+			if run >= 5 {
+				score += 3 + run - 5
+			}
+
+			for x := 0; x+len(finderA) <= size; x++ {
+				matchA, matchB := true, true
+				for k := range finderA {
+					module := at(x+k, y, horizontal)
+					matchA = matchA && module == finderA[k]
+					matchB = matchB && module == finderB[k]
+				}
+				if matchA || matchB {
+					score += 40
+				}
+			}
+		}
+	}
+
+	dark := 0
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			if qr.modules[y][x] {
+				dark++
+			}
+			if x < size-1 && y < size-1 {
+				c := qr.modules[y][x]
+				if c == qr.modules[y][x+1] && c == qr.modules[y+1][x] && c == qr.modules[y+1][x+1] {
+					score += 3
+				}
+			}
+		}
+	}
+	total := size * size
+	score += abs(dark*20-total*10) / total * 10
+
+	return score
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,405 @@
+package helper
+
+import (
+	"strings"
+	"testing"
+)
+
+// qrGoldenExample matriks QR untuk payload "https://example.ac.id" (versi 2, level M).
+// Hasilnya juga lolos decodeTestQR; ubah hanya jika perubahan encoder memang disengaja.
+var qrGoldenExample = []string{
+	"#######...#.#...#.#######",
+	"#.....#..#####.##.#.....#",
+	"#.###.#.##....##..#.###.#",
+	"#.###.#.#.#..#.#..#.###.#",
+	"#.###.#.#..###..#.#.###.#",
+	"#.....#.########..#.....#",
+	"#######.#.#.#.#.#.#######",
+	"........##.##...#........",
+	"#.#####......##...#####..",
+	"###..#...#..##...#.#...#.",
+	".....##..#.##..##..#.#.##",
+	".#####.##.#...###.##....#",
+	"#..######...####.##.#.###",
+	"##...#.#.#......#..#.#.#.",
+	"#.##..##.####.##..####.##",
+	"#.###....###...######...#",
+	"#...#.######....#####.#..",
+	"........###.##.##...##...",
+	"#######......##.#.#.#.###",
+	"#.....#.##..##..#...##.##",
+	"#.###.#.#...#.#######.###",
+	"#.###.#.#.#....#.##.#####",
+	"#.###.#.#####..#.....##.#",
+	"#.....#..#.#..#.##.###..#",
+	"#######.##.#.....########",
+}
+
+func TestEncodeQRGolden(t *testing.T) {
+	modules, err := EncodeQR("https://example.ac.id")
+	if err != nil {
+		t.Fatalf("EncodeQR() error = %v", err)
+	}
+	if got := qrRows(modules); strings.Join(got, "\n") != strings.Join(qrGoldenExample, "\n") {
+		t.Errorf("EncodeQR() berubah dari golden:\n%s", strings.Join(got, "\n"))
+	}
+	if got := decodeTestQR(t, modules); got != "https://example.ac.id" {
+		t.Errorf("payload hasil dekode = %q", got)
+	}
+}
+
+func TestEncodeQRDecodes(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		version int
+	}{
+		{"pendek versi 1", "SPPM-2024", 1},
+		{"URL verifikasi sertifikat", "https://prestasi.example.ac.id/verify/0123456789ABCDEF", 4},
+		{"versi 7 dengan version info", strings.Repeat("verifikasi-", 10), 7},
+		{"UTF-8", "Prestasi Müller – Juara 1", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			modules, err := EncodeQR(tt.payload)
+			if err != nil {
+				t.Fatalf("EncodeQR() error = %v", err)
+			}
+			if want := tt.version*4 + 17; len(modules) != want {
+				t.Fatalf("ukuran matriks = %d, want %d (versi %d)", len(modules), want, tt.version)
+			}
+			if got := decodeTestQR(t, modules); got != tt.payload {
+				t.Errorf("payload hasil dekode = %q, want %q", got, tt.payload)
+			}
+		})
+	}
+}
+
+func TestEncodeQRTooLong(t *testing.T) {
+	if _, err := EncodeQR(strings.Repeat("a", 300)); err == nil {
+		t.Error("EncodeQR() payload melebihi versi 10 seharusnya error")
+	}
+}
+
+func qrRows(modules [][]bool) []string {
+	rows := make([]string, len(modules))
+	for y, row := range modules {
+		var line strings.Builder
+		for _, dark := range row {
+			if dark {
+				line.WriteByte('#')
+			} else {
+				line.WriteByte('.')
+			}
+		}
+		rows[y] = line.String()
+	}
+	return rows
+}
+
+// Tabel di bawah disalin dari ISO/IEC 18004 agar dekoder uji tidak bergantung pada tabel encoder
+
+// qrTestFormatM 15 bit format information level M untuk mask 0-7 (sudah di-XOR 0x5412)
+var qrTestFormatM = []int{0x5412, 0x5125, 0x5E7C, 0x5B4B, 0x45F9, 0x40CE, 0x4F97, 0x4AA0}
+
+// qrTestVersionInfo 18 bit version information untuk versi 7-10
+var qrTestVersionInfo = map[int]int{7: 0x07C94, 8: 0x085BC, 9: 0x09A99, 10: 0x0A4D3}
+
+var qrTestAlignment = map[int][]int{
+	2: {6, 18}, 3: {6, 22}, 4: {6, 26}, 5: {6, 30}, 6: {6, 34},
+	7: {6, 22, 38}, 8: {6, 24, 42}, 9: {6, 26, 46}, 10: {6, 28, 50},
+}
+
+// qrTestBlocksM jumlah codeword error correction per blok dan ukuran data tiap blok, level M
+var qrTestBlocksM = map[int]struct {
+	ec     int
+	blocks []int
+}{
+	1:  {10, []int{16}},
+	2:  {16, []int{28}},
+	3:  {26, []int{44}},
+	4:  {18, []int{32, 32}},
+	5:  {24, []int{43, 43}},
+	6:  {16, []int{27, 27, 27, 27}},
+	7:  {18, []int{31, 31, 31, 31}},
+	8:  {22, []int{38, 38, 39, 39}},
+	9:  {22, []int{36, 36, 36, 37, 37}},
+	10: {26, []int{43, 43, 43, 43, 44}},
+}
+
+// decodeTestQR membaca ulang matriks QR mengikuti spesifikasi: memeriksa pola fungsi,
+// format dan version information, membuka mask, memeriksa sindrom Reed-Solomon tiap blok,
+// lalu mengurai segmen byte mode.
+func decodeTestQR(t *testing.T, modules [][]bool) string {
+	t.Helper()
+	size := len(modules)
+	version := (size - 17) / 4
+	at := func(x, y int) bool { return modules[y][x] }
+
+	// Finder pattern beserta separator di tiga sudut
+	for _, corner := range [][2]int{{3, 3}, {size - 4, 3}, {3, size - 4}} {
+		for dy := -4; dy <= 4; dy++ {
+			for dx := -4; dx <= 4; dx++ {
+				x, y := corner[0]+dx, corner[1]+dy
+				if x < 0 || y < 0 || x >= size || y >= size {
+					continue
+				}
+				dist := max(abs(dx), abs(dy))
+				if want := dist != 2 && dist != 4; at(x, y) != want {
+					t.Fatalf("finder pattern salah pada (%d,%d)", x, y)
+				}
+			}
+		}
+	}
+	for i := 8; i < size-8; i++ {
+		if at(i, 6) != (i%2 == 0) || at(6, i) != (i%2 == 0) {
+			t.Fatalf("timing pattern salah pada indeks %d", i)
+		}
+	}
+	if !at(8, size-8) {
+		t.Fatal("dark module tidak ada")
+	}
+
+	// Dua salinan format information harus sama dan cocok dengan tabel level M
+	var format1, format2 int
+	for i := 0; i < 15; i++ {
+		var x1, y1 int
+		switch {
+		case i <= 5:
+			x1, y1 = 8, i
+		case i == 6:
+			x1, y1 = 8, 7
+		case i == 7:
+			x1, y1 = 8, 8
+		case i == 8:
+			x1, y1 = 7, 8
+		default:
+			x1, y1 = 14-i, 8
+		}
+		x2, y2 := size-1-i, 8
+		if i >= 8 {
+			x2, y2 = 8, size-15+i
+		}
+		if at(x1, y1) {
+			format1 |= 1 << i
+		}
+		if at(x2, y2) {
+			format2 |= 1 << i
+		}
+	}
+	if format1 != format2 {
+		t.Fatalf("salinan format information berbeda: %015b vs %015b", format1, format2)
+	}
+	mask := -1
+	for m, bits := range qrTestFormatM {
+		if bits == format1 {
+			mask = m
+		}
+	}
+	if mask < 0 {
+		t.Fatalf("format information %015b bukan level M yang valid", format1)
+	}
+
+	isFunction := make([][]bool, size)
+	for y := range isFunction {
+		isFunction[y] = make([]bool, size)
+		for x := range isFunction[y] {
+			isFunction[y][x] = x == 6 || y == 6 ||
+				(x < 9 && y < 9) || (x >= size-8 && y < 9) || (x < 9 && y >= size-8)
+		}
+	}
+	alignment := qrTestAlignment[version]
+	for i, ax := range alignment {
+		for j, ay := range alignment {
+			last := len(alignment) - 1
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					if want := max(abs(dx), abs(dy)) != 1; at(ax+dx, ay+dy) != want {
+						t.Fatalf("alignment pattern salah pada (%d,%d)", ax+dx, ay+dy)
+					}
+					isFunction[ay+dy][ax+dx] = true
+				}
+			}
+		}
+	}
+	if version >= 7 {
+		var info1, info2 int
+		for i := 0; i < 18; i++ {
+			a, b := size-11+i%3, i/3
+			if at(a, b) {
+				info1 |= 1 << i
+			}
+			if at(b, a) {
+				info2 |= 1 << i
+			}
+			isFunction[b][a] = true
+			isFunction[a][b] = true
+		}
+		if info1 != qrTestVersionInfo[version] || info2 != qrTestVersionInfo[version] {
+			t.Fatalf("version information = %018b/%018b, want %018b", info1, info2, qrTestVersionInfo[version])
+		}
+	}
+
+	// Baca bit data zig-zag dua kolom dari kanan bawah sambil membuka mask
+	var raw []byte
+	var current byte
+	count := 0
+	for right := size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := ((size-1-right)/2)%2 == 0
+		if right < 6 {
+			upward = ((size-2-right)/2)%2 == 0
+		}
+		for vert := 0; vert < size; vert++ {
+			y := vert
+			if upward {
+				y = size - 1 - vert
+			}
+			for x := right; x >= right-1; x-- {
+				if isFunction[y][x] {
+					continue
+				}
+				bit := at(x, y) != qrTestMask(mask, x, y)
+				current <<= 1
+				if bit {
+					current |= 1
+				}
+				if count++; count%8 == 0 {
+					raw = append(raw, current)
+					current = 0
+				}
+			}
+		}
+	}
+
+	// De-interleave blok lalu periksa sindrom Reed-Solomon
+	layout := qrTestBlocksM[version]
+	total := 0
+	for _, size := range layout.blocks {
+		total += size + layout.ec
+	}
+	if len(raw) != total {
+		t.Fatalf("jumlah codeword = %d, want %d", len(raw), total)
+	}
+	blocks := make([][]byte, len(layout.blocks))
+	index := 0
+	for i := 0; i < layout.blocks[len(layout.blocks)-1]; i++ {
+		for b, size := range layout.blocks {
+			if i < size {
+				blocks[b] = append(blocks[b], raw[index])
+				index++
+			}
+		}
+	}
+	for i := 0; i < layout.ec; i++ {
+		for b := range blocks {
+			blocks[b] = append(blocks[b], raw[index])
+			index++
+		}
+	}
+	var data []byte
+	for b, block := range blocks {
+		for i := 0; i < layout.ec; i++ {
+			if syndrome := qrTestSyndrome(block, i); syndrome != 0 {
+				t.Fatalf("blok %d: sindrom Reed-Solomon ke-%d = %d, want 0", b, i, syndrome)
+			}
+		}
+		data = append(data, block[:layout.blocks[b]]...)
+	}
+
+	// Segmen byte mode: 0100, panjang, isi, terminator, lalu padding EC 11
+	reader := qrTestBitReader{data: data}
+	if mode := reader.read(4); mode != 0b0100 {
+		t.Fatalf("mode = %04b, want 0100 (byte)", mode)
+	}
+	lengthBits := 8
+	if version >= 10 {
+		lengthBits = 16
+	}
+	length := reader.read(lengthBits)
+	payload := make([]byte, length)
+	for i := range payload {
+		payload[i] = byte(reader.read(8))
+	}
+	if remaining := len(data)*8 - reader.pos; remaining > 0 {
+		if terminator := reader.read(min(4, remaining)); terminator != 0 {
+			t.Errorf("terminator = %b, want 0", terminator)
+		}
+	}
+	reader.pos = (reader.pos + 7) / 8 * 8
+	for pad := byte(0xEC); reader.pos < len(data)*8; pad ^= 0xEC ^ 0x11 {
+		if got := byte(reader.read(8)); got != pad {
+			t.Fatalf("byte padding = %#x, want %#x", got, pad)
+		}
+	}
+	return string(payload)
+}
+
+func qrTestMask(mask, x, y int) bool {
+	switch mask {
+	case 0:
+		return (y+x)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (y+x)%3 == 0
+	case 4:
+		return (y/2+x/3)%2 == 0
+	case 5:
+		return (y*x)%2+(y*x)%3 == 0
+	case 6:
+		return ((y*x)%2+(y*x)%3)%2 == 0
+	default:
+		return ((y+x)%2+(y*x)%3)%2 == 0
+	}
+}
+
+// qrTestSyndrome nilai polinom codeword (koefisien pertama berderajat tertinggi) pada alpha^i di GF(256)
+func qrTestSyndrome(codewords []byte, i int) int {
+	alpha := 1
+	for ; i > 0; i-- {
+		alpha = qrTestGFMul(alpha, 2)
+	}
+	result := 0
+	for _, c := range codewords {
+		result = qrTestGFMul(result, alpha) ^ int(c)
+	}
+	return result
+}
+
+func qrTestGFMul(a, b int) int {
+	result := 0
+	for ; b > 0; b >>= 1 {
+		if b&1 == 1 {
+			result ^= a
+		}
+		a <<= 1
+		if a&0x100 != 0 {
+			a ^= 0x11D
+		}
+	}
+	return result
+}
+
+type qrTestBitReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *qrTestBitReader) read(n int) int {
+	value := 0
+	for i := 0; i < n; i++ {
+		bit := (r.data[r.pos/8] >> uint(7-r.pos%8)) & 1
+		value = value<<1 | int(bit)
+		r.pos++
+	}
+	return value
+}
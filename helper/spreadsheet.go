@@ -0,0 +1,290 @@
+package helper
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxXLSXPartBytes batas ukuran satu bagian XML di dalam file XLSX setelah didekompresi,
+// mencegah zip bomb menghabiskan memori
+const maxXLSXPartBytes = 64 << 20
+
+// maxXLSXCells batas jumlah sel setelah setiap baris diperlebar sampai kolom terisi terakhirnya.
+// Satu sel di kolom jauh (misalnya XFD) memaksa ribuan sel kosong, sehingga ukuran file yang
+// kecil tidak menjamin hasil baca yang kecil.
+const maxXLSXCells = 1 << 20
+
+// Batas worksheet Excel: 1.048.576 baris dan 16.384 kolom (A sampai XFD)
+const (
+	xlsxMaxRows    = 1 << 20
+	xlsxMaxColumns = 1 << 14
+)
+
+// ReadCSV membaca seluruh baris CSV. Pemisah koma atau titik koma (format ekspor Excel
+// berlocale Indonesia) dideteksi dari baris pertama.
+func ReadCSV(data []byte) ([][]string, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	firstLine := data
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		firstLine = data[:i]
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+	return reader.ReadAll()
+}
+
+// ReadXLSX membaca baris sebuah worksheet XLSX sebagai teks. sheet kosong berarti sheet
+// pertama. Sel kosong di tengah baris diisi string kosong sehingga indeks kolom tetap sesuai.
+// Tanggal dikembalikan sebagai serial number Excel, lihat ExcelSerialToTime.
+// maxRows membatasi jumlah baris berisi sel (termasuk header), 0 = hanya batas Excel.
+// Total sel hasil baca selalu dibatasi maxXLSXCells.
+func ReadXLSX(data []byte, sheet string, maxRows int) ([][]string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, errors.New("file bukan XLSX yang valid")
+	}
+
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+
+	var workbook struct {
+		Sheets []struct {
+			Name string `xml:"name,attr"`
+			RID  string `xml:"id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := readXLSXPart(files, "xl/workbook.xml", &workbook); err != nil {
+		return nil, err
+	}
+	if len(workbook.Sheets) == 0 {
+		return nil, errors.New("workbook tidak memiliki sheet")
+	}
+
+	rid := workbook.Sheets[0].RID
+	if sheet != "" {
+		rid = ""
+		for _, s := range workbook.Sheets {
+			if strings.EqualFold(s.Name, sheet) {
+				rid = s.RID
+				break
+			}
+		}
+		if rid == "" {
+			return nil, fmt.Errorf("sheet %q tidak ditemukan", sheet)
+		}
+	}
+
+	var rels struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := readXLSXPart(files, "xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return nil, err
+	}
+	sheetPath := ""
+	for _, rel := range rels.Relationships {
+		if rel.ID == rid {
+			if strings.HasPrefix(rel.Target, "/") {
+				sheetPath = strings.TrimPrefix(rel.Target, "/")
+			} else {
+				sheetPath = path.Join("xl", rel.Target)
+			}
+			break
+		}
+	}
+	if sheetPath == "" {
+		return nil, errors.New("lokasi worksheet tidak ditemukan di workbook")
+	}
+
+	var sharedStrings []string
+	if _, ok := files["xl/sharedStrings.xml"]; ok {
+		var sst struct {
+			Items []xlsxRichText `xml:"si"`
+		}
+		if err := readXLSXPart(files, "xl/sharedStrings.xml", &sst); err != nil {
+			return nil, err
+		}
+		sharedStrings = make([]string, len(sst.Items))
+		for i, item := range sst.Items {
+			sharedStrings[i] = item.String()
+		}
+	}
+
+	var worksheet struct {
+		Rows []struct {
+			Index int `xml:"r,attr"`
+			Cells []struct {
+				Ref    string       `xml:"r,attr"`
+				Type   string       `xml:"t,attr"`
+				Value  string       `xml:"v"`
+				Inline xlsxRichText `xml:"is"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := readXLSXPart(files, sheetPath, &worksheet); err != nil {
+		return nil, err
+	}
+
+	// Ukuran hasil dihitung sebelum alokasi: jumlah baris berisi sel dan total sel setelah
+	// tiap baris diperlebar sampai kolom terakhirnya
+	filled, cells := 0, 0
+	for _, row := range worksheet.Rows {
+		if len(row.Cells) == 0 {
+			continue
+		}
+		filled++
+		width := 0
+		for i, cell := range row.Cells {
+			col := xlsxCellColumn(i, cell.Ref)
+			if col >= xlsxMaxColumns {
+				return nil, fmt.Errorf("kolom sel %s melebihi batas worksheet (kolom XFD)", cell.Ref)
+			}
+			if col >= width {
+				width = col + 1
+			}
+		}
+		cells += width
+	}
+	if maxRows > 0 && filled > maxRows {
+		return nil, fmt.Errorf("sheet berisi %d baris, maksimal %d baris", filled, maxRows)
+	}
+	if cells > maxXLSXCells {
+		return nil, fmt.Errorf("sheet berisi %d sel (dihitung sampai kolom terisi terakhir tiap baris), maksimal %d sel", cells, maxXLSXCells)
+	}
+
+	var rows [][]string
+	for _, row := range worksheet.Rows {
+		if row.Index > xlsxMaxRows || len(rows) >= xlsxMaxRows {
+			return nil, fmt.Errorf("nomor baris melebihi batas worksheet (%d baris)", xlsxMaxRows)
+		}
+		// Baris kosong tidak selalu ditulis; sisipkan agar nomor baris sesuai dengan Excel
+		for row.Index > len(rows)+1 {
+			rows = append(rows, nil)
+		}
+
+		var values []string
+		for i, cell := range row.Cells {
+			col := xlsxCellColumn(i, cell.Ref)
+			for len(values) <= col {
+				values = append(values, "")
+			}
+
+			switch cell.Type {
+			case "s":
+				index, err := strconv.Atoi(strings.TrimSpace(cell.Value))
+				if err != nil || index < 0 || index >= len(sharedStrings) {
+					return nil, fmt.Errorf("referensi shared string tidak valid di sel %s", cell.Ref)
+				}
+				values[col] = sharedStrings[index]
+			case "inlineStr":
+				values[col] = cell.Inline.String()
+			case "b":
+				if cell.Value == "1" {
+					values[col] = "TRUE"
+				} else {
+					values[col] = "FALSE"
+				}
+			default:
+				values[col] = cell.Value
+			}
+		}
+		rows = append(rows, values)
+	}
+	return rows, nil
+}
+
+// ExcelSerialToTime mengubah serial number tanggal Excel (sistem 1900) menjadi tanggal UTC
+func ExcelSerialToTime(serial float64) time.Time {
+	// Excel menganggap 1900 tahun kabisat, sehingga epoch efektifnya 30 Desember 1899
+	epoch := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	days := int(serial)
+	seconds := int((serial - float64(days)) * 86400)
+	return epoch.AddDate(0, 0, days).Add(time.Duration(seconds) * time.Second)
+}
+
+// xlsxRichText teks sel yang dapat berupa teks biasa (<t>) atau rich text (<r><t>)
+type xlsxRichText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxRichText) String() string {
+	if len(t.Runs) == 0 {
+		return t.Text
+	}
+	var sb strings.Builder
+	sb.WriteString(t.Text)
+	for _, run := range t.Runs {
+		sb.WriteString(run.Text)
+	}
+	return sb.String()
+}
+
+func readXLSXPart(files map[string]*zip.File, name string, v interface{}) error {
+	file, ok := files[name]
+	if !ok {
+		return fmt.Errorf("bagian %s tidak ditemukan di file XLSX", name)
+	}
+	rc, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, maxXLSXPartBytes+1))
+	if err != nil {
+		return err
+	}
+	if len(data) > maxXLSXPartBytes {
+		return fmt.Errorf("bagian %s terlalu besar", name)
+	}
+	if err := xml.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("gagal membaca %s: %v", name, err)
+	}
+	return nil
+}
+
+// xlsxCellColumn indeks kolom sel ke-i pada baris; sel tanpa referensi mengikuti urutannya
+func xlsxCellColumn(i int, ref string) int {
+	if index := xlsxColumnIndex(ref); index >= 0 {
+		return index
+	}
+	return i
+}
+
+// xlsxColumnIndex mengubah referensi sel seperti "AB12" menjadi indeks kolom 0-based.
+// Referensi di luar kolom XFD menghasilkan xlsxMaxColumns tanpa menghitung sisa huruf.
+func xlsxColumnIndex(ref string) int {
+	index := 0
+	for _, ch := range ref {
+		if ch < 'A' || ch > 'Z' {
+			break
+		}
+		index = index*26 + int(ch-'A'+1)
+		if index > xlsxMaxColumns {
+			return xlsxMaxColumns
+		}
+	}
+	return index - 1
+}
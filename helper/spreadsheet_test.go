@@ -0,0 +1,121 @@
+package helper
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestXLSXColumnIndex(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want int
+	}{
+		{"A1", 0},
+		{"Z9", 25},
+		{"AA1", 26},
+		{"AB12", 27},
+		{"XFD1", xlsxMaxColumns - 1},
+		{"XFE1", xlsxMaxColumns},
+		{strings.Repeat("Z", 40) + "1", xlsxMaxColumns},
+		{"12", -1},
+		{"", -1},
+	}
+
+	for _, tt := range tests {
+		if got := xlsxColumnIndex(tt.ref); got != tt.want {
+			t.Errorf("xlsxColumnIndex(%q) = %d, want %d", tt.ref, got, tt.want)
+		}
+	}
+}
+
+func TestReadXLSX(t *testing.T) {
+	data := testXLSX(t, `<row r="1"><c r="A1" t="s"><v>0</v></c><c r="C1" t="inlineStr"><is><t>judul</t></is></c></row>`+
+		`<row r="3"><c r="B3" t="b"><v>1</v></c><c r="C3"><v>45292</v></c></row>`,
+		`<si><t>nim</t></si>`)
+
+	rows, err := ReadXLSX(data, "", 0)
+	if err != nil {
+		t.Fatalf("ReadXLSX() error = %v", err)
+	}
+	want := [][]string{{"nim", "", "judul"}, nil, {"", "TRUE", "45292"}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("ReadXLSX() = %q, want %q", rows, want)
+	}
+
+	if _, err := ReadXLSX(data, "Tidak Ada", 0); err == nil {
+		t.Error("ReadXLSX() sheet yang tidak ada seharusnya error")
+	}
+}
+
+func TestReadXLSXLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		rows    string
+		maxRows int
+		want    string
+	}{
+		{"kolom melewati XFD", `<row r="1"><c r="XFE1"><v>1</v></c></row>`, 0, "kolom XFD"},
+		{"referensi kolom sangat panjang", `<row r="1"><c r="` + strings.Repeat("Z", 40) + `1"><v>1</v></c></row>`, 0, "kolom XFD"},
+		{"nomor baris melewati batas Excel", `<row r="1048577"><c r="A1048577"><v>1</v></c></row>`, 0, "batas worksheet"},
+		{"jumlah baris melewati maxRows", strings.Repeat(`<row><c><v>1</v></c></row>`, 4), 3, "maksimal 3 baris"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadXLSX(testXLSX(t, tt.rows, ""), "", tt.maxRows)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ReadXLSX() error = %v, want memuat %q", err, tt.want)
+			}
+		})
+	}
+
+	// Sel di kolom XFD pada banyak baris: file kecil, tetapi setiap baris diperlebar 16.384 kolom
+	farRight := ""
+	for i := 1; i <= 5001; i++ {
+		farRight += `<row r="` + strconv.Itoa(i) + `"><c r="XFD` + strconv.Itoa(i) + `"><v>1</v></c></row>`
+	}
+	if _, err := ReadXLSX(testXLSX(t, farRight, ""), "", 5001); err == nil || !strings.Contains(err.Error(), "maksimal 1048576 sel") {
+		t.Errorf("ReadXLSX() sel jauh di kanan error = %v, want ditolak karena jumlah sel", err)
+	}
+	// Satu baris selebar XFD masih di bawah batas sel
+	if rows, err := ReadXLSX(testXLSX(t, `<row r="1"><c r="XFD1"><v>1</v></c></row>`, ""), "", 0); err != nil || len(rows[0]) != xlsxMaxColumns {
+		t.Errorf("ReadXLSX() satu baris sampai XFD error = %v", err)
+	}
+
+	// Baris kosong tanpa sel tidak dihitung terhadap maxRows
+	rows := `<row r="1"><c r="A1"><v>1</v></c></row><row r="2"/><row r="3"/><row r="4"><c r="A4"><v>2</v></c></row>`
+	if _, err := ReadXLSX(testXLSX(t, rows, ""), "", 2); err != nil {
+		t.Errorf("ReadXLSX() baris kosong ikut dihitung: %v", err)
+	}
+}
+
+// testXLSX workbook minimal dengan satu sheet "Data"
+func testXLSX(t *testing.T, sheetRows, sharedStrings string) []byte {
+	t.Helper()
+	parts := map[string]string{
+		"xl/workbook.xml":            `<workbook><sheets><sheet name="Data" r:id="rId1" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships><Relationship Id="rId1" Target="worksheets/sheet1.xml"/></Relationships>`,
+		"xl/worksheets/sheet1.xml":   `<worksheet><sheetData>` + sheetRows + `</sheetData></worksheet>`,
+	}
+	if sharedStrings != "" {
+		parts["xl/sharedStrings.xml"] = `<sst>` + sharedStrings + `</sst>`
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for name, content := range parts {
+		file, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		file.Write([]byte(content))
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
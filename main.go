@@ -2,20 +2,94 @@ package main
 
 import (
 	"log"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/config"
 	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/database"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/route"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/scanner"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/storage"
 )
 
 func main() {
 	config.LoadEnv()
 	config.SetupLogger()
+
+	// Kunci sertifikat terpisah agar bocornya salah satu kunci tidak dapat dipakai memalsukan yang lain
+	if config.CertificateSecret == "" || config.CertificateSecret == config.JWTSecret {
+		log.Fatal("CERTIFICATE_SECRET wajib diisi dan harus berbeda dari JWT_SECRET")
+	}
+
 	database.Connect()
 	database.ConnectMongoDB(config.MongoURI, config.MongoDBName)
 
 	jwtExpiry, _ := time.ParseDuration(config.JWTExpiry)
-	app := config.SetupApp(database.DB, database.MongoDB, config.JWTSecret, jwtExpiry)
+	verificationSLA, _ := time.ParseDuration(config.VerificationSLA)
+	escalationAfter, _ := time.ParseDuration(config.VerificationEscalationAfter)
+	slaCheckInterval, _ := time.ParseDuration(config.SLACheckInterval)
+	achievementRetention, _ := time.ParseDuration(config.AchievementRetention)
+	purgeInterval, _ := time.ParseDuration(config.PurgeInterval)
+	badgeVerifiableCredential, _ := strconv.ParseBool(config.BadgeVerifiableCredential)
+	doiCacheTTL, _ := time.ParseDuration(config.DOICacheTTL)
+	certificationCheckInterval, _ := time.ParseDuration(config.CertificationCheckInterval)
+	attachmentURLTTL, _ := time.ParseDuration(config.AttachmentURLTTL)
+	if attachmentURLTTL > 0 && (config.AttachmentURLSecret == "" || config.AttachmentURLSecret == config.JWTSecret) {
+		log.Fatal("ATTACHMENT_URL_SECRET wajib diisi dan harus berbeda dari JWT_SECRET selama ATTACHMENT_URL_TTL aktif")
+	}
+
+	var certificationReminderDays []int
+	for _, value := range strings.Split(config.CertificationReminderDays, ",") {
+		if days, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+			certificationReminderDays = append(certificationReminderDays, days)
+		}
+	}
+
+	attachmentSizeLimits := make(map[string]int64)
+	for _, value := range strings.Split(config.AttachmentSizeLimits, ",") {
+		fileType, megabytes, ok := strings.Cut(value, "=")
+		if size, err := strconv.ParseFloat(strings.TrimSpace(megabytes), 64); ok && err == nil && size > 0 {
+			attachmentSizeLimits[strings.ToLower(strings.TrimSpace(fileType))] = int64(size * (1 << 20))
+		}
+	}
+
+	blobStore, err := storage.New(config.StorageConfig(""))
+	if err != nil {
+		log.Fatal("Gagal menyiapkan penyimpanan file:", err)
+	}
+
+	fileScanner, err := scanner.New(config.ScannerConfig())
+	if err != nil {
+		log.Fatal("Gagal menyiapkan pemindai malware:", err)
+	}
+
+	app := config.SetupApp(database.DB, database.MongoDB, config.JWTSecret, jwtExpiry, route.Options{
+		VerificationSLA:             verificationSLA,
+		VerificationEscalationAfter: escalationAfter,
+		VerificationAlternate:       config.VerificationAlternate,
+		SLACheckInterval:            slaCheckInterval,
+		AchievementRetention:        achievementRetention,
+		PurgeInterval:               purgeInterval,
+		CertificateSecret:           config.CertificateSecret,
+		PublicBaseURL:               config.PublicBaseURL,
+		SKPITemplatePath:            config.SKPITemplatePath,
+		BadgeIssuerName:             config.BadgeIssuerName,
+		BadgeIssuerEmail:            config.BadgeIssuerEmail,
+		BadgeVerifiableCredential:   badgeVerifiableCredential,
+		BadgeKeyPath:                config.BadgeKeyPath,
+		DOIResolverURL:              config.DOIResolverURL,
+		DOIResolverMailto:           config.DOIResolverMailto,
+		DOICacheDir:                 config.DOICacheDir,
+		DOICacheTTL:                 doiCacheTTL,
+		CertificationReminderDays:   certificationReminderDays,
+		CertificationCheckInterval:  certificationCheckInterval,
+		BlobStore:                   blobStore,
+		AttachmentURLTTL:            attachmentURLTTL,
+		AttachmentURLSecret:         config.AttachmentURLSecret,
+		AttachmentSizeLimits:        attachmentSizeLimits,
+		Scanner:                     fileScanner,
+	})
 
 	port := config.Port
 	log.Printf("Server berjalan di port %s", port)
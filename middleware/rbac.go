@@ -11,9 +11,7 @@ import (
 // Permissions are read from JWT token (not from database) for better performance
 func RBACMiddleware(action, resource string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		// Get permissions from context (set by JWT middleware from token)
-		permissionsInterface := c.Locals("permissions")
-		if permissionsInterface == nil {
+		if !HasPermission(c, action, resource) {
 			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
 				"error":   true,
 				"message": "Anda tidak memiliki izin untuk mengakses resource ini",
@@ -24,67 +22,44 @@ func RBACMiddleware(action, resource string) fiber.Handler {
 			})
 		}
 
-		// Convert permissions to []string
-		permissions, ok := permissionsInterface.([]string)
-		if !ok {
-			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-				"error":   true,
-				"message": "Anda tidak memiliki izin untuk mengakses resource ini",
-				"required_permission": fiber.Map{
-					"action":   action,
-					"resource": resource,
-				},
-			})
-		}
+		// Permission granted, continue to next handler
+		return c.Next()
+	}
+}
 
-		// Get role_name from context for admin check
-		roleNameInterface := c.Locals("role_name")
-		roleName := ""
-		if roleNameInterface != nil {
-			if name, ok := roleNameInterface.(string); ok {
-				roleName = name
-			}
-		}
+// HasPermission checks the permissions set by JWTMiddleware, for handlers whose required
+// permission depends on the request
+func HasPermission(c *fiber.Ctx, action, resource string) bool {
+	// Get permissions from context (set by JWT middleware from token)
+	permissions, ok := c.Locals("permissions").([]string)
+	if !ok {
+		return false
+	}
 
-		// Check if user is admin (case-insensitive)
-		// Admin memiliki akses penuh (permissions contains "*:*")
-		roleNameLower := strings.ToLower(roleName)
-		if strings.Contains(roleNameLower, "admin") {
-			return c.Next()
-		}
+	// Get role_name from context for admin check
+	roleName, _ := c.Locals("role_name").(string)
 
-		// Check for wildcard permission
-		for _, perm := range permissions {
-			if perm == "*:*" {
-				return c.Next()
-			}
-		}
-
-		// Format required permission as "resource:action"
-		requiredPermission := strings.ToLower(resource) + ":" + strings.ToLower(action)
+	// Check if user is admin (case-insensitive)
+	// Admin memiliki akses penuh (permissions contains "*:*")
+	if strings.Contains(strings.ToLower(roleName), "admin") {
+		return true
+	}
 
-		// Check if user has the required permission
-		hasPermission := false
-		for _, perm := range permissions {
-			if strings.ToLower(perm) == requiredPermission {
-				hasPermission = true
-				break
-			}
+	// Check for wildcard permission
+	for _, perm := range permissions {
+		if perm == "*:*" {
+			return true
 		}
+	}
 
-		if !hasPermission {
-			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-				"error":   true,
-				"message": "Anda tidak memiliki izin untuk mengakses resource ini",
-				"required_permission": fiber.Map{
-					"action":   action,
-					"resource": resource,
-				},
-			})
-		}
+	// Format required permission as "resource:action"
+	requiredPermission := strings.ToLower(resource) + ":" + strings.ToLower(action)
 
-		// Permission granted, continue to next handler
-		return c.Next()
+	// Check if user has the required permission
+	for _, perm := range permissions {
+		if strings.ToLower(perm) == requiredPermission {
+			return true
+		}
 	}
+	return false
 }
-
@@ -3,8 +3,9 @@ package route
 import (
 	"context"
 	"encoding/json"
-	"os"
-	"path/filepath"
+	"errors"
+	"fmt"
+	"io"
 	"strconv"
 	"strings"
 	"time"
@@ -90,6 +91,8 @@ func RegisterAchievementRoutes(router fiber.Router, achievementService service.A
 		})
 
 		// POST /api/v1/achievements - Create (Mahasiswa) dengan support file upload
+		// Multipart: hasil tiap file dikembalikan di "uploads". atomic=true (form field atau query)
+		// membatalkan achievement jika ada file yang ditolak atau dikarantina.
 		// Requires: create achievements permission
 		achievements.Post("/", middleware.RBACMiddleware("create", "achievements"), func(c *fiber.Ctx) error {
 			userID, err := getUserIDFromContext(c)
@@ -130,13 +133,6 @@ func RegisterAchievementRoutes(router fiber.Router, achievementService service.A
 						req.Tags = strings.Split(tagsStr, ",")
 					}
 				}
-
-				// Parse points
-				if pointsStr := c.FormValue("points"); pointsStr != "" {
-					if points, err := strconv.ParseFloat(pointsStr, 64); err == nil {
-						req.Points = points
-					}
-				}
 			} else {
 				// Parse dari JSON body
 				if err := c.BodyParser(&req); err != nil {
@@ -147,78 +143,74 @@ func RegisterAchievementRoutes(router fiber.Router, achievementService service.A
 				}
 			}
 
-			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			if !strings.Contains(contentType, "multipart/form-data") {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+
+				// Create achievement
+				result, err := achievementService.CreateAchievement(ctx, userID, &req)
+				if err != nil {
+					return achievementErrorResponse(c, fiber.StatusBadRequest, err)
+				}
+
+				return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+					"error":   false,
+					"message": "Achievement berhasil dibuat",
+					"data":    result,
+				})
+			}
+
+			// Handle file uploads jika ada
+			uploads := []service.AttachmentUpload{}
+			if form, err := c.MultipartForm(); err == nil && form.File != nil {
+				files := form.File["files"] // Support multiple files dengan key "files"
+				if len(files) == 0 {
+					files = form.File["file"] // Fallback ke single file dengan key "file"
+				}
+				for _, file := range files {
+					uploads = append(uploads, service.AttachmentUpload{
+						FileName: file.Filename,
+						Open:     func() (io.ReadCloser, error) { return file.Open() },
+					})
+				}
+			}
+			atomic := c.QueryBool("atomic", false)
+			if value := c.FormValue("atomic"); value != "" {
+				atomic, _ = strconv.ParseBool(value)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
 			defer cancel()
 
-			// Create achievement
-			result, err := achievementService.CreateAchievement(ctx, userID, &req)
+			// Create achievement lalu validasi isi file, pindai dan simpan ke blob store
+			result, uploadResults, err := achievementService.CreateAchievementWithAttachments(ctx, userID, &req, uploads, atomic)
 			if err != nil {
-				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				if uploadResults == nil {
+					return achievementErrorResponse(c, fiber.StatusBadRequest, err)
+				}
+				return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
 					"error":   true,
 					"message": err.Error(),
+					"uploads": uploadResults,
 				})
 			}
 
-			// Handle file uploads jika ada
-			if strings.Contains(contentType, "multipart/form-data") {
-				form, err := c.MultipartForm()
-				if err == nil && form.File != nil {
-					files := form.File["files"] // Support multiple files dengan key "files"
-					if len(files) == 0 {
-						files = form.File["file"] // Fallback ke single file dengan key "file"
-					}
-
-					for _, file := range files {
-						// Validate file
-						if file.Size > 10*1024*1024 { // 10MB limit
-							continue // Skip file yang terlalu besar
-						}
-
-						allowedExtensions := []string{".pdf", ".jpg", ".jpeg", ".png", ".doc", ".docx"}
-						ext := strings.ToLower(filepath.Ext(file.Filename))
-						allowed := false
-						for _, allowedExt := range allowedExtensions {
-							if ext == allowedExt {
-								allowed = true
-								break
-							}
-						}
-						if !allowed {
-							continue // Skip file yang tidak diizinkan
-						}
-
-						// Create upload directory
-						uploadDir := "uploads/achievements"
-						if err := os.MkdirAll(uploadDir, 0755); err != nil {
-							continue
-						}
-
-						// Generate unique filename
-						filename := result.ID + "_" + strconv.FormatInt(time.Now().Unix(), 10) + "_" + file.Filename
-						filePath := filepath.Join(uploadDir, filename)
-
-						// Save file
-						if err := c.SaveFile(file, filePath); err != nil {
-							continue
-						}
-
-						// Update achievement dengan attachment
-						_, err = achievementService.UploadAttachment(ctx, userID, result.ID, filePath)
-						if err != nil {
-							// Rollback: delete file
-							os.Remove(filePath)
-						}
-					}
-
-					// Reload achievement dengan attachments terbaru
-					result, _ = achievementService.GetAchievementByID(ctx, userID, result.ID)
+			message := "Achievement berhasil dibuat"
+			rejected := 0
+			for _, uploadResult := range uploadResults {
+				if uploadResult.Status != service.AttachmentUploadAccepted {
+					rejected++
 				}
 			}
+			if rejected > 0 {
+				message = fmt.Sprintf("Achievement berhasil dibuat, %d dari %d file ditolak", rejected, len(uploadResults))
+			}
 
 			return c.Status(fiber.StatusCreated).JSON(fiber.Map{
 				"error":   false,
-				"message": "Achievement berhasil dibuat",
+				"message": message,
 				"data":    result,
+				"uploads": uploadResults,
 			})
 		})
 
@@ -254,10 +246,7 @@ func RegisterAchievementRoutes(router fiber.Router, achievementService service.A
 
 			result, err := achievementService.UpdateAchievement(ctx, userID, achievementID, &req)
 			if err != nil {
-				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-					"error":   true,
-					"message": err.Error(),
-				})
+				return achievementErrorResponse(c, fiber.StatusBadRequest, err)
 			}
 
 			return c.JSON(fiber.Map{
@@ -327,10 +316,7 @@ func RegisterAchievementRoutes(router fiber.Router, achievementService service.A
 
 			result, err := achievementService.SubmitAchievement(ctx, userID, achievementID)
 			if err != nil {
-				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-					"error":   true,
-					"message": err.Error(),
-				})
+				return achievementErrorResponse(c, fiber.StatusBadRequest, err)
 			}
 
 			return c.JSON(fiber.Map{
@@ -460,9 +446,9 @@ func RegisterAchievementRoutes(router fiber.Router, achievementService service.A
 			})
 		})
 
-		// POST /api/v1/achievements/:id/attachments - Upload files
-		// Requires: update achievements permission
-		achievements.Post("/:id/attachments", middleware.RBACMiddleware("update", "achievements"), func(c *fiber.Ctx) error {
+		// GET /api/v1/achievements/:id/duplicates - Kemungkinan duplikat untuk membantu verifikasi
+		// Requires: verify achievements permission
+		achievements.Get("/:id/duplicates", middleware.RBACMiddleware("verify", "achievements"), func(c *fiber.Ctx) error {
 			userID, err := getUserIDFromContext(c)
 			if err != nil {
 				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
@@ -479,86 +465,211 @@ func RegisterAchievementRoutes(router fiber.Router, achievementService service.A
 				})
 			}
 
-			// Get file from form
-			file, err := c.FormFile("file")
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			result, err := achievementService.GetAchievementDuplicates(ctx, userID, achievementID)
 			if err != nil {
+				if err.Error() == "achievement tidak ditemukan" {
+					return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+						"error":   true,
+						"message": err.Error(),
+					})
+				}
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			return c.JSON(fiber.Map{
+				"error": false,
+				"data":  result,
+				"total": len(result),
+			})
+		})
+
+		// GET /api/v1/achievements/:id/versions - Daftar versi konten
+		// Requires: read achievements permission
+		achievements.Get("/:id/versions", middleware.RBACMiddleware("read", "achievements"), func(c *fiber.Ctx) error {
+			userID, err := getUserIDFromContext(c)
+			if err != nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			achievementID := c.Params("id")
+			if achievementID == "" {
 				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 					"error":   true,
-					"message": "File tidak ditemukan. Gunakan form field 'file'",
+					"message": "achievement ID harus diisi",
+				})
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			result, err := achievementService.GetAchievementVersions(ctx, userID, achievementID)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
 				})
 			}
 
-			// Validate file
-			if file.Size > 10*1024*1024 { // 10MB limit
+			return c.JSON(fiber.Map{
+				"error": false,
+				"data":  result,
+				"total": len(result),
+			})
+		})
+
+		// GET /api/v1/achievements/:id/versions/:v/diff - Diff versi v terhadap versi sebelumnya
+		// Query opsional: against=<nomor versi pembanding>
+		// Requires: read achievements permission
+		achievements.Get("/:id/versions/:v/diff", middleware.RBACMiddleware("read", "achievements"), func(c *fiber.Ctx) error {
+			userID, err := getUserIDFromContext(c)
+			if err != nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			achievementID := c.Params("id")
+			version, err := strconv.Atoi(c.Params("v"))
+			if achievementID == "" || err != nil || version < 1 {
 				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 					"error":   true,
-					"message": "Ukuran file maksimal 10MB",
+					"message": "achievement ID dan nomor versi harus valid",
 				})
 			}
 
-			allowedExtensions := []string{".pdf", ".jpg", ".jpeg", ".png", ".doc", ".docx"}
-			ext := strings.ToLower(filepath.Ext(file.Filename))
-			allowed := false
-			for _, allowedExt := range allowedExtensions {
-				if ext == allowedExt {
-					allowed = true
-					break
-				}
+			against, err := strconv.Atoi(c.Query("against", "0"))
+			if err != nil || against < 0 {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": "nomor versi pembanding tidak valid",
+				})
 			}
 
-			if !allowed {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			result, err := achievementService.GetAchievementVersionDiff(ctx, userID, achievementID, version, against)
+			if err != nil {
 				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 					"error":   true,
-					"message": "Tipe file tidak diizinkan. Hanya PDF, JPG, PNG, DOC, DOCX",
+					"message": err.Error(),
 				})
 			}
 
-			// Create upload directory
-			uploadDir := "uploads/achievements"
-			if err := os.MkdirAll(uploadDir, 0755); err != nil {
-				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			return c.JSON(fiber.Map{
+				"error": false,
+				"data":  result,
+			})
+		})
+
+		// POST /api/v1/achievements/:id/attachments - Upload files
+		// Requires: update achievements permission
+		achievements.Post("/:id/attachments", middleware.RBACMiddleware("update", "achievements"), func(c *fiber.Ctx) error {
+			userID, err := getUserIDFromContext(c)
+			if err != nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 					"error":   true,
-					"message": "Gagal membuat directory",
+					"message": err.Error(),
 				})
 			}
 
-			// Generate unique filename
-			filename := achievementID + "_" + strconv.FormatInt(time.Now().Unix(), 10) + ext
-			filePath := filepath.Join(uploadDir, filename)
+			achievementID := c.Params("id")
+			if achievementID == "" {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": "achievement ID harus diisi",
+				})
+			}
 
-			// Save file
-			if err := c.SaveFile(file, filePath); err != nil {
+			// Get file from form
+			file, err := c.FormFile("file")
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": "File tidak ditemukan. Gunakan form field 'file'",
+				})
+			}
+
+			content, err := file.Open()
+			if err != nil {
 				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 					"error":   true,
-					"message": "Gagal menyimpan file: " + err.Error(),
+					"message": "Gagal membaca file",
 				})
 			}
+			defer content.Close()
 
-			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
 			defer cancel()
 
-			// Update achievement with attachment
-			result, err := achievementService.UploadAttachment(ctx, userID, achievementID, filePath)
+			// Validasi isi file (jenis, ukuran, JavaScript PDF, metadata gambar) lalu simpan ke blob store
+			result, err := achievementService.UploadAttachment(ctx, userID, achievementID, file.Filename, content)
 			if err != nil {
-				// Rollback: delete file
-				os.Remove(filePath)
-				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				status := fiber.StatusBadRequest
+				if strings.HasPrefix(err.Error(), "gagal menyimpan file") {
+					status = fiber.StatusInternalServerError
+				} else if strings.HasPrefix(err.Error(), "pemindai malware tidak tersedia") {
+					status = fiber.StatusServiceUnavailable
+				}
+				return c.Status(status).JSON(fiber.Map{
 					"error":   true,
 					"message": err.Error(),
 				})
 			}
 
+			// File terinfeksi tetap tercatat sebagai attachment berstatus karantina
+			if result.Quarantined {
+				return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+					"error":   true,
+					"message": "File terdeteksi malware (" + result.ScanSignature + ") dan dikarantina",
+					"data": fiber.Map{
+						"id":          result.ID,
+						"file_path":   result.FileURL,
+						"quarantined": true,
+					},
+				})
+			}
+
 			return c.JSON(fiber.Map{
 				"error":   false,
 				"message": "File berhasil diupload",
 				"data": fiber.Map{
-					"file_path": result,
+					"id":        result.ID,
+					"file_path": result.FileURL,
 				},
 			})
 		})
 	}
 }
 
+// achievementErrorResponse mengirim error dalam format standar.
+// Error validasi dikirim bersama daftar field yang bermasalah.
+func achievementErrorResponse(c *fiber.Ctx, status int, err error) error {
+	var validationErr *service.ValidationError
+	if errors.As(err, &validationErr) {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"error":   true,
+			"message": validationErr.Error(),
+			"errors":  validationErr.Errors,
+		})
+	}
+
+	return c.Status(status).JSON(fiber.Map{
+		"error":   true,
+		"message": err.Error(),
+	})
+}
+
 // Helper function to get user ID from context
 func getUserIDFromContext(c *fiber.Ctx) (uuid.UUID, error) {
 	userIDInterface := c.Locals("user_id")
@@ -0,0 +1,157 @@
+package route
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/service"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/middleware"
+)
+
+// RegisterAchievementTypeRoutes mendaftarkan route untuk tipe prestasi (bawaan dan custom)
+func RegisterAchievementTypeRoutes(router fiber.Router, achievementTypeService service.AchievementTypeService) {
+	achievementTypes := router.Group("/achievement-types")
+	{
+		// GET /api/v1/achievement-types - Daftar tipe prestasi beserta schema field untuk form
+		// Query: include_inactive=true untuk menampilkan tipe custom yang sudah dinonaktifkan
+		// Requires: read achievements permission
+		achievementTypes.Get("/", middleware.RBACMiddleware("read", "achievements"), func(c *fiber.Ctx) error {
+			includeInactive := c.Query("include_inactive") == "true"
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			types, err := achievementTypeService.GetAllTypes(ctx, includeInactive)
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			return c.JSON(fiber.Map{
+				"error": false,
+				"data":  types,
+				"total": len(types),
+			})
+		})
+
+		// GET /api/v1/achievement-types/:code - Detail tipe prestasi
+		// Requires: read achievements permission
+		achievementTypes.Get("/:code", middleware.RBACMiddleware("read", "achievements"), func(c *fiber.Ctx) error {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			achievementType, err := achievementTypeService.GetTypeByCode(ctx, c.Params("code"))
+			if err != nil {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			return c.JSON(fiber.Map{
+				"error": false,
+				"data":  achievementType,
+			})
+		})
+
+		// POST /api/v1/achievement-types - Create tipe custom (Admin)
+		// Requires: manage achievement_types permission
+		achievementTypes.Post("/", middleware.RBACMiddleware("manage", "achievement_types"), func(c *fiber.Ctx) error {
+			userID, err := getUserIDFromContext(c)
+			if err != nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			var req service.AchievementTypeRequest
+			if err := c.BodyParser(&req); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": "Invalid request body",
+				})
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			achievementType, err := achievementTypeService.CreateType(ctx, userID, &req)
+			if err != nil {
+				if err.Error() == "code tipe prestasi sudah digunakan" {
+					return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+						"error":   true,
+						"message": err.Error(),
+					})
+				}
+				return achievementErrorResponse(c, fiber.StatusBadRequest, err)
+			}
+
+			return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+				"error":   false,
+				"message": "Tipe prestasi berhasil dibuat",
+				"data":    achievementType,
+			})
+		})
+
+		// PUT /api/v1/achievement-types/:code - Update schema tipe custom (Admin)
+		// Requires: manage achievement_types permission
+		achievementTypes.Put("/:code", middleware.RBACMiddleware("manage", "achievement_types"), func(c *fiber.Ctx) error {
+			var req service.AchievementTypeRequest
+			if err := c.BodyParser(&req); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": "Invalid request body",
+				})
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			achievementType, err := achievementTypeService.UpdateType(ctx, c.Params("code"), &req)
+			if err != nil {
+				if err.Error() == "tipe prestasi tidak ditemukan" {
+					return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+						"error":   true,
+						"message": err.Error(),
+					})
+				}
+				return achievementErrorResponse(c, fiber.StatusBadRequest, err)
+			}
+
+			return c.JSON(fiber.Map{
+				"error":   false,
+				"message": "Tipe prestasi berhasil diupdate",
+				"data":    achievementType,
+			})
+		})
+
+		// DELETE /api/v1/achievement-types/:code - Nonaktifkan tipe custom (Admin)
+		// Requires: manage achievement_types permission
+		achievementTypes.Delete("/:code", middleware.RBACMiddleware("manage", "achievement_types"), func(c *fiber.Ctx) error {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			if err := achievementTypeService.DeactivateType(ctx, c.Params("code")); err != nil {
+				if err.Error() == "tipe prestasi tidak ditemukan" {
+					return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+						"error":   true,
+						"message": err.Error(),
+					})
+				}
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			return c.JSON(fiber.Map{
+				"error":   false,
+				"message": "Tipe prestasi berhasil dinonaktifkan",
+			})
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package route
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/service"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/middleware"
+)
+
+// RegisterApprovalRoutes mendaftarkan route approval chain dan antrian persetujuan per tahap
+func RegisterApprovalRoutes(router fiber.Router, approvalChainService service.ApprovalChainService, achievementService service.AchievementService) {
+	chains := router.Group("/approval-chains")
+	{
+		// GET /api/v1/approval-chains - Daftar approval chain
+		// Requires: read achievements permission
+		chains.Get("/", middleware.RBACMiddleware("read", "achievements"), func(c *fiber.Ctx) error {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			result, err := approvalChainService.GetAllChains(ctx)
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			return c.JSON(fiber.Map{
+				"error": false,
+				"data":  result,
+				"total": len(result),
+			})
+		})
+
+		// POST /api/v1/approval-chains - Create (Admin)
+		// Requires: manage approval_chains permission
+		chains.Post("/", middleware.RBACMiddleware("manage", "approval_chains"), func(c *fiber.Ctx) error {
+			var req service.ApprovalChainRequest
+			if err := c.BodyParser(&req); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": "Invalid request body",
+				})
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			chain, err := approvalChainService.CreateChain(ctx, &req)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+				"error":   false,
+				"message": "Approval chain berhasil dibuat",
+				"data":    chain,
+			})
+		})
+
+		// PUT /api/v1/approval-chains/:id - Update (Admin)
+		// Requires: manage approval_chains permission
+		chains.Put("/:id", middleware.RBACMiddleware("manage", "approval_chains"), func(c *fiber.Ctx) error {
+			chainID, err := uuid.Parse(c.Params("id"))
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": "Chain ID tidak valid",
+				})
+			}
+
+			var req service.ApprovalChainRequest
+			if err := c.BodyParser(&req); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": "Invalid request body",
+				})
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			chain, err := approvalChainService.UpdateChain(ctx, chainID, &req)
+			if err != nil {
+				if err.Error() == "approval chain tidak ditemukan" {
+					return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+						"error":   true,
+						"message": err.Error(),
+					})
+				}
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			return c.JSON(fiber.Map{
+				"error":   false,
+				"message": "Approval chain berhasil diupdate",
+				"data":    chain,
+			})
+		})
+
+		// DELETE /api/v1/approval-chains/:id - Delete (Admin)
+		// Requires: manage approval_chains permission
+		chains.Delete("/:id", middleware.RBACMiddleware("manage", "approval_chains"), func(c *fiber.Ctx) error {
+			chainID, err := uuid.Parse(c.Params("id"))
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": "Chain ID tidak valid",
+				})
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			if err := approvalChainService.DeleteChain(ctx, chainID); err != nil {
+				if err.Error() == "approval chain tidak ditemukan" {
+					return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+						"error":   true,
+						"message": err.Error(),
+					})
+				}
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			return c.JSON(fiber.Map{
+				"error":   false,
+				"message": "Approval chain berhasil dihapus",
+			})
+		})
+	}
+
+	approvals := router.Group("/approvals")
+	{
+		registerApprovalStageRoutes(approvals, achievementService, model.ApprovalStageAdvisor)
+		registerApprovalStageRoutes(approvals, achievementService, model.ApprovalStageProgramHead)
+		registerApprovalStageRoutes(approvals, achievementService, model.ApprovalStageStudentAffairs)
+	}
+}
+
+// approvalStagePermission permission untuk memproses satu tahap. Tahap dosen wali memakai
+// permission verify achievements yang sudah ada.
+func approvalStagePermission(stage model.ApprovalStage) (action, resource string) {
+	if stage == model.ApprovalStageAdvisor {
+		return "verify", "achievements"
+	}
+	return string(stage), "approvals"
+}
+
+// registerApprovalStageRoutes mendaftarkan antrian, approve dan reject untuk satu tahap
+func registerApprovalStageRoutes(router fiber.Router, achievementService service.AchievementService, stage model.ApprovalStage) {
+	action, resource := approvalStagePermission(stage)
+	group := router.Group("/" + string(stage))
+	{
+		// GET /api/v1/approvals/:stage/queue - Achievement yang menunggu tahap ini
+		group.Get("/queue", middleware.RBACMiddleware(action, resource), func(c *fiber.Ctx) error {
+			userID, err := getUserIDFromContext(c)
+			if err != nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			result, err := achievementService.GetApprovalQueue(ctx, userID, stage)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			return c.JSON(fiber.Map{
+				"error": false,
+				"data":  result,
+				"total": len(result),
+			})
+		})
+
+		// POST /api/v1/approvals/:stage/:id/approve - Setujui tahap ini
+		group.Post("/:id/approve", middleware.RBACMiddleware(action, resource), func(c *fiber.Ctx) error {
+			userID, err := getUserIDFromContext(c)
+			if err != nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			result, err := achievementService.ApproveStage(ctx, userID, c.Params("id"), stage)
+			if err != nil {
+				if err.Error() == "achievement tidak ditemukan" {
+					return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+						"error":   true,
+						"message": err.Error(),
+					})
+				}
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			return c.JSON(fiber.Map{
+				"error":   false,
+				"message": "Achievement disetujui",
+				"data":    result,
+			})
+		})
+
+		// POST /api/v1/approvals/:stage/:id/reject - Tolak pada tahap ini
+		group.Post("/:id/reject", middleware.RBACMiddleware(action, resource), func(c *fiber.Ctx) error {
+			userID, err := getUserIDFromContext(c)
+			if err != nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			var req struct {
+				RejectionNote string `json:"rejection_note"`
+			}
+			if err := c.BodyParser(&req); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": "Invalid request body",
+				})
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			result, err := achievementService.RejectStage(ctx, userID, c.Params("id"), stage, req.RejectionNote)
+			if err != nil {
+				if err.Error() == "achievement tidak ditemukan" {
+					return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+						"error":   true,
+						"message": err.Error(),
+					})
+				}
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			return c.JSON(fiber.Map{
+				"error":   false,
+				"message": "Achievement ditolak",
+				"data":    result,
+			})
+		})
+	}
+}
@@ -0,0 +1,259 @@
+package route
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/service"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/middleware"
+)
+
+// RegisterPublicAttachmentRoutes mendaftarkan route unduh attachment melalui URL sementara
+// bertanda tangan, untuk klien yang tidak dapat mengirim header Authorization (viewer PDF, tag <a>)
+func RegisterPublicAttachmentRoutes(app *fiber.App, attachmentService service.AttachmentService) {
+	// GET /files/attachments/:id/:attachmentId?expires=&signature= - Unduh attachment (publik, URL sementara)
+	app.Get("/files/attachments/:id/:attachmentId", func(c *fiber.Ctx) error {
+		// Tanpa batas waktu context karena isi file dibaca saat response dikirim
+		file, err := attachmentService.OpenSigned(context.Background(), c.Params("id"), c.Params("attachmentId"), c.Query("expires"), c.Query("signature"), c.Get(fiber.HeaderRange))
+		if err != nil {
+			return attachmentErrorResponse(c, err)
+		}
+
+		return sendAttachment(c, file)
+	})
+}
+
+// RegisterAttachmentRoutes mendaftarkan route unduh, hapus dan ganti attachment achievement.
+// Attachment dirujuk dengan ID stabilnya (field id pada daftar attachments), bukan posisi.
+func RegisterAttachmentRoutes(router fiber.Router, achievementService service.AchievementService, attachmentService service.AttachmentService) {
+	achievements := router.Group("/achievements")
+	{
+		// GET /api/v1/achievements/:id/attachments/:attachmentId?download=true - Unduh/tampilkan attachment
+		// Mendukung header Range (HTTP 206) untuk PDF besar. download=true = Content-Disposition attachment
+		// Requires: read achievements permission
+		achievements.Get("/:id/attachments/:attachmentId", middleware.RBACMiddleware("read", "achievements"), func(c *fiber.Ctx) error {
+			attachment, status, err := findAttachment(c, achievementService)
+			if err != nil {
+				return c.Status(status).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			// Tanpa batas waktu context karena isi file dibaca saat response dikirim
+			file, err := attachmentService.Open(context.Background(), *attachment, c.Get(fiber.HeaderRange))
+			if err != nil {
+				return attachmentErrorResponse(c, err)
+			}
+
+			return sendAttachment(c, file)
+		})
+
+		// GET /api/v1/achievements/:id/attachments/:attachmentId/url - Buat URL sementara bertanda tangan
+		// Requires: read achievements permission
+		achievements.Get("/:id/attachments/:attachmentId/url", middleware.RBACMiddleware("read", "achievements"), func(c *fiber.Ctx) error {
+			attachment, status, err := findAttachment(c, achievementService)
+			if err != nil {
+				return c.Status(status).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			signed, err := attachmentService.SignURL(c.Params("id"), *attachment)
+			if err != nil {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			return c.JSON(fiber.Map{
+				"error": false,
+				"data":  signed,
+			})
+		})
+
+		// DELETE /api/v1/achievements/:id/attachments/:attachmentId - Hapus attachment (Mahasiswa)
+		// Hanya untuk achievement draft atau rejected
+		// Requires: update achievements permission
+		achievements.Delete("/:id/attachments/:attachmentId", middleware.RBACMiddleware("update", "achievements"), func(c *fiber.Ctx) error {
+			userID, err := getUserIDFromContext(c)
+			if err != nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			result, err := achievementService.DeleteAttachment(ctx, userID, c.Params("id"), c.Params("attachmentId"))
+			if err != nil {
+				return c.Status(attachmentChangeStatus(err)).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			return c.JSON(fiber.Map{
+				"error":   false,
+				"message": "Attachment berhasil dihapus",
+				"data":    result,
+			})
+		})
+
+		// PUT /api/v1/achievements/:id/attachments/:attachmentId - Ganti file attachment (Mahasiswa)
+		// Hanya untuk achievement draft atau rejected. ID attachment tetap sama.
+		// Requires: update achievements permission
+		achievements.Put("/:id/attachments/:attachmentId", middleware.RBACMiddleware("update", "achievements"), func(c *fiber.Ctx) error {
+			userID, err := getUserIDFromContext(c)
+			if err != nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			file, err := c.FormFile("file")
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": "File tidak ditemukan. Gunakan form field 'file'",
+				})
+			}
+
+			content, err := file.Open()
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error":   true,
+					"message": "Gagal membaca file",
+				})
+			}
+			defer content.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			defer cancel()
+
+			result, err := achievementService.ReplaceAttachment(ctx, userID, c.Params("id"), c.Params("attachmentId"), file.Filename, content)
+			if err != nil {
+				return c.Status(attachmentChangeStatus(err)).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			if result.Quarantined {
+				return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+					"error":   true,
+					"message": "File terdeteksi malware (" + result.ScanSignature + ") dan dikarantina, attachment lama tidak diganti",
+					"data":    result,
+				})
+			}
+
+			return c.JSON(fiber.Map{
+				"error":   false,
+				"message": "Attachment berhasil diganti",
+				"data":    result,
+			})
+		})
+	}
+}
+
+// findAttachment memeriksa hak akses (sama dengan melihat detail achievement) lalu mengambil
+// attachment dengan ID :attachmentId beserta status HTTP jika gagal
+func findAttachment(c *fiber.Ctx, achievementService service.AchievementService) (*model.Attachment, int, error) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return nil, fiber.StatusUnauthorized, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	achievement, err := achievementService.GetAchievementByID(ctx, userID, c.Params("id"))
+	if err != nil {
+		if err.Error() == "achievement tidak ditemukan" {
+			return nil, fiber.StatusNotFound, err
+		}
+		return nil, fiber.StatusForbidden, err
+	}
+
+	// ID attachment pada response selalu terisi, termasuk attachment lama
+	for i := range achievement.Attachments {
+		if achievement.Attachments[i].ID == c.Params("attachmentId") {
+			return &achievement.Attachments[i], fiber.StatusOK, nil
+		}
+	}
+	return nil, fiber.StatusNotFound, errors.New("attachment tidak ditemukan")
+}
+
+// attachmentChangeStatus status HTTP untuk error hapus/ganti attachment
+func attachmentChangeStatus(err error) int {
+	switch {
+	case err.Error() == "achievement tidak ditemukan", err.Error() == "attachment tidak ditemukan":
+		return fiber.StatusNotFound
+	case err.Error() == "hanya mahasiswa yang dapat mengubah attachment",
+		err.Error() == "anda tidak memiliki akses untuk mengubah attachment achievement ini":
+		return fiber.StatusForbidden
+	case strings.HasPrefix(err.Error(), "pemindai malware tidak tersedia"):
+		return fiber.StatusServiceUnavailable
+	case strings.HasPrefix(err.Error(), "gagal"):
+		return fiber.StatusInternalServerError
+	default:
+		return fiber.StatusBadRequest
+	}
+}
+
+func attachmentErrorResponse(c *fiber.Ctx, err error) error {
+	var rangeErr *service.RangeNotSatisfiableError
+	if errors.As(err, &rangeErr) {
+		c.Set(fiber.HeaderContentRange, fmt.Sprintf("bytes */%d", rangeErr.Size))
+		return c.Status(fiber.StatusRequestedRangeNotSatisfiable).JSON(fiber.Map{
+			"error":   true,
+			"message": err.Error(),
+		})
+	}
+
+	status := fiber.StatusInternalServerError
+	switch err.Error() {
+	case "file attachment tidak ditemukan":
+		status = fiber.StatusNotFound
+	case "URL attachment tidak valid atau sudah kedaluwarsa", "attachment dikarantina karena terdeteksi malware":
+		status = fiber.StatusForbidden
+	}
+	return c.Status(status).JSON(fiber.Map{
+		"error":   true,
+		"message": err.Error(),
+	})
+}
+
+// sendAttachment mengirim isi file secara streaming. Ditampilkan inline kecuali ?download=true.
+func sendAttachment(c *fiber.Ctx, file *service.AttachmentFile) error {
+	disposition := "inline"
+	if c.QueryBool("download", false) {
+		disposition = "attachment"
+	}
+	if header := mime.FormatMediaType(disposition, map[string]string{"filename": file.FileName}); header != "" {
+		disposition = header
+	}
+
+	c.Set(fiber.HeaderContentType, file.ContentType)
+	c.Set(fiber.HeaderContentDisposition, disposition)
+	c.Set(fiber.HeaderAcceptRanges, "bytes")
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+	c.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+	if file.Partial {
+		c.Set(fiber.HeaderContentRange, fmt.Sprintf("bytes %d-%d/%d", file.Offset, file.Offset+file.Length-1, file.Size))
+		c.Status(fiber.StatusPartialContent)
+	}
+
+	return c.SendStream(file.Content, int(file.Length))
+}
@@ -0,0 +1,188 @@
+package route
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/service"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/middleware"
+)
+
+// RegisterPublicBadgeRoutes mendaftarkan dokumen Open Badges hosted dan endpoint verifikasi
+// publik. URL-URL ini dirujuk dari dalam assertion sehingga harus dapat diakses tanpa login.
+func RegisterPublicBadgeRoutes(app *fiber.App, badgeService service.BadgeService) {
+	badges := app.Group("/badges")
+
+	// GET /badges/issuer - Profil issuer Open Badges
+	badges.Get("/issuer", func(c *fiber.Ctx) error {
+		return c.JSON(badgeService.GetIssuer())
+	})
+
+	// GET /badges/revocations - Daftar assertion yang dicabut
+	badges.Get("/revocations", func(c *fiber.Ctx) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		list, err := badgeService.GetRevocationList(ctx)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error":   true,
+				"message": err.Error(),
+			})
+		}
+		return c.JSON(list)
+	})
+
+	// GET /badges/classes/:id - Badge class hosted untuk achievement terverifikasi
+	badges.Get("/classes/:id", func(c *fiber.Ctx) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		badgeClass, err := badgeService.GetBadgeClass(ctx, c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error":   true,
+				"message": err.Error(),
+			})
+		}
+		return c.JSON(badgeClass)
+	})
+
+	// GET /badges/classes/:id/image - Gambar badge (SVG)
+	badges.Get("/classes/:id/image", func(c *fiber.Ctx) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		image, err := badgeService.RenderBadgeImage(ctx, c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error":   true,
+				"message": err.Error(),
+			})
+		}
+		c.Set(fiber.HeaderContentType, "image/svg+xml")
+		return c.Send(image)
+	})
+
+	// GET /badges/assertions/:id - Hosted assertion; 410 Gone jika sudah dicabut
+	badges.Get("/assertions/:id", func(c *fiber.Ctx) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		assertion, err := badgeService.GetAssertion(ctx, c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error":   true,
+				"message": err.Error(),
+			})
+		}
+		if assertion.Revoked {
+			return c.Status(fiber.StatusGone).JSON(assertion)
+		}
+		return c.JSON(assertion)
+	})
+
+	// GET /badges/assertions/:id/credential - Verifiable Credential (JWT) untuk digital wallet
+	badges.Get("/assertions/:id/credential", func(c *fiber.Ctx) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		credential, err := badgeService.GetCredential(ctx, c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error":   true,
+				"message": err.Error(),
+			})
+		}
+		c.Set(fiber.HeaderContentType, "application/vc+jwt")
+		return c.SendString(credential)
+	})
+
+	// GET /badges/assertions/:id/verify - Verifikasi status badge (publik)
+	badges.Get("/assertions/:id/verify", func(c *fiber.Ctx) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		result, err := badgeService.VerifyBadge(ctx, c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error":   true,
+				"message": err.Error(),
+			})
+		}
+		return c.JSON(fiber.Map{
+			"error":   false,
+			"message": result.Message,
+			"data":    result,
+		})
+	})
+
+	// GET /.well-known/did.json - Dokumen did:web berisi kunci publik institusi
+	app.Get("/.well-known/did.json", func(c *fiber.Ctx) error {
+		document, err := badgeService.GetDIDDocument()
+		if err != nil {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error":   true,
+				"message": err.Error(),
+			})
+		}
+		return c.JSON(document)
+	})
+}
+
+// RegisterBadgeRoutes mendaftarkan route badge milik peserta achievement
+func RegisterBadgeRoutes(router fiber.Router, achievementService service.AchievementService, badgeService service.BadgeService) {
+	achievements := router.Group("/achievements")
+	{
+		// GET /api/v1/achievements/:id/badge?student_id= - Open Badge (dan Verifiable Credential)
+		// Mahasiswa mendapat badge miliknya sendiri, dosen/admin dapat memilih NIM peserta
+		// Requires: read achievements permission
+		achievements.Get("/:id/badge", middleware.RBACMiddleware("read", "achievements"), func(c *fiber.Ctx) error {
+			userID, err := getUserIDFromContext(c)
+			if err != nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			achievementID := c.Params("id")
+			if achievementID == "" {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": "achievement ID harus diisi",
+				})
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			// Hak akses sama dengan melihat detail achievement
+			if _, err := achievementService.GetAchievementByID(ctx, userID, achievementID); err != nil {
+				status := fiber.StatusForbidden
+				if err.Error() == "achievement tidak ditemukan" {
+					status = fiber.StatusNotFound
+				}
+				return c.Status(status).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			badge, err := badgeService.GetBadge(ctx, userID, achievementID, c.Query("student_id"))
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			return c.JSON(fiber.Map{
+				"error":   false,
+				"message": "Badge berhasil diambil",
+				"data":    badge,
+			})
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package route
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/service"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/middleware"
+)
+
+// RegisterPublicCertificateRoutes mendaftarkan route verifikasi sertifikat tanpa login,
+// dituju oleh QR code pada sertifikat
+func RegisterPublicCertificateRoutes(app *fiber.App, certificateService service.CertificateService) {
+	// GET /verify/:code - Verifikasi keaslian sertifikat (publik)
+	app.Get("/verify/:code", func(c *fiber.Ctx) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		result, err := certificateService.VerifyCertificate(ctx, c.Params("code"))
+		if err != nil {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error":   true,
+				"message": err.Error(),
+			})
+		}
+
+		return c.JSON(fiber.Map{
+			"error":   false,
+			"message": result.Message,
+			"data":    result,
+		})
+	})
+}
+
+// RegisterCertificateRoutes mendaftarkan route unduh sertifikat verifikasi
+func RegisterCertificateRoutes(router fiber.Router, achievementService service.AchievementService, certificateService service.CertificateService) {
+	achievements := router.Group("/achievements")
+	{
+		// GET /api/v1/achievements/:id/certificate?student_id= - Unduh sertifikat PDF
+		// Mahasiswa mendapat sertifikatnya sendiri, dosen/admin dapat memilih NIM peserta
+		// Requires: read achievements permission
+		achievements.Get("/:id/certificate", middleware.RBACMiddleware("read", "achievements"), func(c *fiber.Ctx) error {
+			userID, err := getUserIDFromContext(c)
+			if err != nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			achievementID := c.Params("id")
+			if achievementID == "" {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": "achievement ID harus diisi",
+				})
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			// Hak akses sama dengan melihat detail achievement
+			if _, err := achievementService.GetAchievementByID(ctx, userID, achievementID); err != nil {
+				status := fiber.StatusForbidden
+				if err.Error() == "achievement tidak ditemukan" {
+					status = fiber.StatusNotFound
+				}
+				return c.Status(status).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			certificate, err := certificateService.GetCertificate(ctx, userID, achievementID, c.Query("student_id"))
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			// Tanpa batas waktu context karena isi file dibaca saat response dikirim
+			content, size, err := certificateService.OpenCertificate(context.Background(), certificate)
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			c.Set(fiber.HeaderContentType, "application/pdf")
+			c.Set(fiber.HeaderContentDisposition, `attachment; filename="sertifikat-`+certificate.Code+`.pdf"`)
+			return c.SendStream(content, int(size))
+		})
+	}
+}
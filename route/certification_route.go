@@ -0,0 +1,58 @@
+package route
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/service"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/middleware"
+)
+
+// RegisterCertificationRoutes mendaftarkan route masa berlaku sertifikasi
+func RegisterCertificationRoutes(router fiber.Router, certificationService service.CertificationService) {
+	certifications := router.Group("/certifications")
+	{
+		// GET /api/v1/certifications/expiring?days=90&include_expired=true - Sertifikasi yang akan berakhir
+		// Dosen wali: mahasiswa bimbingan, admin: seluruh mahasiswa. days kosong = jendela pengingat terbesar
+		// Requires: verify achievements permission
+		certifications.Get("/expiring", middleware.RBACMiddleware("verify", "achievements"), func(c *fiber.Ctx) error {
+			userID, err := getUserIDFromContext(c)
+			if err != nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			days := c.QueryInt("days", 0)
+			if days < 0 || days > 3650 {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": "days harus antara 1 dan 3650",
+				})
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			result, err := certificationService.GetExpiring(ctx, userID, days, c.QueryBool("include_expired", false))
+			if err != nil {
+				status := fiber.StatusBadRequest
+				if err.Error() == "hanya dosen wali atau admin yang dapat melihat sertifikasi yang akan berakhir" {
+					status = fiber.StatusForbidden
+				}
+				return c.Status(status).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			return c.JSON(fiber.Map{
+				"error": false,
+				"data":  result,
+				"total": len(result),
+			})
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package route
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/service"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/middleware"
+)
+
+// RegisterDelegationRoutes mendaftarkan route delegasi dosen wali pengganti
+func RegisterDelegationRoutes(router fiber.Router, delegationService service.DelegationService) {
+	delegations := router.Group("/delegations")
+	{
+		// GET /api/v1/delegations - Delegasi milik dosen (pemberi/penerima), admin melihat semua
+		// Requires: verify achievements permission
+		delegations.Get("/", middleware.RBACMiddleware("verify", "achievements"), func(c *fiber.Ctx) error {
+			userID, err := getUserIDFromContext(c)
+			if err != nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			result, err := delegationService.GetDelegations(ctx, userID)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			return c.JSON(fiber.Map{
+				"error": false,
+				"data":  result,
+				"total": len(result),
+			})
+		})
+
+		// POST /api/v1/delegations - Tunjuk dosen pengganti untuk rentang tanggal
+		// Requires: verify achievements permission
+		delegations.Post("/", middleware.RBACMiddleware("verify", "achievements"), func(c *fiber.Ctx) error {
+			userID, err := getUserIDFromContext(c)
+			if err != nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			var req service.DelegationRequest
+			if err := c.BodyParser(&req); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": "Invalid request body",
+				})
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			delegation, err := delegationService.CreateDelegation(ctx, userID, &req)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+				"error":   false,
+				"message": "Delegasi berhasil dibuat",
+				"data":    delegation,
+			})
+		})
+
+		// DELETE /api/v1/delegations/:id - Batalkan delegasi
+		// Requires: verify achievements permission
+		delegations.Delete("/:id", middleware.RBACMiddleware("verify", "achievements"), func(c *fiber.Ctx) error {
+			userID, err := getUserIDFromContext(c)
+			if err != nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			delegationID, err := uuid.Parse(c.Params("id"))
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": "Delegation ID tidak valid",
+				})
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			if err := delegationService.DeleteDelegation(ctx, userID, delegationID); err != nil {
+				if err.Error() == "delegasi tidak ditemukan" {
+					return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+						"error":   true,
+						"message": err.Error(),
+					})
+				}
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			return c.JSON(fiber.Map{
+				"error":   false,
+				"message": "Delegasi berhasil dihapus",
+			})
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package route
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/service"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/middleware"
+)
+
+// RegisterImportRoutes mendaftarkan route import massal achievement
+func RegisterImportRoutes(router fiber.Router, importService service.ImportService, achievementService service.AchievementService) {
+	achievements := router.Group("/achievements")
+	{
+		// POST /api/v1/achievements/import - Import achievement dari CSV/XLSX (multipart/form-data)
+		// Form: file, mapping (JSON {"field":"Judul Kolom"}), sheet, status (draft|verified),
+		// dry_run (true = hanya laporan validasi), batch_size
+		// Requires: import achievements permission
+		achievements.Post("/import", middleware.RBACMiddleware("import", "achievements"), func(c *fiber.Ctx) error {
+			userID, err := getUserIDFromContext(c)
+			if err != nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			file, err := c.FormFile("file")
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": "File tidak ditemukan. Gunakan form field 'file'",
+				})
+			}
+			if file.Size > 10*1024*1024 { // 10MB limit
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": "Ukuran file maksimal 10MB",
+				})
+			}
+
+			req := &service.ImportAchievementsRequest{
+				FileName: file.Filename,
+				Sheet:    c.FormValue("sheet"),
+				Status:   model.AchievementStatus(c.FormValue("status")),
+			}
+
+			if mapping := c.FormValue("mapping"); mapping != "" {
+				if err := json.Unmarshal([]byte(mapping), &req.Mapping); err != nil {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+						"error":   true,
+						"message": "mapping harus berupa JSON object {\"field\": \"judul kolom\"}",
+					})
+				}
+			}
+			if dryRun := c.FormValue("dry_run"); dryRun != "" {
+				req.DryRun, err = strconv.ParseBool(dryRun)
+				if err != nil {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+						"error":   true,
+						"message": "dry_run harus berupa true atau false",
+					})
+				}
+			}
+			if batchSize := c.FormValue("batch_size"); batchSize != "" {
+				req.BatchSize, err = strconv.Atoi(batchSize)
+				if err != nil {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+						"error":   true,
+						"message": "batch_size harus berupa angka",
+					})
+				}
+			}
+
+			content, err := file.Open()
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error":   true,
+					"message": "Gagal membaca file",
+				})
+			}
+			defer content.Close()
+			req.Content, err = io.ReadAll(content)
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error":   true,
+					"message": "Gagal membaca file",
+				})
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+			defer cancel()
+
+			report, err := importService.ImportAchievements(ctx, userID, req)
+			if err != nil {
+				status := fiber.StatusBadRequest
+				if err.Error() == "hanya admin yang dapat mengimpor prestasi" {
+					status = fiber.StatusForbidden
+				}
+				return c.Status(status).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			message := "Import achievement selesai"
+			if report.DryRun {
+				message = "Dry run import selesai, tidak ada data yang disimpan"
+			}
+			return c.JSON(fiber.Map{
+				"error":   false,
+				"message": message,
+				"data":    report,
+			})
+		})
+
+		// POST /api/v1/achievements/import/citations - Buat draft publikasi dari BibTeX/RIS (multipart/form-data)
+		// Form: file (.bib atau .ris), format (bib|ris, opsional), dry_run (true = hanya pratinjau draft)
+		// Requires: create achievements permission
+		achievements.Post("/import/citations", middleware.RBACMiddleware("create", "achievements"), func(c *fiber.Ctx) error {
+			userID, err := getUserIDFromContext(c)
+			if err != nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			file, err := c.FormFile("file")
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": "File tidak ditemukan. Gunakan form field 'file'",
+				})
+			}
+			if file.Size > 5*1024*1024 { // 5MB limit
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": "Ukuran file maksimal 5MB",
+				})
+			}
+
+			req := &service.ImportCitationsRequest{
+				FileName: file.Filename,
+				Format:   c.FormValue("format"),
+			}
+			if dryRun := c.FormValue("dry_run"); dryRun != "" {
+				req.DryRun, err = strconv.ParseBool(dryRun)
+				if err != nil {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+						"error":   true,
+						"message": "dry_run harus berupa true atau false",
+					})
+				}
+			}
+
+			content, err := file.Open()
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error":   true,
+					"message": "Gagal membaca file",
+				})
+			}
+			defer content.Close()
+			req.Content, err = io.ReadAll(content)
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error":   true,
+					"message": "Gagal membaca file",
+				})
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+			defer cancel()
+
+			report, err := achievementService.ImportCitations(ctx, userID, req)
+			if err != nil {
+				status := fiber.StatusBadRequest
+				if err.Error() == "hanya mahasiswa yang dapat membuat prestasi" {
+					status = fiber.StatusForbidden
+				}
+				return c.Status(status).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			message := "Import publikasi selesai"
+			if report.DryRun {
+				message = "Pratinjau import publikasi, tidak ada data yang disimpan"
+			}
+			return c.JSON(fiber.Map{
+				"error":   false,
+				"message": message,
+				"data":    report,
+			})
+		})
+	}
+}
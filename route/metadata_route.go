@@ -0,0 +1,86 @@
+package route
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/service"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/middleware"
+)
+
+// RegisterMetadataRoutes mendaftarkan route metadata DOI publikasi
+func RegisterMetadataRoutes(router fiber.Router, achievementService service.AchievementService) {
+	// GET /api/v1/metadata/doi?doi=10.xxxx/yyyy - Metadata DOI untuk mengisi form publikasi
+	// Requires: create achievements permission
+	router.Get("/metadata/doi", middleware.RBACMiddleware("create", "achievements"), func(c *fiber.Ctx) error {
+		doi := c.Query("doi")
+		if doi == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   true,
+				"message": "doi harus diisi",
+			})
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		metadata, err := achievementService.LookupDOI(ctx, doi)
+		if err != nil {
+			return metadataErrorResponse(c, err)
+		}
+
+		return c.JSON(fiber.Map{
+			"error": false,
+			"data":  metadata,
+		})
+	})
+
+	// GET /api/v1/achievements/:id/metadata-check - Perbedaan data publikasi dengan metadata DOI
+	// Requires: verify achievements permission
+	router.Get("/achievements/:id/metadata-check", middleware.RBACMiddleware("verify", "achievements"), func(c *fiber.Ctx) error {
+		userID, err := getUserIDFromContext(c)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   true,
+				"message": err.Error(),
+			})
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		result, err := achievementService.CheckPublicationMetadata(ctx, userID, c.Params("id"))
+		if err != nil {
+			return metadataErrorResponse(c, err)
+		}
+
+		return c.JSON(fiber.Map{
+			"error": false,
+			"data":  result,
+			"total": len(result.Mismatches),
+		})
+	})
+}
+
+func metadataErrorResponse(c *fiber.Ctx, err error) error {
+	status := fiber.StatusBadGateway
+	switch {
+	case errors.Is(err, service.ErrMetadataNotFound), err.Error() == "achievement tidak ditemukan":
+		status = fiber.StatusNotFound
+	case err.Error() == "resolver metadata DOI tidak dikonfigurasi":
+		status = fiber.StatusServiceUnavailable
+	case err.Error() == "DOI tidak valid, contoh: 10.1000/xyz123", err.Error() == "achievement bukan publikasi dengan DOI":
+		status = fiber.StatusBadRequest
+	case err.Error() == "anda tidak memiliki akses untuk melihat achievement ini",
+		err.Error() == "anda bukan dosen wali dari mahasiswa ini",
+		err.Error() == "user tidak ditemukan sebagai mahasiswa",
+		err.Error() == "user tidak ditemukan sebagai dosen":
+		status = fiber.StatusForbidden
+	}
+	return c.Status(status).JSON(fiber.Map{
+		"error":   true,
+		"message": err.Error(),
+	})
+}
@@ -0,0 +1,108 @@
+package route
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/service"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/middleware"
+)
+
+// RegisterNotificationRoutes mendaftarkan route notifikasi milik user yang login
+func RegisterNotificationRoutes(router fiber.Router, notificationService service.NotificationService) {
+	notifications := router.Group("/notifications")
+	{
+		// GET /api/v1/notifications?unread=true - Daftar notifikasi
+		// Requires: read achievements permission
+		notifications.Get("/", middleware.RBACMiddleware("read", "achievements"), func(c *fiber.Ctx) error {
+			userID, err := getUserIDFromContext(c)
+			if err != nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			result, err := notificationService.GetNotifications(ctx, userID, c.QueryBool("unread", false))
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			return c.JSON(fiber.Map{
+				"error": false,
+				"data":  result,
+				"total": len(result),
+			})
+		})
+
+		// PUT /api/v1/notifications/read-all - Tandai semua notifikasi sudah dibaca
+		// Requires: read achievements permission
+		notifications.Put("/read-all", middleware.RBACMiddleware("read", "achievements"), func(c *fiber.Ctx) error {
+			userID, err := getUserIDFromContext(c)
+			if err != nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			if err := notificationService.MarkAllAsRead(ctx, userID); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			return c.JSON(fiber.Map{
+				"error":   false,
+				"message": "Semua notifikasi ditandai sudah dibaca",
+			})
+		})
+
+		// PUT /api/v1/notifications/:id/read - Tandai notifikasi sudah dibaca
+		// Requires: read achievements permission
+		notifications.Put("/:id/read", middleware.RBACMiddleware("read", "achievements"), func(c *fiber.Ctx) error {
+			userID, err := getUserIDFromContext(c)
+			if err != nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			notificationID, err := uuid.Parse(c.Params("id"))
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": "Notification ID tidak valid",
+				})
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			if err := notificationService.MarkAsRead(ctx, userID, notificationID); err != nil {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			return c.JSON(fiber.Map{
+				"error":   false,
+				"message": "Notifikasi ditandai sudah dibaca",
+			})
+		})
+	}
+}
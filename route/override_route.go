@@ -0,0 +1,135 @@
+package route
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/service"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/middleware"
+)
+
+// RegisterOverrideRoutes mendaftarkan route override admin atas achievement.
+// Setiap override wajib menyertakan alasan dan dicatat di history.
+func RegisterOverrideRoutes(router fiber.Router, achievementService service.AchievementService) {
+	override := router.Group("/achievements/:id/override")
+	{
+		// PUT /api/v1/achievements/:id/override - Ubah field apa pun tanpa batasan status
+		// Requires: override achievements permission
+		override.Put("/", middleware.RBACMiddleware("override", "achievements"), func(c *fiber.Ctx) error {
+			userID, err := getUserIDFromContext(c)
+			if err != nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			var req service.OverrideAchievementRequest
+			if err := c.BodyParser(&req); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": "Invalid request body",
+				})
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			result, err := achievementService.OverrideAchievement(ctx, userID, c.Params("id"), &req)
+			if err != nil {
+				return overrideErrorResponse(c, err)
+			}
+
+			return c.JSON(fiber.Map{
+				"error":   false,
+				"message": "Achievement berhasil diubah melalui override",
+				"data":    result,
+			})
+		})
+
+		// POST /api/v1/achievements/:id/override/status - Paksa perpindahan status
+		// Requires: override achievements permission
+		override.Post("/status", middleware.RBACMiddleware("override", "achievements"), func(c *fiber.Ctx) error {
+			userID, err := getUserIDFromContext(c)
+			if err != nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			var req service.OverrideStatusRequest
+			if err := c.BodyParser(&req); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": "Invalid request body",
+				})
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			result, err := achievementService.OverrideStatus(ctx, userID, c.Params("id"), &req)
+			if err != nil {
+				return overrideErrorResponse(c, err)
+			}
+
+			return c.JSON(fiber.Map{
+				"error":   false,
+				"message": "Status achievement berhasil diubah melalui override",
+				"data":    result,
+			})
+		})
+
+		// POST /api/v1/achievements/:id/override/reassign - Pindahkan achievement ke mahasiswa lain
+		// Requires: override achievements permission
+		override.Post("/reassign", middleware.RBACMiddleware("override", "achievements"), func(c *fiber.Ctx) error {
+			userID, err := getUserIDFromContext(c)
+			if err != nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			var req service.ReassignAchievementRequest
+			if err := c.BodyParser(&req); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": "Invalid request body",
+				})
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			result, err := achievementService.ReassignAchievement(ctx, userID, c.Params("id"), &req)
+			if err != nil {
+				return overrideErrorResponse(c, err)
+			}
+
+			return c.JSON(fiber.Map{
+				"error":   false,
+				"message": "Achievement berhasil dipindahkan",
+				"data":    result,
+			})
+		})
+	}
+}
+
+func overrideErrorResponse(c *fiber.Ctx, err error) error {
+	switch err.Error() {
+	case "achievement tidak ditemukan":
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error":   true,
+			"message": err.Error(),
+		})
+	case "hanya admin yang dapat melakukan override":
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error":   true,
+			"message": err.Error(),
+		})
+	}
+	return achievementErrorResponse(c, fiber.StatusBadRequest, err)
+}
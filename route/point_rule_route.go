@@ -0,0 +1,171 @@
+package route
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/service"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/middleware"
+)
+
+// RegisterPointRuleRoutes mendaftarkan route untuk aturan perhitungan poin
+func RegisterPointRuleRoutes(router fiber.Router, pointRuleService service.PointRuleService) {
+	pointRules := router.Group("/point-rules")
+	{
+		// GET /api/v1/point-rules - Daftar aturan poin
+		// Requires: read achievements permission
+		pointRules.Get("/", middleware.RBACMiddleware("read", "achievements"), func(c *fiber.Ctx) error {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			rules, err := pointRuleService.GetAllRules(ctx)
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			return c.JSON(fiber.Map{
+				"error": false,
+				"data":  rules,
+				"total": len(rules),
+			})
+		})
+
+		// POST /api/v1/point-rules/preview - Simulasi poin untuk tipe dan details tertentu
+		// Requires: read achievements permission
+		pointRules.Post("/preview", middleware.RBACMiddleware("read", "achievements"), func(c *fiber.Ctx) error {
+			var req service.PointPreviewRequest
+			if err := c.BodyParser(&req); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": "Invalid request body",
+				})
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			result, err := pointRuleService.PreviewPoints(ctx, &req)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			return c.JSON(fiber.Map{
+				"error": false,
+				"data":  result,
+			})
+		})
+
+		// POST /api/v1/point-rules - Create (Admin)
+		// Requires: manage point_rules permission
+		pointRules.Post("/", middleware.RBACMiddleware("manage", "point_rules"), func(c *fiber.Ctx) error {
+			var req service.PointRuleRequest
+			if err := c.BodyParser(&req); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": "Invalid request body",
+				})
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			rule, err := pointRuleService.CreateRule(ctx, &req)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+				"error":   false,
+				"message": "Aturan poin berhasil dibuat",
+				"data":    rule,
+			})
+		})
+
+		// PUT /api/v1/point-rules/:id - Update (Admin)
+		// Requires: manage point_rules permission
+		pointRules.Put("/:id", middleware.RBACMiddleware("manage", "point_rules"), func(c *fiber.Ctx) error {
+			ruleID, err := uuid.Parse(c.Params("id"))
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": "Rule ID tidak valid",
+				})
+			}
+
+			var req service.PointRuleRequest
+			if err := c.BodyParser(&req); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": "Invalid request body",
+				})
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			rule, err := pointRuleService.UpdateRule(ctx, ruleID, &req)
+			if err != nil {
+				if err.Error() == "aturan poin tidak ditemukan" {
+					return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+						"error":   true,
+						"message": err.Error(),
+					})
+				}
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			return c.JSON(fiber.Map{
+				"error":   false,
+				"message": "Aturan poin berhasil diupdate",
+				"data":    rule,
+			})
+		})
+
+		// DELETE /api/v1/point-rules/:id - Delete (Admin)
+		// Requires: manage point_rules permission
+		pointRules.Delete("/:id", middleware.RBACMiddleware("manage", "point_rules"), func(c *fiber.Ctx) error {
+			ruleID, err := uuid.Parse(c.Params("id"))
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": "Rule ID tidak valid",
+				})
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			if err := pointRuleService.DeleteRule(ctx, ruleID); err != nil {
+				if err.Error() == "aturan poin tidak ditemukan" {
+					return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+						"error":   true,
+						"message": err.Error(),
+					})
+				}
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			return c.JSON(fiber.Map{
+				"error":   false,
+				"message": "Aturan poin berhasil dihapus",
+			})
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package route
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/service"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/middleware"
+)
+
+// RegisterPortfolioRoutes mendaftarkan route ekspor portofolio prestasi mahasiswa
+func RegisterPortfolioRoutes(router fiber.Router, portfolioService service.PortfolioService) {
+	students := router.Group("/students")
+	{
+		// GET /api/v1/students/:id/achievements/export - Unduh portofolio prestasi terverifikasi
+		// Query: format=pdf|html|json (JSON Resume), from=YYYY-MM-DD, to=YYYY-MM-DD,
+		// types=competition,publication, attachments=true
+		// Requires: read students permission (sama dengan GET /students/:id/achievements)
+		students.Get("/:id/achievements/export", middleware.RBACMiddleware("read", "students"), func(c *fiber.Ctx) error {
+			studentID, err := uuid.Parse(c.Params("id"))
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": "Student ID tidak valid",
+				})
+			}
+
+			req := service.PortfolioExportRequest{
+				Format:           c.Query("format", service.PortfolioFormatPDF),
+				EmbedAttachments: c.QueryBool("attachments", false),
+			}
+			for _, param := range []struct {
+				key    string
+				target **time.Time
+			}{{"from", &req.From}, {"to", &req.To}} {
+				value := c.Query(param.key)
+				if value == "" {
+					continue
+				}
+				date, err := time.ParseInLocation("2006-01-02", value, time.Local)
+				if err != nil {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+						"error":   true,
+						"message": "Format tanggal " + param.key + " tidak valid, gunakan YYYY-MM-DD",
+					})
+				}
+				*param.target = &date
+			}
+			for _, t := range strings.Split(c.Query("types"), ",") {
+				if t = strings.TrimSpace(t); t != "" {
+					req.Types = append(req.Types, model.AchievementType(t))
+				}
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			file, err := portfolioService.ExportPortfolio(ctx, studentID, &req)
+			if err != nil {
+				status := fiber.StatusBadRequest
+				if err.Error() == "mahasiswa tidak ditemukan" {
+					status = fiber.StatusNotFound
+				}
+				return c.Status(status).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			c.Set(fiber.HeaderContentType, file.ContentType)
+			c.Set(fiber.HeaderContentDisposition, `attachment; filename="`+file.FileName+`"`)
+			return c.Send(file.Content)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package route
 
 import (
 	"context"
+	"strconv"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -108,6 +109,55 @@ func RegisterReportRoutes(router fiber.Router, reportService service.ReportServi
 				"data":  statistics,
 			})
 		})
+
+		// GET /api/v1/reports/publications.bib - Ekspor publikasi dalam format BibTeX
+		// GET /api/v1/reports/publications.ris - Ekspor publikasi dalam format RIS
+		// Query: program_study, year, status (default verified, "all" untuk semua status)
+		// Requires: read achievements permission
+		reports.Get("/publications.bib", middleware.RBACMiddleware("read", "achievements"), publicationExportHandler(reportService, "bib"))
+		reports.Get("/publications.ris", middleware.RBACMiddleware("read", "achievements"), publicationExportHandler(reportService, "ris"))
 	}
 }
 
+
+func publicationExportHandler(reportService service.ReportService, format string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, err := getUserIDFromContext(c)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   true,
+				"message": err.Error(),
+			})
+		}
+
+		req := &service.PublicationExportRequest{
+			Format:       format,
+			ProgramStudy: c.Query("program_study"),
+			Status:       c.Query("status"),
+		}
+		if year := c.Query("year"); year != "" {
+			req.Year, err = strconv.Atoi(year)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": "year harus berupa angka",
+				})
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		file, err := reportService.ExportPublications(ctx, userID, req)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   true,
+				"message": err.Error(),
+			})
+		}
+
+		c.Set(fiber.HeaderContentType, file.ContentType)
+		c.Set(fiber.HeaderContentDisposition, "attachment; filename=\""+file.FileName+"\"")
+		return c.Send(file.Content)
+	}
+}
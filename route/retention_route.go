@@ -0,0 +1,57 @@
+package route
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/service"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/middleware"
+)
+
+// RegisterRetentionRoutes mendaftarkan route pemulihan achievement yang sudah dihapus
+func RegisterRetentionRoutes(router fiber.Router, retentionService service.RetentionService) {
+	achievements := router.Group("/achievements")
+	{
+		// POST /api/v1/achievements/:id/restore - Pulihkan achievement terhapus selama masa retensi
+		// Requires: delete achievements permission
+		achievements.Post("/:id/restore", middleware.RBACMiddleware("delete", "achievements"), func(c *fiber.Ctx) error {
+			userID, err := getUserIDFromContext(c)
+			if err != nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			achievementID := c.Params("id")
+			if achievementID == "" {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": "achievement ID harus diisi",
+				})
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			result, err := retentionService.RestoreAchievement(ctx, userID, achievementID)
+			if err != nil {
+				status := fiber.StatusBadRequest
+				if err.Error() == "achievement terhapus tidak ditemukan" {
+					status = fiber.StatusNotFound
+				}
+				return c.Status(status).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			return c.JSON(fiber.Map{
+				"error":   false,
+				"message": "Achievement berhasil dipulihkan",
+				"data":    result,
+			})
+		})
+	}
+}
@@ -10,24 +10,103 @@ import (
 	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/repository"
 	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/service"
 	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/middleware"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/scanner"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/storage"
 	"go.mongodb.org/mongo-driver/mongo"
 	"gorm.io/gorm"
 )
 
-func RegisterRoutes(app *fiber.App, db *gorm.DB, mongoDB *mongo.Database, jwtSecret string, jwtExpiry time.Duration) {
+// Options pengaturan tambahan untuk service yang dibuat di RegisterRoutes
+type Options struct {
+	VerificationSLA             time.Duration     // Batas waktu verifikasi dosen wali
+	VerificationEscalationAfter time.Duration     // Batas kedua sebelum eskalasi, 0 = tanpa eskalasi
+	VerificationAlternate       string            // Username verifikator pengganti, kosong = admin
+	SLACheckInterval            time.Duration     // Interval pemeriksaan SLA, 0 = monitor tidak dijalankan
+	AchievementRetention        time.Duration     // Masa pemulihan achievement yang dihapus
+	PurgeInterval               time.Duration     // Interval purge permanen, 0 = purge tidak dijalankan
+	CertificateSecret           string            // Kunci HMAC sertifikat, wajib dan berbeda dari JWT secret
+	PublicBaseURL               string            // Awalan URL verifikasi sertifikat pada QR code
+	SKPITemplatePath            string            // File JSON template SKPI, kosong = template default
+	BadgeIssuerName             string            // Nama issuer Open Badges
+	BadgeIssuerEmail            string            // Email kontak issuer Open Badges
+	BadgeVerifiableCredential   bool              // Terbitkan juga W3C Verifiable Credential
+	BadgeKeyPath                string            // File kunci Ed25519 institusi, dibuat otomatis jika belum ada
+	DOIResolverURL              string            // Base URL API kompatibel Crossref, kosong/"off" = hanya cache
+	DOIResolverMailto           string            // Kontak yang dikirim ke resolver
+	DOICacheDir                 string            // Direktori cache metadata DOI, kosong/"off" = tanpa cache
+	DOICacheTTL                 time.Duration     // Umur cache sebelum diminta ulang, 0 = tidak kedaluwarsa
+	CertificationReminderDays   []int             // Jendela pengingat masa berlaku sertifikasi (hari)
+	CertificationCheckInterval  time.Duration     // Interval pengingat sertifikasi, 0 = tidak dijalankan
+	BlobStore                   storage.BlobStore // Penyimpanan attachment, nil = disk lokal di uploads
+	AttachmentURLTTL            time.Duration     // Masa berlaku URL sementara attachment, 0 = nonaktif
+	AttachmentURLSecret         string            // Kunci HMAC URL sementara attachment, terpisah dari JWT secret
+	AttachmentSizeLimits        map[string]int64  // Batas ukuran attachment per jenis file (byte), kosong = default
+	Scanner                     scanner.Scanner   // Pemindai malware attachment, nil = tanpa pemindaian
+}
+
+func RegisterRoutes(app *fiber.App, db *gorm.DB, mongoDB *mongo.Database, jwtSecret string, jwtExpiry time.Duration, opts Options) {
 	userRepo := repository.NewUserRepository(db)
 	roleRepo := repository.NewRoleRepository(db)
 	lecturerRepo := repository.NewLecturerRepository(db)
 	studentRepo := repository.NewStudentRepository(db)
 	achievementRepo := repository.NewAchievementRepository(db, mongoDB)
 	historyRepo := repository.NewAchievementHistoryRepository(db)
+	versionRepo := repository.NewAchievementVersionRepository(mongoDB)
+	pointRuleRepo := repository.NewPointRuleRepository(db)
+	achievementTypeRepo := repository.NewAchievementTypeRepository(mongoDB)
+	approvalChainRepo := repository.NewApprovalChainRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	delegationRepo := repository.NewDelegationRepository(db)
+	certificateRepo := repository.NewCertificateRepository(db)
+	skpiRepo := repository.NewSKPIRepository(db)
+	badgeRepo := repository.NewBadgeRepository(db)
 
 	authService := service.NewAuthService(userRepo, roleRepo, jwtSecret, jwtExpiry)
 	userService := service.NewUserService(userRepo, roleRepo, lecturerRepo, studentRepo, authService)
-	achievementService := service.NewAchievementService(achievementRepo, historyRepo, studentRepo, lecturerRepo, userRepo, roleRepo)
+	pointRuleService := service.NewPointRuleService(pointRuleRepo, achievementRepo)
+	approvalChainService := service.NewApprovalChainService(approvalChainRepo)
+	blobStore := opts.BlobStore
+	if blobStore == nil {
+		blobStore = storage.NewLocalStore("uploads")
+	}
+	certificateService := service.NewCertificateService(certificateRepo, achievementRepo, achievementTypeRepo, studentRepo, userRepo, blobStore, opts.CertificateSecret, opts.PublicBaseURL)
+	badgeService := service.NewBadgeService(badgeRepo, achievementRepo, studentRepo, opts.PublicBaseURL, opts.BadgeIssuerName, opts.BadgeIssuerEmail, opts.BadgeVerifiableCredential, opts.BadgeKeyPath)
+	fileScanner := opts.Scanner
+	if fileScanner == nil {
+		fileScanner = scanner.NewNoopScanner()
+	}
+	var metadataResolver service.MetadataResolver
+	if opts.DOIResolverURL != "" && !strings.EqualFold(opts.DOIResolverURL, "off") {
+		metadataResolver = service.NewCrossrefResolver(opts.DOIResolverURL, opts.DOIResolverMailto)
+	}
+	if opts.DOICacheDir != "" && !strings.EqualFold(opts.DOICacheDir, "off") {
+		metadataResolver = service.NewCachedMetadataResolver(metadataResolver, opts.DOICacheDir, opts.DOICacheTTL)
+	}
+	achievementService := service.NewAchievementService(achievementRepo, historyRepo, versionRepo, achievementTypeRepo, studentRepo, lecturerRepo, userRepo, roleRepo, pointRuleService, approvalChainService, delegationRepo, certificateService, badgeService, metadataResolver, blobStore, opts.AttachmentSizeLimits, fileScanner)
+	achievementTypeService := service.NewAchievementTypeService(achievementTypeRepo)
 	studentService := service.NewStudentService(studentRepo, lecturerRepo, achievementRepo)
 	lecturerService := service.NewLecturerService(lecturerRepo, studentRepo)
 	reportService := service.NewReportService(achievementRepo, studentRepo, lecturerRepo, userRepo, roleRepo)
+	notificationService := service.NewNotificationService(notificationRepo)
+	delegationService := service.NewDelegationService(delegationRepo, lecturerRepo, userRepo)
+	verificationService := service.NewVerificationService(achievementService, achievementRepo, lecturerRepo, userRepo, notificationService, opts.VerificationSLA, opts.VerificationEscalationAfter, opts.VerificationAlternate)
+
+	retentionService := service.NewRetentionService(achievementService, achievementRepo, historyRepo, versionRepo, studentRepo, userRepo, blobStore, opts.AchievementRetention)
+	portfolioService := service.NewPortfolioService(studentService, achievementTypeRepo, blobStore)
+	skpiService := service.NewSKPIService(skpiRepo, achievementRepo, certificateRepo, studentRepo, lecturerRepo, userRepo, opts.SKPITemplatePath)
+	importService := service.NewImportService(achievementRepo, historyRepo, achievementTypeRepo, studentRepo, userRepo, pointRuleService)
+	certificationService := service.NewCertificationService(achievementRepo, lecturerRepo, userRepo, notificationService, opts.CertificationReminderDays)
+	attachmentService := service.NewAttachmentService(achievementRepo, blobStore, opts.AttachmentURLSecret, opts.PublicBaseURL, opts.AttachmentURLTTL)
+
+	if opts.SLACheckInterval > 0 {
+		verificationService.StartSLAMonitor(opts.SLACheckInterval)
+	}
+	if opts.PurgeInterval > 0 {
+		retentionService.StartPurgeMonitor(opts.PurgeInterval)
+	}
+	if opts.CertificationCheckInterval > 0 {
+		certificationService.StartExpiryMonitor(opts.CertificationCheckInterval)
+	}
 
 	app.Get("/", func(c *fiber.Ctx) error {
 		return c.JSON(fiber.Map{
@@ -36,6 +115,10 @@ func RegisterRoutes(app *fiber.App, db *gorm.DB, mongoDB *mongo.Database, jwtSec
 		})
 	})
 
+	RegisterPublicCertificateRoutes(app, certificateService)
+	RegisterPublicBadgeRoutes(app, badgeService)
+	RegisterPublicAttachmentRoutes(app, attachmentService)
+
 	authPublic := app.Group("/api/v1/auth")
 	{
 		authPublic.Post("/login", func(c *fiber.Ctx) error {
@@ -275,6 +358,22 @@ func RegisterRoutes(app *fiber.App, db *gorm.DB, mongoDB *mongo.Database, jwtSec
 			RegisterStudentRoutes(v1, studentService)
 			RegisterLecturerRoutes(v1, lecturerService)
 			RegisterReportRoutes(v1, reportService)
+			RegisterPointRuleRoutes(v1, pointRuleService)
+			RegisterAchievementTypeRoutes(v1, achievementTypeService)
+			RegisterApprovalRoutes(v1, approvalChainService, achievementService)
+			RegisterVerificationRoutes(v1, verificationService)
+			RegisterNotificationRoutes(v1, notificationService)
+			RegisterDelegationRoutes(v1, delegationService)
+			RegisterRetentionRoutes(v1, retentionService)
+			RegisterOverrideRoutes(v1, achievementService)
+			RegisterCertificateRoutes(v1, achievementService, certificateService)
+			RegisterSKPIRoutes(v1, skpiService)
+			RegisterPortfolioRoutes(v1, portfolioService)
+			RegisterBadgeRoutes(v1, achievementService, badgeService)
+			RegisterImportRoutes(v1, importService, achievementService)
+			RegisterMetadataRoutes(v1, achievementService)
+			RegisterCertificationRoutes(v1, certificationService)
+			RegisterAttachmentRoutes(v1, achievementService, attachmentService)
 		}
 	}
 }
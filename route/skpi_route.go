@@ -0,0 +1,135 @@
+package route
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/service"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/middleware"
+)
+
+// skpiErrorResponse memetakan error SKPI ke status HTTP
+func skpiErrorResponse(c *fiber.Ctx, err error) error {
+	status := fiber.StatusBadRequest
+	switch {
+	case err.Error() == "mahasiswa tidak ditemukan":
+		status = fiber.StatusNotFound
+	case strings.Contains(err.Error(), "akses"), strings.Contains(err.Error(), "bukan dosen wali"), strings.HasPrefix(err.Error(), "hanya admin"):
+		status = fiber.StatusForbidden
+	case err.Error() == "SKPI mahasiswa ini sudah dikunci":
+		status = fiber.StatusConflict
+	}
+	return c.Status(status).JSON(fiber.Map{
+		"error":   true,
+		"message": err.Error(),
+	})
+}
+
+// RegisterSKPIRoutes mendaftarkan route Surat Keterangan Pendamping Ijazah (SKPI)
+func RegisterSKPIRoutes(router fiber.Router, skpiService service.SKPIService) {
+	students := router.Group("/students")
+	{
+		// GET /api/v1/students/:id/skpi?format=json|pdf - SKPI dari prestasi terverifikasi
+		// Mengembalikan versi final jika sudah dikunci, selain itu draft terkini
+		// Requires: read achievements permission
+		students.Get("/:id/skpi", middleware.RBACMiddleware("read", "achievements"), func(c *fiber.Ctx) error {
+			userID, err := getUserIDFromContext(c)
+			if err != nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			studentID, err := uuid.Parse(c.Params("id"))
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": "Invalid student ID",
+				})
+			}
+
+			format := strings.ToLower(c.Query("format", "json"))
+			if format != "json" && format != "pdf" {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": "format tidak valid. Pilih: json, pdf",
+				})
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			skpi, err := skpiService.GetSKPI(ctx, userID, studentID)
+			if err != nil {
+				return skpiErrorResponse(c, err)
+			}
+
+			if format == "pdf" {
+				content, err := skpiService.RenderSKPIPDF(skpi)
+				if err != nil {
+					return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+						"error":   true,
+						"message": "gagal membuat PDF SKPI: " + err.Error(),
+					})
+				}
+				c.Set(fiber.HeaderContentType, "application/pdf")
+				c.Set(fiber.HeaderContentDisposition, `attachment; filename="skpi-`+skpi.Student.NIM+`.pdf"`)
+				return c.Send(content)
+			}
+
+			return c.JSON(fiber.Map{
+				"error":   false,
+				"message": "SKPI berhasil dibuat",
+				"data":    skpi,
+			})
+		})
+
+		// POST /api/v1/students/:id/skpi/lock - Kunci SKPI final saat kelulusan
+		// Requires: manage skpi permission
+		students.Post("/:id/skpi/lock", middleware.RBACMiddleware("manage", "skpi"), func(c *fiber.Ctx) error {
+			userID, err := getUserIDFromContext(c)
+			if err != nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			studentID, err := uuid.Parse(c.Params("id"))
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": "Invalid student ID",
+				})
+			}
+
+			var req service.LockSKPIRequest
+			if len(c.Body()) > 0 {
+				if err := c.BodyParser(&req); err != nil {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+						"error":   true,
+						"message": "Invalid request body",
+					})
+				}
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			skpi, err := skpiService.LockSKPI(ctx, userID, studentID, &req)
+			if err != nil {
+				return skpiErrorResponse(c, err)
+			}
+
+			return c.JSON(fiber.Map{
+				"error":   false,
+				"message": "SKPI berhasil dikunci",
+				"data":    skpi,
+			})
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package route
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/model"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/app/service"
+	"github.com/sayu0044/Sistem-Pelaporan-Prestasi-Mahasiswa/middleware"
+)
+
+// RegisterVerificationRoutes mendaftarkan route antrian verifikasi dengan status SLA
+func RegisterVerificationRoutes(router fiber.Router, verificationService service.VerificationService) {
+	verification := router.Group("/verification")
+	{
+		// GET /api/v1/verification/queue?stage= - Antrian satu tahap persetujuan, paling lama
+		// menunggu di urutan pertama. Tanpa stage dipakai tahap pertama yang boleh diproses caller.
+		// Requires: permission tahap tersebut (lihat approvalStagePermission)
+		verification.Get("/queue", func(c *fiber.Ctx) error {
+			userID, err := getUserIDFromContext(c)
+			if err != nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			stage := model.ApprovalStage(c.Query("stage"))
+			if stage == "" {
+				stage = callerApprovalStage(c)
+			}
+			if action, resource := approvalStagePermission(stage); stage == "" || !middleware.HasPermission(c, action, resource) {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+					"error":   true,
+					"message": "Anda tidak memiliki izin untuk mengakses antrian tahap ini",
+				})
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			result, err := verificationService.GetQueue(ctx, userID, stage)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": err.Error(),
+				})
+			}
+
+			return c.JSON(fiber.Map{
+				"error": false,
+				"data":  result,
+				"total": len(result),
+			})
+		})
+	}
+}
+
+// callerApprovalStage tahap pertama approval chain yang boleh diproses caller, kosong jika tidak ada
+func callerApprovalStage(c *fiber.Ctx) model.ApprovalStage {
+	for _, stage := range []model.ApprovalStage{model.ApprovalStageAdvisor, model.ApprovalStageProgramHead, model.ApprovalStageStudentAffairs} {
+		if action, resource := approvalStagePermission(stage); middleware.HasPermission(c, action, resource) {
+			return stage
+		}
+	}
+	return ""
+}
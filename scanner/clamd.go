@@ -0,0 +1,129 @@
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// clamdChunkSize ukuran potongan data INSTREAM, harus di bawah StreamMaxLength clamd
+const clamdChunkSize = 32 << 10
+
+// clamdScanner klien daemon ClamAV (clamd) memakai perintah INSTREAM: data dikirim dalam
+// potongan <panjang 4 byte big-endian><data>, diakhiri potongan berpanjang 0. Balasan berupa
+// "stream: OK", "stream: <signature> FOUND" atau "<pesan> ERROR".
+type clamdScanner struct {
+	network string
+	address string
+	timeout time.Duration
+}
+
+func NewClamdScanner(address string, timeout time.Duration) (Scanner, error) {
+	network := "tcp"
+	switch {
+	case strings.HasPrefix(address, "unix://"):
+		network = "unix"
+		address = strings.TrimPrefix(address, "unix://")
+	case strings.HasPrefix(address, "tcp://"):
+		address = strings.TrimPrefix(address, "tcp://")
+	}
+	if address == "" {
+		return nil, errors.New("alamat clamd wajib diisi")
+	}
+	if network == "tcp" {
+		if _, _, err := net.SplitHostPort(address); err != nil {
+			return nil, fmt.Errorf("alamat clamd tidak valid: %s", address)
+		}
+	}
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+
+	return &clamdScanner{network: network, address: address, timeout: timeout}, nil
+}
+
+func (s *clamdScanner) Name() string {
+	return "clamd"
+}
+
+func (s *clamdScanner) Scan(ctx context.Context, content io.Reader) (*Result, error) {
+	dialer := net.Dialer{Timeout: s.timeout}
+	conn, err := dialer.DialContext(ctx, s.network, s.address)
+	if err != nil {
+		return nil, fmt.Errorf("gagal menghubungi clamd: %v", err)
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(s.timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	conn.SetDeadline(deadline)
+
+	if err := s.stream(conn, content); err != nil {
+		// clamd memutus koneksi jika StreamMaxLength terlampaui; balasannya menjelaskan sebabnya
+		if reply, readErr := readClamdReply(conn); readErr == nil && reply != "" {
+			return parseClamdReply(reply)
+		}
+		return nil, fmt.Errorf("gagal mengirim file ke clamd: %v", err)
+	}
+
+	reply, err := readClamdReply(conn)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca balasan clamd: %v", err)
+	}
+	return parseClamdReply(reply)
+}
+
+func (s *clamdScanner) stream(conn net.Conn, content io.Reader) error {
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return err
+	}
+
+	buf := make([]byte, 4+clamdChunkSize)
+	for {
+		n, err := content.Read(buf[4:])
+		if n > 0 {
+			binary.BigEndian.PutUint32(buf[:4], uint32(n))
+			if _, writeErr := conn.Write(buf[:4+n]); writeErr != nil {
+				return writeErr
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := conn.Write([]byte{0, 0, 0, 0})
+	return err
+}
+
+// readClamdReply membaca satu balasan yang diakhiri byte NUL (mode perintah berawalan "z")
+func readClamdReply(conn net.Conn) (string, error) {
+	reply, err := bufio.NewReader(io.LimitReader(conn, 4096)).ReadString(0)
+	if err != nil && !(err == io.EOF && reply != "") {
+		return "", err
+	}
+	return strings.TrimSpace(strings.TrimRight(reply, "\x00")), nil
+}
+
+func parseClamdReply(reply string) (*Result, error) {
+	status := strings.TrimPrefix(reply, "stream: ")
+	switch {
+	case status == "OK":
+		return &Result{}, nil
+	case strings.HasSuffix(status, " FOUND"):
+		return &Result{Infected: true, Signature: strings.TrimSuffix(status, " FOUND")}, nil
+	default:
+		return nil, fmt.Errorf("clamd: %s", status)
+	}
+}
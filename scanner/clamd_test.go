@@ -0,0 +1,228 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeClamd daemon clamd lokal yang memahami perintah zINSTREAM. Data berisi "EICAR"
+// dilaporkan terinfeksi; data melebihi maxLength dijawab error seperti StreamMaxLength clamd.
+type fakeClamd struct {
+	listener  net.Listener
+	maxLength int
+	silent    bool // tidak pernah membalas, untuk menguji timeout
+	received  chan []byte
+}
+
+func startFakeClamd(t *testing.T, network, address string, maxLength int) *fakeClamd {
+	t.Helper()
+	listener, err := net.Listen(network, address)
+	if err != nil {
+		t.Fatalf("gagal membuka listener: %v", err)
+	}
+	daemon := &fakeClamd{listener: listener, maxLength: maxLength, received: make(chan []byte, 8)}
+	t.Cleanup(func() { listener.Close() })
+	go daemon.serve(t)
+	return daemon
+}
+
+func (d *fakeClamd) serve(t *testing.T) {
+	for {
+		conn, err := d.listener.Accept()
+		if err != nil {
+			return
+		}
+		go d.handle(t, conn)
+	}
+}
+
+func (d *fakeClamd) handle(t *testing.T, conn net.Conn) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	command := make([]byte, len("zINSTREAM\x00"))
+	if _, err := io.ReadFull(conn, command); err != nil || string(command) != "zINSTREAM\x00" {
+		t.Errorf("perintah clamd = %q, %v; want zINSTREAM", command, err)
+		return
+	}
+
+	var data []byte
+	for {
+		var size uint32
+		if err := binary.Read(conn, binary.BigEndian, &size); err != nil {
+			t.Errorf("gagal membaca panjang potongan: %v", err)
+			return
+		}
+		if size == 0 {
+			break
+		}
+		if size > clamdChunkSize {
+			t.Errorf("potongan %d byte melebihi clamdChunkSize", size)
+		}
+		chunk := make([]byte, size)
+		if _, err := io.ReadFull(conn, chunk); err != nil {
+			t.Errorf("gagal membaca potongan: %v", err)
+			return
+		}
+		data = append(data, chunk...)
+		if d.maxLength > 0 && len(data) > d.maxLength {
+			conn.Write([]byte("INSTREAM size limit exceeded. ERROR\x00"))
+			return
+		}
+	}
+	d.received <- data
+
+	if d.silent {
+		time.Sleep(time.Second)
+		return
+	}
+	if bytes.Contains(data, []byte("EICAR")) {
+		conn.Write([]byte("stream: Eicar-Test-Signature FOUND\x00"))
+		return
+	}
+	conn.Write([]byte("stream: OK\x00"))
+}
+
+func TestClamdScannerInstream(t *testing.T) {
+	daemon := startFakeClamd(t, "tcp", "127.0.0.1:0", 0)
+	scanner, err := NewClamdScanner("tcp://"+daemon.listener.Addr().String(), 5*time.Second)
+	if err != nil {
+		t.Fatalf("NewClamdScanner() error = %v", err)
+	}
+
+	// Lebih besar dari satu potongan agar data dikirim dalam beberapa potongan
+	clean := bytes.Repeat([]byte("%PDF-1.4 bersih "), 5000)
+	result, err := scanner.Scan(context.Background(), bytes.NewReader(clean))
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if result.Infected {
+		t.Errorf("Scan() file bersih = %+v", result)
+	}
+	if got := <-daemon.received; !bytes.Equal(got, clean) {
+		t.Errorf("clamd menerima %d byte, want %d byte yang sama", len(got), len(clean))
+	}
+
+	result, err = scanner.Scan(context.Background(), strings.NewReader("X5O!P%@AP[4\\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*"))
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if !result.Infected || result.Signature != "Eicar-Test-Signature" {
+		t.Errorf("Scan() file EICAR = %+v, want terinfeksi Eicar-Test-Signature", result)
+	}
+	<-daemon.received
+
+	result, err = scanner.Scan(context.Background(), strings.NewReader(""))
+	if err != nil || result.Infected {
+		t.Errorf("Scan() file kosong = %+v, %v", result, err)
+	}
+}
+
+func TestClamdScannerSizeLimit(t *testing.T) {
+	daemon := startFakeClamd(t, "tcp", "127.0.0.1:0", 1024)
+	scanner, err := NewClamdScanner(daemon.listener.Addr().String(), 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = scanner.Scan(context.Background(), bytes.NewReader(make([]byte, 4*clamdChunkSize)))
+	if err == nil || !strings.Contains(err.Error(), "size limit exceeded") {
+		t.Errorf("Scan() melebihi StreamMaxLength error = %v, want pesan dari clamd", err)
+	}
+}
+
+func TestClamdScannerUnixSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "clamd.sock")
+	startFakeClamd(t, "unix", socket, 0)
+	scanner, err := NewClamdScanner("unix://"+socket, 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result, err := scanner.Scan(context.Background(), strings.NewReader("isi")); err != nil || result.Infected {
+		t.Errorf("Scan() lewat unix socket = %+v, %v", result, err)
+	}
+}
+
+func TestClamdScannerUnavailable(t *testing.T) {
+	// Port yang baru saja ditutup: koneksi ditolak
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	scanner, err := NewClamdScanner(address, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := scanner.Scan(context.Background(), strings.NewReader("isi")); err == nil || !strings.Contains(err.Error(), "gagal menghubungi clamd") {
+		t.Errorf("Scan() clamd mati error = %v", err)
+	}
+
+	// Daemon yang tidak membalas dibatasi timeout
+	daemon := startFakeClamd(t, "tcp", "127.0.0.1:0", 0)
+	daemon.silent = true
+	scanner, err = NewClamdScanner(daemon.listener.Addr().String(), 200*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := scanner.Scan(context.Background(), strings.NewReader("isi")); err == nil || !strings.Contains(err.Error(), "gagal membaca balasan clamd") {
+		t.Errorf("Scan() clamd tidak membalas error = %v", err)
+	}
+}
+
+func TestNewClamdScannerAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		wantErr bool
+	}{
+		{"127.0.0.1:3310", false},
+		{"tcp://clamav:3310", false},
+		{"unix:///var/run/clamav/clamd.ctl", false},
+		{"", true},
+		{"tcp://", true},
+		{"clamav", true},
+	}
+
+	for _, tt := range tests {
+		if _, err := NewClamdScanner(tt.address, 0); (err != nil) != tt.wantErr {
+			t.Errorf("NewClamdScanner(%q) error = %v, wantErr %v", tt.address, err, tt.wantErr)
+		}
+	}
+}
+
+func TestParseClamdReply(t *testing.T) {
+	tests := []struct {
+		reply         string
+		wantInfected  bool
+		wantSignature string
+		wantErr       bool
+	}{
+		{"stream: OK", false, "", false},
+		{"stream: Win.Test.EICAR_HDB-1 FOUND", true, "Win.Test.EICAR_HDB-1", false},
+		{"stream: Eicar Signature Dengan Spasi FOUND", true, "Eicar Signature Dengan Spasi", false},
+		{"INSTREAM size limit exceeded. ERROR", false, "", true},
+		{"stream: Can't allocate memory ERROR", false, "", true},
+		{"UNKNOWN COMMAND", false, "", true},
+		{"", false, "", true},
+	}
+
+	for _, tt := range tests {
+		result, err := parseClamdReply(tt.reply)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseClamdReply(%q) error = %v, wantErr %v", tt.reply, err, tt.wantErr)
+			continue
+		}
+		if err == nil && (result.Infected != tt.wantInfected || result.Signature != tt.wantSignature) {
+			t.Errorf("parseClamdReply(%q) = %+v", tt.reply, result)
+		}
+	}
+}
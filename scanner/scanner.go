@@ -0,0 +1,55 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Scanner memindai isi file attachment sebelum disimpan. Error berarti pemindaian tidak
+// dapat dilakukan (misalnya daemon tidak dapat dihubungi), bukan file terinfeksi.
+type Scanner interface {
+	Scan(ctx context.Context, content io.Reader) (*Result, error)
+	Name() string
+}
+
+type Result struct {
+	Infected  bool
+	Signature string // Nama signature malware yang terdeteksi
+}
+
+// Config pengaturan pemindai malware
+type Config struct {
+	Backend      string        // none atau clamd
+	ClamdAddress string        // tcp://host:3310, host:3310 atau unix:///var/run/clamav/clamd.ctl
+	ClamdTimeout time.Duration // Batas waktu koneksi dan pemindaian satu file
+}
+
+// New membuat Scanner sesuai cfg.Backend
+func New(cfg Config) (Scanner, error) {
+	switch strings.ToLower(strings.TrimSpace(cfg.Backend)) {
+	case "", "none", "off":
+		return NewNoopScanner(), nil
+	case "clamd":
+		return NewClamdScanner(cfg.ClamdAddress, cfg.ClamdTimeout)
+	default:
+		return nil, fmt.Errorf("pemindai malware tidak dikenal: %s. Pilih: none, clamd", cfg.Backend)
+	}
+}
+
+// noopScanner menganggap semua file bersih, dipakai jika pemindai tidak dikonfigurasi
+type noopScanner struct{}
+
+func NewNoopScanner() Scanner {
+	return noopScanner{}
+}
+
+func (noopScanner) Name() string {
+	return "none"
+}
+
+func (noopScanner) Scan(ctx context.Context, content io.Reader) (*Result, error) {
+	return &Result{}, nil
+}
@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"path"
+	"strings"
+	"time"
+)
+
+// ErrBlobNotFound object tidak ada pada backend penyimpanan
+var ErrBlobNotFound = errors.New("file tidak ditemukan di penyimpanan")
+
+// BlobStore penyimpanan file attachment. Key memakai "/" sebagai pemisah
+// (misalnya "achievements/<id>_<waktu>.pdf") dan tidak bergantung pada backend,
+// sehingga file dapat dipindahkan antar backend tanpa mengubah key.
+type BlobStore interface {
+	Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, *BlobInfo, error)
+	// GetRange membaca length byte mulai dari offset; BlobInfo.Size tetap ukuran seluruh file
+	GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, *BlobInfo, error)
+	Stat(ctx context.Context, key string) (*BlobInfo, error)
+	Delete(ctx context.Context, key string) error
+	Backend() string
+}
+
+type BlobInfo struct {
+	Key          string
+	Size         int64
+	ContentType  string
+	LastModified time.Time
+}
+
+// Config pengaturan backend penyimpanan
+type Config struct {
+	Backend     string // local atau s3
+	LocalDir    string // direktori root backend local
+	S3Endpoint  string // contoh: https://s3.ap-southeast-1.amazonaws.com atau http://localhost:9000 (MinIO)
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+	S3PathStyle bool // true untuk MinIO dan server kompatibel S3 lain tanpa virtual-host bucket
+}
+
+// New membuat BlobStore sesuai cfg.Backend
+func New(cfg Config) (BlobStore, error) {
+	switch strings.ToLower(strings.TrimSpace(cfg.Backend)) {
+	case "", "local":
+		return NewLocalStore(cfg.LocalDir), nil
+	case "s3":
+		return NewS3Store(S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			PathStyle: cfg.S3PathStyle,
+		})
+	default:
+		return nil, fmt.Errorf("backend penyimpanan tidak dikenal: %s. Pilih: local, s3", cfg.Backend)
+	}
+}
+
+// ValidateKey menolak key kosong, absolut, atau yang mengandung segmen "." / ".."
+func ValidateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return fmt.Errorf("key penyimpanan tidak valid: %q", key)
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return fmt.Errorf("key penyimpanan tidak valid: %q", key)
+		}
+	}
+	return nil
+}
+
+// ContentTypeByKey tipe MIME berdasarkan ekstensi key
+func ContentTypeByKey(key string) string {
+	if contentType := mime.TypeByExtension(strings.ToLower(path.Ext(key))); contentType != "" {
+		return contentType
+	}
+	return "application/octet-stream"
+}
@@ -0,0 +1,166 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// contentTypeSuffix akhiran file pendamping yang menyimpan Content-Type blob, karena
+// disk tidak memiliki metadata object seperti S3
+const contentTypeSuffix = ".content-type"
+
+// localStore menyimpan file di bawah direktori root pada disk server
+type localStore struct {
+	root string
+}
+
+func NewLocalStore(root string) BlobStore {
+	if root == "" {
+		root = "uploads"
+	}
+	return &localStore{root: root}
+}
+
+func (s *localStore) Backend() string {
+	return "local"
+}
+
+func (s *localStore) path(key string) (string, error) {
+	if err := ValidateKey(key); err != nil {
+		return "", err
+	}
+	if strings.HasSuffix(key, contentTypeSuffix) {
+		return "", fmt.Errorf("key penyimpanan tidak valid: %q", key)
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
+
+func (s *localStore) Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	// Tulis ke file sementara lalu rename agar pembaca tidak melihat file setengah jadi
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(tmp, content); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	// File pendamping ditulis lebih dulu agar blob yang sudah terlihat selalu punya Content-Type
+	if contentType == "" {
+		err = os.Remove(path + contentTypeSuffix)
+		if os.IsNotExist(err) {
+			err = nil
+		}
+	} else {
+		err = os.WriteFile(path+contentTypeSuffix, []byte(contentType), 0644)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (s *localStore) Get(ctx context.Context, key string) (io.ReadCloser, *BlobInfo, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, ErrBlobNotFound
+		}
+		return nil, nil, err
+	}
+	stat, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+	return file, s.info(key, path, stat), nil
+}
+
+func (s *localStore) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, *BlobInfo, error) {
+	content, info, err := s.Get(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	file := content.(*os.File)
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+	return &limitedReadCloser{Reader: io.LimitReader(file, length), Closer: file}, info, nil
+}
+
+type limitedReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+func (s *localStore) Stat(ctx context.Context, key string) (*BlobInfo, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	stat, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrBlobNotFound
+		}
+		return nil, err
+	}
+	return s.info(key, path, stat), nil
+}
+
+func (s *localStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.Remove(path + contentTypeSuffix); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// info memakai Content-Type yang disimpan saat Put; file lama tanpa pendamping
+// ditebak dari ekstensi key
+func (s *localStore) info(key, path string, stat os.FileInfo) *BlobInfo {
+	contentType := ContentTypeByKey(key)
+	if stored, err := os.ReadFile(path + contentTypeSuffix); err == nil && len(strings.TrimSpace(string(stored))) > 0 {
+		contentType = strings.TrimSpace(string(stored))
+	}
+	return &BlobInfo{
+		Key:          key,
+		Size:         stat.Size(),
+		ContentType:  contentType,
+		LastModified: stat.ModTime(),
+	}
+}
@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalStoreContentType(t *testing.T) {
+	root := t.TempDir()
+	store := NewLocalStore(root)
+	ctx := context.Background()
+
+	// Key tanpa ekstensi yang sesuai: Content-Type harus mengikuti nilai saat Put
+	key := "achievements/abc_1700000000"
+	if err := store.Put(ctx, key, strings.NewReader("%PDF-1.7 isi"), 12, "application/pdf"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	content, info, err := store.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	data, _ := io.ReadAll(content)
+	content.Close()
+	if string(data) != "%PDF-1.7 isi" || info.Size != 12 {
+		t.Errorf("Get() = %q (%d byte)", data, info.Size)
+	}
+	if info.ContentType != "application/pdf" {
+		t.Errorf("Get() ContentType = %q, want application/pdf", info.ContentType)
+	}
+	if stat, err := store.Stat(ctx, key); err != nil || stat.ContentType != "application/pdf" {
+		t.Errorf("Stat() = %+v, %v", stat, err)
+	}
+
+	ranged, info, err := store.GetRange(ctx, key, 9, 3)
+	if err != nil {
+		t.Fatalf("GetRange() error = %v", err)
+	}
+	data, _ = io.ReadAll(ranged)
+	ranged.Close()
+	if string(data) != "isi" || info.Size != 12 || info.ContentType != "application/pdf" {
+		t.Errorf("GetRange() = %q, info %+v", data, info)
+	}
+
+	// File lama tanpa file pendamping ditebak dari ekstensi
+	legacy := filepath.Join(root, "achievements", "lama.png")
+	if err := os.WriteFile(legacy, []byte("png"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if stat, err := store.Stat(ctx, "achievements/lama.png"); err != nil || stat.ContentType != "image/png" {
+		t.Errorf("Stat() file lama = %+v, %v; want image/png", stat, err)
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "achievements", "abc_1700000000"+contentTypeSuffix)); !os.IsNotExist(err) {
+		t.Errorf("file pendamping Content-Type tidak ikut dihapus: %v", err)
+	}
+	if _, _, err := store.Get(ctx, key); !errors.Is(err, ErrBlobNotFound) {
+		t.Errorf("Get() setelah Delete error = %v, want ErrBlobNotFound", err)
+	}
+	if err := store.Delete(ctx, key); err != nil {
+		t.Errorf("Delete() key yang sudah tidak ada error = %v", err)
+	}
+}
+
+func TestLocalStoreRejectsInvalidKeys(t *testing.T) {
+	store := NewLocalStore(t.TempDir())
+	for _, key := range []string{"", "/etc/passwd", "../rahasia", "a//b", "a\\b", "achievements/x.pdf" + contentTypeSuffix} {
+		if err := store.Put(context.Background(), key, strings.NewReader("x"), 1, ""); err == nil {
+			t.Errorf("Put(%q) seharusnya ditolak", key)
+		}
+	}
+}